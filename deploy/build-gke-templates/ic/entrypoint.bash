@@ -34,7 +34,7 @@ export HYDRA_ADMIN_URL="${HYDRA_ADMIN_URL?}"
 export USE_HYDRA="true"
 # CONFIG_PATH is the path used for reading and writing config files.
 export CONFIG_PATH="deploy/config"
-# STORAGE is one of: "memory", "datastore".
+# STORAGE is one of: "memory", "datastore", "postgres" (requires POSTGRES_DSN), "embedded".
 export STORAGE="datastore"
 
 export IC_PORT="8080"
@@ -36,7 +36,7 @@ function start_ic() {
   export USE_HYDRA="true"
   # CONFIG_PATH is the path used for reading and writing config files.
   export CONFIG_PATH="deploy/config"
-  # STORAGE is one of: "memory", "datastore".
+  # STORAGE is one of: "memory", "datastore", "postgres" (requires POSTGRES_DSN), "embedded".
   export STORAGE="datastore"
   # FEDERATED_ACCESS_ENABLE_EXPERIMENTAL turns on experimental features if set to 'true'.
   # Not for use with production systems.
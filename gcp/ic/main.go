@@ -23,27 +23,58 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"time"
 
-	"cloud.google.com/go/kms/apiv1" /* copybara-comment: kms */
+	cloudkms "cloud.google.com/go/kms/apiv1" /* copybara-comment: kms */
 	"cloud.google.com/go/logging" /* copybara-comment: logging */
+	"cloud.google.com/go/secretmanager/apiv1" /* copybara-comment: secretmanager */
 	"github.com/gorilla/mux" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/boltstore" /* copybara-comment: boltstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache" /* copybara-comment: cache */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache/lru" /* copybara-comment: lru */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache/rediz" /* copybara-comment: rediz */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/dsstore" /* copybara-comment: dsstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/encryptedstore" /* copybara-comment: encryptedstore */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/grpcutil" /* copybara-comment: grpcutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/hydraproxy" /* copybara-comment: hydraproxy */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ic" /* copybara-comment: ic */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/filesign" /* copybara-comment: filesign */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/gcpcrypt" /* copybara-comment: gcpcrypt */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/gcpsign" /* copybara-comment: gcpsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localcrypt" /* copybara-comment: localcrypt */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/vaultcrypt" /* copybara-comment: vaultcrypt */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/vaultsign" /* copybara-comment: vaultsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/lockstore" /* copybara-comment: lockstore */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/osenv" /* copybara-comment: osenv */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/pgstore" /* copybara-comment: pgstore */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/server" /* copybara-comment: server */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret" /* copybara-comment: secret */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret/gcpsecret" /* copybara-comment: gcpsecret */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/serviceinfo" /* copybara-comment: serviceinfo */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/vault" /* copybara-comment: vault */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/verifier" /* copybara-comment: verifier */
 
 	glog "github.com/golang/glog" /* copybara-comment */
 	lgrpcpb "google.golang.org/genproto/googleapis/logging/v2" /* copybara-comment: logging_go_grpc */
 )
 
+const (
+	// signingKeysReloadInterval is how often keys of LOCAL_SIGNING_KEYS or
+	// VAULT_SIGNING_KEY are reloaded.
+	signingKeysReloadInterval = time.Minute
+	// secretCacheTTL is how long secrets of a remote SECRET_PROVIDER are cached.
+	secretCacheTTL = time.Minute
+	// localCacheTTL is how long values of redis are kept locally with CACHE
+	// "tiered".
+	localCacheTTL = 10 * time.Second
+)
+
 var (
 	// srvName is the name of this service.
 	srvName = osenv.VarWithDefault("SERVICE_NAME", "ic")
@@ -57,7 +88,9 @@ var (
 	// project is default GCP project for hosting storage,
 	// config options can override this.
 	project = osenv.MustVar("PROJECT")
-	// storageType determines we should be using in-mem storage or not.
+	// storageType determines the storage layer: "memory", "datastore", "postgres" or "embedded".
+	// "postgres" requires POSTGRES_DSN to be set to the connection string.
+	// "embedded" stores data in the file EMBEDDED_STORAGE_FILE (default: "<service name>.db").
 	storageType = osenv.MustVar("STORAGE")
 
 	port = osenv.VarWithDefault("IC_PORT", "8080")
@@ -72,6 +105,52 @@ var (
 	consentDashboardURL = osenv.VarWithDefault("CONSENT_DASHBOARD_URL", "https://github.com/GoogleCloudPlatform/healthcare-federated-access-services/blob/0f366e73284377571bc314da7666e4b14233c3fa/howto.md#how-do-i-revoke-a-remembered-consent")
 
 	useHydra = os.Getenv("USE_HYDRA") != ""
+	// encryptStorage enables encryption at rest of secrets, remembered consents
+	// and CLI login tokens in storage with the service KMS key. Items stored
+	// before it was enabled are read as plaintext, and encrypted when written
+	// again or by gcp/kms_rewrap.
+	encryptStorage = os.Getenv("ENCRYPT_STORAGE") != ""
+	// localKeyring is a JSON keyring to encrypt data locally instead of with
+	// GCP Cloud KMS. See lib/kms/localcrypt for the format.
+	localKeyring = os.Getenv("LOCAL_KEYRING")
+	// localKeyringFile is the path to a file with the JSON keyring, used if
+	// localKeyring is not set.
+	localKeyringFile = os.Getenv("LOCAL_KEYRING_FILE")
+	// localSigningKeys is the path to a PEM file or directory of PEM files with
+	// the keys to sign tokens instead of with GCP Cloud KMS. See lib/kms/filesign.
+	localSigningKeys = os.Getenv("LOCAL_SIGNING_KEYS")
+	// kmsSigningAlgorithm is the algorithm of the GCP Cloud KMS signing key:
+	// RS256, ES256 or ES384. The algorithm of local keys is their key type.
+	kmsSigningAlgorithm = osenv.VarWithDefault("KMS_SIGNING_ALGORITHM", "RS256")
+	// vaultEncryptionKey is the name of an aes256-gcm96 key of HashiCorp Vault
+	// Transit to encrypt data with instead of GCP Cloud KMS. Requires VAULT_ADDR
+	// and VAULT_TOKEN.
+	vaultEncryptionKey = os.Getenv("VAULT_ENCRYPTION_KEY")
+	// vaultSigningKey is the name of an asymmetric key of HashiCorp Vault
+	// Transit to sign tokens with instead of GCP Cloud KMS. Requires VAULT_ADDR
+	// and VAULT_TOKEN.
+	vaultSigningKey = os.Getenv("VAULT_SIGNING_KEY")
+	// vaultTransitMount is the mount path of the Vault Transit secrets engine.
+	vaultTransitMount = osenv.VarWithDefault("VAULT_TRANSIT_MOUNT", "transit")
+	// secretProvider resolves secret:// references of the service secrets:
+	// "env" (env vars with prefix SECRET_ENV_PREFIX, default "SECRET_"), "file"
+	// (files of the directory SECRET_DIR), "vault" (KV engine mounted at
+	// VAULT_KV_MOUNT, default "secret") or "secretmanager" (GCP Secret Manager
+	// of project SECRET_PROJECT, default PROJECT). Unset if the secrets hold raw
+	// values.
+	secretProvider = os.Getenv("SECRET_PROVIDER")
+	// cacheType selects the cache: "memory" (in-process LRU of CACHE_MAX_ENTRIES
+	// entries, default 10000), "redis" (at REDIS_ADDR) or "tiered" (redis
+	// fronted by the in-process LRU). Unset disables caching.
+	cacheType = os.Getenv("CACHE")
+	// lockProvider selects the locks of background work, such as garbage
+	// collection, shared by the instances of the service: "redis" (at
+	// REDIS_ADDR). Unset uses the locks of the storage, which are only shared
+	// across instances by "datastore".
+	lockProvider = os.Getenv("LOCK_PROVIDER")
+	// jwksRefreshInterval is how often the keys of token issuers are refreshed,
+	// such as "10m".
+	jwksRefreshInterval = osenv.VarWithDefault("JWKS_REFRESH_INTERVAL", "10m")
 	// hydraAdminAddr is the address for the Hydra admin endpoint.
 	hydraAdminAddr = ""
 	// hydraPublicAddr is the address for the Hydra public endpoint.
@@ -98,11 +177,54 @@ func main() {
 	defer sdlcc.Close()
 	sdlc := lgrpcpb.NewLoggingServiceV2Client(sdlcc)
 
+	var err error
+	var encryption kms.Encryption
+	switch {
+	case vaultEncryptionKey != "":
+		encryption, err = vaultcrypt.New(ctx, newVaultClient(), vaultTransitMount, vaultEncryptionKey)
+		if err != nil {
+			glog.Exitf("vaultcrypt.New(ctx, _, %q, %q) failed: %v", vaultTransitMount, vaultEncryptionKey, err)
+		}
+	case localKeyring != "":
+		encryption, err = localcrypt.New([]byte(localKeyring))
+		if err != nil {
+			glog.Exitf("localcrypt.New(LOCAL_KEYRING) failed: %v", err)
+		}
+	case localKeyringFile != "":
+		encryption, err = localcrypt.NewFromFile(localKeyringFile)
+		if err != nil {
+			glog.Exitf("localcrypt.NewFromFile(%q) failed: %v", localKeyringFile, err)
+		}
+	default:
+		encryption, err = gcpcrypt.New(ctx, project, "global", srvName+"_ring", srvName+"_key", gcpKMSClient(ctx))
+		if err != nil {
+			glog.Exitf("gcpcrypt.New(ctx, %q, %q, %q, %q, kmsClient) failed: %v", project, "global", srvName+"_ring", srvName+"_key", err)
+		}
+	}
+
 	var store storage.Store
 	switch storageType {
 	case "datastore":
-		store = dsstore.NewStore(ctx, project, srvName, cfgPath)
+		store = encryptStore(ctx, dsstore.NewStore(ctx, project, srvName, cfgPath), encryption)
+	case "postgres":
+		store = encryptStore(ctx, pgstore.NewStore(ctx, osenv.MustVar("POSTGRES_DSN"), srvName, cfgPath), encryption)
+	case "embedded":
+		store = encryptStore(ctx, boltstore.NewStore(osenv.VarWithDefault("EMBEDDED_STORAGE_FILE", srvName+".db"), srvName, cfgPath), encryption)
+		// Import and resolve template variables on first start only, later changes are kept on disk.
+		exists, err := store.Exists(storage.ConfigDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev)
+		if err != nil {
+			glog.Exitf("store.Exists(config) failed: %v", err)
+		}
+		if !exists {
+			if err := ic.ImportConfig(store, srvName, cfgVars, true, true, true); err != nil {
+				glog.Exitf("ic.ImportConfig(_, %q, _) failed: %v", srvName, err)
+			}
+		}
 	case "memory":
+		// Memory storage reads the plaintext config files of cfgPath.
+		if encryptStorage {
+			glog.Exitf("ENCRYPT_STORAGE is not supported with memory storage")
+		}
 		store = storage.NewMemoryStorage(srvName, cfgPath)
 		// Import and resolve template variables, if any.
 		if err := ic.ImportConfig(store, srvName, cfgVars, true, true, true); err != nil {
@@ -111,18 +233,29 @@ func main() {
 	default:
 		glog.Exitf("Unknown storage type: %q", storageType)
 	}
+	store = lockStore(ctx, store)
 
-	kmsClient, err := kms.NewKeyManagementClient(ctx)
-	if err != nil {
-		glog.Exitf("kms.NewKeyManagementClient(ctx) failed: %v", err)
-	}
-	gcpEncryption, err := gcpcrypt.New(ctx, project, "global", srvName+"_ring", srvName+"_key", kmsClient)
-	if err != nil {
-		glog.Exitf("gcpcrypt.New(ctx, %q, %q, %q, %q, kmsClient) failed: %v", project, "global", srvName+"_ring", srvName+"_key", err)
-	}
-	gcpSigner, err := gcpsign.New(ctx, project, "global", srvName+"_sign_ring", srvName+"_key", kmsClient)
-	if err != nil {
-		glog.Exitf("gcpsign.New(ctx, %q, %q, %q, %q, cliekmsClientnt) failed: %v", project, "global", srvName+"_sign_ring", srvName+"_key", err)
+	var signer kms.Signer
+	switch {
+	case vaultSigningKey != "":
+		vaultSigner, err := vaultsign.New(ctx, newVaultClient(), vaultTransitMount, vaultSigningKey)
+		if err != nil {
+			glog.Exitf("vaultsign.New(ctx, _, %q, %q) failed: %v", vaultTransitMount, vaultSigningKey, err)
+		}
+		go vaultSigner.Run(ctx, signingKeysReloadInterval)
+		signer = vaultSigner
+	case localSigningKeys != "":
+		fileSigner, err := filesign.New(localSigningKeys)
+		if err != nil {
+			glog.Exitf("filesign.New(%q) failed: %v", localSigningKeys, err)
+		}
+		go fileSigner.Run(ctx, signingKeysReloadInterval)
+		signer = fileSigner
+	default:
+		signer, err = gcpsign.NewWithAlgorithm(ctx, project, "global", srvName+"_sign_ring", srvName+"_key", jose.SignatureAlgorithm(kmsSigningAlgorithm), gcpKMSClient(ctx))
+		if err != nil {
+			glog.Exitf("gcpsign.NewWithAlgorithm(ctx, %q, %q, %q, %q, %q, kmsClient) failed: %v", project, "global", srvName+"_sign_ring", srvName+"_key", kmsSigningAlgorithm, err)
+		}
 	}
 
 	logger, err := logging.NewClient(ctx, project)
@@ -145,6 +278,13 @@ func main() {
 		}
 	}
 
+	refresh, err := time.ParseDuration(jwksRefreshInterval)
+	if err != nil {
+		glog.Exitf("invalid JWKS_REFRESH_INTERVAL %q: %v", jwksRefreshInterval, err)
+	}
+	jwks := verifier.NewJWKSCache(verifier.JWKSCacheOptions{RefreshInterval: refresh})
+	go jwks.Run(ctx)
+
 	r := mux.NewRouter()
 
 	s := ic.New(r, &ic.Options{
@@ -152,8 +292,8 @@ func main() {
 		ServiceName:                srvName,
 		AccountDomain:              acctDomain,
 		Store:                      store,
-		Encryption:                 gcpEncryption,
-		Signer:                     gcpSigner,
+		Encryption:                 encryption,
+		Signer:                     signer,
 		Logger:                     logger,
 		SDLC:                       sdlc,
 		AuditLogProject:            project,
@@ -163,6 +303,9 @@ func main() {
 		HydraPublicURL:             hydraPublicAddr,
 		HydraPublicProxy:           hyproxy,
 		ConsentDashboardURL:        consentDashboardURL,
+		SecretProvider:             newSecretProvider(ctx),
+		Cache:                      newCache(),
+		JWKSCache:                  jwks,
 	})
 
 	r.HandleFunc("/liveness_check", httputils.LivenessCheckHandler)
@@ -181,6 +324,114 @@ func main() {
 	srv.Shutdown()
 }
 
+// encryptStore wraps store to encrypt sensitive datatypes at rest if enabled.
+func encryptStore(ctx context.Context, store storage.Store, enc kms.Encryption) storage.Store {
+	if !encryptStorage {
+		return store
+	}
+	return encryptedstore.New(ctx, store, enc, encryptedstore.DefaultPolicy)
+}
+
+// lockStore wraps store to take the locks of LockTx from LOCK_PROVIDER if set.
+func lockStore(ctx context.Context, store storage.Store) storage.Store {
+	switch lockProvider {
+	case "":
+		return store
+	case "redis":
+		locker := rediz.NewLocker(rediz.NewPool(osenv.MustVar("REDIS_ADDR")), srvName+":")
+		return lockstore.New(ctx, store, locker, lockstore.DefaultTTL)
+	default:
+		glog.Exitf("Unknown lock provider %q", lockProvider)
+		return nil
+	}
+}
+
+// newCache returns the cache factory selected by CACHE.
+func newCache() func() cache.Client {
+	if cacheType == "" {
+		return nil
+	}
+	var local *lru.Cache
+	if cacheType == "memory" || cacheType == "tiered" {
+		maxEntries, err := strconv.Atoi(osenv.VarWithDefault("CACHE_MAX_ENTRIES", "10000"))
+		if err != nil {
+			glog.Exitf("invalid CACHE_MAX_ENTRIES: %v", err)
+		}
+		local, err = lru.New(lru.Options{MaxEntries: maxEntries})
+		if err != nil {
+			glog.Exitf("lru.New() failed: %v", err)
+		}
+	}
+	switch cacheType {
+	case "memory":
+		return func() cache.Client { return local }
+	case "redis":
+		pool := rediz.NewPool(osenv.MustVar("REDIS_ADDR"))
+		return func() cache.Client { return pool.Client() }
+	case "tiered":
+		pool := rediz.NewPool(osenv.MustVar("REDIS_ADDR"))
+		return func() cache.Client { return lru.NewTiered(local, pool.Client(), localCacheTTL) }
+	default:
+		glog.Exitf("Unknown cache type %q", cacheType)
+		return nil
+	}
+}
+
+// newSecretProvider returns the secret provider selected by SECRET_PROVIDER.
+func newSecretProvider(ctx context.Context) secret.SecretProvider {
+	switch secretProvider {
+	case "":
+		return nil
+	case "env":
+		return secret.NewEnvProvider(osenv.VarWithDefault("SECRET_ENV_PREFIX", "SECRET_"))
+	case "file":
+		return secret.NewFileProvider(osenv.MustVar("SECRET_DIR"))
+	case "vault":
+		kv := vault.NewKV(newVaultClient(), osenv.VarWithDefault("VAULT_KV_MOUNT", "secret"))
+		return secret.NewCachedProvider(kv, secretCacheTTL)
+	case "secretmanager":
+		client, err := secretmanager.NewClient(ctx)
+		if err != nil {
+			glog.Exitf("secretmanager.NewClient() failed: %v", err)
+		}
+		sm := gcpsecret.New(client, osenv.VarWithDefault("SECRET_PROJECT", project))
+		return secret.NewCachedProvider(sm, secretCacheTTL)
+	default:
+		glog.Exitf("Unknown secret provider %q", secretProvider)
+		return nil
+	}
+}
+
+// kmsClient is the client of GCP Cloud KMS, see gcpKMSClient.
+var kmsClient *cloudkms.KeyManagementClient
+
+// gcpKMSClient returns the client of GCP Cloud KMS. It is created on first
+// use, so that deployments with local or Vault keys need no GCP credentials.
+func gcpKMSClient(ctx context.Context) *cloudkms.KeyManagementClient {
+	if kmsClient == nil {
+		client, err := cloudkms.NewKeyManagementClient(ctx)
+		if err != nil {
+			glog.Exitf("kms.NewKeyManagementClient(ctx) failed: %v", err)
+		}
+		kmsClient = client
+	}
+	return kmsClient
+}
+
+// newVaultClient returns a client of HashiCorp Vault configured with the
+// standard VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE env vars.
+func newVaultClient() *vault.Client {
+	client, err := vault.New(&vault.Config{
+		Address:   osenv.MustVar("VAULT_ADDR"),
+		Token:     osenv.MustVar("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	})
+	if err != nil {
+		glog.Exitf("vault.New() failed: %v", err)
+	}
+	return client
+}
+
 func envPrefix(name string) string {
 	if strings.Contains(name, "-") {
 		return "-" + strings.SplitN(name, "-", 2)[1]
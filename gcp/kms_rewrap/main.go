@@ -0,0 +1,77 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary kms_rewrap re-encrypts the items a DAM or IC encrypts at rest with
+// the primary key of a local keyring, to retire older keys after a rotation.
+//
+// The store is given as <type>:<param>, see migrate.OpenStore.
+//
+// Example:
+//   kms_rewrap -store=postgres:"host=db user=dam" -keyring_file=keyring.json dam
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/encryptedstore" /* copybara-comment: encryptedstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localcrypt" /* copybara-comment: localcrypt */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/migrate" /* copybara-comment: migrate */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+func main() {
+	spec := flag.String("store", "", "the store as <type>:<param>")
+	service := flag.String("service", "", "the name of the service in the store, defaults to the service type")
+	realm := flag.String("realm", storage.AllRealms, "only rewrap the given realm, all realms by default")
+	keyringFile := flag.String("keyring_file", "", "the JSON keyring with the old keys and the new primary key")
+
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 1 || *spec == "" || *keyringFile == "" {
+		glog.Exitf("Usage: kms_rewrap -store=<type>:<param> -keyring_file=<file> [-realm=...] <dam|ic>")
+	}
+	serviceType := args[0]
+	datatypes, err := migrate.Datatypes(serviceType)
+	if err != nil {
+		glog.Exitf("%v", err)
+	}
+	if *service == "" {
+		*service = serviceType
+	}
+
+	enc, err := localcrypt.NewFromFile(*keyringFile)
+	if err != nil {
+		glog.Exitf("localcrypt.NewFromFile(%q) failed: %v", *keyringFile, err)
+	}
+
+	ctx := context.Background()
+	raw, err := migrate.OpenStore(ctx, *spec, *service)
+	if err != nil {
+		glog.Exitf("%v", err)
+	}
+	store := encryptedstore.New(ctx, raw, enc, encryptedstore.DefaultPolicy)
+	for _, dt := range datatypes {
+		if _, ok := encryptedstore.DefaultPolicy[dt.Name]; !ok {
+			continue
+		}
+		n, err := store.Rewrap(dt.Name, *realm, dt.Type)
+		if err != nil {
+			glog.Exitf("rewrapping %q failed after %d items: %v", dt.Name, n, err)
+		}
+		glog.Infof("Rewrapped %d items of %q with key version %d", n, dt.Name, enc.PrimaryVersion())
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary realm_backup exports a realm of a DAM or IC to a zip archive or restores it.
+//
+// The store is given as <type>:<param>, see migrate.OpenStore.
+//
+// An archive is read completely before the realm is modified. Unlike the backup
+// endpoint of the services, the import does not check the integrity of the
+// config of the archive. If an import fails, e.g. when the store becomes
+// unavailable, the realm may be partially restored: run the import again.
+//
+// Example:
+//   realm_backup -store=datastore:my-project -realm=master dam export master.zip
+//   realm_backup -store=datastore:my-project -realm=master -replace dam import master.zip
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"flag"
+	"os"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/backup" /* copybara-comment: backup */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/migrate" /* copybara-comment: migrate */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+func main() {
+	spec := flag.String("store", "", "the store as <type>:<param>")
+	service := flag.String("service", "", "the name of the service in the store, defaults to the service type")
+	realm := flag.String("realm", storage.DefaultRealm, "the realm to export or import into")
+	replace := flag.Bool("replace", false, "delete the items of the realm and their history before the import")
+
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 3 || *spec == "" {
+		glog.Exitf("Usage: realm_backup -store=<type>:<param> [-realm=...] [-replace] <dam|ic> <export|import> <file>")
+	}
+	serviceType, op, file := args[0], args[1], args[2]
+	datatypes, err := backup.Datatypes(serviceType)
+	if err != nil {
+		glog.Exitf("%v", err)
+	}
+	if *service == "" {
+		*service = serviceType
+	}
+
+	ctx := context.Background()
+	store, err := migrate.OpenStore(ctx, *spec, *service)
+	if err != nil {
+		glog.Exitf("%v", err)
+	}
+
+	switch op {
+	case "export":
+		f, err := os.Create(file)
+		if err != nil {
+			glog.Exitf("os.Create(%q) failed: %v", file, err)
+		}
+		if err := backup.Export(ctx, store, datatypes, *realm, f); err != nil {
+			f.Close()
+			glog.Exitf("exporting realm %q failed: %v", *realm, err)
+		}
+		if err := f.Close(); err != nil {
+			glog.Exitf("writing %q failed: %v", file, err)
+		}
+		glog.Infof("Exported realm %q to %q", *realm, file)
+
+	case "import":
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			glog.Exitf("zip.OpenReader(%q) failed: %v", file, err)
+		}
+		defer zr.Close()
+		n, err := backup.Import(ctx, store, datatypes, *realm, &zr.Reader, &backup.ImportOptions{Replace: *replace})
+		if err != nil {
+			glog.Exitf("importing realm %q failed after %d items: %v", *realm, n, err)
+		}
+		glog.Infof("Imported %d items from %q to realm %q", n, file, *realm)
+
+	default:
+		glog.Exitf("unknown operation %q: want export or import", op)
+	}
+}
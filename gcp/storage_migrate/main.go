@@ -0,0 +1,104 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary storage_migrate copies the storage of a DAM or IC to another storage backend.
+//
+// Stores are given as <type>:<param>, see migrate.OpenStore. The memory
+// storage is only useful as the source. With -dry_run, the destination is not
+// opened.
+//
+// Example:
+//   storage_migrate -from=memory:deploy/config -to=datastore:my-project -checkpoint=dam.json dam
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/migrate" /* copybara-comment: migrate */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+func main() {
+	from := flag.String("from", "", "the source store as <type>:<param>")
+	to := flag.String("to", "", "the destination store as <type>:<param>")
+	service := flag.String("service", "", "the name of the service in the stores, defaults to the service type")
+	realm := flag.String("realm", storage.AllRealms, "only migrate the given realm, all realms by default")
+	batch := flag.Int("batch_size", migrate.DefaultBatchSize, "the number of items written per transaction")
+	dryRun := flag.Bool("dry_run", false, "read the source and report the counts without writing to the destination")
+	checkpoint := flag.String("checkpoint", "", "file recording the migrated datatypes to resume an interrupted migration")
+	verify := flag.Bool("verify", true, "compare the counts and checksums of the source and the destination after the migration")
+
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 1 || *from == "" || *to == "" {
+		glog.Exitf("Usage: storage_migrate -from=<type>:<param> -to=<type>:<param> [-realm=...] [-batch_size=...] [-dry_run] [-checkpoint=<file>] <dam|ic>")
+	}
+	serviceType := args[0]
+	datatypes, err := migrate.Datatypes(serviceType)
+	if err != nil {
+		glog.Exitf("%v", err)
+	}
+	if *service == "" {
+		*service = serviceType
+	}
+
+	ctx := context.Background()
+	src, err := migrate.OpenStore(ctx, *from, *service)
+	if err != nil {
+		glog.Exitf("%v", err)
+	}
+	// Opening a store may initialize it, eg. create its tables or file.
+	var dst storage.Store
+	if !*dryRun {
+		dst, err = migrate.OpenStore(ctx, *to, *service)
+		if err != nil {
+			glog.Exitf("%v", err)
+		}
+	}
+
+	report, err := migrate.Migrate(ctx, src, dst, &migrate.Options{
+		Datatypes:      datatypes,
+		Realm:          *realm,
+		BatchSize:      *batch,
+		DryRun:         *dryRun,
+		CheckpointFile: *checkpoint,
+	})
+	if err != nil {
+		glog.Exitf("migration failed: %v", err)
+	}
+	for _, dt := range datatypes {
+		if c, ok := report.Copied[dt.Name]; ok {
+			glog.Infof("%s: %d items, %d history entries, checksum %s", dt.Name, c.Items, c.History, c.Checksum)
+		}
+	}
+	glog.Infof("%d items already migrated, %d history entries without revision content", report.Skipped, report.MissingRevisions)
+
+	if *dryRun || !*verify {
+		return
+	}
+	mismatches, err := migrate.Verify(ctx, src, dst, datatypes, *realm)
+	if err != nil {
+		glog.Exitf("verification failed: %v", err)
+	}
+	for _, m := range mismatches {
+		glog.Errorf("MISMATCH %v", m)
+	}
+	if len(mismatches) > 0 {
+		glog.Exitf("verification failed: %d datatypes differ", len(mismatches))
+	}
+	glog.Infof("Migration complete and verified")
+}
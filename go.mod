@@ -20,10 +20,12 @@ require (
   github.com/gomodule/redigo v1.8.2
   github.com/google/go-cmp v0.5.0
   github.com/gorilla/mux v1.7.3
+  github.com/lib/pq v1.8.0
   github.com/hashicorp/golang-lru v0.5.3 // indirect
   github.com/pborman/uuid v1.2.0
   github.com/pquerna/cachecontrol v0.0.0-20180517163645-1555304b9b35 // indirect
   github.com/yuin/gopher-lua v0.0.0-20200816102855-ee81675732da // indirect
+  go.etcd.io/bbolt v1.3.5
   go.mongodb.org/mongo-driver v1.1.3 // indirect
   golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
   golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 // indirect
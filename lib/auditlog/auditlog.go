@@ -52,6 +52,13 @@ const (
 	TypeRequestLog = "request"
 	// TypePolicyLog log type string for policy log
 	TypePolicyLog = "policy_decision"
+
+	// PolicyMessageField is the field of the message in the JSON payload of a
+	// policy log with an explanation.
+	PolicyMessageField = "message"
+	// PolicyExplanationField is the field of the explanation in the JSON payload
+	// of a policy log.
+	PolicyExplanationField = "explanation"
 )
 
 // RequestLog logs the http endpoint accessing.
@@ -139,6 +146,9 @@ type PolicyDecisionLog struct {
 	ConfigRevision int64
 	// Message of deny.
 	Message interface{}
+	// Explanation of deny, the trace of the policy evaluation. It must encode
+	// to a JSON object, it is written to the payload along with the Message.
+	Explanation interface{}
 }
 
 // WritePolicyDecisionLog puts the policy decision log to StackDriver.
@@ -158,10 +168,17 @@ func WritePolicyDecisionLog(client *logging.Client, log *PolicyDecisionLog) {
 		"cart_id":         log.CartID,
 		"config_revision": strconv.FormatInt(log.ConfigRevision, 10),
 	}
+	payload := log.Message
+	if log.Explanation != nil {
+		payload = map[string]interface{}{
+			PolicyMessageField:     log.Message,
+			PolicyExplanationField: log.Explanation,
+		}
+	}
 
 	entry := logging.Entry{
 		Labels:  labels,
-		Payload: log.Message,
+		Payload: payload,
 	}
 
 	writeLog(client, entry)
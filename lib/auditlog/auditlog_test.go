@@ -16,6 +16,7 @@ package auditlog
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -177,3 +178,30 @@ func TestDisable_flag(t *testing.T) {
 		t.Errorf("logs should not push to server")
 	}
 }
+
+func TestWritePolicyDecisionLog_Explanation(t *testing.T) {
+	server, close := fakesdl.New()
+	defer close()
+
+	pl := &PolicyDecisionLog{
+		TokenID:       "tid",
+		PassAuthCheck: false,
+		Message:       "denied",
+		Explanation:   json.RawMessage(`{"kind":"passport","result":false}`),
+	}
+
+	WritePolicyDecisionLog(server.Client, pl)
+	server.Client.Close()
+
+	got := server.Server.Logs[0].Entries[0]
+	if _, ok := got.Labels[PolicyExplanationField]; ok {
+		t.Errorf("Labels[%s] is set, want the explanation in the payload only", PolicyExplanationField)
+	}
+	fields := got.GetJsonPayload().GetFields()
+	if msg := fields[PolicyMessageField].GetStringValue(); msg != "denied" {
+		t.Errorf("payload %s = %q, want %q", PolicyMessageField, msg, "denied")
+	}
+	if kind := fields[PolicyExplanationField].GetStructValue().GetFields()["kind"].GetStringValue(); kind != "passport" {
+		t.Errorf("payload %s kind = %q, want %q", PolicyExplanationField, kind, "passport")
+	}
+}
@@ -122,7 +122,7 @@ func ToPolicyLog(e *lepb.LogEntry) (*apb.AuditLog, error) {
 
 		Decision:  decision,
 		ErrorType: labels["error_type"],
-		Reason:    extractPayload(e),
+		Reason:    extractPolicyReason(e),
 
 		Time: e.GetTimestamp(),
 
@@ -140,6 +140,15 @@ func logID(e *lepb.LogEntry) string {
 	return "users/" + user + "/auditlogs/" + e.InsertId
 }
 
+// extractPolicyReason returns the message of a policy log, the payload of
+// which also has the explanation of the decision if any.
+func extractPolicyReason(e *lepb.LogEntry) string {
+	if msg, ok := e.GetJsonPayload().GetFields()[auditlog.PolicyMessageField]; ok {
+		return msg.GetStringValue()
+	}
+	return extractPayload(e)
+}
+
 func extractPayload(e *lepb.LogEntry) string {
 	switch e.GetPayload().(type) {
 	case *lepb.LogEntry_TextPayload:
@@ -131,6 +131,8 @@ type Checker struct {
 	// use cache to cache auth result for opaque token, eg. token verifies via userinfo
 	// Need to set useUserinfoVerifyToken true to enable cache.
 	cache func() cache.Client
+	// verifierOptions are passed to the access token verifier.
+	verifierOptions []verifier.Option
 }
 
 func (s *Checker) getVerifier(ctx context.Context) (verifier.AccessTokenVerifier, error) {
@@ -153,7 +155,7 @@ func (s *Checker) getVerifier(ctx context.Context) (verifier.AccessTokenVerifier
 	}
 
 	var err error
-	s.verifier, err = verifier.NewAccessTokenVerifier(ctx, s.issuer, s.useUserinfoVerifyToken)
+	s.verifier, err = verifier.NewAccessTokenVerifier(ctx, s.issuer, s.useUserinfoVerifyToken, s.verifierOptions...)
 	return s.verifier, err
 }
 
@@ -164,7 +166,8 @@ func (s *Checker) getVerifier(ctx context.Context) (verifier.AccessTokenVerifier
 // permissions: contains method to check if user admin permission.
 // fetchClientSecrets: fetches client id and client secret.
 // transformIdentity: transform as needed, will run just after token convert to identity.
-func NewChecker(logger *logging.Client, issuer string, permissions *permissions.Permissions, fetchClientSecrets func() (map[string]string, error), transformIdentity func(*ga4gh.Identity) *ga4gh.Identity, useUserinfoVerifyToken bool, cache func() cache.Client) *Checker {
+// opts: options of the access token verifier, eg. verifier.JWKSCacheOption.
+func NewChecker(logger *logging.Client, issuer string, permissions *permissions.Permissions, fetchClientSecrets func() (map[string]string, error), transformIdentity func(*ga4gh.Identity) *ga4gh.Identity, useUserinfoVerifyToken bool, cache func() cache.Client, opts ...verifier.Option) *Checker {
 	return &Checker{
 		logger:                 logger,
 		issuer:                 issuer,
@@ -173,6 +176,7 @@ func NewChecker(logger *logging.Client, issuer string, permissions *permissions.
 		transformIdentity:      transformIdentity,
 		useUserinfoVerifyToken: useUserinfoVerifyToken,
 		cache:                  cache,
+		verifierOptions:        opts,
 	}
 }
 
@@ -411,6 +415,7 @@ func (s *Checker) verifiedBearerToken(r *http.Request, authHeader, clientID stri
 	var cache cache.Client
 	if s.cache != nil {
 		cache = s.cache()
+		defer cache.Close()
 	}
 	id, err := verifyToken(r.Context(), v, cache, tok, s.issuer, clientID, s.useUserinfoVerifyToken, allowIssuerInAudAndAzp, allowAzp)
 	if err != nil {
@@ -0,0 +1,866 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package boltstore is an embedded on-disk storage for single-node DAM/IC
+// deployments based on bbolt.
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	glog "github.com/golang/glog" /* copybara-comment */
+	bolt "go.etcd.io/bbolt" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/golang/protobuf/jsonpb" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+)
+
+// storageVersion is the version of store data model.
+// If there is a breaking change to the store data model, the version needs to
+// be updated.
+
+const (
+	storageType    = "embedded"
+	storageVersion = "v0"
+	metaVersion    = "version"
+
+	// sep separates the parts of a key. It cannot appear in any key part.
+	sep = "\x00"
+
+	// openTimeout is how long to wait for the file lock of the database.
+	openTimeout = 10 * time.Second
+	// initialMmapSize is large enough to keep read transactions from blocking
+	// the write transaction when the database file grows.
+	initialMmapSize = 256 << 20
+
+	maxRowsPerBatchOperation = 50000 // never exceed this number of rows without a LRO
+)
+
+var (
+	// writeTimeout is how long to wait for another update transaction to finish.
+	writeTimeout = 10 * time.Second
+)
+
+// Data
+
+var (
+	entityBucket  = []byte("entity")
+	historyBucket = []byte("history")
+	metaBucket    = []byte("meta")
+)
+
+// Store is a bbolt based implementation of storage.
+// Each service has its own top level bucket containing the entity, history
+// and meta buckets, so several services can share the same file.
+type Store struct {
+	db *bolt.DB
+
+	// service is the name of the service, e.g. "dam" or "ic", and of its top
+	// level bucket.
+	service string
+	// path is the path to the config files, only reported by Info.
+	path string
+
+	// writer holds a token while an update transaction is open. Unlike the
+	// lock of bbolt, waiting for it times out.
+	writer chan struct{}
+
+	// hub delivers committed changes to watchers. The database file is locked
+	// by a single process, so there are no changes made elsewhere.
+	hub *storage.ChangeHub
+}
+
+// Entity is the record stored for data and history items.
+type Entity struct {
+	Datatype string `json:"type"`
+	Realm    string `json:"realm"`
+	User     string `json:"user_id"`
+	ID       string `json:"id"`
+	Rev      int64  `json:"rev"`
+	Version  string `json:"version"`
+	Modified int64  `json:"modified"`
+	Content  string `json:"content"`
+}
+
+// NewStore opens the database file and initilizes the store.
+func NewStore(file, service, path string) *Store {
+	db, err := bolt.Open(file, 0600, &bolt.Options{Timeout: openTimeout, InitialMmapSize: initialMmapSize})
+	if err != nil {
+		glog.Fatalf("cannot open embedded storage file %q: %v", file, err)
+	}
+	s := New(db, service, path)
+	if err := s.Init(context.Background()); err != nil {
+		glog.Fatalf("Embedded storage failed to initialize: %v", err)
+	}
+	return s
+}
+
+// New creates a new storage.
+func New(db *bolt.DB, service, path string) *Store {
+	return &Store{
+		db:      db,
+		service: service,
+		path:    path,
+		writer:  make(chan struct{}, 1),
+		hub:     storage.NewChangeHub(),
+	}
+}
+
+// Info returns some information about the store.
+// TODO: delete this and pass the information directly rather than through store.
+func (s *Store) Info() map[string]string {
+	return map[string]string{
+		"type":    storageType,
+		"version": storageVersion,
+		"service": s.service,
+		"path":    s.path,
+	}
+}
+
+// Close closes the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Exists checks if a data entity with the given name exists.
+func (s *Store) Exists(datatype, realm, user, id string, rev int64) (bool, error) {
+	exists := false
+	err := s.db.View(func(btx *bolt.Tx) error {
+		exists = s.bucket(btx, entityBucket).Get(entityKey(datatype, realm, user, id, rev)) != nil
+		return nil
+	})
+	return exists, err
+}
+
+// Read reads a data entity.
+func (s *Store) Read(datatype, realm, user, id string, rev int64, content proto.Message) error {
+	return s.ReadTx(datatype, realm, user, id, rev, content, nil)
+}
+
+// ReadTx reads a data entity inside a transaction.
+func (s *Store) ReadTx(datatype, realm, user, id string, rev int64, content proto.Message, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(false)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return err
+	}
+
+	k := entityKey(datatype, realm, user, id, rev)
+	v := s.bucket(btx.Tx, entityBucket).Get(k)
+	if v == nil {
+		return status.Errorf(codes.NotFound, "not found: %q", s.name(k))
+	}
+	e := &Entity{}
+	if err := json.Unmarshal(v, e); err != nil {
+		return err
+	}
+	return jsonpb.Unmarshal(strings.NewReader(e.Content), content)
+}
+
+// MultiReadTx reads a set of data entities matching the filters.
+// If realm is "" reads all realms.
+// if user is "" reads all users.
+// Returns a results object and error.
+func (s *Store) MultiReadTx(datatype, realm, user, id string, filters [][]storage.Filter, offset, pageSize int, typ proto.Message, tx storage.Tx) (_ *storage.Results, ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(false)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pageSize > storage.MaxPageSize {
+		pageSize = storage.MaxPageSize
+	}
+
+	results := storage.NewResults()
+	err = s.scanUsers(btx.Tx, entityBucket, datatype, realm, user, func(_ []byte, e *Entity) error {
+		if e.Rev != storage.LatestRev || (id != storage.MatchAllIDs && e.ID != id) {
+			return nil
+		}
+		if len(e.Content) == 0 {
+			return nil
+		}
+		p := proto.Clone(typ)
+		if err := jsonpb.Unmarshal(strings.NewReader(e.Content), p); err != nil {
+			return err
+		}
+		if !storage.MatchProtoFilters(filters, p) {
+			return nil
+		}
+		// For pagination, decrease any remaining offset before accepting this entry.
+		if offset > 0 {
+			offset--
+			return nil
+		}
+		if pageSize > results.MatchCount {
+			results.Entries = append(results.Entries, &storage.Entry{
+				Realm:   e.Realm,
+				GroupID: e.User,
+				ItemID:  e.ID,
+				Item:    p,
+			})
+		}
+		results.MatchCount++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ReadHistory reads the history.
+func (s *Store) ReadHistory(datatype, realm, user, id string, content *[]proto.Message) error {
+	return s.ReadHistoryTx(datatype, realm, user, id, content, nil)
+}
+
+// ReadHistoryTx reads the history inside a transaction.
+func (s *Store) ReadHistoryTx(datatype, realm, user, id string, content *[]proto.Message, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(false)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return err
+	}
+
+	var list []*Entity
+	err = s.scan(btx.Tx, historyBucket, keyPrefix(datatype, realm, user, id), func(_ []byte, e *Entity) error {
+		if len(e.Content) > 0 {
+			list = append(list, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Rev < list[j].Rev })
+	if len(list) > storage.MaxPageSize {
+		// TODO: handle pagination.
+		list = list[:storage.MaxPageSize]
+	}
+
+	for _, e := range list {
+		he := &cpb.HistoryEntry{}
+		if err := jsonpb.Unmarshal(strings.NewReader(e.Content), he); err != nil {
+			return err
+		}
+		*content = append(*content, he)
+	}
+	return nil
+}
+
+// Write writes a data entity.
+func (s *Store) Write(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message) error {
+	return s.WriteTx(datatype, realm, user, id, rev, content, history, nil)
+}
+
+// WriteTx writes a data entity inside a transaction.
+func (s *Store) WriteTx(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return err
+	}
+
+	if rev != storage.LatestRev {
+		if err := s.put(btx.Tx, entityBucket, datatype, realm, user, id, rev, content); err != nil {
+			btx.Rollback()
+			return err
+		}
+	}
+
+	if history != nil {
+		if err := s.put(btx.Tx, historyBucket, datatype, realm, user, id, rev, history); err != nil {
+			btx.Rollback()
+			return err
+		}
+	}
+
+	if err := s.put(btx.Tx, entityBucket, datatype, realm, user, id, storage.LatestRev, content); err != nil {
+		btx.Rollback()
+		return err
+	}
+	btx.changes = append(btx.changes, &storage.Change{Datatype: datatype, Realm: realm, User: user, ID: id, Rev: rev, Op: storage.ChangeWrite})
+	return nil
+}
+
+// WriteIfMatchTx writes a data entity inside a transaction if the etag of the
+// entity matches. Update transactions are exclusive, so the check is atomic.
+func (s *Store) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	if err := storage.CheckETagTx(s, datatype, realm, user, id, etag, content, tx); err != nil {
+		return err
+	}
+	return s.WriteTx(datatype, realm, user, id, rev, content, history, tx)
+}
+
+// Delete deletes a data entity.
+func (s *Store) Delete(datatype, realm, user, id string, rev int64) error {
+	return s.DeleteTx(datatype, realm, user, id, rev, nil)
+}
+
+// DeleteTx deletes a data entity inside a transaction.
+func (s *Store) DeleteTx(datatype, realm, user, id string, rev int64, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return err
+	}
+
+	b := s.bucket(btx.Tx, entityBucket)
+	k := entityKey(datatype, realm, user, id, rev)
+	if b.Get(k) == nil {
+		return status.Errorf(codes.NotFound, "not found: %q", s.name(k))
+	}
+	if err := b.Delete(k); err != nil {
+		btx.Rollback()
+		return err
+	}
+	btx.changes = append(btx.changes, &storage.Change{Datatype: datatype, Realm: realm, User: user, ID: id, Rev: rev, Op: storage.ChangeDelete})
+	return nil
+}
+
+// MultiDeleteTx deletes many records of a certain data type within a realm.
+// If user is "", deletes for all users.
+func (s *Store) MultiDeleteTx(datatype, realm, user string, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	var changes []*storage.Change
+	err = s.scanUsers(btx.Tx, entityBucket, datatype, realm, user, func(k []byte, e *Entity) error {
+		if e.Rev == storage.LatestRev {
+			keys = append(keys, append([]byte{}, k...))
+			changes = append(changes, &storage.Change{Datatype: e.Datatype, Realm: e.Realm, User: e.User, ID: e.ID, Rev: e.Rev, Op: storage.ChangeDelete})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	b := s.bucket(btx.Tx, entityBucket)
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			btx.Rollback()
+			return err
+		}
+	}
+	btx.changes = append(btx.changes, changes...)
+	return nil
+}
+
+// MultiDeleteHistoryTx deletes the history and the revisions other than the
+// latest of the records of a certain data type within a realm.
+// If user is "", deletes for all users.
+func (s *Store) MultiDeleteHistoryTx(datatype, realm, user string, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	btx, err := asTx(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range [][]byte{historyBucket, entityBucket} {
+		history := bytes.Equal(bucket, historyBucket)
+		var keys [][]byte
+		err := s.scanUsers(btx.Tx, bucket, datatype, realm, user, func(k []byte, e *Entity) error {
+			if history || e.Rev != storage.LatestRev {
+				keys = append(keys, append([]byte{}, k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		b := s.bucket(btx.Tx, bucket)
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				btx.Rollback()
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Wipe deletes all data and history within a realm but no more than maxEntries.
+// If realm is "" deletes for all realms; use all realms mode with caution as it will remove the master realm's config too.
+// Returns count of deleted items and error.
+func (s *Store) Wipe(ctx context.Context, realm string, batchNum, maxEntries int) (int, error) {
+	if batchNum == 0 {
+		glog.Infof("Embedded storage wipe service %q realm %q: started", s.service, realm)
+	}
+	results := make(map[string]int)
+	if maxEntries <= 0 {
+		maxEntries = maxRowsPerBatchOperation
+	}
+	deleted := 0
+	err := s.update(func(btx *bolt.Tx) error {
+		for _, name := range [][]byte{historyBucket, entityBucket} {
+			b := s.bucket(btx, name)
+			var keys [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if deleted+len(keys) >= maxEntries {
+					return nil
+				}
+				if realm != storage.AllRealms {
+					e := &Entity{}
+					if err := json.Unmarshal(v, e); err != nil {
+						return err
+					}
+					if e.Realm != realm {
+						return nil
+					}
+				}
+				keys = append(keys, append([]byte{}, k...))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			results[string(name)] = len(keys)
+			deleted += len(keys)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	glog.Infof("Embedded storage wipe service %q realm %q: completed results: %#v", s.service, realm, results)
+	return deleted, nil
+}
+
+// Tx creates a new transaction for the store.
+// bbolt allows many concurrent read-only transactions but only one update
+// transaction at a time: Tx(true) waits for any other update transaction to
+// finish, and returns an Unavailable error if it is still open after
+// writeTimeout, e.g. when the caller holds it.
+func (s *Store) Tx(update bool) (storage.Tx, error) {
+	btx, err := begin(s.db, s.writer, update)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{
+		update: update,
+		db:     s.db,
+		writer: s.writer,
+		hub:    s.hub,
+		Tx:     btx,
+	}, nil
+}
+
+// update runs fn in an update transaction like bolt.DB.Update.
+func (s *Store) update(fn func(btx *bolt.Tx) error) error {
+	btx, err := begin(s.db, s.writer, true)
+	if err != nil {
+		return err
+	}
+	defer func() { <-s.writer }()
+	if err := fn(btx); err != nil {
+		btx.Rollback()
+		return err
+	}
+	return btx.Commit()
+}
+
+// begin starts a bbolt transaction. For an update transaction, it first takes
+// the writer token, which is returned when the transaction is closed.
+func begin(db *bolt.DB, writer chan struct{}, update bool) (*bolt.Tx, error) {
+	if update {
+		select {
+		case writer <- struct{}{}:
+		case <-time.After(writeTimeout):
+			return nil, status.Errorf(codes.Unavailable, "embedded storage: another update transaction is still open after %v", writeTimeout)
+		}
+	}
+	btx, err := db.Begin(update)
+	if err != nil && update {
+		<-writer
+	}
+	return btx, err
+}
+
+// Watch returns a channel receiving the changes committed after the call.
+func (s *Store) Watch(ctx context.Context, datatype, realm string) (<-chan *storage.Change, error) {
+	return s.hub.Watch(ctx, datatype, realm), nil
+}
+
+// LockTx returns a storage-wide lock by the given name. Only one such lock should
+// be requested at a time. If Tx is provided, it must be an update Tx.
+// As update transactions are exclusive, holding the returned transaction holds
+// the lock. Returns nil if the lock was taken less than minFrequency ago.
+func (s *Store) LockTx(lockName string, minFrequency time.Duration, tx storage.Tx) storage.Tx {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return nil
+		}
+		// Do not defer tx.Finish() as it must be not be freed unless the lock attempt fails.
+	} else if !tx.IsUpdate() {
+		return nil
+	}
+	entry := cpb.HistoryEntry{}
+	if err := s.ReadTx(storage.LockDatatype, storage.DefaultRealm, storage.DefaultUser, lockName, storage.LatestRev, &entry, tx); err != nil && !storage.ErrNotFound(err) {
+		tx.Finish()
+		return nil
+	}
+	if diff := time.Now().Sub(time.Unix(int64(entry.CommitTime), 0)); diff < minFrequency {
+		tx.Finish()
+		return nil
+	}
+
+	entry.CommitTime = float64(time.Now().Unix())
+	if err := s.WriteTx(storage.LockDatatype, storage.DefaultRealm, storage.DefaultUser, lockName, storage.LatestRev, &entry, nil, tx); err != nil {
+		tx.Finish()
+		return nil
+	}
+	return tx
+}
+
+// Init initilizes the store.
+// It creates the buckets of the service and some metadata information about
+// the store. If metada information already exists, it comapres to see if they
+// are compatible with the metadata information of the current store.
+func (s *Store) Init(ctx context.Context) error {
+	var version []byte
+	err := s.update(func(btx *bolt.Tx) error {
+		root, err := btx.CreateBucketIfNotExists([]byte(s.service))
+		if err != nil {
+			return err
+		}
+		for _, name := range [][]byte{entityBucket, historyBucket, metaBucket} {
+			if _, err := root.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		meta := root.Bucket(metaBucket)
+		version = meta.Get([]byte(metaVersion))
+		if version == nil {
+			version = []byte(storageVersion)
+			return meta.Put([]byte(metaVersion), version)
+		}
+		version = append([]byte{}, version...)
+		return nil
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot initialize embedded storage: %v", err)
+	}
+	glog.Infof("Embedded storage service %q version: %s", s.service, version)
+	if string(version) != storageVersion {
+		return status.Errorf(codes.FailedPrecondition, "embedded storage version not compatible: expected %q, got %q", storageVersion, version)
+	}
+	return nil
+}
+
+// Data
+
+// bucket returns the named bucket of the service. Buckets are created by Init.
+func (s *Store) bucket(btx *bolt.Tx, name []byte) *bolt.Bucket {
+	return btx.Bucket([]byte(s.service)).Bucket(name)
+}
+
+// put writes an item into the given bucket.
+func (s *Store) put(btx *bolt.Tx, bucket []byte, datatype, realm, user, id string, rev int64, content proto.Message) error {
+	js, err := (&jsonpb.Marshaler{}).MarshalToString(content)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(&Entity{
+		Datatype: datatype,
+		Realm:    realm,
+		User:     user,
+		ID:       id,
+		Rev:      keyRev(rev),
+		Version:  storageVersion,
+		Modified: time.Now().Unix(),
+		Content:  js,
+	})
+	if err != nil {
+		return err
+	}
+	return s.bucket(btx, bucket).Put(entityKey(datatype, realm, user, id, rev), v)
+}
+
+// scan visits the items of a bucket with keys starting with prefix in key
+// order, i.e. ordered by datatype, realm, user, id and rev.
+func (s *Store) scan(btx *bolt.Tx, bucket, prefix []byte, fn func(k []byte, e *Entity) error) error {
+	c := s.bucket(btx, bucket).Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		e := &Entity{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return fmt.Errorf("invalid item %q: %v", s.name(k), err)
+		}
+		if err := fn(k, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanUsers visits the items of a bucket for a datatype. Empty realm and user
+// match all.
+func (s *Store) scanUsers(btx *bolt.Tx, bucket []byte, datatype, realm, user string, fn func(k []byte, e *Entity) error) error {
+	parts := []string{datatype}
+	if realm != storage.AllRealms {
+		parts = append(parts, realm)
+		if user != storage.MatchAllUsers {
+			parts = append(parts, user)
+		}
+	}
+	return s.scan(btx, bucket, keyPrefix(parts...), func(k []byte, e *Entity) error {
+		if user != storage.MatchAllUsers && e.User != user {
+			return nil
+		}
+		return fn(k, e)
+	})
+}
+
+// name returns a readable name of a key for error messages.
+func (s *Store) name(k []byte) string {
+	return s.service + "/" + strings.Replace(string(k), sep, "/", -1)
+}
+
+// entityKey returns the key of an item: datatype, realm, user, id and rev.
+// Revisions are zero-padded so that they sort in order.
+func entityKey(datatype, realm, user, id string, rev int64) []byte {
+	r := storage.LatestRevName
+	if rev > 0 {
+		r = fmt.Sprintf("%020d", rev)
+	}
+	return []byte(strings.Join([]string{datatype, realm, user, id, r}, sep))
+}
+
+// keyPrefix returns the prefix of the keys of items matching the given key parts.
+func keyPrefix(parts ...string) []byte {
+	return []byte(strings.Join(parts, sep) + sep)
+}
+
+// keyRev maps revisions onto the stored rev: as with the keys, any
+// non-positive revision refers to the latest revision.
+func keyRev(rev int64) int64 {
+	if rev > 0 {
+		return rev
+	}
+	return storage.LatestRev
+}
+
+// Transaction
+
+// asTx returns the bbolt transaction of tx, failing if it is not one or has
+// already been finished or rolled back.
+func asTx(tx storage.Tx) (*Tx, error) {
+	btx, ok := tx.(*Tx)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid transaction")
+	}
+	if btx.Tx == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "transaction already finished")
+	}
+	return btx, nil
+}
+
+// Tx is a transaction.
+type Tx struct {
+	update  bool
+	db      *bolt.DB
+	writer  chan struct{}
+	hub     *storage.ChangeHub
+	changes []*storage.Change
+	Tx      *bolt.Tx
+}
+
+// IsUpdate tells if the transaction is an update or read-only.
+func (tx *Tx) IsUpdate() bool {
+	return tx.update
+}
+
+// Finish attempts to commit a transaction.
+func (tx *Tx) Finish() error {
+	if tx.Tx == nil {
+		return nil
+	}
+	btx := tx.Tx
+	tx.Tx = nil
+	if !btx.Writable() {
+		// Read-only transactions must be closed with a rollback.
+		return btx.Rollback()
+	}
+	defer func() { <-tx.writer }()
+	if err := btx.Commit(); err != nil {
+		glog.Infof("embedded storage error committing transaction: %v", err)
+		return err
+	}
+	tx.hub.Publish(tx.changes...)
+	tx.changes = nil
+	return nil
+}
+
+// Rollback attempts to rollback a transaction.
+func (tx *Tx) Rollback() error {
+	if tx.Tx == nil {
+		return nil
+	}
+	if tx.Tx.Writable() {
+		defer func() { <-tx.writer }()
+	}
+	err := tx.Tx.Rollback()
+	// Transaction cannot be used after a rollback.
+	tx.Tx = nil
+	tx.changes = nil
+	if err != nil {
+		glog.Infof("embedded storage error during rollback of transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// MakeUpdate will upgrade a read-only transaction to an update transaction.
+func (tx *Tx) MakeUpdate() error {
+	if tx.IsUpdate() {
+		return nil
+	}
+	if err := tx.Finish(); err != nil {
+		return err
+	}
+	btx, err := begin(tx.db, tx.writer, true)
+	if err != nil {
+		return err
+	}
+	tx.update = true
+	tx.Tx = btx
+	return nil
+}
+
+var _ storage.Store = &Store{}
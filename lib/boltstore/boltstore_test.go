@@ -0,0 +1,234 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"google.golang.org/protobuf/testing/protocmp" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/storagetest" /* copybara-comment: storagetest */
+
+	dpb "github.com/golang/protobuf/ptypes/duration" /* copybara-comment */
+)
+
+func newTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "boltstore")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	file := filepath.Join(dir, "test.db")
+	s := NewStore(file, "fake-service", "fake-config-path")
+	t.Cleanup(func() { s.Close() })
+	return s, file
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Store {
+		s, _ := newTestStore(t)
+		return s
+	}, nil)
+}
+
+func TestStore_Persistent(t *testing.T) {
+	s, file := newTestStore(t)
+
+	want := &dpb.Duration{Seconds: 60}
+	if err := s.Write("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, want, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("store.Close() failed: %v", err)
+	}
+
+	s = NewStore(file, "fake-service", "fake-config-path")
+	defer s.Close()
+	got := &dpb.Duration{}
+	if err := s.Read("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) after reopen failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.Read(...) after reopen diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestStore_Tx_Isolation(t *testing.T) {
+	s, _ := newTestStore(t)
+
+	if err := s.Write("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+
+	rtx, err := s.Tx(false)
+	if err != nil {
+		t.Fatalf("store.Tx(false) failed: %v", err)
+	}
+	defer rtx.Finish()
+
+	wtx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("store.Tx(true) failed: %v", err)
+	}
+	if err := s.WriteTx("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 2}, nil, wtx); err != nil {
+		t.Fatalf("store.WriteTx(...) failed: %v", err)
+	}
+	if err := wtx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+
+	// The read transaction started before the commit keeps its snapshot.
+	got := &dpb.Duration{}
+	if err := s.ReadTx("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, got, rtx); err != nil {
+		t.Fatalf("store.ReadTx(...) failed: %v", err)
+	}
+	if got.Seconds != 1 {
+		t.Errorf("store.ReadTx(...) in older transaction = %v, want 1 second", got)
+	}
+	if err := s.Read("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) failed: %v", err)
+	}
+	if got.Seconds != 2 {
+		t.Errorf("store.Read(...) = %v, want 2 seconds", got)
+	}
+}
+
+func TestStore_Tx_Rollback(t *testing.T) {
+	s, _ := newTestStore(t)
+
+	tx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("store.Tx(true) failed: %v", err)
+	}
+	if err := s.WriteTx("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) failed: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("tx.Rollback() failed: %v", err)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() after Rollback() failed: %v", err)
+	}
+	if err := s.WriteTx("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err == nil {
+		t.Errorf("store.WriteTx(...) on a finished transaction should fail")
+	}
+
+	exists, err := s.Exists("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev)
+	if err != nil || exists {
+		t.Errorf("store.Exists(...) after rollback = %v, %v, want false", exists, err)
+	}
+}
+
+func TestStore_Tx_MakeUpdate(t *testing.T) {
+	s, _ := newTestStore(t)
+
+	tx, err := s.Tx(false)
+	if err != nil {
+		t.Fatalf("store.Tx(false) failed: %v", err)
+	}
+	if err := s.WriteTx("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err == nil {
+		t.Fatalf("store.WriteTx(...) in a read-only transaction should fail")
+	}
+
+	tx, err = s.Tx(false)
+	if err != nil {
+		t.Fatalf("store.Tx(false) failed: %v", err)
+	}
+	if err := tx.MakeUpdate(); err != nil {
+		t.Fatalf("tx.MakeUpdate() failed: %v", err)
+	}
+	if err := s.WriteTx("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) after MakeUpdate() failed: %v", err)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+}
+
+func TestStore_Tx_UpdateTimeout(t *testing.T) {
+	defer func(d time.Duration) { writeTimeout = d }(writeTimeout)
+	writeTimeout = 100 * time.Millisecond
+	s, _ := newTestStore(t)
+
+	tx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("store.Tx(true) failed: %v", err)
+	}
+	// A write outside of the open update transaction fails instead of blocking.
+	err = s.Write("fake-datatype", "fake-realm", "fake-user", "fake-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("store.Write(...) while holding an update transaction = %v, want Unavailable error", err)
+	}
+	if _, err := s.Wipe(context.Background(), "fake-realm", 0, 0); status.Code(err) != codes.Unavailable {
+		t.Errorf("store.Wipe(...) while holding an update transaction = %v, want Unavailable error", err)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+
+	// The update transaction is available again once finished or rolled back.
+	for _, finish := range []func(storage.Tx) error{storage.Tx.Finish, storage.Tx.Rollback} {
+		tx, err := s.Tx(true)
+		if err != nil {
+			t.Fatalf("store.Tx(true) after the other one is closed failed: %v", err)
+		}
+		if err := finish(tx); err != nil {
+			t.Fatalf("closing the transaction failed: %v", err)
+		}
+	}
+}
+
+func TestStore_LockTx(t *testing.T) {
+	s, _ := newTestStore(t)
+
+	tx := s.LockTx("fake-lock", 0, nil)
+	if tx == nil {
+		t.Fatalf("store.LockTx(...) failed")
+	}
+
+	// Another locker waits until the lock is released.
+	locked := make(chan storage.Tx)
+	go func() {
+		locked <- s.LockTx("fake-lock", 0, nil)
+	}()
+	select {
+	case <-locked:
+		t.Fatalf("store.LockTx(...) acquired a held lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+	tx = <-locked
+	if tx == nil {
+		t.Fatalf("store.LockTx(...) after release failed")
+	}
+	tx.Finish()
+
+	if tx := s.LockTx("fake-lock", time.Hour, nil); tx != nil {
+		tx.Finish()
+		t.Errorf("store.LockTx(..., 1h, ...) within minFrequency should fail")
+	}
+}
+
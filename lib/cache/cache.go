@@ -15,12 +15,42 @@
 // Package cache includes error and interface for cache.
 package cache
 
+import (
+	"time"
+)
+
 // Client of cache.
 type Client interface {
 	// Get a value associated with given key in cache.
 	Get(key string) ([]byte, error)
 	// SetWithExpiry add a key-value pair with expiry in cache. Expiry in duration second not timestamp.
 	SetWithExpiry(key string, value []byte, seconds int64) error
+	// Delete removes a key from cache, it is not an error if the key is missing.
+	Delete(key string) error
+	// DeleteByPrefix removes all keys with the given prefix from cache and
+	// returns the number of keys removed. Keys of the same owner, such as a
+	// user, share a prefix so they can be invalidated together.
+	DeleteByPrefix(prefix string) (int, error)
+	// IncrWithExpiry atomically increments the integer value of a key and
+	// returns the new value. A missing key starts at 0 and expires after the
+	// given seconds, later increments keep the expiry, so the key counts
+	// within a fixed window.
+	IncrWithExpiry(key string, seconds int64) (int64, error)
+	// MGet gets the values of the keys, the value of a missing key is nil.
+	MGet(keys []string) ([][]byte, error)
+	// MSetWithExpiry adds the key-value pairs with the same expiry in cache.
+	MSetWithExpiry(values map[string][]byte, seconds int64) error
 	// Close returns the client after use.
 	Close() error
 }
+
+// ExpiryGetter is implemented by clients which get the remaining time to live
+// of a value along with it.
+type ExpiryGetter interface {
+	// GetWithExpiry gets a value and its remaining time to live, which is
+	// negative if the value does not expire.
+	GetWithExpiry(key string) ([]byte, time.Duration, error)
+	// MGetWithExpiry gets the values of the keys and their remaining times to
+	// live, the value of a missing key is nil.
+	MGetWithExpiry(keys []string) ([][]byte, []time.Duration, error)
+}
@@ -0,0 +1,444 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lru contains an in-process cache with least recently used eviction,
+// and a tiered cache fronting a shared cache such as redis with it.
+package lru
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache" /* copybara-comment: cache */
+)
+
+// Options of a Cache. At least one of the limits must be set.
+type Options struct {
+	// MaxEntries is the maximum number of entries, 0 for no limit.
+	MaxEntries int
+	// MaxBytes is the maximum total size of keys and values, 0 for no limit.
+	MaxBytes int64
+}
+
+// Stats are the counters of a Cache.
+type Stats struct {
+	// Hits is the number of Get of a live entry.
+	Hits int64
+	// Misses is the number of Get of a missing or expired entry.
+	Misses int64
+	// Evictions is the number of live entries removed to respect the limits.
+	Evictions int64
+	// Expirations is the number of expired entries removed.
+	Expirations int64
+	// Entries is the number of entries, including expired ones not yet removed.
+	Entries int
+	// Bytes is the total size of the keys and values of the entries.
+	Bytes int64
+}
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func (e *entry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// Cache is an in-process cache.Client with a bounded size. Entries expire
+// like SetWithExpiry of redis, and the least recently used entries are evicted
+// when the cache is full. It is safe for concurrent use and Close is a no-op,
+// so the same Cache can be returned to every caller of a cache factory.
+type Cache struct {
+	opts Options
+	now  func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	bytes int64
+
+	hits        int64
+	misses      int64
+	evictions   int64
+	expirations int64
+}
+
+// New creates a Cache.
+func New(opts Options) (*Cache, error) {
+	if opts.MaxEntries <= 0 && opts.MaxBytes <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "lru cache requires MaxEntries or MaxBytes")
+	}
+	return &Cache{
+		opts:  opts,
+		now:   time.Now,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+	}, nil
+}
+
+// Get a value associated with given key in cache. It returns a NotFound
+// status error if the key is missing or expired.
+func (c *Cache) Get(key string) ([]byte, error) {
+	v, ok := c.get(key)
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, status.Errorf(codes.NotFound, "key not found")
+	}
+	atomic.AddInt64(&c.hits, 1)
+	return v, nil
+}
+
+// get returns a copy of the value of a live entry.
+func (c *Cache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		c.expirations++
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return append([]byte(nil), e.value...), true
+}
+
+// SetWithExpiry add a key-value pair with expiry in cache. Expiry in duration
+// second not timestamp.
+func (c *Cache) SetWithExpiry(key string, value []byte, seconds int64) error {
+	if seconds <= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid expire time %d", seconds)
+	}
+	c.set(key, value, time.Duration(seconds)*time.Second)
+	return nil
+}
+
+func (c *Cache) set(key string, value []byte, ttl time.Duration) {
+	e := &entry{key: key, value: append([]byte(nil), value...), expires: c.now().Add(ttl)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	if c.opts.MaxBytes > 0 && e.size() > c.opts.MaxBytes {
+		// Never fits, do not evict everything else for it.
+		c.evictions++
+		return
+	}
+	c.items[key] = c.ll.PushFront(e)
+	c.bytes += e.size()
+
+	for c.full() {
+		c.evictOldest()
+	}
+}
+
+// Delete removes a key from cache.
+func (c *Cache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	return nil
+}
+
+// DeleteByPrefix removes all keys with the given prefix from cache.
+func (c *Cache) DeleteByPrefix(prefix string) (int, error) {
+	if prefix == "" {
+		return 0, status.Errorf(codes.InvalidArgument, "empty prefix")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleted := 0
+	for k, el := range c.items {
+		if strings.HasPrefix(k, prefix) {
+			c.remove(el)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// IncrWithExpiry atomically increments the value of a key, a new or expired
+// key expires after the given seconds. Values are stored in decimal like
+// redis.
+func (c *Cache) IncrWithExpiry(key string, seconds int64) (int64, error) {
+	if seconds <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid expire time %d", seconds)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	n := int64(0)
+	expires := now.Add(time.Duration(seconds) * time.Second)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		if now.Before(e.expires) {
+			v, err := strconv.ParseInt(string(e.value), 10, 64)
+			if err != nil {
+				return 0, status.Errorf(codes.FailedPrecondition, "value is not an integer")
+			}
+			n, expires = v, e.expires
+		} else {
+			c.expirations++
+		}
+		c.remove(el)
+	}
+	n++
+
+	e := &entry{key: key, value: []byte(strconv.FormatInt(n, 10)), expires: expires}
+	c.items[key] = c.ll.PushFront(e)
+	c.bytes += e.size()
+	for c.full() {
+		c.evictOldest()
+	}
+	return n, nil
+}
+
+// MGet gets the values of the keys in cache.
+func (c *Cache) MGet(keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, k := range keys {
+		// Misses are nil values, not errors.
+		values[i], _ = c.Get(k)
+	}
+	return values, nil
+}
+
+// MSetWithExpiry adds the key-value pairs with expiry in cache.
+func (c *Cache) MSetWithExpiry(values map[string][]byte, seconds int64) error {
+	if seconds <= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid expire time %d", seconds)
+	}
+	for k, v := range values {
+		c.set(k, v, time.Duration(seconds)*time.Second)
+	}
+	return nil
+}
+
+// full returns true if the cache is over one of its limits.
+func (c *Cache) full() bool {
+	return (c.opts.MaxEntries > 0 && c.ll.Len() > c.opts.MaxEntries) ||
+		(c.opts.MaxBytes > 0 && c.bytes > c.opts.MaxBytes)
+}
+
+// evictOldest removes the least recently used entry. Expired entries are not
+// counted as evictions.
+func (c *Cache) evictOldest() {
+	el := c.ll.Back()
+	if el == nil {
+		return
+	}
+	if c.now().Before(el.Value.(*entry).expires) {
+		c.evictions++
+	} else {
+		c.expirations++
+	}
+	c.remove(el)
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := c.ll.Remove(el).(*entry)
+	delete(c.items, e.key)
+	c.bytes -= e.size()
+}
+
+// Stats returns the counters of the cache.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Hits:        atomic.LoadInt64(&c.hits),
+		Misses:      atomic.LoadInt64(&c.misses),
+		Evictions:   c.evictions,
+		Expirations: c.expirations,
+		Entries:     c.ll.Len(),
+		Bytes:       c.bytes,
+	}
+}
+
+// Close is a no-op, the cache stays usable.
+func (c *Cache) Close() error {
+	return nil
+}
+
+// Tiered is a cache.Client of a shared remote cache, such as redis, fronted
+// by a local Cache to save round-trips for hot keys. Values are kept locally
+// for at most the local TTL, bounding how long a change of the remote cache by
+// another instance goes unnoticed. Values are not kept past their expiry in the
+// remote cache if it implements cache.ExpiryGetter.
+type Tiered struct {
+	local    *Cache
+	remote   cache.Client
+	localTTL time.Duration
+}
+
+// NewTiered creates a Tiered client. Close closes the remote client only, so
+// typically a cache factory creates a Tiered with the same local Cache and a
+// new remote client per call.
+func NewTiered(local *Cache, remote cache.Client, localTTL time.Duration) *Tiered {
+	return &Tiered{local: local, remote: remote, localTTL: localTTL}
+}
+
+// Get a value from the local cache, or from the remote cache on a local miss.
+func (t *Tiered) Get(key string) ([]byte, error) {
+	if v, err := t.local.Get(key); err == nil {
+		return v, nil
+	}
+	eg, ok := t.remote.(cache.ExpiryGetter)
+	if !ok {
+		v, err := t.remote.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		t.local.set(key, v, t.localTTL)
+		return v, nil
+	}
+	v, ttl, err := eg.GetWithExpiry(key)
+	if err != nil {
+		return nil, err
+	}
+	t.setLocal(key, v, ttl)
+	return v, nil
+}
+
+// setLocal keeps a remote value with the given remaining time to live, which
+// is negative if it does not expire, locally for at most the local TTL.
+func (t *Tiered) setLocal(key string, value []byte, ttl time.Duration) {
+	if ttl < 0 || ttl > t.localTTL {
+		ttl = t.localTTL
+	}
+	if ttl > 0 {
+		t.local.set(key, value, ttl)
+	}
+}
+
+// SetWithExpiry add a key-value pair with expiry to the remote and the local
+// cache.
+func (t *Tiered) SetWithExpiry(key string, value []byte, seconds int64) error {
+	if err := t.remote.SetWithExpiry(key, value, seconds); err != nil {
+		return err
+	}
+	ttl := time.Duration(seconds) * time.Second
+	if ttl > t.localTTL {
+		ttl = t.localTTL
+	}
+	t.local.set(key, value, ttl)
+	return nil
+}
+
+// Delete removes a key from the remote and the local cache.
+func (t *Tiered) Delete(key string) error {
+	if err := t.remote.Delete(key); err != nil {
+		return err
+	}
+	return t.local.Delete(key)
+}
+
+// DeleteByPrefix removes all keys with the given prefix from the remote and
+// the local cache, and returns the number of keys removed from the remote
+// cache.
+func (t *Tiered) DeleteByPrefix(prefix string) (int, error) {
+	n, err := t.remote.DeleteByPrefix(prefix)
+	if err != nil {
+		return n, err
+	}
+	if _, err := t.local.DeleteByPrefix(prefix); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// IncrWithExpiry increments the value of a key in the remote cache, since
+// counters are shared by all instances.
+func (t *Tiered) IncrWithExpiry(key string, seconds int64) (int64, error) {
+	t.local.Delete(key)
+	return t.remote.IncrWithExpiry(key, seconds)
+}
+
+// MGet gets the values of the keys from the local cache, and the local misses
+// from the remote cache.
+func (t *Tiered) MGet(keys []string) ([][]byte, error) {
+	values, _ := t.local.MGet(keys)
+	var missing []string
+	var missingIndex []int
+	for i, v := range values {
+		if v == nil {
+			missing = append(missing, keys[i])
+			missingIndex = append(missingIndex, i)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+	var remote [][]byte
+	var ttls []time.Duration
+	var err error
+	if eg, ok := t.remote.(cache.ExpiryGetter); ok {
+		remote, ttls, err = eg.MGetWithExpiry(missing)
+	} else {
+		remote, err = t.remote.MGet(missing)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for j, v := range remote {
+		if v == nil {
+			continue
+		}
+		values[missingIndex[j]] = v
+		ttl := t.localTTL
+		if ttls != nil {
+			ttl = ttls[j]
+		}
+		t.setLocal(missing[j], v, ttl)
+	}
+	return values, nil
+}
+
+// MSetWithExpiry adds the key-value pairs to the remote and the local cache.
+func (t *Tiered) MSetWithExpiry(values map[string][]byte, seconds int64) error {
+	if err := t.remote.MSetWithExpiry(values, seconds); err != nil {
+		return err
+	}
+	ttl := time.Duration(seconds) * time.Second
+	if ttl > t.localTTL {
+		ttl = t.localTTL
+	}
+	for k, v := range values {
+		t.local.set(k, v, ttl)
+	}
+	return nil
+}
+
+// Close returns the remote client after use.
+func (t *Tiered) Close() error {
+	return t.remote.Close()
+}
@@ -0,0 +1,366 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache/rediz" /* copybara-comment: rediz */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakecache" /* copybara-comment: fakecache */
+)
+
+func newCache(t *testing.T, opts Options) (*Cache, *time.Time) {
+	t.Helper()
+	c, err := New(opts)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestNew_NoLimit(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Errorf("New() without limits wants error")
+	}
+}
+
+func TestCache_Expiry(t *testing.T) {
+	c, now := newCache(t, Options{MaxEntries: 10})
+
+	if err := c.SetWithExpiry("k", []byte("v"), 2); err != nil {
+		t.Fatalf("SetWithExpiry() failed: %v", err)
+	}
+	got, err := c.Get("k")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if string(got) != "v" {
+		t.Errorf("Get() = %s, want v", got)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if _, err := c.Get("k"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of expired key = %v, wants NotFound", err)
+	}
+	if _, err := c.Get("missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of missing key = %v, wants NotFound", err)
+	}
+	if err := c.SetWithExpiry("k", []byte("v"), 0); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetWithExpiry() with 0 seconds = %v, wants InvalidArgument", err)
+	}
+
+	want := Stats{Hits: 1, Misses: 2, Expirations: 1}
+	if d := cmp.Diff(want, c.Stats()); d != "" {
+		t.Errorf("Stats() (-want, +got):\n%s", d)
+	}
+}
+
+func TestCache_Eviction(t *testing.T) {
+	c, _ := newCache(t, Options{MaxEntries: 2})
+
+	c.SetWithExpiry("a", []byte("1"), 60)
+	c.SetWithExpiry("b", []byte("2"), 60)
+	// "a" becomes the most recently used.
+	c.Get("a")
+	c.SetWithExpiry("c", []byte("3"), 60)
+
+	if _, err := c.Get("b"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of least recently used key = %v, wants NotFound", err)
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, err := c.Get(k); err != nil {
+			t.Errorf("Get(%q) failed: %v", k, err)
+		}
+	}
+
+	want := Stats{Hits: 3, Misses: 1, Evictions: 1, Entries: 2, Bytes: 4}
+	if d := cmp.Diff(want, c.Stats()); d != "" {
+		t.Errorf("Stats() (-want, +got):\n%s", d)
+	}
+}
+
+func TestCache_MaxBytes(t *testing.T) {
+	c, _ := newCache(t, Options{MaxBytes: 10})
+
+	c.SetWithExpiry("a", []byte("1234"), 60)
+	c.SetWithExpiry("b", []byte("1234"), 60)
+	if got := c.Stats().Bytes; got != 10 {
+		t.Errorf("Bytes = %d, want 10", got)
+	}
+	// Replacing a key updates the size.
+	c.SetWithExpiry("b", []byte("12"), 60)
+	if got := c.Stats().Bytes; got != 8 {
+		t.Errorf("Bytes = %d, want 8", got)
+	}
+	c.SetWithExpiry("c", []byte("12"), 60)
+	if _, err := c.Get("a"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of evicted key = %v, wants NotFound", err)
+	}
+	// Too large for the cache, not stored.
+	c.SetWithExpiry("d", []byte("12345678901"), 60)
+	if _, err := c.Get("d"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of oversized key = %v, wants NotFound", err)
+	}
+	if _, err := c.Get("c"); err != nil {
+		t.Errorf("Get() after oversized set failed: %v", err)
+	}
+}
+
+func TestCache_CopiesValue(t *testing.T) {
+	c, _ := newCache(t, Options{MaxEntries: 1})
+	v := []byte("v")
+	c.SetWithExpiry("k", v, 60)
+	v[0] = 'x'
+
+	got, _ := c.Get("k")
+	if string(got) != "v" {
+		t.Errorf("Get() = %s, want v", got)
+	}
+	got[0] = 'y'
+	if got, _ := c.Get("k"); string(got) != "v" {
+		t.Errorf("Get() = %s, want v", got)
+	}
+}
+
+func TestCache_Delete(t *testing.T) {
+	c, _ := newCache(t, Options{MaxEntries: 10})
+	for _, k := range []string{"user_a_1", "user_a_2", "user_ab_1"} {
+		c.SetWithExpiry(k, []byte("v"), 60)
+	}
+
+	if err := c.Delete("user_ab_1"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if err := c.Delete("missing"); err != nil {
+		t.Errorf("Delete() of missing key failed: %v", err)
+	}
+	n, err := c.DeleteByPrefix("user_a_")
+	if err != nil {
+		t.Fatalf("DeleteByPrefix() failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("DeleteByPrefix() = %d, want 2", n)
+	}
+	if got := c.Stats(); got.Entries != 0 || got.Bytes != 0 {
+		t.Errorf("Stats() = %+v, wants empty cache", got)
+	}
+}
+
+func TestCache_IncrWithExpiry(t *testing.T) {
+	c, now := newCache(t, Options{MaxEntries: 10})
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := c.IncrWithExpiry("counter", 10)
+		if err != nil {
+			t.Fatalf("IncrWithExpiry() failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("IncrWithExpiry() = %d, want %d", got, want)
+		}
+		*now = now.Add(3 * time.Second)
+	}
+	// The window started 9 seconds ago.
+	*now = now.Add(time.Second)
+	got, err := c.IncrWithExpiry("counter", 10)
+	if err != nil {
+		t.Fatalf("IncrWithExpiry() failed: %v", err)
+	}
+	if got != 1 {
+		t.Errorf("IncrWithExpiry() after expiry = %d, want 1", got)
+	}
+	if v, _ := c.Get("counter"); string(v) != "1" {
+		t.Errorf("Get() = %s, want 1", v)
+	}
+
+	c.SetWithExpiry("str", []byte("abc"), 10)
+	if _, err := c.IncrWithExpiry("str", 10); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("IncrWithExpiry() of non integer = %v, wants FailedPrecondition", err)
+	}
+}
+
+func TestCache_MGet_MSetWithExpiry(t *testing.T) {
+	c, _ := newCache(t, Options{MaxEntries: 10})
+
+	if err := c.MSetWithExpiry(map[string][]byte{"a": []byte("1"), "b": []byte("2")}, 10); err != nil {
+		t.Fatalf("MSetWithExpiry() failed: %v", err)
+	}
+	got, err := c.MGet([]string{"a", "missing", "b"})
+	if err != nil {
+		t.Fatalf("MGet() failed: %v", err)
+	}
+	want := [][]byte{[]byte("1"), nil, []byte("2")}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("MGet() (-want, +got):\n%s", d)
+	}
+}
+
+func TestTiered(t *testing.T) {
+	r, client := fakecache.New(t)
+	local, now := newCache(t, Options{MaxEntries: 10})
+	tiered := NewTiered(local, client(), time.Second)
+	defer tiered.Close()
+
+	if err := tiered.SetWithExpiry("k", []byte("v1"), 60); err != nil {
+		t.Fatalf("SetWithExpiry() failed: %v", err)
+	}
+	if got, err := r.Get("k"); err != nil || got != "v1" {
+		t.Errorf("redis value = %q, %v, want v1", got, err)
+	}
+
+	// Change by another instance, the local value is used until it expires.
+	r.Set("k", "v2")
+	if got, _ := tiered.Get("k"); string(got) != "v1" {
+		t.Errorf("Get() = %s, want local v1", got)
+	}
+	*now = now.Add(time.Second)
+	if got, _ := tiered.Get("k"); string(got) != "v2" {
+		t.Errorf("Get() after local expiry = %s, want v2", got)
+	}
+	if got := local.Stats().Hits; got != 1 {
+		t.Errorf("local hits = %d, want 1", got)
+	}
+
+	// Read from remote on a local miss.
+	r.Set("other", "o")
+	if got, _ := tiered.Get("other"); string(got) != "o" {
+		t.Errorf("Get() = %s, want o", got)
+	}
+	if _, err := tiered.Get("missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of missing key = %v, wants NotFound", err)
+	}
+}
+
+func TestTiered_RemoteExpiry(t *testing.T) {
+	r, client := fakecache.New(t)
+	local, now := newCache(t, Options{MaxEntries: 10})
+	tiered := NewTiered(local, client(), time.Minute)
+	defer tiered.Close()
+
+	// A remote value expiring before the local TTL is not kept past its expiry.
+	r.Set("k", "v")
+	r.SetTTL("k", 10*time.Second)
+	if got, _ := tiered.Get("k"); string(got) != "v" {
+		t.Errorf("Get() = %s, want v", got)
+	}
+	*now = now.Add(10 * time.Second)
+	if _, err := local.Get("k"); status.Code(err) != codes.NotFound {
+		t.Errorf("local Get() after the remote expiry = %v, wants NotFound", err)
+	}
+
+	// A remote value without expiry is kept for the local TTL.
+	r.Set("persistent", "p")
+	if got, _ := tiered.Get("persistent"); string(got) != "p" {
+		t.Errorf("Get() = %s, want p", got)
+	}
+	*now = now.Add(time.Minute - time.Second)
+	if _, err := local.Get("persistent"); err != nil {
+		t.Errorf("local Get() within the local TTL failed: %v", err)
+	}
+
+	// Values read by MGet are kept the same way.
+	r.Set("m1", "1")
+	r.SetTTL("m1", 10*time.Second)
+	r.Set("m2", "2")
+	got, err := tiered.MGet([]string{"m1", "m2"})
+	if err != nil {
+		t.Fatalf("MGet() failed: %v", err)
+	}
+	if d := cmp.Diff([][]byte{[]byte("1"), []byte("2")}, got); d != "" {
+		t.Errorf("MGet() (-want, +got):\n%s", d)
+	}
+	*now = now.Add(10 * time.Second)
+	if _, err := local.Get("m1"); status.Code(err) != codes.NotFound {
+		t.Errorf("local Get() after the remote expiry of an MGet() value = %v, wants NotFound", err)
+	}
+	if _, err := local.Get("m2"); err != nil {
+		t.Errorf("local Get() of an MGet() value within the local TTL failed: %v", err)
+	}
+}
+
+func TestTiered_Operations(t *testing.T) {
+	r, client := fakecache.New(t)
+	local, _ := newCache(t, Options{MaxEntries: 10})
+	tiered := NewTiered(local, client(), time.Minute)
+	defer tiered.Close()
+
+	if err := tiered.MSetWithExpiry(map[string][]byte{"user_a_1": []byte("1"), "user_a_2": []byte("2")}, 60); err != nil {
+		t.Fatalf("MSetWithExpiry() failed: %v", err)
+	}
+	r.Set("remote", "r")
+	got, err := tiered.MGet([]string{"user_a_1", "remote", "missing"})
+	if err != nil {
+		t.Fatalf("MGet() failed: %v", err)
+	}
+	want := [][]byte{[]byte("1"), []byte("r"), nil}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("MGet() (-want, +got):\n%s", d)
+	}
+	if _, err := local.Get("remote"); err != nil {
+		t.Errorf("remote value of MGet() not kept locally: %v", err)
+	}
+
+	n, err := tiered.DeleteByPrefix("user_a_")
+	if err != nil {
+		t.Fatalf("DeleteByPrefix() failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("DeleteByPrefix() = %d, want 2", n)
+	}
+	if _, err := tiered.Get("user_a_1"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() after DeleteByPrefix() = %v, wants NotFound", err)
+	}
+
+	if err := tiered.Delete("remote"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := tiered.Get("remote"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() after Delete() = %v, wants NotFound", err)
+	}
+
+	// Counters are shared in the remote cache.
+	for want := int64(1); want <= 2; want++ {
+		got, err := tiered.IncrWithExpiry("counter", 60)
+		if err != nil {
+			t.Fatalf("IncrWithExpiry() failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("IncrWithExpiry() = %d, want %d", got, want)
+		}
+	}
+	if v, _ := r.Get("counter"); v != "2" {
+		t.Errorf("remote counter = %q, want 2", v)
+	}
+}
+
+func TestTiered_RemoteUnavailable(t *testing.T) {
+	local, _ := newCache(t, Options{MaxEntries: 10})
+	p := rediz.NewPool("localhost:0")
+	defer p.Close()
+	tiered := NewTiered(local, p.Client(), time.Second)
+	defer tiered.Close()
+
+	if err := tiered.SetWithExpiry("k", []byte("v"), 60); status.Code(err) != codes.Unavailable {
+		t.Errorf("SetWithExpiry() = %v, wants Unavailable", err)
+	}
+	if _, err := local.Get("k"); err == nil {
+		t.Errorf("local cache has a value not written to the remote cache")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rediz
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/gomodule/redigo/redis" /* copybara-comment */
+	"github.com/pborman/uuid" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+)
+
+var (
+	// acquireScript sets the lock key to the holder unless the lock is held or
+	// was acquired within minFrequency, and returns the next fencing token or 0.
+	// KEYS: lock, fencing token counter, last acquisition marker.
+	// ARGV: holder, ttl in ms, minFrequency in ms.
+	acquireScript = redis.NewScript(3, `
+if redis.call("EXISTS", KEYS[3]) == 1 then
+  return 0
+end
+if not redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+  return 0
+end
+if tonumber(ARGV[3]) > 0 then
+  redis.call("SET", KEYS[3], ARGV[1], "PX", ARGV[3])
+end
+return redis.call("INCR", KEYS[2])
+`)
+
+	// releaseScript deletes the lock key if it is still set to the holder.
+	// KEYS: lock. ARGV: holder.
+	releaseScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// Locker is a storage.Locker of locks in redis. A lock is a key set with
+// SET NX PX to a random holder id, and fencing tokens are from a counter
+// incremented with each acquisition.
+type Locker struct {
+	pool   *Pool
+	prefix string
+}
+
+// NewLocker creates a Locker. The prefix of the keys separates the locks of
+// services sharing the redis.
+func NewLocker(pool *Pool, prefix string) *Locker {
+	return &Locker{pool: pool, prefix: prefix}
+}
+
+// Lock acquires the named lock for at most ttl, unless it was acquired less
+// than minFrequency ago. Returns a nil Lease if the lock is not available.
+func (l *Locker) Lock(ctx context.Context, name string, ttl, minFrequency time.Duration) (storage.Lease, error) {
+	if ttl < time.Millisecond {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid lock ttl %v", ttl)
+	}
+	conn, err := l.pool.pool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	defer conn.Close()
+
+	key := l.prefix + "lock:" + name
+	holder := uuid.New()
+	token, err := redis.Int64(acquireScript.Do(conn, key, key+":fence", key+":last", holder, ttl.Milliseconds(), minFrequency.Milliseconds()))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	if token == 0 {
+		return nil, nil
+	}
+	return &lease{pool: l.pool, key: key, holder: holder, token: token}, nil
+}
+
+// lease is a lock held in redis.
+type lease struct {
+	pool   *Pool
+	key    string
+	holder string
+	token  int64
+}
+
+// Token is the fencing token of the lease.
+func (l *lease) Token() int64 {
+	return l.token
+}
+
+// Release releases the lock if it is still held by the lease.
+func (l *lease) Release(ctx context.Context) error {
+	conn, err := l.pool.pool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	defer conn.Close()
+
+	n, err := redis.Int(releaseScript.Do(conn, l.key, l.holder))
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	if n == 0 {
+		return status.Errorf(codes.FailedPrecondition, "lease %d of lock %q expired", l.token, l.key)
+	}
+	return nil
+}
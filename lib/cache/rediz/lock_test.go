@@ -0,0 +1,118 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rediz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+)
+
+func Test_Lock(t *testing.T) {
+	r, p := setup(t)
+	ctx := context.Background()
+	l := NewLocker(p, "dam:")
+
+	lease, err := l.Lock(ctx, "gc", time.Minute, 0)
+	if err != nil || lease == nil {
+		t.Fatalf("Lock() = %v, %v, want a lease", lease, err)
+	}
+	if !r.Exists("dam:lock:gc") {
+		t.Errorf("lock key not set")
+	}
+
+	// The lock is held.
+	if other, err := l.Lock(ctx, "gc", time.Minute, 0); err != nil || other != nil {
+		t.Fatalf("Lock() of held lock = %v, %v, want nil", other, err)
+	}
+	// Other locks and other prefixes are independent.
+	if other, err := l.Lock(ctx, "sync", time.Minute, 0); err != nil || other == nil {
+		t.Fatalf("Lock() of other lock = %v, %v, want a lease", other, err)
+	}
+	if other, err := NewLocker(p, "ic:").Lock(ctx, "gc", time.Minute, 0); err != nil || other == nil {
+		t.Fatalf("Lock() with other prefix = %v, %v, want a lease", other, err)
+	}
+
+	if err := lease.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	next, err := l.Lock(ctx, "gc", time.Minute, 0)
+	if err != nil || next == nil {
+		t.Fatalf("Lock() after release = %v, %v, want a lease", next, err)
+	}
+	if next.Token() <= lease.Token() {
+		t.Errorf("fencing token %d after %d, want increasing tokens", next.Token(), lease.Token())
+	}
+}
+
+func Test_Lock_Expiry(t *testing.T) {
+	r, p := setup(t)
+	ctx := context.Background()
+	l := NewLocker(p, "")
+
+	lease, err := l.Lock(ctx, "gc", time.Minute, 0)
+	if err != nil || lease == nil {
+		t.Fatalf("Lock() = %v, %v, want a lease", lease, err)
+	}
+
+	// An expired lease is taken over, and cannot release the lock of the next
+	// holder.
+	r.FastForward(time.Minute)
+	next, err := l.Lock(ctx, "gc", time.Minute, 0)
+	if err != nil || next == nil {
+		t.Fatalf("Lock() after expiry = %v, %v, want a lease", next, err)
+	}
+	if next.Token() <= lease.Token() {
+		t.Errorf("fencing token %d after %d, want increasing tokens", next.Token(), lease.Token())
+	}
+	if err := lease.Release(ctx); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Release() of expired lease = %v, want FailedPrecondition", err)
+	}
+	if !r.Exists("lock:gc") {
+		t.Errorf("lock of next holder released")
+	}
+}
+
+func Test_Lock_MinFrequency(t *testing.T) {
+	r, p := setup(t)
+	ctx := context.Background()
+	l := NewLocker(p, "")
+
+	lease, err := l.Lock(ctx, "gc", time.Minute, time.Hour)
+	if err != nil || lease == nil {
+		t.Fatalf("Lock() = %v, %v, want a lease", lease, err)
+	}
+	if err := lease.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+
+	if other, err := l.Lock(ctx, "gc", time.Minute, time.Hour); err != nil || other != nil {
+		t.Fatalf("Lock() within minFrequency = %v, %v, want nil", other, err)
+	}
+	r.FastForward(time.Hour)
+	if other, err := l.Lock(ctx, "gc", time.Minute, time.Hour); err != nil || other == nil {
+		t.Fatalf("Lock() after minFrequency = %v, %v, want a lease", other, err)
+	}
+}
+
+func Test_Lock_InvalidTTL(t *testing.T) {
+	_, p := setup(t)
+	if _, err := NewLocker(p, "").Lock(context.Background(), "gc", 0, 0); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Lock() with ttl 0 = %v, want InvalidArgument", err)
+	}
+}
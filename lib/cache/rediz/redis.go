@@ -12,10 +12,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package rediz includes helpers to access cache. Rightnow, we only use GET and SETEX (set with expiration).
+// Package rediz includes helpers to access cache in redis.
 package rediz
 
 import (
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes" /* copybara-comment */
@@ -81,6 +82,66 @@ func (s *Client) Get(key string) ([]byte, error) {
 	return redis.Bytes(reply, err)
 }
 
+// GetWithExpiry gets a value and its remaining time to live in redis in a
+// transaction.
+func (s *Client) GetWithExpiry(key string) ([]byte, time.Duration, error) {
+	s.conn.Send("MULTI")
+	s.conn.Send("GET", key)
+	s.conn.Send("PTTL", key)
+	reply, err := redis.Values(s.conn.Do("EXEC"))
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	if len(reply) != 2 {
+		return nil, 0, status.Errorf(codes.Internal, "invalid EXEC reply")
+	}
+	if reply[0] == nil {
+		return nil, 0, status.Errorf(codes.NotFound, "key not found")
+	}
+	v, err := redis.Bytes(reply[0], nil)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "invalid GET reply: %v", err)
+	}
+	ms, err := redis.Int64(reply[1], nil)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "invalid PTTL reply: %v", err)
+	}
+	return v, time.Duration(ms) * time.Millisecond, nil
+}
+
+// MGetWithExpiry gets the values of the keys and their remaining times to
+// live in redis in a transaction.
+func (s *Client) MGetWithExpiry(keys []string) ([][]byte, []time.Duration, error) {
+	if len(keys) == 0 {
+		return nil, nil, nil
+	}
+	s.conn.Send("MULTI")
+	s.conn.Send("MGET", redis.Args{}.AddFlat(keys)...)
+	for _, k := range keys {
+		s.conn.Send("PTTL", k)
+	}
+	reply, err := redis.Values(s.conn.Do("EXEC"))
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	if len(reply) != len(keys)+1 {
+		return nil, nil, status.Errorf(codes.Internal, "invalid EXEC reply")
+	}
+	values, err := redis.ByteSlices(reply[0], nil)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "invalid MGET reply: %v", err)
+	}
+	ttls := make([]time.Duration, len(keys))
+	for i := range keys {
+		ms, err := redis.Int64(reply[i+1], nil)
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "invalid PTTL reply: %v", err)
+		}
+		ttls[i] = time.Duration(ms) * time.Millisecond
+	}
+	return values, ttls, nil
+}
+
 // SetWithExpiry add a key-value pair with expiry in redis.
 func (s *Client) SetWithExpiry(key string, value []byte, seconds int64) error {
 	_, err := redis.String(s.conn.Do("SETEX", key, seconds, value))
@@ -90,6 +151,113 @@ func (s *Client) SetWithExpiry(key string, value []byte, seconds int64) error {
 	return nil
 }
 
+// Delete removes a key from redis.
+func (s *Client) Delete(key string) error {
+	if _, err := s.conn.Do("DEL", key); err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return nil
+}
+
+// scanCount is the number of keys per SCAN of DeleteByPrefix.
+const scanCount = 1000
+
+// globEscaper escapes the special characters of redis glob patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// DeleteByPrefix removes all keys with the given prefix from redis. Keys are
+// found with SCAN, so keys added meanwhile may not be removed.
+func (s *Client) DeleteByPrefix(prefix string) (int, error) {
+	if prefix == "" {
+		return 0, status.Errorf(codes.InvalidArgument, "empty prefix")
+	}
+	match := globEscaper.Replace(prefix) + "*"
+	deleted := 0
+	cursor := "0"
+	for {
+		reply, err := redis.Values(s.conn.Do("SCAN", cursor, "MATCH", match, "COUNT", scanCount))
+		if err != nil {
+			return deleted, status.Errorf(codes.Unavailable, "%v", err)
+		}
+		if len(reply) != 2 {
+			return deleted, status.Errorf(codes.Internal, "invalid SCAN reply")
+		}
+		cursor, err = redis.String(reply[0], nil)
+		if err != nil {
+			return deleted, status.Errorf(codes.Internal, "invalid SCAN cursor: %v", err)
+		}
+		keys, err := redis.Values(reply[1], nil)
+		if err != nil {
+			return deleted, status.Errorf(codes.Internal, "invalid SCAN keys: %v", err)
+		}
+		if len(keys) > 0 {
+			n, err := redis.Int(s.conn.Do("DEL", keys...))
+			if err != nil {
+				return deleted, status.Errorf(codes.Unavailable, "%v", err)
+			}
+			deleted += n
+		}
+		if cursor == "0" {
+			return deleted, nil
+		}
+	}
+}
+
+// IncrWithExpiry atomically increments the value of a key in redis, a new key
+// expires after the given seconds.
+func (s *Client) IncrWithExpiry(key string, seconds int64) (int64, error) {
+	if seconds <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid expire time %d", seconds)
+	}
+	s.conn.Send("MULTI")
+	// Creates the key with the expiry only if missing, INCR keeps the expiry.
+	s.conn.Send("SET", key, 0, "EX", seconds, "NX")
+	s.conn.Send("INCR", key)
+	reply, err := redis.Values(s.conn.Do("EXEC"))
+	if err != nil {
+		return 0, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	if len(reply) != 2 {
+		return 0, status.Errorf(codes.Internal, "invalid EXEC reply")
+	}
+	n, err := redis.Int64(reply[1], nil)
+	if err != nil {
+		return 0, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return n, nil
+}
+
+// MGet gets the values of the keys in redis.
+func (s *Client) MGet(keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	values, err := redis.ByteSlices(s.conn.Do("MGET", redis.Args{}.AddFlat(keys)...))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return values, nil
+}
+
+// MSetWithExpiry adds the key-value pairs with expiry in redis in a
+// transaction.
+func (s *Client) MSetWithExpiry(values map[string][]byte, seconds int64) error {
+	if seconds <= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid expire time %d", seconds)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	s.conn.Send("MULTI")
+	for k, v := range values {
+		s.conn.Send("SETEX", k, seconds, v)
+	}
+	if _, err := s.conn.Do("EXEC"); err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return nil
+}
+
 // Close returns the client to pool.
 func (s *Client) Close() error {
 	return s.conn.Close()
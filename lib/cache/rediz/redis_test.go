@@ -118,6 +118,154 @@ func Test_Get(t *testing.T) {
 	}
 }
 
+func Test_GetWithExpiry(t *testing.T) {
+	r, p := setup(t)
+
+	c := p.Client()
+	defer c.Close()
+
+	if err := c.SetWithExpiry("key", []byte("hello"), 60); err != nil {
+		t.Fatalf("SetWithExpiry() failed: %v", err)
+	}
+	r.Set("persistent", "p")
+
+	v, ttl, err := c.GetWithExpiry("key")
+	if err != nil {
+		t.Fatalf("GetWithExpiry() failed: %v", err)
+	}
+	if string(v) != "hello" || ttl != time.Minute {
+		t.Errorf("GetWithExpiry() = %s, %v, want hello, %v", v, ttl, time.Minute)
+	}
+	if _, ttl, err := c.GetWithExpiry("persistent"); err != nil || ttl >= 0 {
+		t.Errorf("GetWithExpiry() of a key without expiry = %v, %v, want a negative TTL", ttl, err)
+	}
+	if _, _, err := c.GetWithExpiry("missing"); !errutil.NotFound(err) {
+		t.Errorf("GetWithExpiry() of a missing key = %v, wants NotFound", err)
+	}
+}
+
+func Test_MGetWithExpiry(t *testing.T) {
+	r, p := setup(t)
+
+	c := p.Client()
+	defer c.Close()
+
+	if err := c.SetWithExpiry("key", []byte("hello"), 60); err != nil {
+		t.Fatalf("SetWithExpiry() failed: %v", err)
+	}
+	r.Set("persistent", "p")
+
+	values, ttls, err := c.MGetWithExpiry([]string{"key", "persistent", "missing"})
+	if err != nil {
+		t.Fatalf("MGetWithExpiry() failed: %v", err)
+	}
+	if d := cmp.Diff([][]byte{[]byte("hello"), []byte("p"), nil}, values); len(d) > 0 {
+		t.Errorf("MGetWithExpiry() values (-want, +got): %s", d)
+	}
+	if len(ttls) != 3 || ttls[0] != time.Minute || ttls[1] >= 0 {
+		t.Errorf("MGetWithExpiry() TTLs = %v, want %v and a negative TTL for the key without expiry", ttls, time.Minute)
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	r, p := setup(t)
+	c := p.Client()
+	defer c.Close()
+
+	r.Set("k", "v")
+	if err := c.Delete("k"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if r.Exists("k") {
+		t.Errorf("key exists after Delete()")
+	}
+	if err := c.Delete("not_exist"); err != nil {
+		t.Errorf("Delete() of missing key failed: %v", err)
+	}
+}
+
+func Test_DeleteByPrefix(t *testing.T) {
+	r, p := setup(t)
+	c := p.Client()
+	defer c.Close()
+
+	for _, k := range []string{"user_a*_1", "user_a*_2", "user_ab_1", "other"} {
+		r.Set(k, "v")
+	}
+	// The prefix contains a glob character that must match literally.
+	n, err := c.DeleteByPrefix("user_a*_")
+	if err != nil {
+		t.Fatalf("DeleteByPrefix() failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("DeleteByPrefix() = %d, want 2", n)
+	}
+	if d := cmp.Diff([]string{"other", "user_ab_1"}, r.Keys()); len(d) > 0 {
+		t.Errorf("keys (-want, +got): %s", d)
+	}
+	if _, err := c.DeleteByPrefix(""); err == nil {
+		t.Errorf("DeleteByPrefix() with empty prefix wants error")
+	}
+}
+
+func Test_IncrWithExpiry(t *testing.T) {
+	r, p := setup(t)
+	c := p.Client()
+	defer c.Close()
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := c.IncrWithExpiry("counter", 10)
+		if err != nil {
+			t.Fatalf("IncrWithExpiry() failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("IncrWithExpiry() = %d, want %d", got, want)
+		}
+		// Later increments keep the expiry of the window.
+		r.FastForward(3 * time.Second)
+	}
+	if ttl := r.TTL("counter"); ttl != time.Second {
+		t.Errorf("TTL = %v, want 1s", ttl)
+	}
+
+	r.FastForward(time.Second)
+	got, err := c.IncrWithExpiry("counter", 10)
+	if err != nil {
+		t.Fatalf("IncrWithExpiry() failed: %v", err)
+	}
+	if got != 1 {
+		t.Errorf("IncrWithExpiry() after expiry = %d, want 1", got)
+	}
+
+	r.Set("str", "abc")
+	if _, err := c.IncrWithExpiry("str", 10); err == nil {
+		t.Errorf("IncrWithExpiry() of non integer wants error")
+	}
+}
+
+func Test_MGet_MSetWithExpiry(t *testing.T) {
+	r, p := setup(t)
+	c := p.Client()
+	defer c.Close()
+
+	values := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	if err := c.MSetWithExpiry(values, 10); err != nil {
+		t.Fatalf("MSetWithExpiry() failed: %v", err)
+	}
+	if ttl := r.TTL("b"); ttl != 10*time.Second {
+		t.Errorf("TTL = %v, want 10s", ttl)
+	}
+
+	got, err := c.MGet([]string{"a", "not_exist", "b"})
+	if err != nil {
+		t.Fatalf("MGet() failed: %v", err)
+	}
+	want := [][]byte{[]byte("1"), nil, []byte("2")}
+	if d := cmp.Diff(want, got); len(d) > 0 {
+		t.Errorf("MGet() (-want, +got): %s", d)
+	}
+}
+
 func setup(t *testing.T) (*miniredis.Miniredis, *Pool) {
 	t.Helper()
 
@@ -43,6 +43,23 @@ const (
 var (
 	uuidRE  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
 	timeNow = time.Now
+
+	// consentSortFields maps the "sortBy" names of the list consents request to
+	// the sortable value of the field.
+	consentSortFields = map[string]func(p proto.Message) string{
+		"name": func(p proto.Message) string {
+			return p.(*cspb.Consent).Name
+		},
+		"client.name": func(p proto.Message) string {
+			return p.(*cspb.Consent).GetClient().GetName()
+		},
+		"createtime": func(p proto.Message) string {
+			return storage.SortInt(p.(*cspb.Consent).GetCreateTime().GetSeconds())
+		},
+		"expiretime": func(p proto.Message) string {
+			return storage.SortInt(p.(*cspb.Consent).GetExpireTime().GetSeconds())
+		},
+	}
 )
 
 // Service contains store and funcs to access data.
@@ -94,40 +111,58 @@ func (s *listConsentsHandler) Setup(r *http.Request, tx storage.Tx) (int, error)
 }
 
 func (s *listConsentsHandler) Get(r *http.Request, name string) (proto.Message, error) {
-	return toListConsentsResponse(s.remembered, s.userID, s.clients), nil
+	// Most recent consents are listed first unless requested otherwise.
+	sortBy, sortOrder := httputils.QueryParam(r, "sortBy"), httputils.QueryParam(r, "sortOrder")
+	if len(sortBy) == 0 && len(sortOrder) == 0 {
+		sortBy, sortOrder = "createTime", storage.SortDescending
+	}
+	order, err := storage.BuildSort(sortBy, sortOrder, consentSortFields)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return toListConsentsResponse(s.remembered, s.userID, s.clients, order), nil
 }
 
-func toListConsentsResponse(m map[string]*storepb.RememberedConsentPreference, userID string, clients map[string]*cpb.Client) *cspb.ListConsentsResponse {
+func toListConsentsResponse(m map[string]*storepb.RememberedConsentPreference, userID string, clients map[string]*cpb.Client, order *storage.Sort) *cspb.ListConsentsResponse {
 	res := &cspb.ListConsentsResponse{}
 
 	for k, v := range m {
-		res.Consents = append(res.Consents, &cspb.Consent{
-			Name:                fmt.Sprintf("users/%s/consents/%s", userID, k),
-			Client:              toConsentClient(v.ClientName, clients[v.ClientName]),
-			CreateTime:          v.CreateTime,
-			ExpireTime:          v.ExpireTime,
-			RequestMatchType:    cspb.Consent_RequestMatchType(v.RequestMatchType),
-			RequestedResources:  v.RequestedResources,
-			RequestedScopes:     v.RequestedScopes,
-			ReleaseType:         cspb.Consent_ReleaseType(v.ReleaseType),
-			SelectedVisas:       toConsentVisas(v.SelectedVisas),
-			ReleaseProfileName:  v.ReleaseProfileName,
-			ReleaseProfileEmail: v.ReleaseProfileEmail,
-			ReleaseProfileOther: v.ReleaseProfileOther,
-			ReleaseAccountAdmin: v.ReleaseAccountAdmin,
-			ReleaseLink:         v.ReleaseLink,
-			ReleaseIdentities:   v.ReleaseIdentities,
-		})
+		res.Consents = append(res.Consents, toConsent(k, v, userID, clients))
 	}
 
-	// order by CreateTime
+	// Consents with the same value are ordered by name for a stable order.
 	sort.Slice(res.Consents, func(i int, j int) bool {
-		return res.Consents[i].CreateTime.Seconds > res.Consents[j].CreateTime.Seconds
+		if order != nil {
+			if c := order.Compare(res.Consents[i], res.Consents[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return res.Consents[i].Name < res.Consents[j].Name
 	})
 
 	return res
 }
 
+func toConsent(id string, v *storepb.RememberedConsentPreference, userID string, clients map[string]*cpb.Client) *cspb.Consent {
+	return &cspb.Consent{
+		Name:                fmt.Sprintf("users/%s/consents/%s", userID, id),
+		Client:              toConsentClient(v.ClientName, clients[v.ClientName]),
+		CreateTime:          v.CreateTime,
+		ExpireTime:          v.ExpireTime,
+		RequestMatchType:    cspb.Consent_RequestMatchType(v.RequestMatchType),
+		RequestedResources:  v.RequestedResources,
+		RequestedScopes:     v.RequestedScopes,
+		ReleaseType:         cspb.Consent_ReleaseType(v.ReleaseType),
+		SelectedVisas:       toConsentVisas(v.SelectedVisas),
+		ReleaseProfileName:  v.ReleaseProfileName,
+		ReleaseProfileEmail: v.ReleaseProfileEmail,
+		ReleaseProfileOther: v.ReleaseProfileOther,
+		ReleaseAccountAdmin: v.ReleaseAccountAdmin,
+		ReleaseLink:         v.ReleaseLink,
+		ReleaseIdentities:   v.ReleaseIdentities,
+	}
+}
+
 func toConsentClient(name string, client *cpb.Client) *cspb.Consent_Client {
 	c := &cspb.Consent_Client{Name: name}
 	if client != nil {
@@ -152,6 +187,7 @@ func toConsentVisas(list []*storepb.RememberedConsentPreference_Visa) []*cspb.Co
 }
 
 // DeleteConsentFactory http handler for "/identity/v1alpha/{realm}/users/{user}/consents/{consent_id}"
+// GET returns the consent with its ETag, and DELETE honours If-Match.
 func DeleteConsentFactory(serv *Service, consentPath string, consentIDUseUUID bool) *handlerfactory.Options {
 	opts := &handlerfactory.Options{
 		TypeName:            "consent",
@@ -177,17 +213,43 @@ type deleteConsentHandler struct {
 	userID    string
 	realm     string
 	consentID string
+	item      *storepb.RememberedConsentPreference
+	etag      string
+	tx        storage.Tx
 }
 
-func (s *deleteConsentHandler) Remove(r *http.Request, name string) (proto.Message, error) {
+func (s *deleteConsentHandler) Setup(r *http.Request, tx storage.Tx) (int, error) {
 	s.userID = mux.Vars(r)["user"]
 	s.consentID = mux.Vars(r)["consent_id"]
 	s.realm = mux.Vars(r)["realm"]
+	s.tx = tx
+	return http.StatusOK, nil
+}
 
+func (s *deleteConsentHandler) LookupItem(r *http.Request, name string, vars map[string]string) bool {
+	rcp := &storepb.RememberedConsentPreference{}
+	if err := s.s.Store.ReadTx(storage.RememberedConsentDatatype, s.realm, s.userID, s.consentID, storage.LatestRev, rcp, s.tx); err != nil {
+		return false
+	}
+	s.item = rcp
+	s.etag = storage.ETag(rcp)
+	return true
+}
+
+func (s *deleteConsentHandler) Get(r *http.Request, name string) (proto.Message, error) {
+	clients, err := s.s.Clients(s.tx)
+	if err != nil {
+		return nil, err
+	}
+	return toConsent(s.consentID, s.item, s.userID, clients), nil
+}
+
+func (s *deleteConsentHandler) Remove(r *http.Request, name string) (proto.Message, error) {
 	return &epb.Empty{}, nil
 }
 
 func (s *deleteConsentHandler) Save(r *http.Request, tx storage.Tx, name string, vars map[string]string, desc, typeName string) error {
+	// The etag was checked against If-Match in the same transaction.
 	if err := s.s.Store.DeleteTx(storage.RememberedConsentDatatype, s.realm, s.userID, s.consentID, storage.LatestRev, tx); err != nil {
 		if storage.ErrNotFound(err) {
 			return status.Errorf(codes.NotFound, "delete consent item not found")
@@ -197,6 +259,11 @@ func (s *deleteConsentHandler) Save(r *http.Request, tx storage.Tx, name string,
 	return nil
 }
 
+// ETag returns the etag of the consent.
+func (s *deleteConsentHandler) ETag() string {
+	return s.etag
+}
+
 // findRememberedConsentsByUser returns all RememberedConsents of user of client.
 func findRememberedConsentsByUser(store storage.Store, subject, realm, clientName string, offset, pageSize int, tx storage.Tx) (map[string]*storepb.RememberedConsentPreference, error) {
 	results, err := store.MultiReadTx(storage.RememberedConsentDatatype, realm, subject, storage.MatchAllIDs, nil, offset, pageSize, &storepb.RememberedConsentPreference{}, tx)
@@ -17,6 +17,7 @@ package consentsapi
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -240,6 +241,82 @@ func TestListConsents(t *testing.T) {
 	}
 }
 
+func TestListConsents_Sort(t *testing.T) {
+	timeNow = func() time.Time { return time.Time{} }
+	consents := map[string]*storepb.RememberedConsentPreference{
+		"c1": {ClientName: "cli1", CreateTime: timeutil.TimestampProto(time.Time{}.Add(100 * time.Hour)), ExpireTime: timeutil.TimestampProto(time.Time{}.Add(300 * time.Hour))},
+		"c2": {ClientName: "cli2", CreateTime: timeutil.TimestampProto(time.Time{}.Add(200 * time.Hour)), ExpireTime: timeutil.TimestampProto(time.Time{}.Add(300 * time.Hour))},
+		"c3": {ClientName: "cli3", CreateTime: timeutil.TimestampProto(time.Time{}.Add(150 * time.Hour)), ExpireTime: timeutil.TimestampProto(time.Time{}.Add(200 * time.Hour))},
+	}
+
+	tests := []struct {
+		name       string
+		params     string
+		want       []string
+		wantStatus int
+	}{
+		{
+			name:       "default",
+			want:       []string{"c2", "c3", "c1"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "createTime ascending",
+			params:     "sortBy=createTime",
+			want:       []string{"c1", "c3", "c2"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "expireTime descending, ties by name",
+			params:     "sortBy=expireTime&sortOrder=descending",
+			want:       []string{"c1", "c2", "c3"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown field",
+			params:     "sortBy=scope",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := &stub{}
+			store := fakestore.New()
+			handler := handlerfactory.MakeHandler(store, ListConsentsFactory(&Service{
+				Store:   store,
+				Clients: stub.clients,
+			}, "/identity/v1alpha/{realm}/users/{user}/consents"))
+			storeRememberedConsents(t, store, "user1", storage.DefaultRealm, consents)
+
+			r := httptest.NewRequest(http.MethodGet, "/identity/v1alpha/master/user1/consents?"+tc.params, nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"user":  "user1",
+				"realm": "master",
+			})
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("StatusCode = %d, wants %d", resp.StatusCode, tc.wantStatus)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			got := &cspb.ListConsentsResponse{}
+			httputils.MustDecodeJSONPBResp(t, resp, got)
+			var names []string
+			for _, c := range got.Consents {
+				names = append(names, strings.TrimPrefix(c.Name, "users/user1/consents/"))
+			}
+			if d := cmp.Diff(tc.want, names); len(d) > 0 {
+				t.Errorf("ListConsents() order diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
 func TestDeleteConsent(t *testing.T) {
 	stub := &stub{}
 
@@ -292,6 +369,61 @@ func TestDeleteConsent(t *testing.T) {
 	}
 }
 
+func TestConsent_IfMatch(t *testing.T) {
+	stub := &stub{}
+
+	store := fakestore.New()
+	handler := handlerfactory.MakeHandler(store, DeleteConsentFactory(&Service{
+		Store:   store,
+		Clients: stub.clients,
+	}, "/identity/v1alpha/{realm}/users/{user}/consents/{consent_id}", true))
+
+	consentID := "00000000-0000-0000-0000-000000000001"
+	send := func(method, ifMatch string) *http.Response {
+		r := httptest.NewRequest(method, "/identity/v1alpha/master/users/user1/consents/"+consentID, nil)
+		r = mux.SetURLVars(r, map[string]string{
+			"user":       "user1",
+			"realm":      "master",
+			"consent_id": consentID,
+		})
+		if len(ifMatch) > 0 {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	rcp := &storepb.RememberedConsentPreference{ClientName: "c1"}
+	if err := store.Write(storage.RememberedConsentDatatype, storage.DefaultRealm, "user1", consentID, storage.LatestRev, rcp, nil); err != nil {
+		t.Fatalf("Write RememberedConsentDatatype failed: %v", err)
+	}
+
+	resp := send(http.MethodGet, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET StatusCode = %d, wants %d", resp.StatusCode, http.StatusOK)
+	}
+	etag := resp.Header.Get("ETag")
+	if want := httputils.ETagHeader(storage.ETag(rcp)); etag != want {
+		t.Fatalf("GET ETag = %q, wants %q", etag, want)
+	}
+
+	// The consent changed since it was read.
+	rcp.ClientName = "c2"
+	if err := store.Write(storage.RememberedConsentDatatype, storage.DefaultRealm, "user1", consentID, storage.LatestRev, rcp, nil); err != nil {
+		t.Fatalf("Write RememberedConsentDatatype failed: %v", err)
+	}
+	if resp := send(http.MethodDelete, etag); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("DELETE with stale If-Match StatusCode = %d, wants %d", resp.StatusCode, http.StatusPreconditionFailed)
+	}
+	if resp := send(http.MethodDelete, httputils.ETagHeader(storage.ETag(rcp))); resp.StatusCode != http.StatusOK {
+		t.Errorf("DELETE with If-Match StatusCode = %d, wants %d", resp.StatusCode, http.StatusOK)
+	}
+	if resp := send(http.MethodGet, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET after DELETE StatusCode = %d, wants %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
 func storeRememberedConsents(t *testing.T, store storage.Store, subject, realm string, consents map[string]*storepb.RememberedConsentPreference) {
 	t.Helper()
 	for id, rcp := range consents {
@@ -0,0 +1,49 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dam
+
+import (
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/backup" /* copybara-comment: backup */
+
+	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+// backupHandler returns the handler exporting and restoring the realms of the service.
+// The config of an archive must pass the integrity checks before a restore.
+func (s *Service) backupHandler() *backup.Handler {
+	validate := func(realm, datatype string, content proto.Message) error {
+		if cfg, ok := content.(*pb.DamConfig); ok {
+			if st := s.CheckIntegrity(cfg, realm, nil); st != nil {
+				return st.Err()
+			}
+		}
+		return nil
+	}
+	restored := func(realm string) error {
+		if s.useHydra && realm == storage.DefaultRealm {
+			return s.syncHydraClients()
+		}
+		return nil
+	}
+	return &backup.Handler{
+		ServiceType: "dam",
+		ServiceName: s.serviceName,
+		Store:       s.store,
+		Validate:    validate,
+		Restored:    restored,
+	}
+}
@@ -51,7 +51,7 @@ func (c *clientService) HandlerSetup(tx storage.Tx, r *http.Request) (*ga4gh.Ide
 	if err != nil {
 		return id, status, err
 	}
-	sec, err := c.s.loadSecrets(tx)
+	sec, err := c.s.loadStoredSecrets(tx)
 
 	c.cfg = cfg
 	c.sec = sec
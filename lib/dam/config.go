@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strings"
 
+	glog "github.com/golang/glog" /* copybara-comment */
 	"github.com/gorilla/mux" /* copybara-comment */
 	"google.golang.org/grpc/codes" /* copybara-comment */
 	"google.golang.org/grpc/status" /* copybara-comment */
@@ -31,6 +32,7 @@ import (
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/timeutil" /* copybara-comment: timeutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/translator" /* copybara-comment: translator */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/verifier" /* copybara-comment: verifier */
 
 	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
 )
@@ -328,7 +330,7 @@ func (s *Service) getIssuerTranslator(ctx context.Context, issuer string, cfg *p
 		}
 	}
 
-	t, err = createIssuerTranslator(ctx, cfgTpi, secrets, s.signer)
+	t, err = createIssuerTranslator(ctx, cfgTpi, secrets, s.signer, verifier.JWKSCacheOption(s.jwksCache))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create translator for issuer %q: %v", issuer, err)
 	}
@@ -336,8 +338,33 @@ func (s *Service) getIssuerTranslator(ctx context.Context, issuer string, cfg *p
 	return t, err
 }
 
-func createIssuerTranslator(ctx context.Context, cfgTpi *pb.TrustedIssuer, secrets *pb.DamSecrets, signer kms.Signer) (translator.Translator, error) {
-	return translator.CreateTranslator(ctx, cfgTpi.Issuer, cfgTpi.TranslateUsing, cfgTpi.ClientId, "", "", signer)
+// watchConfig drops the cached translators and visa type validators when the
+// config or the secrets they are created from change, in this or another
+// instance, until ctx is done.
+func (s *Service) watchConfig(ctx context.Context) {
+	for _, datatype := range []string{storage.ConfigDatatype, storage.SecretsDatatype} {
+		changes, err := s.store.Watch(ctx, datatype, storage.AllRealms)
+		if err != nil {
+			glog.Errorf("watching changes of %q failed, translators and visa types are not refreshed: %v", datatype, err)
+			continue
+		}
+		go func() {
+			for range changes {
+				s.translators.Range(func(issuer, _ interface{}) bool {
+					s.translators.Delete(issuer)
+					return true
+				})
+				s.visaTypes.Range(func(key, _ interface{}) bool {
+					s.visaTypes.Delete(key)
+					return true
+				})
+			}
+		}()
+	}
+}
+
+func createIssuerTranslator(ctx context.Context, cfgTpi *pb.TrustedIssuer, secrets *pb.DamSecrets, signer kms.Signer, opts ...verifier.Option) (translator.Translator, error) {
+	return translator.CreateTranslator(ctx, cfgTpi.Issuer, cfgTpi.TranslateUsing, cfgTpi.ClientId, "", "", signer, opts...)
 }
 
 // GetLocaleMetadata implements the corresponding REST API endpoint.
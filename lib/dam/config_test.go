@@ -17,6 +17,7 @@ package dam
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/aws" /* copybara-comment: aws */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/clouds" /* copybara-comment: clouds */
@@ -126,3 +127,48 @@ func TestConfigHandlers(t *testing.T) {
 	}
 	test.HandlerTests(t, s.Handler, tests, hydraPublicURL, server.Config())
 }
+
+func TestWatchConfig(t *testing.T) {
+	store := storage.NewMemoryStorage("dam", "testdata/config")
+	server, err := fakeoidcissuer.New(hydraPublicURL, &testkeys.PersonaBrokerKey, "dam", "testdata/config", false)
+	if err != nil {
+		t.Fatalf("fakeoidcissuer.New(%q, _, _) failed: %v", hydraPublicURL, err)
+	}
+	s := NewService(&Options{
+		HTTPClient:     server.Client(),
+		Domain:         "test.org",
+		ServiceName:    "dam",
+		DefaultBroker:  "no-broker",
+		Store:          store,
+		Warehouse:      clouds.NewMockTokenCreator(false),
+		AWSClient:      aws.NewMockAPIClient("123456", "dam-user-id"),
+		UseHydra:       useHydra,
+		HydraAdminURL:  hydraAdminURL,
+		HydraPublicURL: hydraPublicURL,
+		LRO:            fakelro.New(),
+	})
+
+	cfg, err := s.loadConfig(nil, storage.DefaultRealm)
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+	s.translators.Store("https://issuer.example.org", nil)
+	s.visaTypes.Store("1/0", &visaTypesEntry{})
+
+	// A config saved by another instance through the shared store.
+	if err := store.Write(storage.ConfigDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, cfg, nil); err != nil {
+		t.Fatalf("Write(config) failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, translator := s.translators.Load("https://issuer.example.org")
+		_, visaTypes := s.visaTypes.Load("1/0")
+		if !translator && !visaTypes {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("translators or visa types not dropped after the config changed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -49,6 +49,7 @@ type configHandler struct {
 	input *pb.ConfigRequest
 	save  *pb.DamConfig
 	cfg   *pb.DamConfig
+	etag  string
 	id    *ga4gh.Identity
 	tx    storage.Tx
 }
@@ -68,6 +69,7 @@ func (h *configHandler) Setup(r *http.Request, tx storage.Tx) (int, error) {
 }
 func (h *configHandler) LookupItem(r *http.Request, name string, vars map[string]string) bool {
 	// Trival name as there is only one config and it was fetched during Setup().
+	h.etag = strconv.FormatInt(h.cfg.Revision, 10)
 	return true
 }
 func (h *configHandler) NormalizeInput(r *http.Request, name string, vars map[string]string) error {
@@ -123,6 +125,7 @@ func (h *configHandler) Save(r *http.Request, tx storage.Tx, name string, vars m
 	if err := h.s.saveConfig(h.save, desc, typeName, r, h.id, h.cfg, h.save, h.input.Modification, tx); err != nil {
 		return err
 	}
+	h.etag = strconv.FormatInt(h.save.Revision, 10)
 	secrets, err := h.s.loadSecrets(tx)
 	if err != nil {
 		return err
@@ -140,6 +143,11 @@ func (h *configHandler) Save(r *http.Request, tx storage.Tx, name string, vars m
 	return nil
 }
 
+// ETag returns the revision of the config.
+func (h *configHandler) ETag() string {
+	return h.etag
+}
+
 //////////////////////////////////////////////////////////////////
 
 func (s *Service) configOptionsFactory() *handlerfactory.Options {
@@ -160,6 +168,7 @@ type configOptionsHandler struct {
 	orig  *pb.ConfigOptions
 	save  *pb.ConfigOptions
 	cfg   *pb.DamConfig
+	etag  string
 	id    *ga4gh.Identity
 	tx    storage.Tx
 }
@@ -179,6 +188,7 @@ func (h *configOptionsHandler) Setup(r *http.Request, tx storage.Tx) (int, error
 }
 func (h *configOptionsHandler) LookupItem(r *http.Request, name string, vars map[string]string) bool {
 	h.item = h.cfg.Options
+	h.etag = strconv.FormatInt(h.cfg.Revision, 10)
 	return true
 }
 func (h *configOptionsHandler) NormalizeInput(r *http.Request, name string, vars map[string]string) error {
@@ -222,6 +232,7 @@ func (h *configOptionsHandler) Save(r *http.Request, tx storage.Tx, name string,
 	if err := h.s.saveConfig(h.cfg, desc, typeName, r, h.id, h.item, h.save, h.input.Modification, h.tx); err != nil {
 		return err
 	}
+	h.etag = strconv.FormatInt(h.cfg.Revision, 10)
 	if h.orig != nil && !proto.Equal(h.orig, h.save) {
 		h.s.updateWarehouseOptions(h.save, getRealm(r), h.tx)
 		return h.s.registerProject(h.save.GcpServiceAccountProject, h.tx)
@@ -229,6 +240,11 @@ func (h *configOptionsHandler) Save(r *http.Request, tx storage.Tx, name string,
 	return nil
 }
 
+// ETag returns the revision of the config.
+func (h *configOptionsHandler) ETag() string {
+	return h.etag
+}
+
 //////////////////////////////////////////////////////////////////
 
 func (s *Service) configResourceFactory() *handlerfactory.Options {
@@ -457,7 +473,7 @@ func (h *configIssuerHandler) Setup(r *http.Request, tx storage.Tx) (int, error)
 		return status, err
 	}
 
-	sec, err := h.s.loadSecrets(tx)
+	sec, err := h.s.loadStoredSecrets(tx)
 	if err != nil {
 		return http.StatusServiceUnavailable, err
 	}
@@ -1069,24 +1085,27 @@ func (s *Service) ConfigReset(w http.ResponseWriter, r *http.Request) {
 		if getRealm(r) != storage.DefaultRealm {
 			return
 		}
-
-		conf, err := s.loadConfig(nil, storage.DefaultRealm)
-		if err != nil {
-			httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
-			return
-		}
-
-		secrets, err := s.loadSecrets(nil)
-		if err != nil {
-			httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
+		if err := s.syncHydraClients(); err != nil {
+			httputils.WriteError(w, err)
 			return
 		}
+	}
+}
 
-		if _, err := s.syncToHydra(conf.Clients, secrets.ClientSecrets, 0, nil); err != nil {
-			httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
-			return
-		}
+// syncHydraClients syncs the clients of the default realm to Hydra.
+func (s *Service) syncHydraClients() error {
+	conf, err := s.loadConfig(nil, storage.DefaultRealm)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	secrets, err := s.loadSecrets(nil)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
 	}
+	if _, err := s.syncToHydra(conf.Clients, secrets.ClientSecrets, 0, nil); err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return nil
 }
 
 // ConfigTestPersonas implements the ConfigTestPersonas RPC method.
@@ -1101,3 +1120,17 @@ func (s *Service) ConfigTestPersonas(w http.ResponseWriter, r *http.Request) {
 	}
 	httputils.WriteResp(w, out)
 }
+
+// GetTestResults runs the tests of the test personas of the configuration.
+// With "explain=true", the results have the trace of the policy evaluation of
+// each persona.
+func (s *Service) GetTestResults(w http.ResponseWriter, r *http.Request) {
+	realm := getRealm(r)
+	cfg, err := s.loadConfig(nil, realm)
+	if err != nil {
+		httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
+		return
+	}
+	explain := httputils.QueryParam(r, "explain") == "true"
+	httputils.WriteResp(w, runTests(r.Context(), cfg, nil, s.ValidateCfgOpts(realm, nil), explain))
+}
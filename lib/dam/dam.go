@@ -41,6 +41,7 @@ import (
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/adapter" /* copybara-comment: adapter */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/auditlogsapi" /* copybara-comment: auditlogsapi */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/auth" /* copybara-comment: auth */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache" /* copybara-comment: cache */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/aws" /* copybara-comment: aws */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/clouds" /* copybara-comment: clouds */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/consentsapi" /* copybara-comment: consentsapi */
@@ -51,12 +52,14 @@ import (
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/hydraproxy" /* copybara-comment: hydraproxy */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/lockstore" /* copybara-comment: lockstore */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/lro" /* copybara-comment: lro */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/oathclients" /* copybara-comment: oathclients */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/permissions" /* copybara-comment: permissions */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/persona" /* copybara-comment: persona */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/saw" /* copybara-comment: saw */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/scim" /* copybara-comment: scim */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret" /* copybara-comment: secret */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/srcutil" /* copybara-comment: srcutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/timeutil" /* copybara-comment: timeutil */
@@ -117,7 +120,14 @@ type Service struct {
 	httpClient                 *http.Client
 	startTime                  int64
 	translators                sync.Map
+	visaTypes                  sync.Map
 	visaVerifiers              sync.Map
+	passportVerifiers          sync.Map
+	signingAlgorithms          []string
+	secrets                    secret.SecretProvider
+	cache                      func() cache.Client
+	jwksCache                  *verifier.JWKSCache
+	statusLists                ga4gh.StatusListFetcher
 	useHydra                   bool
 	scim                       *scim.Scim
 	tokens                     tgrpcpb.TokensServer
@@ -185,6 +195,19 @@ type Options struct {
 	ConsentDashboardURL string
 	// LRO: the long running operation background process
 	LRO lro.LRO
+	// SigningAlgorithms: the signing algorithms accepted for visas, defaults to
+	// ga4gh.DefaultSigningAlgorithms.
+	SigningAlgorithms []string
+	// SecretProvider: resolves the secret references of the service secrets,
+	// see lib/secret. Optional if the secrets hold raw values only.
+	SecretProvider secret.SecretProvider
+	// Cache: returns a cache client, optional. Clients are closed after use.
+	Cache func() cache.Client
+	// JWKSCache: shares the keys of token issuers among verifiers, optional.
+	JWKSCache *verifier.JWKSCache
+	// StatusLists: fetches the status lists revoking visas, optional. Defaults
+	// to fetching them from the visa issuers.
+	StatusLists ga4gh.StatusListFetcher
 }
 
 // NewService create DAM service
@@ -237,11 +260,19 @@ func New(r *mux.Router, params *Options) *Service {
 		signer:                     params.Signer,
 		encryption:                 params.Encryption,
 		lro:                        params.LRO,
+		signingAlgorithms:          params.SigningAlgorithms,
+		secrets:                    params.SecretProvider,
+		cache:                      params.Cache,
+		jwksCache:                  params.JWKSCache,
+		statusLists:                params.StatusLists,
 	}
 
 	if s.httpClient == nil {
 		s.httpClient = http.DefaultClient
 	}
+	if s.statusLists == nil {
+		s.statusLists = verifier.NewStatusListCache(verifier.StatusListCacheOptions{})
+	}
 
 	exists, err := configExists(params.Store)
 	if err != nil {
@@ -283,7 +314,7 @@ func New(r *mux.Router, params *Options) *Service {
 	}
 
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.httpClient)
-	if tests := runTests(ctx, cfg, nil, s.ValidateCfgOpts(storage.DefaultRealm, nil)); hasTestError(tests) {
+	if tests := runTests(ctx, cfg, nil, s.ValidateCfgOpts(storage.DefaultRealm, nil), false); hasTestError(tests) {
 		glog.Exitf("run tests error: %v; results: %v; modification: <%v>", tests.Error, tests.TestResults, tests.Modification)
 	}
 
@@ -308,10 +339,11 @@ func New(r *mux.Router, params *Options) *Service {
 	}
 
 	a := authChecker{s: s}
-	checker := auth.NewChecker(s.logger, s.getIssuerString(), permissions.New(s.store), a.fetchClientSecrets, a.transformIdentity, false, nil)
+	checker := auth.NewChecker(s.logger, s.getIssuerString(), permissions.New(s.store), a.fetchClientSecrets, a.transformIdentity, false, s.cache, verifier.JWKSCacheOption(s.jwksCache))
 	s.checker = checker
 
 	go s.lro.Run(ctx)
+	s.watchConfig(ctx)
 
 	sh.s = s
 	sh.Handler = r
@@ -418,8 +450,23 @@ func (s *Service) upstreamTokenToPassportIdentity(ctx context.Context, cfg *pb.D
 		return nil, err
 	}
 
+	var c cache.Client
+	if s.cache != nil {
+		c = s.cache()
+		defer c.Close()
+	}
+
+	cached, err := cachedPassportIdentity(c, tok)
+	if err != nil {
+		return nil, fmt.Errorf("translating token from issuer %q: %v", iss, err)
+	}
+	if cached != nil {
+		return s.verifyIdentityVisas(ctx, cached, cfg, c)
+	}
+
 	id, err = t.TranslateToken(ctx, tok)
 	if err != nil {
+		cachePassportRejection(c, tok, err)
 		return nil, fmt.Errorf("translating token from issuer %q: %v", iss, err)
 	}
 	if ga4gh.HasUserinfoClaims(id) {
@@ -428,11 +475,23 @@ func (s *Service) upstreamTokenToPassportIdentity(ctx context.Context, cfg *pb.D
 			return nil, fmt.Errorf("fetching user info from issuer %q: %v", iss, err)
 		}
 	}
+	cachePassportIdentity(c, tok, id)
 
-	return s.populateIdentityVisas(ctx, id, cfg)
+	return s.verifyIdentityVisas(ctx, id, cfg, c)
 }
 
 func (s *Service) populateIdentityVisas(ctx context.Context, id *ga4gh.Identity, cfg *pb.DamConfig) (*ga4gh.Identity, error) {
+	var c cache.Client
+	if s.cache != nil {
+		c = s.cache()
+		defer c.Close()
+	}
+	return s.verifyIdentityVisas(ctx, id, cfg, c)
+}
+
+// verifyIdentityVisas populates the claims of the identity from its visas
+// from trusted issuers. Visa verification results are cached in c if not nil.
+func (s *Service) verifyIdentityVisas(ctx context.Context, id *ga4gh.Identity, cfg *pb.DamConfig, c cache.Client) (*ga4gh.Identity, error) {
 	// Filter visas by trusted issuers.
 	trusted := trustedIssuers(cfg.TrustedIssuers)
 	var vs []ga4gh.VisaJWT
@@ -441,8 +500,13 @@ func (s *Service) populateIdentityVisas(ctx context.Context, id *ga4gh.Identity,
 		v, err := ga4gh.NewVisaFromJWT(jwt)
 		if err != nil {
 			id.RejectVisa(nil, ga4gh.UnspecifiedVisaFormat, "invalid_visa", "", fmt.Sprintf("cannot unpack visa %d", i))
+			continue
 		}
 		d := v.Data()
+		if err := ga4gh.CheckSigningAlgorithm(string(jwt), s.signingAlgorithms); err != nil {
+			id.RejectVisa(d, v.Format(), "invalid_visa", "alg", fmt.Sprintf("visa %d: %v", i, err))
+			continue
+		}
 		if _, ok := trusted[d.Issuer]; !ok {
 			id.RejectVisa(d, v.Format(), "untrusted_issuer", "iss", fmt.Sprintf("issuer %q is not a trusted author of visas by the DAM", d.Issuer))
 			continue
@@ -450,15 +514,99 @@ func (s *Service) populateIdentityVisas(ctx context.Context, id *ga4gh.Identity,
 		vs = append(vs, jwt)
 	}
 
-	claims, _, err := ga4gh.VisasToOldClaims(ctx, vs, s.verifyVisa)
+	// Revocation is checked on every use, cached visa verification results do
+	// not cover the status of visas.
+	claims, rejected, err := ga4gh.VisasToOldClaims(ctx, vs, ga4gh.StatusCheckingVerifier(cachedVisaVerifier(c, s.verifyVisa), s.statusLists))
+	if err != nil {
+		return nil, err
+	}
+	vtvs, err := s.visaTypeValidators(cfg)
 	if err != nil {
 		return nil, err
 	}
+	rejectNonConformingVisas(id, claims, vtvs)
 	id.GA4GH = claims
+	// Report revoked visas, which would otherwise meet the policies.
+	for _, rv := range rejected {
+		if visaStatusRejections[rv.Rejection.Reason] {
+			id.RejectedVisas = append(id.RejectedVisas, rv)
+		}
+	}
 
 	return id, nil
 }
 
+// visaTypesEntry holds the visa type validators compiled from a config.
+type visaTypesEntry struct {
+	defs       map[string]*pb.VisaType
+	validators map[string]*validator.VisaTypeValidator
+}
+
+// visaTypeValidators returns the validators of the visa types of the config,
+// compiled once per config revision. A revision is looked up by its number
+// and commit time, and its visa types are compared in case configs of several
+// realms share both. The cache is dropped on config changes, see watchConfig.
+func (s *Service) visaTypeValidators(cfg *pb.DamConfig) (map[string]*validator.VisaTypeValidator, error) {
+	key := fmt.Sprintf("%d/%v", cfg.Revision, cfg.CommitTime)
+	if v, ok := s.visaTypes.Load(key); ok {
+		if e := v.(*visaTypesEntry); visaTypesEqual(e.defs, cfg.VisaTypes) {
+			return e.validators, nil
+		}
+	}
+	vtvs, err := validator.VisaTypeValidators(cfg.VisaTypes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid visa types: %v", err)
+	}
+	defs := make(map[string]*pb.VisaType, len(cfg.VisaTypes))
+	for name, vt := range cfg.VisaTypes {
+		defs[name] = proto.Clone(vt).(*pb.VisaType)
+	}
+	s.visaTypes.Store(key, &visaTypesEntry{defs: defs, validators: vtvs})
+	return vtvs, nil
+}
+
+func visaTypesEqual(a, b map[string]*pb.VisaType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, vt := range a {
+		if !proto.Equal(vt, b[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// rejectNonConformingVisas removes from the claims the visas which do not
+// conform to the constraints of their visa type, and reports them as rejected
+// visas of the identity.
+func rejectNonConformingVisas(id *ga4gh.Identity, claims map[string][]ga4gh.OldClaim, vtvs map[string]*validator.VisaTypeValidator) {
+	for typ, vtv := range vtvs {
+		var conforming []ga4gh.OldClaim
+		for _, c := range claims[typ] {
+			reason, field, err := vtv.Check(c)
+			if err != nil {
+				id.RejectVisa(c.VisaData, c.TokenFormat, reason, field, err.Error())
+				continue
+			}
+			conforming = append(conforming, c)
+		}
+		if len(conforming) == 0 {
+			delete(claims, typ)
+			continue
+		}
+		claims[typ] = conforming
+	}
+}
+
+// visaStatusRejections are the reasons of visas rejected by their status list.
+var visaStatusRejections = map[string]bool{
+	ga4gh.VisaRevokedReason:           true,
+	ga4gh.VisaSuspendedReason:         true,
+	ga4gh.StatusListUnavailableReason: true,
+	ga4gh.StatusListUntrustedReason:   true,
+}
+
 func (s *Service) verifyVisa(ctx context.Context, token, issuer, jku string) error {
 	v, err := s.getVisaVerifier(ctx, issuer, jku)
 	if err != nil {
@@ -479,7 +627,7 @@ func (s *Service) getVisaVerifier(ctx context.Context, issuer, jku string) (*ver
 		}
 		return v, nil
 	}
-	v, err := verifier.NewVisaVerifier(ctx, issuer, jku, "")
+	v, err := verifier.NewVisaVerifier(ctx, issuer, jku, "", verifier.AlgorithmsOption(s.signingAlgorithms), verifier.JWKSCacheOption(s.jwksCache))
 	if err != nil {
 		return nil, err
 	}
@@ -487,6 +635,74 @@ func (s *Service) getVisaVerifier(ctx context.Context, issuer, jku string) (*ver
 	return v, nil
 }
 
+// selfContainedPassportIdentity verifies a passport JWT of a trusted issuer and
+// populates the claims of its identity from its visas. Returns the name of
+// the trusted issuer of the passport.
+func (s *Service) selfContainedPassportIdentity(ctx context.Context, cfg *pb.DamConfig, tok string) (*ga4gh.Identity, string, error) {
+	p, err := ga4gh.NewSelfContainedPassportFromJWT(ga4gh.SelfContainedPassportJWT(tok))
+	if err != nil {
+		return nil, "", status.Errorf(codes.Unauthenticated, "inspecting passport: %v", err)
+	}
+	d := p.Data()
+
+	// Use the first trusted issuer by name for the account of the passport.
+	var names []string
+	for name, ti := range cfg.TrustedIssuers {
+		if ti.Issuer == d.Issuer {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	brokerName := ""
+	if len(names) > 0 {
+		brokerName = names[0]
+	}
+	if len(brokerName) == 0 {
+		return nil, "", errutil.WithErrorReason(errUntrustedIssuer, status.Errorf(codes.Unauthenticated, "passport issuer %q is not a trusted issuer of the DAM", d.Issuer))
+	}
+
+	v, err := s.getPassportVerifier(ctx, d.Issuer, p.JKU(), cfg.TrustedIssuers[brokerName].ClientId)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := v.Verify(ctx, tok); err != nil {
+		return nil, "", err
+	}
+
+	id := &ga4gh.Identity{
+		Issuer:     d.Issuer,
+		Subject:    d.Subject,
+		Expiry:     d.ExpiresAt,
+		ID:         d.ID,
+		Identities: map[string][]string{},
+	}
+	for _, visa := range d.Visas {
+		id.VisaJWTs = append(id.VisaJWTs, string(visa))
+	}
+	id, err = s.populateIdentityVisas(ctx, id, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return id, brokerName, nil
+}
+
+func (s *Service) getPassportVerifier(ctx context.Context, issuer, jku, clientID string) (*verifier.SelfContainedPassportVerifier, error) {
+	key := issuer + " " + jku + " " + clientID
+	if cached, ok := s.passportVerifiers.Load(key); ok {
+		v, ok := cached.(*verifier.SelfContainedPassportVerifier)
+		if !ok {
+			return nil, fmt.Errorf("verifier type is wrong")
+		}
+		return v, nil
+	}
+	v, err := verifier.NewSelfContainedPassportVerifier(ctx, issuer, jku, clientID, verifier.AlgorithmsOption(s.signingAlgorithms), verifier.JWKSCacheOption(s.jwksCache))
+	if err != nil {
+		return nil, err
+	}
+	s.passportVerifiers.Store(key, v)
+	return v, nil
+}
+
 func trustedIssuers(trustedIssuers map[string]*pb.TrustedIssuer) map[string]bool {
 	trusted := make(map[string]bool)
 	for _, tpi := range trustedIssuers {
@@ -508,20 +724,27 @@ func (s *Service) getPassportIdentity(cfg *pb.DamConfig, tx storage.Tx, r *http.
 	return id, http.StatusOK, nil
 }
 
-func testPersona(ctx context.Context, personaName string, resources []string, cfg *pb.DamConfig, vopts ValidateCfgOpts) (string, []string, []*ga4gh.RejectedVisa, error) {
+// testPersona tests the access of the persona to the resources. If explain,
+// it also returns the trace of the policy evaluation.
+func testPersona(ctx context.Context, personaName string, resources []string, cfg *pb.DamConfig, vopts ValidateCfgOpts, explain bool) (string, []string, []*ga4gh.RejectedVisa, *ga4gh.Trace, error) {
 	p := cfg.TestPersonas[personaName]
 	id, err := persona.ToIdentity(ctx, personaName, p, defaultPersonaScope, "")
 	if err != nil {
-		return "INVALID", nil, nil, err
+		return "INVALID", nil, nil, nil, err
+	}
+	var trace *ga4gh.Trace
+	if explain {
+		ctx, trace = withPassportTrace(ctx, personaName, id)
 	}
 	state, got, err := resolveAccessList(ctx, id, resources, nil, nil, cfg, vopts)
 	if err != nil {
-		return state, got, id.RejectedVisas, err
+		return state, got, id.RejectedVisas, trace, err
 	}
 	if reflect.DeepEqual(p.Access, got) || (len(p.Access) == 0 && len(got) == 0) {
-		return "PASSED", got, id.RejectedVisas, nil
+		trace.SetResult(true)
+		return "PASSED", got, id.RejectedVisas, trace, nil
 	}
-	return "FAILED", got, id.RejectedVisas, fmt.Errorf("access does not match expectations")
+	return "FAILED", got, id.RejectedVisas, trace, fmt.Errorf("access does not match expectations")
 }
 
 // syncToHydra pushes the configuration of clients and secrets to Hydra.
@@ -538,9 +761,14 @@ func (s *Service) syncToHydra(clients map[string]*cpb.Client, secrets map[string
 		return nil, fmt.Errorf("hydra sync has completed recently or is active")
 	}
 	if tx == nil {
-		// Is a new tx (i.e. ltx didn't override tx)
+		// Is a new tx (i.e. ltx didn't override tx), otherwise the lock is
+		// released when tx finishes.
 		defer ltx.Finish()
 	}
+	// Do not overwrite the clients pushed by a newer holder of the lock.
+	if err := lockstore.CheckFencingToken(ltx); err != nil {
+		return nil, err
+	}
 	state, err := oathclients.SyncClients(s.httpClient, s.hydraAdminURL, clients, secrets)
 	if err != nil {
 		glog.Errorf("failed to sync hydra clients: %v", err)
@@ -596,7 +824,21 @@ func (s *Service) makeAccessList(id *ga4gh.Identity, resources, views, roles []s
 	return got
 }
 
-func checkAuthorization(ctx context.Context, id *ga4gh.Identity, ttl time.Duration, resourceName, viewName, roleName string, cfg *pb.DamConfig, client string, vopts ValidateCfgOpts) error {
+// withPassportTrace returns a ctx recording the evaluation of policies for the
+// identity into the returned trace, which starts with the visas of the
+// identity rejected before policies are evaluated.
+func withPassportTrace(ctx context.Context, name string, id *ga4gh.Identity) (context.Context, *ga4gh.Trace) {
+	ctx, trace := ga4gh.WithTrace(ctx, ga4gh.TracePassport, name)
+	for _, rv := range id.RejectedVisas {
+		trace.RejectVisa(rv.Issuer, rv.Assertion, rv.Rejection.Reason, rv.Rejection.Field, rv.Rejection.Description)
+	}
+	return ctx, trace
+}
+
+func checkAuthorization(ctx context.Context, id *ga4gh.Identity, ttl time.Duration, resourceName, viewName, roleName string, cfg *pb.DamConfig, client string, vopts ValidateCfgOpts) (err error) {
+	ctx, trace := ga4gh.StartTrace(ctx, ga4gh.TraceResource, resourceName+"/"+viewName+"/"+roleName)
+	defer func() { trace.SetResult(err == nil) }()
+
 	if stat := checkTrustedIssuer(id.Issuer, cfg, vopts); stat != nil {
 		return errutil.WithErrorReason(errUntrustedIssuer, stat.Err())
 	}
@@ -633,8 +875,10 @@ func checkAuthorization(ctx context.Context, id *ga4gh.Identity, ttl time.Durati
 
 		ctxWithTTL := context.WithValue(ctx, validator.RequestTTLInNanoFloat64, float64(ttl.Nanoseconds())/1e9)
 		for _, p := range vRole.Policies {
+			pctx, pt := ga4gh.StartTrace(ctxWithTTL, ga4gh.TracePolicy, p.Name)
 			if p.Name == allowlistPolicyName {
 				ok, err := checkAllowlist(p.Args, id, cfg, vopts)
+				pt.SetResult(ok)
 				if err != nil {
 					return errutil.WithErrorReason(errAllowlistUnavailable, status.Errorf(codes.PermissionDenied, "unauthorized for resource %q view %q role %q (allowlist unavailable): %v", resourceName, viewName, roleName, err))
 				}
@@ -649,7 +893,8 @@ func checkAuthorization(ctx context.Context, id *ga4gh.Identity, ttl time.Durati
 			if err != nil {
 				return errutil.WithErrorReason(errCannotEnforcePolicies, status.Errorf(codes.PermissionDenied, "cannot enforce policies for resource %q view %q role %q: %v", resourceName, viewName, roleName, err))
 			}
-			ok, err = v.Validate(ctxWithTTL, id)
+			ok, err = v.Validate(pctx, id)
+			pt.SetResult(ok)
 			if err != nil {
 				// Strip internal error in case it contains any sensitive data.
 				return errutil.WithErrorReason(errCannotValidateIdentity, status.Errorf(codes.PermissionDenied, "cannot validate identity (subject %q, issuer %q): internal error", id.Subject, id.Issuer))
@@ -1293,7 +1538,29 @@ func (s *Service) saveSecrets(secrets *pb.DamSecrets, desc, resType string, r *h
 	return nil
 }
 
+// loadSecrets loads the service secrets with the secret references resolved
+// to their values. The result must not be saved, use loadStoredSecrets to
+// modify the secrets.
 func (s *Service) loadSecrets(tx storage.Tx) (*pb.DamSecrets, error) {
+	stored, err := s.loadStoredSecrets(tx)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve on a copy, storage may cache the stored secrets.
+	secrets := proto.Clone(stored).(*pb.DamSecrets)
+	ctx := context.Background()
+	if err := secret.ResolveMap(ctx, s.secrets, secrets.ClientSecrets); err != nil {
+		return nil, err
+	}
+	if err := secret.ResolveMap(ctx, s.secrets, secrets.BrokerSecrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// loadStoredSecrets loads the service secrets as stored, secret references
+// are not resolved.
+func (s *Service) loadStoredSecrets(tx storage.Tx) (*pb.DamSecrets, error) {
 	secrets := &pb.DamSecrets{}
 	_, err := s.realmReadTx(storage.SecretsDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, secrets, tx)
 	if err != nil {
@@ -1507,7 +1774,11 @@ func registerHandlers(r *mux.Router, s *Service) {
 	r.HandleFunc(configHistoryPath, auth.MustWithAuth(s.ConfigHistory, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
 	r.HandleFunc(configHistoryRevisionPath, auth.MustWithAuth(s.ConfigHistoryRevision, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
 	r.HandleFunc(configResetPath, auth.MustWithAuth(s.ConfigReset, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
+	bh := s.backupHandler()
+	r.HandleFunc(backupPath, auth.MustWithAuth(bh.Export, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
+	r.HandleFunc(backupPath, auth.MustWithAuth(bh.Restore, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodPost)
 	r.HandleFunc(configTestPersonasPath, auth.MustWithAuth(s.ConfigTestPersonas, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
+	r.HandleFunc(testPath, auth.MustWithAuth(s.GetTestResults, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
 	r.HandleFunc(configPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.configFactory()), s.checker, auth.RequireAdminTokenClientCredential))
 	r.HandleFunc(configOptionsPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.configOptionsFactory()), s.checker, auth.RequireAdminTokenClientCredential))
 	r.HandleFunc(configResourcePath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.configResourceFactory()), s.checker, auth.RequireAdminTokenClientCredential))
@@ -1541,7 +1812,8 @@ func registerHandlers(r *mux.Router, s *Service) {
 	// oidc auth callback endpoint
 	r.HandleFunc(loggedInPath, auth.MustWithAuth(s.LoggedInHandler, s.checker, auth.RequireNone)).Methods(http.MethodGet)
 
-	// resource token exchange endpoint
+	// resource token exchange endpoint, with a passport JWT or the token of a login flow
+	r.HandleFunc(resourceTokensPath, auth.MustWithAuth(s.PassportResourceTokens, s.checker, auth.RequireClientIDAndSecret)).Methods(http.MethodPost).MatcherFunc(hasPassport)
 	r.HandleFunc(resourceTokensPath, auth.MustWithAuth(s.ResourceTokens, s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet, http.MethodPost)
 
 	// token service endpoints
@@ -1551,7 +1823,7 @@ func registerHandlers(r *mux.Router, s *Service) {
 	// consents service endpoints
 	consentService := s.consentService()
 	r.HandleFunc(listConsentPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), consentsapi.ListConsentsFactory(consentService, listConsentPath)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet)
-	r.HandleFunc(deleteConsentPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), consentsapi.DeleteConsentFactory(consentService, deleteConsentPath, false)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodDelete)
+	r.HandleFunc(deleteConsentPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), consentsapi.DeleteConsentFactory(consentService, deleteConsentPath, false)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet, http.MethodDelete)
 
 	// audit logs endpoints
 	r.HandleFunc(auditlogsPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.store, auditlogsapi.ListAuditlogsPathFactory(auditlogsPath, s.auditlogs)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet)
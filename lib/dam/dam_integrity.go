@@ -205,6 +205,9 @@ func checkBasicIntegrity(cfg *pb.DamConfig, vopts ValidateCfgOpts) *status.Statu
 		if path, err := check.CheckUI(def.Ui, true); err != nil {
 			return httputils.NewInfoStatus(codes.InvalidArgument, httputils.StatusPath(cfgVisaTypes, n, path), fmt.Sprintf("claim definitions UI settings: %v", err))
 		}
+		if path, err := validator.ValidateVisaType(n, def); err != nil {
+			return httputils.NewInfoStatus(codes.InvalidArgument, httputils.StatusPath(cfgVisaTypes, n, path), err.Error())
+		}
 	}
 
 	personaEmail := make(map[string]string)
@@ -544,7 +547,7 @@ func configCheckIntegrity(cfg *pb.DamConfig, mod *pb.ConfigModification, r *http
 	if stat := checkBasicIntegrity(cfg, vopts); stat != nil {
 		return stat
 	}
-	if tests := runTests(r.Context(), cfg, nil, vopts); hasTestError(tests) {
+	if tests := runTests(r.Context(), cfg, nil, vopts, false); hasTestError(tests) {
 		stat := httputils.NewStatus(codes.FailedPrecondition, tests.Error)
 		return httputils.AddStatusDetails(stat, tests.Modification)
 	}
@@ -613,7 +616,7 @@ func updateTests(cfg *pb.DamConfig, modification *pb.ConfigModification, vopts V
 	return nil
 }
 
-func runTests(ctx context.Context, cfg *pb.DamConfig, resources []string, vopts ValidateCfgOpts) *pb.GetTestResultsResponse {
+func runTests(ctx context.Context, cfg *pb.DamConfig, resources []string, vopts ValidateCfgOpts, explain bool) *pb.GetTestResultsResponse {
 	t := float64(time.Now().UnixNano()) / 1e9
 	personas := make(map[string]*cpb.TestPersona)
 	results := make([]*pb.GetTestResultsResponse_TestResult, 0)
@@ -634,7 +637,7 @@ func runTests(ctx context.Context, cfg *pb.DamConfig, resources []string, vopts
 			Passport: p.Passport,
 			Access:   p.Access,
 		}
-		status, got, rejectedVisas, err := testPersona(ctx, pname, resources, cfg, vopts)
+		status, got, rejectedVisas, trace, err := testPersona(ctx, pname, resources, cfg, vopts, explain)
 		e := ""
 		if err == nil {
 			passed++
@@ -647,6 +650,7 @@ func runTests(ctx context.Context, cfg *pb.DamConfig, resources []string, vopts
 			Access:        got,
 			RejectedVisas: makeRejectedVisas(rejectedVisas),
 			Error:         e,
+			Explanation:   makeTrace(trace),
 		})
 		calculateModification(pname, p.Access, got, modification, vopts)
 	}
@@ -687,6 +691,37 @@ func makeRejectedVisas(rejected []*ga4gh.RejectedVisa) []*pb.GetTestResultsRespo
 	return out
 }
 
+func makeTrace(trace *ga4gh.Trace) *pb.GetTestResultsResponse_Trace {
+	if trace == nil {
+		return nil
+	}
+	out := &pb.GetTestResultsResponse_Trace{
+		Kind:   trace.Kind,
+		Name:   trace.Name,
+		Result: trace.Result,
+	}
+	for _, v := range trace.Visas {
+		tv := &pb.GetTestResultsResponse_Trace_Visa{
+			Issuer:   v.Issuer,
+			VisaType: string(v.Assertion.Type),
+			Source:   string(v.Assertion.Source),
+			Value:    string(v.Assertion.Value),
+			By:       string(v.Assertion.By),
+			Matched:  v.Matched,
+		}
+		if v.Rejection != nil {
+			tv.Reason = v.Rejection.Reason
+			tv.Field = v.Rejection.Field
+			tv.Description = v.Rejection.Description
+		}
+		out.Visas = append(out.Visas, tv)
+	}
+	for _, c := range trace.Children {
+		out.Children = append(out.Children, makeTrace(c))
+	}
+	return out
+}
+
 func hasTestError(tr *pb.GetTestResultsResponse) bool {
 	return len(tr.Error) > 0
 }
@@ -123,6 +123,20 @@ func TestCheckIntegrity_BadCfg(t *testing.T) {
 			},
 			want: codes.InvalidArgument,
 		},
+		{
+			desc: "invalid visa type value schema",
+			mutation: func(cfg *pb.DamConfig) {
+				cfg.VisaTypes["ControlledAccessGrants"].ValueSchema = `{"type": "url"}`
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "policy value not conforming to visa type",
+			mutation: func(cfg *pb.DamConfig) {
+				cfg.VisaTypes["AcceptedTermsAndPolicies"].ValueRegex = `https://doi\.org/.*`
+			},
+			want: codes.InvalidArgument,
+		},
 		{
 			desc: "bad gcpIamBillingProject option value",
 			mutation: func(cfg *pb.DamConfig) {
@@ -51,12 +51,14 @@ import (
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/fakeencryption" /* copybara-comment: fakeencryption */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/persona" /* copybara-comment: persona */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret" /* copybara-comment: secret */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/serviceinfo" /* copybara-comment: serviceinfo */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakehydra" /* copybara-comment: fakehydra */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakelro" /* copybara-comment: fakelro */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakeoidcissuer" /* copybara-comment: fakeoidcissuer */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakesdl" /* copybara-comment: fakesdl */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakestatuslist" /* copybara-comment: fakestatuslist */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/httptestclient" /* copybara-comment: httptestclient */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test" /* copybara-comment: test */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/testhttp" /* copybara-comment: testhttp */
@@ -170,6 +172,21 @@ func TestHandlers(t *testing.T) {
 			})},
 			Status: http.StatusOK,
 		},
+		{
+			Method:  "GET",
+			Path:    "/dam/v1alpha/master/tests",
+			Persona: "admin",
+			Output:  `^\{"version":.*"testResults":\[\{"name":[^{]*"result":"PASSED"`,
+			Status:  http.StatusOK,
+		},
+		{
+			Method:  "GET",
+			Path:    "/dam/v1alpha/master/tests",
+			Params:  "explain=true",
+			Persona: "admin",
+			Output:  `^.*"explanation":\{"kind":"passport"`,
+			Status:  http.StatusOK,
+		},
 		{
 			Method:  "GET",
 			Path:    "/dam/v1alpha/master/processes",
@@ -1239,6 +1256,60 @@ func TestCheckAuthorization_UntrustedIssuer(t *testing.T) {
 	}
 }
 
+func TestCheckAuthorization_NonConformingVisa(t *testing.T) {
+	auth := setupAuthorizationTest(t)
+	// The visa of dr_joe_elixir is "by": "peer".
+	auth.cfg.VisaTypes["ResearcherStatus"].By = []string{"so", "system"}
+
+	id, err := auth.dam.populateIdentityVisas(auth.ctx, auth.id, auth.cfg)
+	if err != nil {
+		t.Fatalf("unable to obtain passport identity: %v", err)
+	}
+	if _, ok := id.GA4GH["ResearcherStatus"]; ok {
+		t.Errorf("id.GA4GH[ResearcherStatus] has the non-conforming visa, want removed")
+	}
+	found := false
+	for _, rv := range id.RejectedVisas {
+		if rv.Rejection.Reason == validator.VisaByInvalidReason && rv.Rejection.Field == "visa.by" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("id.RejectedVisas = %+v, want a visa rejected with reason %q", id.RejectedVisas, validator.VisaByInvalidReason)
+	}
+
+	err = checkAuthorization(auth.ctx, id, auth.ttl, auth.resource, auth.view, auth.role, auth.cfg, test.TestClientID, auth.dam.ValidateCfgOpts(storage.DefaultRealm, nil))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("checkAuthorization(ctx, id, %v, %q, %q, %q, cfg, %q) failed, expected %d, got: %v", auth.ttl, auth.resource, auth.view, auth.role, test.TestClientID, codes.PermissionDenied, err)
+	}
+}
+
+func TestVisaTypeValidators(t *testing.T) {
+	auth := setupAuthorizationTest(t)
+
+	first, err := auth.dam.visaTypeValidators(auth.cfg)
+	if err != nil {
+		t.Fatalf("visaTypeValidators() failed: %v", err)
+	}
+	got, err := auth.dam.visaTypeValidators(auth.cfg)
+	if err != nil {
+		t.Fatalf("visaTypeValidators() failed: %v", err)
+	}
+	if got["ResearcherStatus"] != first["ResearcherStatus"] {
+		t.Errorf("visaTypeValidators() of the same config revision compiled the visa types again")
+	}
+
+	// Visa types which changed without a new revision are not taken from the cache.
+	auth.cfg.VisaTypes["ResearcherStatus"].By = []string{"so", "system"}
+	got, err = auth.dam.visaTypeValidators(auth.cfg)
+	if err != nil {
+		t.Fatalf("visaTypeValidators() failed: %v", err)
+	}
+	if got["ResearcherStatus"] == first["ResearcherStatus"] {
+		t.Errorf("visaTypeValidators() of changed visa types returned the cached validators")
+	}
+}
+
 func TestCheckAuthorization_ResourceNotFound(t *testing.T) {
 	auth := setupAuthorizationTest(t)
 	auth.cfg.Resources = nil
@@ -2495,8 +2566,8 @@ func TestLoggedIn_Hydra_Error_Log(t *testing.T) {
 		t.Errorf("Labels[pass_auth_check] = %s want %s", got.Labels["error_type"], errRejectedPolicy)
 	}
 
-	if len(got.GetTextPayload()) == 0 {
-		t.Errorf("got.GetTextPayload() want not empty")
+	if len(policyLogMessage(got)) == 0 {
+		t.Errorf("policyLogMessage() want not empty")
 	}
 
 	wantRejectedPolicy := &cpb.RejectedPolicy{
@@ -2506,7 +2577,7 @@ func TestLoggedIn_Hydra_Error_Log(t *testing.T) {
 	}
 
 	gotRejectedPolicy := &cpb.RejectedPolicy{}
-	if err := jsonpb.UnmarshalString(got.GetTextPayload(), gotRejectedPolicy); err != nil {
+	if err := jsonpb.UnmarshalString(policyLogMessage(got), gotRejectedPolicy); err != nil {
 		t.Fatalf("Unmarshal() failed: %v", err)
 	}
 
@@ -2518,6 +2589,148 @@ func TestLoggedIn_Hydra_Error_Log(t *testing.T) {
 	}
 }
 
+// policyLogMessage returns the message of a policy decision log entry.
+func policyLogMessage(e *lepb.LogEntry) string {
+	if msg, ok := e.GetJsonPayload().GetFields()[auditlog.PolicyMessageField]; ok {
+		return msg.GetStringValue()
+	}
+	return e.GetTextPayload()
+}
+
+func TestExplainDecision(t *testing.T) {
+	newTrace := func(visas, children int) *ga4gh.Trace {
+		trace := &ga4gh.Trace{Kind: ga4gh.TracePassport, Name: "dr_joe"}
+		for i := 0; i < visas; i++ {
+			trace.RejectVisa("https://issuer.example.com", ga4gh.Assertion{Type: "ResearcherStatus", Value: "https://example.com/researcher"}, ga4gh.UntrustedIssuerReason, "iss", "issuer not trusted")
+		}
+		for i := 0; i < children; i++ {
+			trace.Children = append(trace.Children, &ga4gh.Trace{Kind: ga4gh.TraceResource, Name: strings.Repeat("r", 1000)})
+		}
+		return trace
+	}
+	visas := func(explanation json.RawMessage) int {
+		trace := &pb.GetTestResultsResponse_Trace{}
+		if err := jsonpb.UnmarshalString(string(explanation), trace); err != nil {
+			t.Fatalf("Unmarshal(explanation) failed: %v", err)
+		}
+		return len(trace.Visas)
+	}
+
+	if got := visas(explainDecision(newTrace(2, 0), false)); got != 2 {
+		t.Errorf("explainDecision() has %d visas, want 2", got)
+	}
+	if got := visas(explainDecision(newTrace(2, 0), true)); got != 0 {
+		t.Errorf("explainDecision() hiding reject details has %d visas, want 0", got)
+	}
+	// Visas are left out of a trace too large.
+	if got := visas(explainDecision(newTrace(1000, 0), false)); got != 0 {
+		t.Errorf("explainDecision() of a large trace has %d visas, want 0", got)
+	}
+	if got := explainDecision(newTrace(0, 100), false); got != nil {
+		t.Errorf("explainDecision() of a trace too large = %d bytes, want nil", len(got))
+	}
+	if got := explainDecision(nil, false); got != nil {
+		t.Errorf("explainDecision(nil) = %s, want nil", got)
+	}
+}
+
+func TestLoggedIn_Hydra_Error_Log_Explanation(t *testing.T) {
+	s, cfg, _, h, _, err := setupHydraTest(true)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+	logs, close := fakesdl.New()
+	defer close()
+	s.logger = logs.Client
+
+	h.RejectLoginResp = &hydraapi.RequestHandlerResponse{RedirectTo: hydraPublicURL}
+
+	sendLoggedIn(t, s, cfg, h, "dr_joe_era_commons", "", storage.DefaultRealm, loginStateID, pb.ResourceTokenRequestState_DATASET)
+
+	logs.Client.Close()
+
+	got := logs.Server.Logs[0].Entries[0]
+	if _, ok := got.Labels[auditlog.PolicyExplanationField]; ok {
+		t.Errorf("Labels[%s] is set, want the explanation in the payload only", auditlog.PolicyExplanationField)
+	}
+	explanation, err := (&jsonpb.Marshaler{}).MarshalToString(got.GetJsonPayload().GetFields()[auditlog.PolicyExplanationField])
+	if err != nil {
+		t.Fatalf("Marshal(payload explanation) failed: %v", err)
+	}
+	trace := &pb.GetTestResultsResponse_Trace{}
+	if err := jsonpb.UnmarshalString(explanation, trace); err != nil {
+		t.Fatalf("Unmarshal(payload explanation) failed: %v", err)
+	}
+	if trace.Kind != ga4gh.TracePassport || trace.Result {
+		t.Errorf("trace = %s:%v, want %s:false", trace.Kind, trace.Result, ga4gh.TracePassport)
+	}
+	if len(trace.Children) != 1 {
+		t.Fatalf("trace children = %v, want the requested resource", trace.Children)
+	}
+	res := trace.Children[0]
+	if res.Kind != ga4gh.TraceResource || res.Name != "ga4gh-apis/gcs_read/viewer" || res.Result {
+		t.Errorf("resource trace = %s:%s:%v, want %s:ga4gh-apis/gcs_read/viewer:false", res.Kind, res.Name, res.Result, ga4gh.TraceResource)
+	}
+	if len(res.Children) == 0 || res.Children[0].Kind != ga4gh.TracePolicy || res.Children[0].Result {
+		t.Errorf("resource trace children = %v, want a failed policy", res.Children)
+	}
+}
+
+func TestRunTests_Explain(t *testing.T) {
+	s, cfg, _, _, _, err := setupHydraTest(true)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+
+	tests := runTests(context.Background(), cfg, nil, s.ValidateCfgOpts(storage.DefaultRealm, nil), true)
+	if hasTestError(tests) {
+		t.Fatalf("runTests() failed: %v", tests.Error)
+	}
+
+	// Visas of the trace of the resources, by whether access was granted.
+	visas := map[bool][]*pb.GetTestResultsResponse_Trace_Visa{}
+	var collect func(trace *pb.GetTestResultsResponse_Trace, granted bool)
+	collect = func(trace *pb.GetTestResultsResponse_Trace, granted bool) {
+		visas[granted] = append(visas[granted], trace.Visas...)
+		for _, c := range trace.Children {
+			collect(c, granted)
+		}
+	}
+	for _, tr := range tests.TestResults {
+		trace := tr.Explanation
+		if trace == nil || trace.Kind != ga4gh.TracePassport || trace.Name != tr.Name || !trace.Result {
+			t.Fatalf("test result %q explanation = %v, want the passport trace of the passed test", tr.Name, trace)
+		}
+		for _, res := range trace.Children {
+			collect(res, res.Result)
+		}
+	}
+
+	matched := func(vs []*pb.GetTestResultsResponse_Trace_Visa) bool {
+		for _, v := range vs {
+			if v.Matched {
+				return true
+			}
+		}
+		return false
+	}
+	if !matched(visas[true]) {
+		t.Errorf("traces of granted resources have no matched visas")
+	}
+	for _, v := range visas[false] {
+		if !v.Matched && len(v.Reason) == 0 {
+			t.Errorf("trace visa %v is not matched and has no reason", v)
+		}
+	}
+
+	tests = runTests(context.Background(), cfg, nil, s.ValidateCfgOpts(storage.DefaultRealm, nil), false)
+	for _, tr := range tests.TestResults {
+		if tr.Explanation != nil {
+			t.Errorf("test result %q explanation = %v, want none without explain", tr.Name, tr.Explanation)
+		}
+	}
+}
+
 func TestLoggedIn_Endpoint_Hydra_Success(t *testing.T) {
 	s, cfg, _, h, _, err := setupHydraTest(true)
 	if err != nil {
@@ -3155,6 +3368,196 @@ func TestResourceTokens_TokenExpiry(t *testing.T) {
 	}
 }
 
+func sendPassportResourceTokens(t *testing.T, s *Service, broker *persona.Server, pname string, d *ga4gh.SelfContainedPassportData) *http.Response {
+	t.Helper()
+
+	if d == nil {
+		p := broker.Config().TestPersonas[pname]
+		id, err := persona.ToIdentity(context.Background(), pname, p, persona.DefaultScope, hydraPublicURL)
+		if err != nil {
+			t.Fatalf("persona.ToIdentity() failed: %v", err)
+		}
+		d = &ga4gh.SelfContainedPassportData{
+			StdClaims: ga4gh.StdClaims{
+				Issuer:    hydraPublicURL,
+				Subject:   pname,
+				Audience:  ga4gh.NewAudience(test.TestClientID),
+				IssuedAt:  time.Now().Unix(),
+				ExpiresAt: time.Now().Add(time.Hour).Unix(),
+				ID:        "passport-1234",
+			},
+		}
+		for _, v := range id.VisaJWTs {
+			d.Visas = append(d.Visas, ga4gh.VisaJWT(v))
+		}
+	}
+	passport, err := ga4gh.NewSelfContainedPassportFromData(context.Background(), d, ga4gh.JWTEmptyJKU, localsign.New(&testkeys.PersonaBrokerKey))
+	if err != nil {
+		t.Fatalf("ga4gh.NewSelfContainedPassportFromData() failed: %v", err)
+	}
+
+	q := url.Values{
+		"client_id":     []string{test.TestClientID},
+		"client_secret": []string{test.TestClientSecret},
+		"passport":      []string{string(passport.JWT())},
+		"resource":      []string{"https://test.org/dam/master/resources/ga4gh-apis/views/gcs_read/roles/viewer/interfaces/http:gcp:gs"},
+		"ttl":           []string{"1h"},
+	}
+	return testhttp.SendTestRequest(t, s.Handler, http.MethodPost, resourceTokensPath, q, nil, nil)
+}
+
+func TestPassportResourceTokens(t *testing.T) {
+	s, _, _, _, broker, err := setupHydraTest(true)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+
+	resp := sendPassportResourceTokens(t, s, broker, "dr_joe_elixir", nil)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("status = %d, wants %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+
+	got := &pb.ResourceResults{}
+	if err := jsonpb.Unmarshal(resp.Body, got); err != nil {
+		t.Fatalf("jsonpb.Unmarshal() failed: %v", err)
+	}
+	if len(got.Access) != 1 || len(got.Access["0"].GetCredentials()) == 0 {
+		t.Errorf("access = %v, want credentials for the resource", got.Access)
+	}
+}
+
+func TestPassportResourceTokens_Rejected(t *testing.T) {
+	s, _, _, _, broker, err := setupHydraTest(true)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name  string
+		pname string
+		data  *ga4gh.SelfContainedPassportData
+		want  int
+	}{
+		{
+			name:  "missing visas",
+			pname: "non-admin",
+			want:  http.StatusForbidden,
+		},
+		{
+			name: "untrusted issuer",
+			data: &ga4gh.SelfContainedPassportData{
+				StdClaims: ga4gh.StdClaims{Issuer: "https://untrusted.example.com", Subject: "dr_joe_elixir", ExpiresAt: now.Add(time.Hour).Unix()},
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "expired",
+			data: &ga4gh.SelfContainedPassportData{
+				StdClaims: ga4gh.StdClaims{Issuer: hydraPublicURL, Subject: "dr_joe_elixir", ExpiresAt: now.Add(-time.Hour).Unix()},
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "missing audience",
+			data: &ga4gh.SelfContainedPassportData{
+				StdClaims: ga4gh.StdClaims{Issuer: hydraPublicURL, Subject: "dr_joe_elixir", ExpiresAt: now.Add(time.Hour).Unix()},
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "other audience",
+			data: &ga4gh.SelfContainedPassportData{
+				StdClaims: ga4gh.StdClaims{Issuer: hydraPublicURL, Subject: "dr_joe_elixir", Audience: ga4gh.NewAudience("other"), ExpiresAt: now.Add(time.Hour).Unix()},
+			},
+			want: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := sendPassportResourceTokens(t, s, broker, tc.pname, tc.data)
+			if resp.StatusCode != tc.want {
+				t.Errorf("status = %d, wants %d", resp.StatusCode, tc.want)
+			}
+		})
+	}
+}
+
+func TestPassportResourceTokens_RevokedVisa(t *testing.T) {
+	s, cfg, _, _, _, err := setupHydraTest(true)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+	lists := fakestatuslist.New()
+	s.statusLists = lists
+	logs, close := fakesdl.New()
+	defer close()
+	s.logger = logs.Client
+
+	// Add a status to the visas of the persona, and revoke its ResearcherStatus.
+	ctx := context.Background()
+	pname := "dr_joe_elixir"
+	id, err := persona.ToIdentity(ctx, pname, cfg.TestPersonas[pname], persona.DefaultScope, hydraPublicURL)
+	if err != nil {
+		t.Fatalf("persona.ToIdentity() failed: %v", err)
+	}
+	uri := strings.TrimSuffix(hydraPublicURL, "/") + "/statuslists/1"
+	d := &ga4gh.SelfContainedPassportData{
+		StdClaims: ga4gh.StdClaims{
+			Issuer:    hydraPublicURL,
+			Subject:   pname,
+			Audience:  ga4gh.NewAudience(test.TestClientID),
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	for i, j := range id.VisaJWTs {
+		v, err := ga4gh.NewVisaFromJWT(ga4gh.VisaJWT(j))
+		if err != nil {
+			t.Fatalf("ga4gh.NewVisaFromJWT() failed: %v", err)
+		}
+		vd := v.Data()
+		vd.Status = &ga4gh.VisaStatus{StatusList: &ga4gh.StatusListReference{Index: i, URI: uri}}
+		lists.Set(uri, i, ga4gh.StatusValid)
+		if vd.Assertion.Type == "ResearcherStatus" {
+			lists.Revoke(uri, i)
+		}
+		v, err = ga4gh.NewVisaFromData(ctx, vd, v.JKU(), localsign.New(&testkeys.PersonaBrokerKey))
+		if err != nil {
+			t.Fatalf("ga4gh.NewVisaFromData() failed: %v", err)
+		}
+		d.Visas = append(d.Visas, v.JWT())
+	}
+
+	resp := sendPassportResourceTokens(t, s, nil, pname, d)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, wants %d", resp.StatusCode, http.StatusForbidden)
+	}
+
+	logs.Client.Close()
+	got := logs.Server.Logs[0].Entries[0]
+	if got.Labels["pass_auth_check"] == "true" {
+		t.Errorf("Labels[pass_auth_check] want false")
+	}
+	rejected := &cpb.RejectedPolicy{}
+	if err := jsonpb.UnmarshalString(policyLogMessage(got), rejected); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	revoked := 0
+	for _, rv := range rejected.RejectedVisas {
+		if rv.Rejection.GetReason() == ga4gh.VisaRevokedReason {
+			revoked++
+			if rv.Assertion.GetType() != "ResearcherStatus" {
+				t.Errorf("revoked visa type = %q, want ResearcherStatus", rv.Assertion.GetType())
+			}
+		}
+	}
+	if revoked != 1 {
+		t.Errorf("rejected visas = %v, want the revoked visa", rejected.RejectedVisas)
+	}
+}
+
 func damSendTestQuery(t *testing.T, method, path, pathname, realm, personaName string, query url.Values, data proto.Message, s *Service, iss *persona.Server) *http.Response {
 	t.Helper()
 
@@ -3686,6 +4089,164 @@ func TestConfigClients_Create_Error(t *testing.T) {
 	}
 }
 
+func sendConfigOptions(t *testing.T, method, ifMatch string, cfg *pb.DamConfig, sec *pb.DamSecrets, s *Service, iss *persona.Server) *http.Response {
+	t.Helper()
+
+	pname := "admin"
+	cli := cfg.Clients["test_client"]
+	tok, _, err := persona.NewAccessToken(pname, hydraPublicURL, cli.ClientId, noScope, iss.Config().TestPersonas[pname])
+	if err != nil {
+		t.Fatalf("persona.NewAccessToken(%q, %q, _, _) failed: %v", pname, hydraPublicURL, err)
+	}
+
+	var body io.Reader
+	if method != http.MethodGet {
+		m := jsonpb.Marshaler{}
+		var buf bytes.Buffer
+		if err := m.Marshal(&buf, &pb.ConfigOptionsRequest{Item: &pb.ConfigOptions{}}); err != nil {
+			t.Fatal(err)
+		}
+		body = &buf
+	}
+
+	path := strings.ReplaceAll(configOptionsPath, "{realm}", "test")
+	q := url.Values{
+		"client_id":     []string{cli.ClientId},
+		"client_secret": []string{sec.ClientSecrets[cli.ClientId]},
+	}
+	h := http.Header{"Authorization": []string{"Bearer " + string(tok)}}
+	if len(ifMatch) > 0 {
+		h.Set("If-Match", ifMatch)
+	}
+	return testhttp.SendTestRequest(t, s.Handler, method, path, q, body, h)
+}
+
+func TestConfigOptions_IfMatch(t *testing.T) {
+	s, cfg, sec, _, iss, err := setupHydraTest(false)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+
+	resp := sendConfigOptions(t, http.MethodGet, "", cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	etag := resp.Header.Get("ETag")
+	if len(etag) == 0 {
+		t.Fatalf("GET response has no ETag header")
+	}
+
+	resp = sendConfigOptions(t, http.MethodPatch, etag, cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PATCH with If-Match %s status = %d, want %d", etag, resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("ETag"); got == etag || len(got) == 0 {
+		t.Errorf("PATCH response ETag = %q, want a new etag", got)
+	}
+
+	// The config has been modified since the etag was read.
+	resp = sendConfigOptions(t, http.MethodPatch, etag, cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("PATCH with stale If-Match %s status = %d, want %d", etag, resp.StatusCode, http.StatusPreconditionFailed)
+	}
+}
+
+func sendBackupRequest(t *testing.T, method, realm string, replace bool, body io.Reader, cfg *pb.DamConfig, sec *pb.DamSecrets, s *Service, iss *persona.Server) *http.Response {
+	t.Helper()
+
+	pname := "admin"
+	cli := cfg.Clients["test_client"]
+	tok, _, err := persona.NewAccessToken(pname, hydraPublicURL, cli.ClientId, noScope, iss.Config().TestPersonas[pname])
+	if err != nil {
+		t.Fatalf("persona.NewAccessToken(%q, %q, _, _) failed: %v", pname, hydraPublicURL, err)
+	}
+
+	path := strings.ReplaceAll(backupPath, "{realm}", realm)
+	q := url.Values{
+		"client_id":     []string{cli.ClientId},
+		"client_secret": []string{sec.ClientSecrets[cli.ClientId]},
+	}
+	if replace {
+		q.Set("replace", "true")
+	}
+	h := http.Header{"Authorization": []string{"Bearer " + string(tok)}}
+	return testhttp.SendTestRequest(t, s.Handler, method, path, q, body, h)
+}
+
+func sendBackup(t *testing.T, method, realm string, body io.Reader, cfg *pb.DamConfig, sec *pb.DamSecrets, s *Service, iss *persona.Server) *http.Response {
+	t.Helper()
+	return sendBackupRequest(t, method, realm, false, body, cfg, sec, s, iss)
+}
+
+func sendBackupReplace(t *testing.T, realm string, body io.Reader, cfg *pb.DamConfig, sec *pb.DamSecrets, s *Service, iss *persona.Server) *http.Response {
+	t.Helper()
+	return sendBackupRequest(t, http.MethodPost, realm, true, body, cfg, sec, s, iss)
+}
+
+func TestBackup(t *testing.T) {
+	s, cfg, sec, _, iss, err := setupHydraTest(false)
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+
+	resp := sendBackup(t, http.MethodGet, storage.DefaultRealm, nil, cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	archive, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll() failed: %v", err)
+	}
+
+	resp = sendBackup(t, http.MethodPost, "restored", bytes.NewReader(archive), cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	got := &pb.DamConfig{}
+	if err := s.store.Read(storage.ConfigDatatype, "restored", storage.DefaultUser, storage.DefaultID, storage.LatestRev, got); err != nil {
+		t.Fatalf("Read(config) of restored realm failed: %v", err)
+	}
+	want := &pb.DamConfig{}
+	if err := s.store.Read(storage.ConfigDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, want); err != nil {
+		t.Fatalf("Read(config) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("restored config diff (-want +got):\n%s", diff)
+	}
+
+	resp = sendBackup(t, http.MethodPost, "restored", strings.NewReader("not a zip"), cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST of invalid archive status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	// An archive with an invalid config is rejected before the realm is replaced.
+	broken := proto.Clone(want).(*pb.DamConfig)
+	broken.TrustedIssuers["invalid name!"] = &pb.TrustedIssuer{Issuer: "https://issuer.example.com"}
+	if err := s.store.Write(storage.ConfigDatatype, "broken", storage.DefaultUser, storage.DefaultID, storage.LatestRev, broken, nil); err != nil {
+		t.Fatalf("Write(config) failed: %v", err)
+	}
+	resp = sendBackup(t, http.MethodGet, "broken", nil, cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	archive, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll() failed: %v", err)
+	}
+	resp = sendBackupReplace(t, "restored", bytes.NewReader(archive), cfg, sec, s, iss)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST of archive with invalid config status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	got = &pb.DamConfig{}
+	if err := s.store.Read(storage.ConfigDatatype, "restored", storage.DefaultUser, storage.DefaultID, storage.LatestRev, got); err != nil {
+		t.Fatalf("Read(config) of restored realm failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("restored config after rejected restore diff (-want +got):\n%s", diff)
+	}
+}
+
 func TestConfigClients_Create_Hydra_Error(t *testing.T) {
 	s, _, _, h, iss, err := setupHydraTest(false)
 	if err != nil {
@@ -4608,3 +5169,65 @@ func TestConfigTrustedIssuer_ClientSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadSecrets_SecretReferences(t *testing.T) {
+	store := storage.NewMemoryStorage("dam", "testdata/config")
+	provider := secret.NewMemoryProvider(map[string]string{"broker": "broker-secret"})
+	s := NewService(&Options{
+		HTTPClient:     http.DefaultClient,
+		Domain:         "test.org",
+		ServiceName:    "dam",
+		DefaultBroker:  testBroker,
+		Store:          store,
+		Warehouse:      clouds.NewMockTokenCreator(false),
+		AWSClient:      aws.NewMockAPIClient("123456", "dam-user-id"),
+		Encryption:     fakeencryption.New(),
+		LRO:            fakelro.New(),
+		SecretProvider: provider,
+	})
+
+	stored, err := s.loadStoredSecrets(nil)
+	if err != nil {
+		t.Fatalf("loadStoredSecrets() failed: %v", err)
+	}
+	stored.BrokerSecrets = map[string]string{"broker-client": "secret://broker"}
+	if err := store.Write(storage.SecretsDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, stored, nil); err != nil {
+		t.Fatalf("Write secrets failed: %v", err)
+	}
+
+	sec, err := s.loadSecrets(nil)
+	if err != nil {
+		t.Fatalf("loadSecrets() failed: %v", err)
+	}
+	if got := sec.BrokerSecrets["broker-client"]; got != "broker-secret" {
+		t.Errorf("BrokerSecrets[broker-client] = %q, want broker-secret", got)
+	}
+	if len(sec.ClientSecrets) == 0 {
+		t.Errorf("ClientSecrets is empty, wants raw values kept")
+	}
+
+	stored, err = s.loadStoredSecrets(nil)
+	if err != nil {
+		t.Fatalf("loadStoredSecrets() failed: %v", err)
+	}
+	if got := stored.BrokerSecrets["broker-client"]; got != "secret://broker" {
+		t.Errorf("stored BrokerSecrets[broker-client] = %q, wants the reference", got)
+	}
+
+	provider.AddVersion("broker", "rotated")
+	sec, err = s.loadSecrets(nil)
+	if err != nil {
+		t.Fatalf("loadSecrets() failed: %v", err)
+	}
+	if got := sec.BrokerSecrets["broker-client"]; got != "rotated" {
+		t.Errorf("BrokerSecrets[broker-client] after rotation = %q, want rotated", got)
+	}
+
+	stored.BrokerSecrets["broker-client"] = "secret://missing"
+	if err := store.Write(storage.SecretsDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, stored, nil); err != nil {
+		t.Fatalf("Write secrets failed: %v", err)
+	}
+	if _, err := s.loadSecrets(nil); status.Code(err) != codes.NotFound {
+		t.Errorf("loadSecrets() with missing secret = %v, wants NotFound", err)
+	}
+}
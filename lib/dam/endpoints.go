@@ -76,6 +76,10 @@ const (
 	// Required permission: trusted client with "sync" scope defined in the config.
 	syncClientsPath = "/dam/v1alpha/{realm}/clients:sync"
 
+	// Backup: GET exports the items of a realm as a zip archive, POST restores them.
+	// Required permission: admin
+	backupPath = "/dam/v1alpha/{realm}/backup"
+
 	// ConfigHistory: history of configuration changes.
 	// Required permission: admin
 	configHistoryPath         = "/dam/v1alpha/{realm}/config/history"
@@ -44,6 +44,7 @@ var (
 		"POST /dam/inforelease/accept",
 		"POST /dam/inforelease/reject",
 		"GET|POST /dam/checkout",
+		"POST /dam/checkout",
 
 		// proxy hydra token endpoint
 		"POST /oauth2/token",
@@ -54,7 +55,7 @@ var (
 
 		// consent management endpoints
 		"GET /dam/v1alpha/{realm}/users/{user}/consents",
-		"DELETE /dam/v1alpha/{realm}/users/{user}/consents/{consent_id}",
+		"DELETE|GET /dam/v1alpha/{realm}/users/{user}/consents/{consent_id}",
 
 		// token management endpoints
 		"GET /dam/v1alpha/users/{user}/tokens",
@@ -77,10 +78,13 @@ var (
 		"/dam/v1alpha/{realm}/config/trustedIssuers/{name}",
 		"/dam/v1alpha/{realm}/config/trustedSources/{name}",
 		"/dam/v1alpha/{realm}/config/visaTypes/{name}",
+		"GET /dam/v1alpha/{realm}/backup",
+		"POST /dam/v1alpha/{realm}/backup",
 		"GET /dam/v1alpha/{realm}/config/history",
 		"GET /dam/v1alpha/{realm}/config/history/{name}",
 		"GET /dam/v1alpha/{realm}/config/reset",
 		"GET /dam/v1alpha/{realm}/config/testPersonas",
+		"GET /dam/v1alpha/{realm}/tests",
 
 		// read-only non-admin access to configurations
 		"/dam/v1alpha/{realm}/client/{name}",
@@ -0,0 +1,182 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dam
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache" /* copybara-comment: cache */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+const (
+	// passportCacheMaxExpiry bounds how long a verified upstream identity or
+	// visa is cached when its own expiry is later or missing.
+	passportCacheMaxExpiry = int64(10 * time.Minute / time.Second)
+	// passportRejectionCacheExpiry is how long a rejected token or visa is
+	// cached, short so that transient upstream failures recover quickly.
+	passportRejectionCacheExpiry = int64(30 * time.Second / time.Second)
+
+	passportCachePrefix = "dam_passport_"
+	visaCachePrefix     = "dam_visa_"
+)
+
+// passportCacheEntry is the cached result of translating an upstream token.
+// Visas are kept as JWTs so that they are filtered against the current
+// trusted issuers on every use.
+type passportCacheEntry struct {
+	Identity *ga4gh.Identity `json:"identity,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// visaCacheEntry is the cached result of verifying a visa.
+type visaCacheEntry struct {
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// passportCacheKey creates the caching key of an upstream token.
+func passportCacheKey(tok string) string {
+	return passportCachePrefix + hashForCacheKey(tok)
+}
+
+// visaCacheKey creates the caching key of a visa JWT. The JKU and issuer are
+// part of the JWT, so the JWT is enough to identify the verification.
+func visaCacheKey(jwt string) string {
+	return visaCachePrefix + hashForCacheKey(jwt)
+}
+
+func hashForCacheKey(s string) string {
+	b := sha256.Sum256([]byte(s))
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// cacheExpiry returns the seconds to cache an entry expiring at exp, bounded
+// by passportCacheMaxExpiry. It returns 0 if the entry has already expired.
+func cacheExpiry(exp, now int64) int64 {
+	if exp == 0 || exp > now+passportCacheMaxExpiry {
+		return passportCacheMaxExpiry
+	}
+	if exp <= now {
+		return 0
+	}
+	return exp - now
+}
+
+// cachedPassportIdentity returns the cached identity of a token, or a cached
+// rejection error. It returns nil, nil on a cache miss.
+func cachedPassportIdentity(c cache.Client, tok string) (*ga4gh.Identity, error) {
+	if c == nil {
+		return nil, nil
+	}
+	b, err := c.Get(passportCacheKey(tok))
+	if err != nil {
+		return nil, nil
+	}
+	e := &passportCacheEntry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		glog.Errorf("decode cached passport failed: %v", err)
+		return nil, nil
+	}
+	if len(e.Error) > 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "%s", e.Error)
+	}
+	if e.Identity == nil {
+		return nil, nil
+	}
+	return e.Identity, nil
+}
+
+// cachePassportIdentity caches the identity translated from a token, before
+// the visas are verified, until the token expires.
+func cachePassportIdentity(c cache.Client, tok string, id *ga4gh.Identity) {
+	exp := cacheExpiry(id.Expiry, time.Now().Unix())
+	if exp == 0 {
+		return
+	}
+	putCacheEntry(c, passportCacheKey(tok), &passportCacheEntry{Identity: id}, exp)
+}
+
+// cachePassportRejection caches that a token failed to translate.
+func cachePassportRejection(c cache.Client, tok string, err error) {
+	putCacheEntry(c, passportCacheKey(tok), &passportCacheEntry{Error: err.Error()}, passportRejectionCacheExpiry)
+}
+
+func putCacheEntry(c cache.Client, key string, v interface{}, exp int64) {
+	if c == nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		glog.Errorf("encode cache entry failed: %v", err)
+		return
+	}
+	if err := c.SetWithExpiry(key, b, exp); err != nil {
+		// if cache unavailable, just skip and fallback.
+		glog.Errorf("cache.SetWithExpiry() failed: %v", err)
+	}
+}
+
+// cachedVisaVerifier wraps f to cache the verification result of each visa:
+// verified visas until they expire, rejected visas briefly.
+func cachedVisaVerifier(c cache.Client, f ga4gh.JWTVerifier) ga4gh.JWTVerifier {
+	if c == nil {
+		return f
+	}
+	return func(ctx context.Context, jwt, issuer, jku string) error {
+		key := visaCacheKey(jwt)
+		if b, err := c.Get(key); err == nil {
+			e := &visaCacheEntry{}
+			if err := json.Unmarshal(b, e); err == nil {
+				if len(e.Error) == 0 {
+					return nil
+				}
+				err := status.Errorf(codes.Unauthenticated, "%s", e.Error)
+				if len(e.Reason) > 0 {
+					err = errutil.WithErrorReason(e.Reason, err)
+				}
+				return err
+			}
+		}
+
+		err := f(ctx, jwt, issuer, jku)
+		if err != nil {
+			e := &visaCacheEntry{Error: err.Error()}
+			if _, ok := status.FromError(err); ok {
+				e.Reason = errutil.ErrorReason(err)
+			}
+			putCacheEntry(c, key, e, passportRejectionCacheExpiry)
+			return err
+		}
+
+		var exp int64
+		if v, err := ga4gh.NewVisaFromJWT(ga4gh.VisaJWT(jwt)); err == nil {
+			exp = cacheExpiry(v.Data().ExpiresAt, time.Now().Unix())
+		}
+		if exp > 0 {
+			putCacheEntry(c, key, &visaCacheEntry{}, exp)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dam
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/aws" /* copybara-comment: aws */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/fakeencryption" /* copybara-comment: fakeencryption */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/persona" /* copybara-comment: persona */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakecache" /* copybara-comment: fakecache */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakelro" /* copybara-comment: fakelro */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakeoidcissuer" /* copybara-comment: fakeoidcissuer */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test" /* copybara-comment: test */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+
+	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+type passportCacheTest struct {
+	s     *Service
+	redis *miniredis.Miniredis
+	ctx   context.Context
+	cfg   *pb.DamConfig
+	tok   string
+}
+
+func setupPassportCacheTest(t *testing.T) *passportCacheTest {
+	t.Helper()
+
+	server, err := fakeoidcissuer.New(hydraPublicURL, &testkeys.PersonaBrokerKey, "dam", "testdata/config", false)
+	if err != nil {
+		t.Fatalf("fakeoidcissuer.New(%q, _, _) failed: %v", hydraPublicURL, err)
+	}
+	redis, getCache := fakecache.New(t)
+	s := NewService(&Options{
+		HTTPClient:     server.Client(),
+		Domain:         "test.org",
+		ServiceName:    "dam",
+		DefaultBroker:  "no-broker",
+		Store:          storage.NewMemoryStorage("dam", "testdata/config"),
+		AWSClient:      aws.NewMockAPIClient("123456", "dam-user-id"),
+		UseHydra:       useHydra,
+		HydraAdminURL:  hydraAdminURL,
+		HydraPublicURL: hydraPublicURL,
+		Encryption:     fakeencryption.New(),
+		LRO:            fakelro.New(),
+		Cache:          getCache,
+	})
+
+	cfg, err := s.loadConfig(nil, storage.DefaultRealm)
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	pname := "dr_joe_elixir"
+	tok, _, err := persona.NewAccessToken(pname, hydraPublicURL, test.TestClientID, persona.DefaultScope, cfg.TestPersonas[pname])
+	if err != nil {
+		t.Fatalf("persona.NewAccessToken(%q, %q, _, _) failed: %v", pname, hydraPublicURL, err)
+	}
+
+	return &passportCacheTest{
+		s:     s,
+		redis: redis,
+		ctx:   server.ContextWithClient(context.Background()),
+		cfg:   cfg,
+		tok:   string(tok),
+	}
+}
+
+func TestUpstreamTokenToPassportIdentity_Cache(t *testing.T) {
+	pt := setupPassportCacheTest(t)
+
+	id, err := pt.s.upstreamTokenToPassportIdentity(pt.ctx, pt.cfg, nil, pt.tok, test.TestClientID)
+	if err != nil {
+		t.Fatalf("upstreamTokenToPassportIdentity() failed: %v", err)
+	}
+	if len(id.GA4GH) == 0 {
+		t.Fatalf("upstreamTokenToPassportIdentity() returned no claims")
+	}
+
+	key := passportCacheKey(pt.tok)
+	if !pt.redis.Exists(key) {
+		t.Fatalf("passport of token not cached")
+	}
+	if ttl := pt.redis.TTL(key); ttl <= 0 || ttl > time.Duration(passportCacheMaxExpiry)*time.Second {
+		t.Errorf("TTL of cached passport = %v, want in (0, %ds]", ttl, passportCacheMaxExpiry)
+	}
+	visas := 0
+	for _, k := range pt.redis.Keys() {
+		if strings.HasPrefix(k, visaCachePrefix) {
+			visas++
+		}
+	}
+	if visas != len(id.VisaJWTs) {
+		t.Errorf("cached visa results = %d, want %d", visas, len(id.VisaJWTs))
+	}
+
+	// Later calls use the cached identity instead of translating the token.
+	b, err := json.Marshal(&passportCacheEntry{Identity: &ga4gh.Identity{Subject: "cached", VisaJWTs: id.VisaJWTs}})
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	pt.redis.Set(key, string(b))
+
+	got, err := pt.s.upstreamTokenToPassportIdentity(pt.ctx, pt.cfg, nil, pt.tok, test.TestClientID)
+	if err != nil {
+		t.Fatalf("upstreamTokenToPassportIdentity() failed: %v", err)
+	}
+	if got.Subject != "cached" {
+		t.Errorf("Subject = %q, want cached identity", got.Subject)
+	}
+	if len(got.GA4GH) != len(id.GA4GH) {
+		t.Errorf("claims of cached identity = %d, want %d", len(got.GA4GH), len(id.GA4GH))
+	}
+
+	// Visas are still checked against the trusted issuers of the config.
+	delete(pt.cfg.TrustedIssuers, "test")
+	delete(pt.cfg.TrustedIssuers, "testBroker")
+	got, err = pt.s.upstreamTokenToPassportIdentity(pt.ctx, pt.cfg, nil, pt.tok, test.TestClientID)
+	if err != nil {
+		t.Fatalf("upstreamTokenToPassportIdentity() failed: %v", err)
+	}
+	if len(got.GA4GH) != 0 {
+		t.Errorf("claims of untrusted visas = %v, want none", got.GA4GH)
+	}
+}
+
+func TestUpstreamTokenToPassportIdentity_CacheRejection(t *testing.T) {
+	pt := setupPassportCacheTest(t)
+
+	// Break the signature of the token.
+	tok := pt.tok[:len(pt.tok)-4] + "AAAA"
+	if _, err := pt.s.upstreamTokenToPassportIdentity(pt.ctx, pt.cfg, nil, tok, test.TestClientID); err == nil {
+		t.Fatalf("upstreamTokenToPassportIdentity() of invalid token wants error")
+	}
+
+	key := passportCacheKey(tok)
+	if !pt.redis.Exists(key) {
+		t.Fatalf("rejection of token not cached")
+	}
+	if ttl := pt.redis.TTL(key); ttl <= 0 || ttl > time.Duration(passportRejectionCacheExpiry)*time.Second {
+		t.Errorf("TTL of cached rejection = %v, want in (0, %ds]", ttl, passportRejectionCacheExpiry)
+	}
+
+	// A cached rejection is returned without translating the token.
+	b, err := json.Marshal(&passportCacheEntry{Error: "rejected"})
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	pt.redis.Set(passportCacheKey(pt.tok), string(b))
+	_, err = pt.s.upstreamTokenToPassportIdentity(pt.ctx, pt.cfg, nil, pt.tok, test.TestClientID)
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("upstreamTokenToPassportIdentity() = %v, want cached rejection", err)
+	}
+}
+
+func TestCachedVisaVerifier(t *testing.T) {
+	redis, getCache := fakecache.New(t)
+	c := getCache()
+	defer c.Close()
+
+	ctx := context.Background()
+	signer := localsign.New(&testkeys.PersonaBrokerKey)
+	newVisa := func(exp time.Duration) string {
+		v, err := ga4gh.NewVisaFromData(ctx, &ga4gh.VisaData{
+			StdClaims: ga4gh.StdClaims{
+				Issuer:    hydraPublicURL,
+				Subject:   "subject1",
+				ExpiresAt: time.Now().Add(exp).Unix(),
+			},
+			Assertion: ga4gh.Assertion{
+				Type:     "AcceptedTermsAndPolicies",
+				Value:    "https://agreements.example.org/ds123",
+				Source:   "http://testkeys-visa-issuer-0.org",
+				Asserted: 10100,
+			},
+		}, hydraPublicURL+".well-known/jwks", signer)
+		if err != nil {
+			t.Fatalf("NewVisaFromData() failed: %v", err)
+		}
+		return string(v.JWT())
+	}
+
+	calls := 0
+	var verifyErr error
+	f := cachedVisaVerifier(c, func(ctx context.Context, jwt, iss, jku string) error {
+		calls++
+		return verifyErr
+	})
+
+	tests := []struct {
+		name    string
+		exp     time.Duration
+		err     error
+		wantTTL time.Duration
+	}{
+		{
+			name:    "long lived visa",
+			exp:     time.Hour,
+			wantTTL: time.Duration(passportCacheMaxExpiry) * time.Second,
+		},
+		{
+			name:    "short lived visa",
+			exp:     time.Minute,
+			wantTTL: time.Minute,
+		},
+		{
+			name:    "rejected visa",
+			exp:     2 * time.Hour,
+			err:     errutil.WithErrorReason("jku_verify_failed", status.Errorf(codes.Unauthenticated, "bad signature")),
+			wantTTL: time.Duration(passportRejectionCacheExpiry) * time.Second,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = 0
+			verifyErr = tc.err
+			jwt := newVisa(tc.exp)
+
+			for i := 0; i < 2; i++ {
+				err := f(ctx, jwt, hydraPublicURL, "")
+				if (err != nil) != (tc.err != nil) {
+					t.Fatalf("verify %d = %v, want error %v", i, err, tc.err)
+				}
+				if tc.err != nil && errutil.ErrorReason(err) != errutil.ErrorReason(tc.err) {
+					t.Errorf("verify %d reason = %q, want %q", i, errutil.ErrorReason(err), errutil.ErrorReason(tc.err))
+				}
+			}
+			if calls != 1 {
+				t.Errorf("verifier calls = %d, want 1", calls)
+			}
+
+			ttl := redis.TTL(visaCacheKey(jwt))
+			if ttl <= tc.wantTTL-5*time.Second || ttl > tc.wantTTL {
+				t.Errorf("TTL = %v, want about %v", ttl, tc.wantTTL)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package dam
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -26,6 +27,7 @@ import (
 	"cloud.google.com/go/logging" /* copybara-comment: logging */
 	"google.golang.org/grpc/codes" /* copybara-comment */
 	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/gorilla/mux" /* copybara-comment */
 	"golang.org/x/oauth2" /* copybara-comment */
 	"github.com/golang/protobuf/jsonpb" /* copybara-comment */
 	"github.com/pborman/uuid" /* copybara-comment */
@@ -40,12 +42,16 @@ import (
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/timeutil" /* copybara-comment: timeutil */
 
+	glog "github.com/golang/glog" /* copybara-comment */
 	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
 )
 
 const (
 	maxResourceStateSeconds = 300
 	accountNameLength = 30
+	// maxExplanationSize is the maximum size of the explanation of a policy
+	// decision log, Cloud Logging entries are limited to 256KB.
+	maxExplanationSize = 64 << 10
 )
 
 var (
@@ -271,9 +277,68 @@ func (s *Service) auth(ctx context.Context, in authHandlerIn) (_ *authHandlerOut
 		return nil, status.Errorf(codes.FailedPrecondition, "client secret of broker %q is not defined", s.defaultBroker)
 	}
 
-	var list []*pb.ResourceTokenRequestState_Resource
+	list, err := requestedResources(cfg, realm, in.resources)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: need support real policy filter
+	scopes := []string{"openid", "ga4gh_passport_v1", "identities", "account_admin"}
+	if in.tokenType == pb.ResourceTokenRequestState_ENDPOINT {
+		scopes = []string{"openid", "identities"}
+	}
+
+	sID := uuid.New()
 
-	for _, rvr := range in.resources {
+	state := &pb.ResourceTokenRequestState{
+		Type:              in.tokenType,
+		ClientId:          in.clientID,
+		ClientName:        in.clientName,
+		State:             in.stateID,
+		Broker:            s.defaultBroker,
+		Redirect:          in.redirect,
+		Ttl:               int64(in.ttl),
+		ResponseKeyFile:   in.responseKeyFile,
+		Resources:         list,
+		LoginChallenge:    in.challenge,
+		EpochSeconds:      time.Now().Unix(),
+		Realm:             realm,
+		RequestedAudience: in.requestedAudience,
+		RequestedScope:    in.requestedScope,
+	}
+
+	err = s.store.WriteTx(storage.ResourceTokenRequestStateDataType, storage.DefaultRealm, storage.DefaultUser, sID, storage.LatestRev, state, nil, tx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, err.Error())
+	}
+
+	conf := s.oauthConf(s.defaultBroker, broker, clientSecret, scopes)
+	return &authHandlerOut{
+		oauth:   conf,
+		stateID: sID,
+	}, nil
+}
+
+type loggedInHandlerIn struct {
+	authCode string
+	stateID  string
+	errStr   string
+	errDesc  string
+	r        *http.Request
+}
+
+type loggedInHandlerOut struct {
+	redirect   string
+	stateID    string
+	subject    string
+	identities []string
+}
+
+// requestedResources checks the requested resources exist in the config of
+// the realm, and fills in their default role and interface.
+func requestedResources(cfg *pb.DamConfig, realm string, resources []resourceViewRole) ([]*pb.ResourceTokenRequestState_Resource, error) {
+	var list []*pb.ResourceTokenRequestState_Resource
+	for _, rvr := range resources {
 		if rvr.realm != realm {
 			return nil, status.Errorf(codes.Aborted, "cannot authorize resources using different realms")
 		}
@@ -329,56 +394,7 @@ func (s *Service) auth(ctx context.Context, in authHandlerIn) (_ *authHandlerOut
 		})
 	}
 
-	// TODO: need support real policy filter
-	scopes := []string{"openid", "ga4gh_passport_v1", "identities", "account_admin"}
-	if in.tokenType == pb.ResourceTokenRequestState_ENDPOINT {
-		scopes = []string{"openid", "identities"}
-	}
-
-	sID := uuid.New()
-
-	state := &pb.ResourceTokenRequestState{
-		Type:              in.tokenType,
-		ClientId:          in.clientID,
-		ClientName:        in.clientName,
-		State:             in.stateID,
-		Broker:            s.defaultBroker,
-		Redirect:          in.redirect,
-		Ttl:               int64(in.ttl),
-		ResponseKeyFile:   in.responseKeyFile,
-		Resources:         list,
-		LoginChallenge:    in.challenge,
-		EpochSeconds:      time.Now().Unix(),
-		Realm:             realm,
-		RequestedAudience: in.requestedAudience,
-		RequestedScope:    in.requestedScope,
-	}
-
-	err = s.store.WriteTx(storage.ResourceTokenRequestStateDataType, storage.DefaultRealm, storage.DefaultUser, sID, storage.LatestRev, state, nil, tx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unavailable, err.Error())
-	}
-
-	conf := s.oauthConf(s.defaultBroker, broker, clientSecret, scopes)
-	return &authHandlerOut{
-		oauth:   conf,
-		stateID: sID,
-	}, nil
-}
-
-type loggedInHandlerIn struct {
-	authCode string
-	stateID  string
-	errStr   string
-	errDesc  string
-	r        *http.Request
-}
-
-type loggedInHandlerOut struct {
-	redirect   string
-	stateID    string
-	subject    string
-	identities []string
+	return list, nil
 }
 
 func (s *Service) loggedIn(ctx context.Context, in loggedInHandlerIn) (_ *loggedInHandlerOut, _ string, ferr error) {
@@ -510,7 +526,9 @@ func resourceToString(res *pb.ResourceTokenRequestState_Resource) string {
 	return fmt.Sprintf("%s/%s/%s/%s", res.Realm, res.Resource, res.View, res.Role)
 }
 
-func writePolicyDeccisionLog(logger *logging.Client, id *ga4gh.Identity, res *pb.ResourceTokenRequestState_Resource, ttl time.Duration, cartID string, cfgRevision int64, err error) {
+// writePolicyDeccisionLog logs the decision of checkAuthorization, with the
+// explanation of the policy evaluation if access is denied.
+func writePolicyDeccisionLog(logger *logging.Client, id *ga4gh.Identity, res *pb.ResourceTokenRequestState_Resource, ttl time.Duration, cartID string, cfgRevision int64, explanation json.RawMessage, err error) {
 	log := &auditlog.PolicyDecisionLog{
 		TokenID:        id.ID,
 		TokenSubject:   id.Subject,
@@ -537,11 +555,51 @@ func writePolicyDeccisionLog(logger *logging.Client, id *ga4gh.Identity, res *pb
 		} else {
 			log.Message = err.Error()
 		}
+
+		if explanation != nil {
+			log.Explanation = explanation
+		}
 	}
 
 	auditlog.WritePolicyDecisionLog(logger, log)
 }
 
+// explainDecision returns the trace of a policy evaluation as JSON for a
+// policy decision log. The visas are left out if the details of rejections
+// are hidden or if the trace is larger than maxExplanationSize. Returns nil
+// if the trace is still too large.
+func explainDecision(trace *ga4gh.Trace, hideRejectDetail bool) json.RawMessage {
+	if trace == nil {
+		return nil
+	}
+	t := makeTrace(trace)
+	if hideRejectDetail {
+		removeTraceVisas(t)
+	}
+	marshaler := jsonpb.Marshaler{}
+	s, err := marshaler.MarshalToString(t)
+	if err == nil && len(s) > maxExplanationSize && !hideRejectDetail {
+		removeTraceVisas(t)
+		s, err = marshaler.MarshalToString(t)
+	}
+	if err != nil {
+		glog.Warningf("encoding policy trace: %v", err)
+		return nil
+	}
+	if len(s) > maxExplanationSize {
+		glog.Warningf("policy trace of %d bytes exceeds %d bytes, not logged", len(s), maxExplanationSize)
+		return nil
+	}
+	return json.RawMessage(s)
+}
+
+func removeTraceVisas(t *pb.GetTestResultsResponse_Trace) {
+	t.Visas = nil
+	for _, c := range t.Children {
+		removeTraceVisas(c)
+	}
+}
+
 func (s *Service) loggedInForDatasetToken(ctx context.Context, id *ga4gh.Identity, state *pb.ResourceTokenRequestState, cfg *pb.DamConfig, stateID, realm string, tx storage.Tx) (*loggedInHandlerOut, error) {
 	ttl := time.Duration(state.Ttl)
 
@@ -554,8 +612,10 @@ func (s *Service) loggedInForDatasetToken(ctx context.Context, id *ga4gh.Identit
 			return nil, status.Errorf(codes.Aborted, "cannot authorize resources using different realms")
 		}
 
-		err := checkAuthorization(ctx, id, ttl, r.Resource, r.View, r.Role, cfg, state.ClientId, s.ValidateCfgOpts(realm, tx))
-		writePolicyDeccisionLog(s.logger, id, r, ttl, stateID, cfg.Revision, err)
+		actx, trace := withPassportTrace(ctx, id.Subject, id)
+		err := checkAuthorization(actx, id, ttl, r.Resource, r.View, r.Role, cfg, state.ClientId, s.ValidateCfgOpts(realm, tx))
+		trace.SetResult(err == nil)
+		writePolicyDeccisionLog(s.logger, id, r, ttl, stateID, cfg.Revision, explainDecision(trace, s.hideRejectDetail), err)
 		if err != nil {
 			return nil, err
 		}
@@ -645,14 +705,96 @@ func (s *Service) fetchResourceTokens(r *http.Request) (_ *pb.ResourceResults, f
 		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	return s.resourceResults(r.Context(), state.ClientId, state.Resources, time.Duration(state.Ttl), false, id, cfg)
+}
+
+// hasPassport matches checkout requests with a passport JWT.
+func hasPassport(r *http.Request, _ *mux.RouteMatch) bool {
+	return len(r.FormValue("passport")) > 0
+}
+
+// PassportResourceTokens returns a set of access tokens for a set of resources
+// authorized by the visas of a passport JWT, without a login flow.
+func (s *Service) PassportResourceTokens(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.passportResourceTokens(r)
+	if err != nil {
+		httputils.WriteError(w, err)
+		return
+	}
+	httputils.WriteResp(w, resp)
+}
+
+func (s *Service) passportResourceTokens(r *http.Request) (_ *pb.ResourceResults, ferr error) {
 	ctx := r.Context()
-	keyFile := false
+	a, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := extractTTL(httputils.QueryParam(r, "max_age"), httputils.QueryParam(r, "ttl"))
+	if err != nil {
+		return nil, errutil.WithErrorReason("ttl_invalid", status.Errorf(codes.InvalidArgument, "ttl invalid: %v", err))
+	}
+	rvrs, err := s.resourceViewRoleFromRequest(r.Form["resource"])
+	if err != nil {
+		return nil, errutil.WithErrorReason("resource_invalid", status.Errorf(codes.InvalidArgument, "resource invalid: %v", err))
+	}
+	realm := rvrs[0].realm
+
+	tx, err := s.store.Tx(true)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	defer func() {
+		err := tx.Finish()
+		if ferr == nil && err != nil {
+			ferr = status.Errorf(codes.Unavailable, err.Error())
+		}
+	}()
+
+	cfg, err := s.loadConfig(tx, realm)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, err.Error())
+	}
+	list, err := requestedResources(cfg, realm, rvrs)
+	if err != nil {
+		return nil, err
+	}
+
+	id, brokerName, err := s.selfContainedPassportIdentity(ctx, cfg, httputils.QueryParam(r, "passport"))
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := s.createOrUpdateAccount(ctx, r, id, brokerName, realm, tx)
+	if err != nil {
+		return nil, err
+	}
+	// Add the upstream id to identities, and change id subject to dam account subject.
+	id.Identities[id.Subject] = nil
+	id.Subject = subject
+
+	for _, res := range list {
+		actx, trace := withPassportTrace(ctx, id.Subject, id)
+		err := checkAuthorization(actx, id, ttl, res.Resource, res.View, res.Role, cfg, a.ClientID, s.ValidateCfgOpts(realm, tx))
+		trace.SetResult(err == nil)
+		writePolicyDeccisionLog(s.logger, id, res, ttl, "", cfg.Revision, explainDecision(trace, s.hideRejectDetail), err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.resourceResults(ctx, a.ClientID, list, ttl, responseKeyFile(r), id, cfg)
+}
+
+// resourceResults mints the tokens of the authorized resources for id.
+func (s *Service) resourceResults(ctx context.Context, clientID string, resources []*pb.ResourceTokenRequestState_Resource, ttl time.Duration, keyFile bool, id *ga4gh.Identity, cfg *pb.DamConfig) (*pb.ResourceResults, error) {
 	out := &pb.ResourceResults{
 		Resources:    make(map[string]*pb.ResourceResults_ResourceDescriptor),
 		Access:       make(map[string]*pb.ResourceResults_ResourceAccess),
 		EpochSeconds: uint32(time.Now().Unix()),
 	}
-	for i, r := range state.Resources {
+	for i, r := range resources {
 		res, ok := cfg.Resources[r.Resource]
 		if !ok {
 			return nil, status.Errorf(codes.NotFound, "resource not found: %q", r.Resource)
@@ -663,7 +805,7 @@ func (s *Service) fetchResourceTokens(r *http.Request) (_ *pb.ResourceResults, f
 			return nil, status.Errorf(codes.NotFound, "view %q not found for resource %q", r.View, r.Resource)
 		}
 
-		result, st, err := s.generateResourceToken(ctx, state.ClientId, r.Resource, r.View, r.Role, r.Interface, time.Duration(state.Ttl), keyFile, id, cfg, res, view)
+		result, st, err := s.generateResourceToken(ctx, clientID, r.Resource, r.View, r.Role, r.Interface, ttl, keyFile, id, cfg, res, view)
 		if err != nil {
 			return nil, status.Errorf(httputils.RPCCode(st), "%v", err)
 		}
@@ -44,6 +44,17 @@ const (
 	metaVersion    = "version"
 
 	maxRowsPerBatchOperation = 50000 // never exceed this number of rows without a LRO
+
+	// watchPollInterval is how often watchers poll the change log.
+	watchPollInterval = time.Second
+	// changeLogLookback is how far before the last change seen watchers look
+	// at again: changes are timestamped when written but become visible when
+	// the transactions commit, which may happen in a different order.
+	changeLogLookback = 30 * time.Second
+	// changeLogRetention is how long changes are kept in the change log.
+	changeLogRetention = time.Hour
+	// changeLogPruneInterval is how often the change log is pruned.
+	changeLogPruneInterval = changeLogRetention / 10
 )
 
 // Data
@@ -52,6 +63,7 @@ var (
 	entityKind  = "entity"
 	historyKind = "history"
 	metaKind    = "meta"
+	changeKind  = "change"
 )
 
 // Key is the key for items.
@@ -105,6 +117,19 @@ type History struct {
 	Content  string         `datastore:"content,noindex"`
 }
 
+// Change is a datastore entity for the change log.
+type Change struct {
+	Key      *datastore.Key `datastore:"__key__"`
+	Service  string         `datastore:"service"`
+	Datatype string         `datastore:"type"`
+	Realm    string         `datastore:"realm"`
+	User     string         `datastore:"user_id,noindex"`
+	ID       string         `datastore:"id,noindex"`
+	Rev      int64          `datastore:"rev,noindex"`
+	Op       string         `datastore:"op,noindex"`
+	Modified int64          `datastore:"modified"`
+}
+
 // Meta is a datastore entity for meta.
 type Meta struct {
 	Key   *datastore.Key `datastore:"__key__"`
@@ -123,6 +148,7 @@ func NewStore(ctx context.Context, project, service, path string) *Store {
 	if err := s.Init(context.Background()); err != nil {
 		glog.Fatalf("Datastore failed to initialize: %v", err)
 	}
+	go s.RunChangeLogPruning(ctx)
 	return s
 }
 
@@ -401,9 +427,41 @@ func (s *Store) WriteTx(datatype, realm, user, id string, rev int64, content pro
 		dstx.Rollback()
 		return err
 	}
+	if storage.Unwatched(datatype) {
+		return nil
+	}
+	c := s.newChange(datatype, realm, user, id, rev, storage.ChangeWrite)
+	if _, err := dstx.Tx.Put(c.Key, c); err != nil {
+		dstx.Rollback()
+		return err
+	}
 	return nil
 }
 
+// WriteIfMatchTx writes a data entity inside a transaction if the etag of the
+// entity matches. Datastore fails to commit the transaction if the entity read
+// for the check changes concurrently, which makes the check atomic.
+func (s *Store) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx storage.Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	if err := storage.CheckETagTx(s, datatype, realm, user, id, etag, content, tx); err != nil {
+		return err
+	}
+	return s.WriteTx(datatype, realm, user, id, rev, content, history, tx)
+}
+
 // Delete deletes a data entity.
 func (s *Store) Delete(datatype, realm, user, id string, rev int64) error {
 	return s.DeleteTx(datatype, realm, user, id, rev, nil)
@@ -438,6 +496,14 @@ func (s *Store) DeleteTx(datatype, realm, user, id string, rev int64, tx storage
 		}
 		return err
 	}
+	if storage.Unwatched(datatype) {
+		return nil
+	}
+	c := s.newChange(datatype, realm, user, id, rev, storage.ChangeDelete)
+	if _, err := dstx.Tx.Put(c.Key, c); err != nil {
+		dstx.Rollback()
+		return err
+	}
 
 	return nil
 }
@@ -456,8 +522,79 @@ func (s *Store) MultiDeleteTx(datatype, realm, user string, tx storage.Tx) error
 	q = q.Filter("rev = ", storage.LatestRev).
 		Order("id")
 
-	_, err := s.multiDelete(context.Background() /* TODO: pass ctx from request */, q, maxRowsPerBatchOperation)
-	return err
+	ctx := context.Background() /* TODO: pass ctx from request */
+	var entities []*Entity
+	if _, err := s.client.GetAll(ctx, q, &entities); err != nil {
+		return err
+	}
+	if _, err := s.multiDelete(ctx, q, maxRowsPerBatchOperation); err != nil {
+		return err
+	}
+	if storage.Unwatched(datatype) {
+		return nil
+	}
+	// Like the delete, the change log is not updated within the transaction.
+	var keys []*datastore.Key
+	var changes []*Change
+	for _, e := range entities {
+		c := s.newChange(e.Datatype, e.Realm, e.User, e.ID, e.Rev, storage.ChangeDelete)
+		keys = append(keys, c.Key)
+		changes = append(changes, c)
+	}
+	for i := 0; i < len(keys); i += 400 {
+		end := i + 400
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if _, err := s.client.PutMulti(ctx, keys[i:end], changes[i:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MultiDeleteHistoryTx deletes the history and the revisions other than the
+// latest of the records of a certain data type within a realm. Like
+// MultiDeleteTx, it is limited by maxRowsPerBatchOperation and the deletes are
+// not part of the transaction.
+// If user is "", deletes for all users.
+func (s *Store) MultiDeleteHistoryTx(datatype, realm, user string, tx storage.Tx) error {
+	query := func(kind string) *datastore.Query {
+		q := datastore.NewQuery(kind).
+			Filter("service =", s.service).
+			Filter("type =", datatype).
+			Filter("realm =", realm)
+		if user != storage.DefaultUser {
+			q = q.Filter("user_id =", user)
+		}
+		return q
+	}
+
+	ctx := context.Background() /* TODO: pass ctx from request */
+	if _, err := s.multiDelete(ctx, query(historyKind), maxRowsPerBatchOperation); err != nil {
+		return err
+	}
+	// Revisions are selected here as Datastore has no "not equal" filter.
+	var entities []*Entity
+	if _, err := s.client.GetAll(ctx, query(entityKind), &entities); err != nil {
+		return err
+	}
+	var keys []*datastore.Key
+	for _, e := range entities {
+		if e.Rev != storage.LatestRev {
+			keys = append(keys, e.Key)
+		}
+	}
+	for i := 0; i < len(keys); i += 400 {
+		end := i + 400
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := s.client.DeleteMulti(ctx, keys[i:end]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Wipe deletes all data and history within a realm but no more than maxEntries.
@@ -473,7 +610,7 @@ func (s *Store) Wipe(ctx context.Context, realm string, batchNum, maxEntries int
 	}
 	deleted := 0
 	max := maxEntries
-	for _, kind := range []string{historyKind, entityKind} {
+	for _, kind := range []string{historyKind, entityKind, changeKind} {
 		q := datastore.NewQuery(kind).
 			Filter("service =", s.service)
 		if realm != storage.AllRealms {
@@ -599,6 +736,100 @@ func (s *Store) LockTx(lockName string, minFrequency time.Duration, tx storage.T
 	return tx
 }
 
+// Watch polls the change log for changes committed after the call.
+// Changes are delivered within watchPollInterval of their commit.
+func (s *Store) Watch(ctx context.Context, datatype, realm string) (<-chan *storage.Change, error) {
+	ch := make(chan *storage.Change, storage.WatchBufferSize)
+	go s.pollChanges(ctx, datatype, realm, time.Now().UnixNano(), ch)
+	return ch, nil
+}
+
+// pollChanges delivers the changes written after start to ch until ctx is done.
+func (s *Store) pollChanges(ctx context.Context, datatype, realm string, start int64, ch chan<- *storage.Change) {
+	defer close(ch)
+
+	// seen contains the modified time of the changes already delivered within the lookback window.
+	seen := make(map[string]int64)
+	last := start
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		from := last - int64(changeLogLookback)
+		if from < start {
+			from = start
+		}
+		q := datastore.NewQuery(changeKind).
+			Filter("service =", s.service).
+			Filter("type =", datatype).
+			Filter("modified >", from).
+			Order("modified")
+		var changes []*Change
+		if _, err := s.client.GetAll(ctx, q, &changes); err != nil {
+			if ctx.Err() == nil {
+				glog.Warningf("datastore watch of %q in realm %q: reading change log failed: %v", datatype, realm, err)
+			}
+			continue
+		}
+		for _, c := range changes {
+			name := c.Key.Name
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = c.Modified
+			if c.Modified > last {
+				last = c.Modified
+			}
+			if realm != storage.AllRealms && c.Realm != realm {
+				continue
+			}
+			change := &storage.Change{Datatype: c.Datatype, Realm: c.Realm, User: c.User, ID: c.ID, Rev: c.Rev, Op: storage.ChangeOp(c.Op)}
+			select {
+			case ch <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+		for name, modified := range seen {
+			if modified <= last-int64(changeLogLookback) {
+				delete(seen, name)
+			}
+		}
+	}
+}
+
+// PruneChangeLog deletes the changes older than changeLogRetention from the
+// change log. Returns the number of changes deleted.
+func (s *Store) PruneChangeLog(ctx context.Context) (int, error) {
+	q := datastore.NewQuery(changeKind).
+		Filter("service =", s.service).
+		Filter("modified <", time.Now().Add(-changeLogRetention).UnixNano())
+	return s.multiDelete(ctx, q, maxRowsPerBatchOperation)
+}
+
+// RunChangeLogPruning prunes the change log every changeLogPruneInterval until
+// ctx is done, whether there are watchers or not. Typically this will be on its
+// own go routine.
+func (s *Store) RunChangeLogPruning(ctx context.Context) {
+	ticker := time.NewTicker(changeLogPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := s.PruneChangeLog(ctx); err != nil && ctx.Err() == nil {
+			glog.Warningf("datastore: pruning change log failed: %v", err)
+		}
+	}
+}
+
 // Init initilizes the store.
 // It creates some metadata information about the store on datastore.
 // If metada information already exists on datastore, it comapres to see if they
@@ -662,6 +893,22 @@ func (s *Store) newEntityKey(datatype, realm, user, id string, rev int64) string
 	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", s.service, datatype, realm, user, id, r)
 }
 
+// newChange creates a change log entity with a unique key.
+func (s *Store) newChange(datatype, realm, user, id string, rev int64, op storage.ChangeOp) *Change {
+	now := time.Now().UnixNano()
+	return &Change{
+		Key:      datastore.NameKey(changeKind, fmt.Sprintf("%s/%d/%08x", s.service, now, rand.Uint32()), nil),
+		Service:  s.service,
+		Datatype: datatype,
+		Realm:    realm,
+		User:     user,
+		ID:       id,
+		Rev:      rev,
+		Op:       string(op),
+		Modified: now,
+	}
+}
+
 func (s *Store) newEntity(key *datastore.Key, datatype, realm, user, id string, rev int64, content proto.Message) (*Entity, error) {
 	js, err := (&jsonpb.Marshaler{}).MarshalToString(content)
 	if err != nil {
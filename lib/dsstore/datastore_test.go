@@ -0,0 +1,59 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/datastore" /* copybara-comment: datastore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/storagetest" /* copybara-comment: storagetest */
+)
+
+// TestStore_Conformance requires a Datastore emulator given by
+// DATASTORE_EMULATOR_HOST, started with --consistency=1.0 as the tests query
+// right after writing. See itest/README.md.
+// Each store uses its own service name so tests do not see each other's data.
+func TestStore_Conformance(t *testing.T) {
+	if os.Getenv("DATASTORE_EMULATOR_HOST") == "" {
+		t.Skip("DATASTORE_EMULATOR_HOST is not set")
+	}
+	project := os.Getenv("DATASTORE_PROJECT_ID")
+	if project == "" {
+		project = "fake-project-id"
+	}
+	ctx := context.Background()
+	client, err := datastore.NewClient(ctx, project)
+	if err != nil {
+		t.Fatalf("datastore.NewClient(...) failed: %v", err)
+	}
+	defer client.Close()
+
+	n := 0
+	storagetest.Run(t, func() storage.Store {
+		n++
+		s := New(client, project, fmt.Sprintf("storagetest-%d-%d", os.Getpid(), n), "fake-config-path")
+		if err := s.Init(ctx); err != nil {
+			t.Errorf("store.Init(...) failed: %v", err)
+		}
+		t.Cleanup(func() {
+			s.Wipe(ctx, storage.AllRealms, 0, 0)
+		})
+		return s
+	}, &storagetest.Options{ChangeLog: true, NoReadOwnWrites: true})
+}
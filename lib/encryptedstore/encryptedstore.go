@@ -0,0 +1,536 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encryptedstore provides a storage.Store decorator encrypting
+// selected datatypes or proto fields at rest with a kms.Encryption.
+package encryptedstore
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect" /* copybara-comment */
+	"github.com/golang/protobuf/jsonpb" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	spb "github.com/golang/protobuf/ptypes/struct" /* copybara-comment */
+	wpb "github.com/golang/protobuf/ptypes/wrappers" /* copybara-comment */
+)
+
+const (
+	// fieldPrefix marks an encrypted string field value.
+	// Values without it are read as is, so field encryption can be enabled
+	// for datatypes which already have items in storage.
+	fieldPrefix = "enc:"
+)
+
+// Policy selects what is encrypted for each datatype.
+// A datatype mapped to no fields is encrypted as a whole item. Otherwise only
+// the given top-level fields are encrypted, which must be string, repeated
+// string or map with string values fields. Other datatypes are not encrypted.
+type Policy map[string][]string
+
+// DefaultPolicy encrypts the service secrets, remembered consents and the
+// tokens of CLI login states.
+var DefaultPolicy = Policy{
+	storage.SecretsDatatype:           nil,
+	storage.RememberedConsentDatatype: nil,
+	storage.CliAuthDatatype:           {"secret", "access_token", "refresh_token"},
+}
+
+// Store is a storage.Store encrypting items of another store according to a
+// policy. The realm, datatype and id of an item are used as additional
+// authenticated data, so encrypted content cannot be moved to another item.
+//
+// Whole encrypted items are stored as wrapped bytes, so MultiReadTx filters
+// and pagination of encrypted datatypes are evaluated after decryption on all
+// matching items. History entries are not encrypted.
+//
+// Whole items stored in plaintext before encryption was enabled for their
+// datatype are read as is, and encrypted when they are next written or
+// rewrapped.
+type Store struct {
+	ctx    context.Context
+	store  storage.Store
+	enc    kms.Encryption
+	policy Policy
+}
+
+// New creates a Store encrypting the items of store selected by policy.
+func New(ctx context.Context, store storage.Store, enc kms.Encryption, policy Policy) *Store {
+	return &Store{
+		ctx:    ctx,
+		store:  store,
+		enc:    enc,
+		policy: policy,
+	}
+}
+
+// Info returns the info of the underlying store.
+func (s *Store) Info() map[string]string {
+	return s.store.Info()
+}
+
+// Exists checks if data item with given key exists.
+func (s *Store) Exists(datatype, realm, user, id string, rev int64) (bool, error) {
+	return s.store.Exists(datatype, realm, user, id, rev)
+}
+
+// Read reads a data item of a given key.
+func (s *Store) Read(datatype, realm, user, id string, rev int64, content proto.Message) error {
+	return s.ReadTx(datatype, realm, user, id, rev, content, nil)
+}
+
+// ReadTx reads a data item of a given key inside a transaction.
+func (s *Store) ReadTx(datatype, realm, user, id string, rev int64, content proto.Message, tx storage.Tx) error {
+	fields, ok := s.policy[datatype]
+	if !ok {
+		return s.store.ReadTx(datatype, realm, user, id, rev, content, tx)
+	}
+	stored, err := s.readStored(datatype, realm, user, id, rev, fields, content, tx)
+	if err != nil {
+		return err
+	}
+	return s.decrypt(datatype, realm, id, fields, stored, content)
+}
+
+// MultiReadTx reads a set of objects matching the input parameters and filters.
+func (s *Store) MultiReadTx(datatype, realm, user, id string, filters [][]storage.Filter, offset, pageSize int, typ proto.Message, tx storage.Tx) (*storage.Results, error) {
+	fields, ok := s.policy[datatype]
+	if !ok {
+		return s.store.MultiReadTx(datatype, realm, user, id, filters, offset, pageSize, typ, tx)
+	}
+
+	if pageSize > storage.MaxPageSize {
+		pageSize = storage.MaxPageSize
+	}
+	results := storage.NewResults()
+	for next := 0; ; {
+		page, err := s.readPage(datatype, realm, user, id, fields, next, typ, tx)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page.Entries {
+			p := proto.Clone(typ)
+			if err := s.decrypt(datatype, e.Realm, e.ItemID, fields, e.Item, p); err != nil {
+				return nil, err
+			}
+			if !storage.MatchProtoFilters(filters, p) {
+				continue
+			}
+			// For pagination, decrease any remaining offset before accepting this entry.
+			if offset > 0 {
+				offset--
+				continue
+			}
+			if pageSize > results.MatchCount {
+				results.Entries = append(results.Entries, &storage.Entry{
+					Realm:   e.Realm,
+					GroupID: e.GroupID,
+					ItemID:  e.ItemID,
+					Item:    p,
+				})
+			}
+			results.MatchCount++
+		}
+		next += len(page.Entries)
+		if len(page.Entries) < storage.MaxPageSize {
+			return results, nil
+		}
+	}
+}
+
+// ReadHistory reads the history.
+func (s *Store) ReadHistory(datatype, realm, user, id string, content *[]proto.Message) error {
+	return s.store.ReadHistory(datatype, realm, user, id, content)
+}
+
+// ReadHistoryTx reads the history inside a transaction.
+func (s *Store) ReadHistoryTx(datatype, realm, user, id string, content *[]proto.Message, tx storage.Tx) error {
+	return s.store.ReadHistoryTx(datatype, realm, user, id, content, tx)
+}
+
+// Write writes an item.
+func (s *Store) Write(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message) error {
+	return s.WriteTx(datatype, realm, user, id, rev, content, history, nil)
+}
+
+// WriteTx writes an item inside a transaction.
+func (s *Store) WriteTx(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message, tx storage.Tx) error {
+	fields, ok := s.policy[datatype]
+	if !ok {
+		return s.store.WriteTx(datatype, realm, user, id, rev, content, history, tx)
+	}
+	stored, err := s.encrypt(datatype, realm, id, fields, content)
+	if err != nil {
+		return err
+	}
+	return s.store.WriteTx(datatype, realm, user, id, rev, stored, history, tx)
+}
+
+// WriteIfMatchTx writes an item inside a transaction if the etag of its
+// latest revision matches. The etag is the one of the decrypted content.
+func (s *Store) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx storage.Tx) (ferr error) {
+	fields, ok := s.policy[datatype]
+	if !ok {
+		return s.store.WriteIfMatchTx(datatype, realm, user, id, rev, etag, content, history, tx)
+	}
+	stored, err := s.encrypt(datatype, realm, id, fields, content)
+	if err != nil {
+		return err
+	}
+	if etag == "" || etag == storage.AnyETag {
+		return s.store.WriteIfMatchTx(datatype, realm, user, id, rev, etag, stored, history, tx)
+	}
+
+	if tx == nil {
+		var err error
+		tx, err = s.store.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	// Encryption is not deterministic: translate the etag of the decrypted
+	// content to the one of the stored content, which the underlying store
+	// checks atomically.
+	current, err := s.readStored(datatype, realm, user, id, storage.LatestRev, fields, content, tx)
+	if err != nil {
+		if storage.ErrNotFound(err) {
+			return errutil.NewPreconditionFailedError(fmt.Sprintf("%s %q does not exist", datatype, id))
+		}
+		return err
+	}
+	plain := proto.Clone(content)
+	if err := s.decrypt(datatype, realm, id, fields, current, plain); err != nil {
+		return err
+	}
+	if storage.ETag(plain) != etag {
+		return errutil.NewPreconditionFailedError(fmt.Sprintf("%s %q has been modified", datatype, id))
+	}
+	return s.store.WriteIfMatchTx(datatype, realm, user, id, rev, storage.ETag(current), stored, history, tx)
+}
+
+// Delete deletes a record.
+func (s *Store) Delete(datatype, realm, user, id string, rev int64) error {
+	return s.store.Delete(datatype, realm, user, id, rev)
+}
+
+// DeleteTx deletes a record inside a transaction.
+func (s *Store) DeleteTx(datatype, realm, user, id string, rev int64, tx storage.Tx) error {
+	return s.store.DeleteTx(datatype, realm, user, id, rev, tx)
+}
+
+// MultiDeleteTx deletes all records of a certain data type within a realm.
+func (s *Store) MultiDeleteTx(datatype, realm, user string, tx storage.Tx) error {
+	return s.store.MultiDeleteTx(datatype, realm, user, tx)
+}
+
+// MultiDeleteHistoryTx deletes the history and old revisions of all records of a certain data type within a realm.
+func (s *Store) MultiDeleteHistoryTx(datatype, realm, user string, tx storage.Tx) error {
+	return s.store.MultiDeleteHistoryTx(datatype, realm, user, tx)
+}
+
+// Wipe deletes all data within a realm.
+func (s *Store) Wipe(ctx context.Context, realm string, batchNum, maxEntries int) (int, error) {
+	return s.store.Wipe(ctx, realm, batchNum, maxEntries)
+}
+
+// Tx creates a new transaction.
+func (s *Store) Tx(update bool) (storage.Tx, error) {
+	return s.store.Tx(update)
+}
+
+// LockTx returns a storage-wide lock by the given name.
+func (s *Store) LockTx(lockName string, minFrequency time.Duration, tx storage.Tx) storage.Tx {
+	return s.store.LockTx(lockName, minFrequency, tx)
+}
+
+// Watch returns a channel receiving the changes of items of the underlying store.
+func (s *Store) Watch(ctx context.Context, datatype, realm string) (<-chan *storage.Change, error) {
+	return s.store.Watch(ctx, datatype, realm)
+}
+
+// Rewrap re-encrypts the latest revision of the items of a datatype within a
+// realm, all realms if realm is "", with the current key of the encryption so
+// that older keys can be retired after a key rotation. Items and field values
+// stored in plaintext are encrypted as well. Older revisions are not rewrapped. typ is
+// the type of the items. Returns the number of items written.
+func (s *Store) Rewrap(datatype, realm string, typ proto.Message) (int, error) {
+	fields, ok := s.policy[datatype]
+	if !ok {
+		return 0, fmt.Errorf("datatype %q is not encrypted", datatype)
+	}
+	n := 0
+	for {
+		page, err := s.rewrapPage(datatype, realm, fields, n, typ)
+		n += page
+		if err != nil || page < storage.MaxPageSize {
+			return n, err
+		}
+	}
+}
+
+// rewrapPage rewraps a page of items in a transaction.
+func (s *Store) rewrapPage(datatype, realm string, fields []string, offset int, typ proto.Message) (_ int, ferr error) {
+	tx, err := s.store.Tx(true)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err := tx.Finish()
+		if ferr == nil {
+			ferr = err
+		}
+	}()
+
+	page, err := s.readPage(datatype, realm, storage.MatchAllUsers, storage.MatchAllIDs, fields, offset, typ, tx)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range page.Entries {
+		p := proto.Clone(typ)
+		if err := s.decrypt(datatype, e.Realm, e.ItemID, fields, e.Item, p); err != nil {
+			return i, err
+		}
+		stored, err := s.encrypt(datatype, e.Realm, e.ItemID, fields, p)
+		if err != nil {
+			return i, err
+		}
+		if err := s.store.WriteTx(datatype, e.Realm, e.GroupID, e.ItemID, storage.LatestRev, stored, nil, tx); err != nil {
+			return i, err
+		}
+	}
+	return len(page.Entries), nil
+}
+
+// aad returns the additional authenticated data of an item.
+func aad(datatype, realm, id string) string {
+	return strings.Join([]string{realm, datatype, id}, "/")
+}
+
+// storedType returns an empty message of the type stored for a datatype.
+func storedType(fields []string, typ proto.Message) proto.Message {
+	if len(fields) == 0 {
+		return &wpb.BytesValue{}
+	}
+	return newMessage(typ)
+}
+
+// newMessage returns an empty message of the type of typ.
+func newMessage(typ proto.Message) proto.Message {
+	p := proto.Clone(typ)
+	p.Reset()
+	return p
+}
+
+// readStored reads the stored message of an item. A whole item which cannot
+// be read as encrypted bytes is read as typ, for items stored in plaintext.
+func (s *Store) readStored(datatype, realm, user, id string, rev int64, fields []string, typ proto.Message, tx storage.Tx) (proto.Message, error) {
+	stored := storedType(fields, typ)
+	err := s.store.ReadTx(datatype, realm, user, id, rev, stored, tx)
+	if err == nil || len(fields) > 0 || storage.ErrNotFound(err) {
+		return stored, err
+	}
+	plain := newMessage(typ)
+	if perr := s.store.ReadTx(datatype, realm, user, id, rev, plain, tx); perr != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// readPage reads a page of the stored messages of items. If whole items
+// cannot be read as encrypted bytes, the page is read as JSON values, and the
+// items stored in plaintext are read as typ next to the encrypted ones.
+func (s *Store) readPage(datatype, realm, user, id string, fields []string, offset int, typ proto.Message, tx storage.Tx) (*storage.Results, error) {
+	page, err := s.store.MultiReadTx(datatype, realm, user, id, nil, offset, storage.MaxPageSize, storedType(fields, typ), tx)
+	if err == nil || len(fields) > 0 || storage.ErrNotFound(err) {
+		return page, err
+	}
+	mixed, merr := s.store.MultiReadTx(datatype, realm, user, id, nil, offset, storage.MaxPageSize, &spb.Value{}, tx)
+	if merr != nil {
+		return nil, err
+	}
+	for _, e := range mixed.Entries {
+		v := e.Item.(*spb.Value)
+		if b64, ok := v.Kind.(*spb.Value_StringValue); ok {
+			// The JSON encoding of wrapped bytes.
+			b, err := base64.StdEncoding.DecodeString(b64.StringValue)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s %q: invalid encrypted bytes: %v", datatype, e.ItemID, err)
+			}
+			e.Item = &wpb.BytesValue{Value: b}
+			continue
+		}
+		js, err := (&jsonpb.Marshaler{}).MarshalToString(v)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s %q: %v", datatype, e.ItemID, err)
+		}
+		plain := newMessage(typ)
+		if err := jsonpb.UnmarshalString(js, plain); err != nil {
+			return nil, fmt.Errorf("reading %s %q: %v", datatype, e.ItemID, err)
+		}
+		e.Item = plain
+	}
+	return mixed, nil
+}
+
+// encrypt returns the message to store for content.
+func (s *Store) encrypt(datatype, realm, id string, fields []string, content proto.Message) (proto.Message, error) {
+	ad := aad(datatype, realm, id)
+	if len(fields) == 0 {
+		b, err := proto.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting %s %q: marshal failed: %v", datatype, id, err)
+		}
+		b, err = s.enc.Encrypt(s.ctx, b, ad)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting %s %q: %v", datatype, id, err)
+		}
+		return &wpb.BytesValue{Value: b}, nil
+	}
+
+	stored := proto.Clone(content)
+	err := transformFields(stored, fields, func(v string) (string, error) {
+		if len(v) == 0 {
+			return v, nil
+		}
+		b, err := s.enc.Encrypt(s.ctx, []byte(v), ad)
+		if err != nil {
+			return "", err
+		}
+		return fieldPrefix + base64.StdEncoding.EncodeToString(b), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encrypting %s %q: %v", datatype, id, err)
+	}
+	return stored, nil
+}
+
+// decrypt sets content to the decrypted stored message.
+func (s *Store) decrypt(datatype, realm, id string, fields []string, stored, content proto.Message) error {
+	ad := aad(datatype, realm, id)
+	if len(fields) == 0 {
+		w, ok := stored.(*wpb.BytesValue)
+		if !ok {
+			// Stored in plaintext, see readStored.
+			content.Reset()
+			proto.Merge(content, stored)
+			return nil
+		}
+		b, err := s.enc.Decrypt(s.ctx, w.Value, ad)
+		if err != nil {
+			return fmt.Errorf("decrypting %s %q: %v", datatype, id, err)
+		}
+		content.Reset()
+		if err := proto.Unmarshal(b, content); err != nil {
+			return fmt.Errorf("decrypting %s %q: unmarshal failed: %v", datatype, id, err)
+		}
+		return nil
+	}
+
+	plain := proto.Clone(stored)
+	err := transformFields(plain, fields, func(v string) (string, error) {
+		if !strings.HasPrefix(v, fieldPrefix) {
+			return v, nil
+		}
+		b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, fieldPrefix))
+		if err != nil {
+			return "", err
+		}
+		b, err = s.enc.Decrypt(s.ctx, b, ad)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	})
+	if err != nil {
+		return fmt.Errorf("decrypting %s %q: %v", datatype, id, err)
+	}
+	content.Reset()
+	proto.Merge(content, plain)
+	return nil
+}
+
+// transformFields replaces the values of the given string fields of msg with
+// the results of fn.
+func transformFields(msg proto.Message, fields []string, fn func(string) (string, error)) error {
+	m := proto.MessageReflect(msg)
+	for _, name := range fields {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return fmt.Errorf("field %q not found in %s", name, m.Descriptor().FullName())
+		}
+		switch {
+		case fd.IsMap() && fd.MapValue().Kind() == protoreflect.StringKind:
+			if !m.Has(fd) {
+				continue
+			}
+			mp := m.Mutable(fd).Map()
+			var keys []protoreflect.MapKey
+			mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, k := range keys {
+				v, err := fn(mp.Get(k).String())
+				if err != nil {
+					return fmt.Errorf("field %q: %v", name, err)
+				}
+				mp.Set(k, protoreflect.ValueOfString(v))
+			}
+
+		case fd.IsList() && fd.Kind() == protoreflect.StringKind:
+			if !m.Has(fd) {
+				continue
+			}
+			list := m.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				v, err := fn(list.Get(i).String())
+				if err != nil {
+					return fmt.Errorf("field %q: %v", name, err)
+				}
+				list.Set(i, protoreflect.ValueOfString(v))
+			}
+
+		case fd.Cardinality() != protoreflect.Repeated && fd.Kind() == protoreflect.StringKind:
+			if !m.Has(fd) {
+				continue
+			}
+			v, err := fn(m.Get(fd).String())
+			if err != nil {
+				return fmt.Errorf("field %q: %v", name, err)
+			}
+			m.Set(fd, protoreflect.ValueOfString(v))
+
+		default:
+			return fmt.Errorf("field %q of %s is not a string field", name, m.Descriptor().FullName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryptedstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/protobuf/testing/protocmp" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/boltstore" /* copybara-comment: boltstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localcrypt" /* copybara-comment: localcrypt */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/storagetest" /* copybara-comment: storagetest */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakestore" /* copybara-comment: fakestore */
+
+	wpb "github.com/golang/protobuf/ptypes/wrappers" /* copybara-comment */
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+	dpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+const (
+	fakeRealm = "fake-realm"
+	fakeUser  = "fake-user"
+	fakeID    = "fake-id"
+)
+
+// aesEncryption is an AES-GCM kms.Encryption for tests.
+type aesEncryption struct {
+	aead cipher.AEAD
+}
+
+func newAESEncryption(t *testing.T) *aesEncryption {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("rand.Read() failed: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("aes.NewCipher() failed: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("cipher.NewGCM() failed: %v", err)
+	}
+	return &aesEncryption{aead: aead}
+}
+
+func (e *aesEncryption) Encrypt(ctx context.Context, data []byte, additionalAuthData string) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return e.aead.Seal(nonce, nonce, data, []byte(additionalAuthData)), nil
+}
+
+func (e *aesEncryption) Decrypt(ctx context.Context, encrypted []byte, additionalAuthData string) ([]byte, error) {
+	n := e.aead.NonceSize()
+	if len(encrypted) < n {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return e.aead.Open(nil, encrypted[:n], encrypted[n:], []byte(additionalAuthData))
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Store {
+		return New(context.Background(), fakestore.New(), newAESEncryption(t), Policy{"fake-datatype": nil})
+	}, nil)
+}
+
+func TestStore_WholeItem(t *testing.T) {
+	raw := fakestore.New()
+	s := New(context.Background(), raw, newAESEncryption(t), DefaultPolicy)
+
+	want := &dpb.DamSecrets{ClientSecrets: map[string]string{"client": "secret"}}
+	if err := s.Write(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, want, nil); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	got := &dpb.DamSecrets{}
+	if err := s.Read(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Read() diff (-want +got):\n%s", diff)
+	}
+
+	stored := &wpb.BytesValue{}
+	if err := raw.Read(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, stored); err != nil {
+		t.Fatalf("raw.Read() failed: %v", err)
+	}
+	if strings.Contains(string(stored.Value), "secret") {
+		t.Errorf("stored item %q contains the plaintext", stored.Value)
+	}
+}
+
+func TestStore_WholeItem_Plaintext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryptedstore")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	// A store serializing items, where secrets were stored before encryption
+	// was enabled.
+	raw := boltstore.NewStore(filepath.Join(dir, "test.db"), "fake-service", "fake-config-path")
+	defer raw.Close()
+	plain := &dpb.DamSecrets{ClientSecrets: map[string]string{"client": "plain"}}
+	for _, id := range []string{"a", "b"} {
+		if err := raw.Write(storage.SecretsDatatype, fakeRealm, fakeUser, id, storage.LatestRev, plain, nil); err != nil {
+			t.Fatalf("raw.Write() failed: %v", err)
+		}
+	}
+	s := New(context.Background(), raw, newAESEncryption(t), DefaultPolicy)
+	encrypted := &dpb.DamSecrets{ClientSecrets: map[string]string{"client": "encrypted"}}
+	if err := s.Write(storage.SecretsDatatype, fakeRealm, fakeUser, "c", storage.LatestRev, encrypted, nil); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	got := &dpb.DamSecrets{}
+	if err := s.Read(storage.SecretsDatatype, fakeRealm, fakeUser, "a", storage.LatestRev, got); err != nil {
+		t.Fatalf("Read() of plaintext item failed: %v", err)
+	}
+	if diff := cmp.Diff(plain, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Read() of plaintext item diff (-want +got):\n%s", diff)
+	}
+	results, err := s.MultiReadTx(storage.SecretsDatatype, fakeRealm, fakeUser, storage.MatchAllIDs, nil, 0, 10, &dpb.DamSecrets{}, nil)
+	if err != nil {
+		t.Fatalf("MultiReadTx() of plaintext and encrypted items failed: %v", err)
+	}
+	want := map[string]*dpb.DamSecrets{"a": plain, "b": plain, "c": encrypted}
+	gotItems := map[string]*dpb.DamSecrets{}
+	for _, e := range results.Entries {
+		gotItems[e.ItemID] = e.Item.(*dpb.DamSecrets)
+	}
+	if diff := cmp.Diff(want, gotItems, protocmp.Transform()); diff != "" {
+		t.Errorf("MultiReadTx() diff (-want +got):\n%s", diff)
+	}
+
+	// Plaintext items are encrypted on write and by Rewrap.
+	if err := s.Write(storage.SecretsDatatype, fakeRealm, fakeUser, "a", storage.LatestRev, plain, nil); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if n, err := s.Rewrap(storage.SecretsDatatype, fakeRealm, &dpb.DamSecrets{}); err != nil || n != 3 {
+		t.Fatalf("Rewrap() = %d, %v, want 3 items", n, err)
+	}
+	for id := range want {
+		stored := &wpb.BytesValue{}
+		if err := raw.Read(storage.SecretsDatatype, fakeRealm, fakeUser, id, storage.LatestRev, stored); err != nil {
+			t.Errorf("raw.Read(%q) as encrypted bytes failed: %v", id, err)
+		}
+	}
+}
+
+func TestStore_Fields(t *testing.T) {
+	raw := fakestore.New()
+	s := New(context.Background(), raw, newAESEncryption(t), DefaultPolicy)
+
+	want := &cpb.CliState{Id: fakeID, Email: "a@example.com", AccessToken: "access", RefreshToken: "refresh"}
+	if err := s.Write(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, fakeID, storage.LatestRev, want, nil); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	got := &cpb.CliState{}
+	if err := s.Read(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Read() diff (-want +got):\n%s", diff)
+	}
+
+	stored := &cpb.CliState{}
+	if err := raw.Read(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, fakeID, storage.LatestRev, stored); err != nil {
+		t.Fatalf("raw.Read() failed: %v", err)
+	}
+	if stored.Email != want.Email {
+		t.Errorf("stored Email = %q, want %q", stored.Email, want.Email)
+	}
+	if !strings.HasPrefix(stored.AccessToken, fieldPrefix) || !strings.HasPrefix(stored.RefreshToken, fieldPrefix) {
+		t.Errorf("stored tokens = %q, %q, want encrypted", stored.AccessToken, stored.RefreshToken)
+	}
+	if len(stored.Secret) != 0 {
+		t.Errorf("stored Secret = %q, want empty", stored.Secret)
+	}
+}
+
+func TestStore_Fields_Plaintext(t *testing.T) {
+	raw := fakestore.New()
+	s := New(context.Background(), raw, newAESEncryption(t), DefaultPolicy)
+
+	// Items written before encryption was enabled are still readable.
+	want := &cpb.CliState{Id: fakeID, AccessToken: "access"}
+	if err := raw.Write(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, fakeID, storage.LatestRev, want, nil); err != nil {
+		t.Fatalf("raw.Write() failed: %v", err)
+	}
+	got := &cpb.CliState{}
+	if err := s.Read(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Read() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestStore_Fields_NotString(t *testing.T) {
+	s := New(context.Background(), fakestore.New(), newAESEncryption(t), Policy{storage.CliAuthDatatype: {"created_at"}})
+
+	err := s.Write(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, fakeID, storage.LatestRev, &cpb.CliState{}, nil)
+	if err == nil {
+		t.Errorf("Write() succeeded for a non-string field, want error")
+	}
+}
+
+func TestStore_AdditionalAuthData(t *testing.T) {
+	raw := fakestore.New()
+	s := New(context.Background(), raw, newAESEncryption(t), DefaultPolicy)
+
+	secrets := &dpb.DamSecrets{ClientSecrets: map[string]string{"client": "secret"}}
+	if err := s.Write(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, secrets, nil); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	// Copy the encrypted item to another id and realm.
+	stored := &wpb.BytesValue{}
+	if err := raw.Read(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, stored); err != nil {
+		t.Fatalf("raw.Read() failed: %v", err)
+	}
+	if err := raw.Write(storage.SecretsDatatype, fakeRealm, fakeUser, "other-id", storage.LatestRev, stored, nil); err != nil {
+		t.Fatalf("raw.Write() failed: %v", err)
+	}
+	if err := raw.Write(storage.SecretsDatatype, "other-realm", fakeUser, fakeID, storage.LatestRev, stored, nil); err != nil {
+		t.Fatalf("raw.Write() failed: %v", err)
+	}
+
+	if err := s.Read(storage.SecretsDatatype, fakeRealm, fakeUser, "other-id", storage.LatestRev, &dpb.DamSecrets{}); err == nil {
+		t.Errorf("Read() of an item copied to another id succeeded, want error")
+	}
+	if err := s.Read(storage.SecretsDatatype, "other-realm", fakeUser, fakeID, storage.LatestRev, &dpb.DamSecrets{}); err == nil {
+		t.Errorf("Read() of an item copied to another realm succeeded, want error")
+	}
+}
+
+func TestStore_MultiReadTx(t *testing.T) {
+	s := New(context.Background(), fakestore.New(), newAESEncryption(t), DefaultPolicy)
+
+	for _, id := range []string{"a", "b", "c"} {
+		item := &cpb.CliState{Id: id, AccessToken: "token-" + id}
+		if err := s.Write(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, id, storage.LatestRev, item, nil); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+
+	filters, err := storage.BuildFilters(`token ne "token-a"`, map[string]func(p proto.Message) string{
+		"token": func(p proto.Message) string { return p.(*cpb.CliState).AccessToken },
+	})
+	if err != nil {
+		t.Fatalf("BuildFilters() failed: %v", err)
+	}
+	results, err := s.MultiReadTx(storage.CliAuthDatatype, fakeRealm, storage.DefaultUser, storage.MatchAllIDs, filters, 1, 10, &cpb.CliState{}, nil)
+	if err != nil {
+		t.Fatalf("MultiReadTx() failed: %v", err)
+	}
+	var got []string
+	for _, e := range results.Entries {
+		got = append(got, e.Item.(*cpb.CliState).AccessToken)
+	}
+	if diff := cmp.Diff([]string{"token-c"}, got); diff != "" {
+		t.Errorf("MultiReadTx() diff (-want +got):\n%s", diff)
+	}
+	if results.MatchCount != 1 {
+		t.Errorf("MultiReadTx() MatchCount = %d, want 1", results.MatchCount)
+	}
+}
+
+func TestStore_WriteIfMatchTx(t *testing.T) {
+	s := New(context.Background(), fakestore.New(), newAESEncryption(t), DefaultPolicy)
+
+	v1 := &dpb.DamSecrets{Version: "v1"}
+	if err := s.WriteIfMatchTx(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, "", v1, nil, nil); err != nil {
+		t.Fatalf("WriteIfMatchTx(create) failed: %v", err)
+	}
+
+	v2 := &dpb.DamSecrets{Version: "v2"}
+	if err := s.WriteIfMatchTx(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, storage.ETag(v1), v2, nil, nil); err != nil {
+		t.Fatalf("WriteIfMatchTx(etag of v1) failed: %v", err)
+	}
+
+	err := s.WriteIfMatchTx(storage.SecretsDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, storage.ETag(v1), v1, nil, nil)
+	if !errutil.PreconditionFailed(err) {
+		t.Errorf("WriteIfMatchTx(stale etag) = %v, want precondition failed", err)
+	}
+}
+
+func TestStore_Rewrap(t *testing.T) {
+	ctx := context.Background()
+	key := func(b byte) string { return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32)) }
+	newStore := func(raw storage.Store, keys ...*localcrypt.Key) *Store {
+		enc, err := localcrypt.NewFromKeyring(&localcrypt.Keyring{Keys: keys})
+		if err != nil {
+			t.Fatalf("localcrypt.NewFromKeyring() failed: %v", err)
+		}
+		return New(ctx, raw, enc, DefaultPolicy)
+	}
+	key1 := &localcrypt.Key{Version: 1, Key: key(1)}
+	key2 := &localcrypt.Key{Version: 2, Key: key(2)}
+
+	raw := fakestore.New()
+	old := newStore(raw, key1)
+	secrets := &dpb.DamSecrets{ClientSecrets: map[string]string{"client": "secret"}}
+	if err := old.Write(storage.SecretsDatatype, fakeRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, secrets, nil); err != nil {
+		t.Fatalf("Write(secrets) failed: %v", err)
+	}
+	cli := &cpb.CliState{Id: fakeID, AccessToken: "access"}
+	if err := old.Write(storage.CliAuthDatatype, "other-realm", storage.DefaultUser, fakeID, storage.LatestRev, cli, nil); err != nil {
+		t.Fatalf("Write(cli_auth) failed: %v", err)
+	}
+
+	rotated := newStore(raw, key1, key2)
+	if n, err := rotated.Rewrap(storage.SecretsDatatype, storage.AllRealms, &dpb.DamSecrets{}); err != nil || n != 1 {
+		t.Fatalf("Rewrap(secrets) = %d, %v, want 1 item", n, err)
+	}
+	if n, err := rotated.Rewrap(storage.CliAuthDatatype, storage.AllRealms, &cpb.CliState{}); err != nil || n != 1 {
+		t.Fatalf("Rewrap(cli_auth) = %d, %v, want 1 item", n, err)
+	}
+	if _, err := rotated.Rewrap(storage.AccountDatatype, storage.AllRealms, &cpb.Account{}); err == nil {
+		t.Errorf("Rewrap(account) succeeded, want error for a datatype which is not encrypted")
+	}
+
+	// The items are readable without the old key.
+	latest := newStore(raw, key2)
+	gotSecrets := &dpb.DamSecrets{}
+	if err := latest.Read(storage.SecretsDatatype, fakeRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, gotSecrets); err != nil {
+		t.Fatalf("Read(secrets) failed: %v", err)
+	}
+	if diff := cmp.Diff(secrets, gotSecrets, protocmp.Transform()); diff != "" {
+		t.Errorf("Read(secrets) diff (-want +got):\n%s", diff)
+	}
+	gotCli := &cpb.CliState{}
+	if err := latest.Read(storage.CliAuthDatatype, "other-realm", storage.DefaultUser, fakeID, storage.LatestRev, gotCli); err != nil {
+		t.Fatalf("Read(cli_auth) failed: %v", err)
+	}
+	if diff := cmp.Diff(cli, gotCli, protocmp.Transform()); diff != "" {
+		t.Errorf("Read(cli_auth) diff (-want +got):\n%s", diff)
+	}
+}
@@ -27,6 +27,11 @@ import (
 	edpb "google.golang.org/genproto/googleapis/rpc/errdetails" /* copybara-comment */
 )
 
+const (
+	// preconditionFailedReason is the error reason of failed conditional requests.
+	preconditionFailedReason = "precondition_failed"
+)
+
 // NewError returns a Status error with path or name field.
 func NewError(code codes.Code, name string, msg string) error {
 	s := status.New(code, msg)
@@ -119,3 +124,18 @@ func NotFound(err error) bool {
 	}
 	return st.Code() == codes.NotFound
 }
+
+// NewPreconditionFailedError returns a FailedPrecondition Status error for a
+// conditional request or write whose condition, such as an etag, is not met.
+// HTTP handlers respond to it with 412 Precondition Failed.
+func NewPreconditionFailedError(msg string) error {
+	return WithErrorReason(preconditionFailedReason, status.Error(codes.FailedPrecondition, msg))
+}
+
+// PreconditionFailed checks if the given error is a failed conditional request.
+func PreconditionFailed(err error) bool {
+	if status.Code(err) != codes.FailedPrecondition {
+		return false
+	}
+	return ErrorReason(err) == preconditionFailedReason
+}
@@ -131,3 +131,39 @@ func TestWithMetadata(t *testing.T) {
 		t.Errorf("s.Details() (-want, +got): %s", d)
 	}
 }
+
+func TestPreconditionFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "precondition failed",
+			err:  NewPreconditionFailedError("etag mismatch"),
+			want: true,
+		},
+		{
+			name: "other failed precondition",
+			err:  status.Error(codes.FailedPrecondition, "this is a error"),
+			want: false,
+		},
+		{
+			name: "other code with reason",
+			err:  WithErrorReason(preconditionFailedReason, status.Error(codes.Internal, "this is a error")),
+			want: false,
+		},
+		{
+			name: "not status err",
+			err:  fmt.Errorf("this is a error"),
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PreconditionFailed(tc.err); got != tc.want {
+				t.Errorf("PreconditionFailed(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,11 @@ package ga4gh
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ontology" /* copybara-comment: ontology */
 
 	glog "github.com/golang/glog" /* copybara-comment */
 	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
@@ -38,8 +43,10 @@ func CheckConditions(ctx context.Context, c Conditions, vs []*Visa, f JWTVerifie
 	if len(c) == 0 {
 		return nil
 	}
-	for _, clause := range c {
-		if err := checkClause(ctx, clause, vs, f); err == nil {
+	ctx, t := StartTrace(ctx, TraceConditions, "")
+	for i, clause := range c {
+		if err := checkClause(ctx, i, clause, vs, f); err == nil {
+			t.SetResult(true)
 			return nil
 		}
 	}
@@ -52,32 +59,65 @@ type Clause []Condition
 // Literal is a Condition.
 type Literal = Condition
 
-func checkClause(ctx context.Context, c Clause, vs []*Visa, f JWTVerifier) error {
+func checkClause(ctx context.Context, i int, c Clause, vs []*Visa, f JWTVerifier) error {
 	glog.V(1).Info("checkClause")
+	ctx, t := StartTrace(ctx, TraceClause, strconv.Itoa(i))
 	for _, literal := range c {
 		if err := checkLiteral(ctx, literal, vs, f); err != nil {
 			return err
 		}
 	}
+	t.SetResult(true)
 	return nil
 }
 
 func checkLiteral(ctx context.Context, l Literal, vs []*Visa, f JWTVerifier) error {
 	glog.V(1).Info("checkLiteral")
+	_, t := StartTrace(ctx, TraceLiteral, literalName(l))
 	for _, v := range vs {
-		if err := CheckCondition(l, v.Data().Assertion); err != nil {
+		d := v.Data()
+		if reason, field, err := checkCondition(l, d.Assertion); err != nil {
 			glog.V(1).Infof("CheckCondition failed: %v", err)
+			// Visas of other types are not considered by the literal.
+			if len(reason) > 0 {
+				t.RejectVisa(d.Issuer, d.Assertion, reason, field, err.Error())
+			}
 			continue
 		}
-		if err := f(ctx, string(v.JWT()), v.Data().Issuer, v.JKU()); err != nil {
+		if err := f(ctx, string(v.JWT()), d.Issuer, v.JKU()); err != nil {
 			glog.V(1).Infof("JWT verification failed: %v", err)
+			reason := errutil.ErrorReason(err)
+			if len(reason) == 0 {
+				reason = VerifyFailedReason
+			}
+			t.RejectVisa(d.Issuer, d.Assertion, reason, "", err.Error())
 			continue
 		}
+		t.AcceptVisa(d.Issuer, d.Assertion)
+		t.SetResult(true)
 		return nil
 	}
 	return fmt.Errorf("insufficient visas")
 }
 
+// literalName is the name of the Trace of a literal.
+func literalName(l Literal) string {
+	parts := []string{"type=" + string(l.Type)}
+	if len(l.Value) > 0 {
+		parts = append(parts, "value="+string(l.Value))
+	}
+	if len(l.Source) > 0 {
+		parts = append(parts, "source="+string(l.Source))
+	}
+	if len(l.By) > 0 {
+		parts = append(parts, "by="+string(l.By))
+	}
+	if len(l.DUOTerms) > 0 {
+		parts = append(parts, "duo_terms="+strings.Join(l.DUOTerms, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
 // Condition represnet a GA4GH Passport Visa Condition.
 // http://bit.ly/ga4gh-passport-v1#conditions
 type Condition struct {
@@ -92,33 +132,67 @@ type Condition struct {
 
 	// By http://bit.ly/ga4gh-passport-v1#by
 	By Pattern `json:"by,omitempty"`
+
+	// DUOTerms are Data Use Ontology terms which the value must be, or be a
+	// narrower term of, see MatchDUOTerms. It is an extension of the GA4GH
+	// format.
+	DUOTerms []string `json:"duo_terms,omitempty"`
 }
 
 // CheckCondition checks if a Visa satisfies a Condition.
 // We use Visa because we would also need to verify the Visa.
 // https://bit.ly/ga4gh-passport-v1#conditions
 func CheckCondition(c Condition, a Assertion) error {
+	_, _, err := checkCondition(c, a)
+	return err
+}
+
+// checkCondition is CheckCondition, it also returns the reason and field of
+// the mismatch of assertions of the type of the condition.
+func checkCondition(c Condition, a Assertion) (string, string, error) {
 	glog.V(1).Info("CheckCondition")
 	if c.Type == "" {
-		return fmt.Errorf("Condition must specifiy Type")
+		return "", "", fmt.Errorf("Condition must specifiy Type")
 	}
 	if c.Type != a.Type {
-		return fmt.Errorf("Type mismatch: %q %q", c.Type, a.Type)
+		return "", "", fmt.Errorf("Type mismatch: %q %q", c.Type, a.Type)
 	}
 
 	if err := MatchPatterns(Pattern(c.By), string(a.By)); err != nil {
-		return fmt.Errorf("By mismatch: %v", err)
+		return VisaByRejectedReason, "visa.by", fmt.Errorf("By mismatch: %v", err)
 	}
 
 	if err := MatchPatterns(c.Source, string(a.Source)); err != nil {
-		return fmt.Errorf("Source mismatch: %v", err)
+		return VisaSourceRejectedReason, "visa.source", fmt.Errorf("Source mismatch: %v", err)
 	}
 
 	if err := MatchPatterns(c.Value, string(a.Value)); err != nil {
-		return fmt.Errorf("Value mismatch: %v", err)
+		return VisaValueRejectedReason, "visa.value", fmt.Errorf("Value mismatch: %v", err)
 	}
 
-	return nil
+	if err := MatchDUOTerms(c.DUOTerms, string(a.Value)); err != nil {
+		return VisaValueRejectedReason, "visa.value", fmt.Errorf("Value mismatch: %v", err)
+	}
+
+	return "", "", nil
+}
+
+// MatchDUOTerms checks if a value is one of the Data Use Ontology terms, or a
+// narrower term of one of them. Any value matches no terms.
+func MatchDUOTerms(terms []string, v string) error {
+	if len(terms) == 0 {
+		return nil
+	}
+	duo, err := ontology.DUO()
+	if err != nil {
+		return err
+	}
+	for _, term := range terms {
+		if duo.Subsumes(term, v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("duo terms not matched: %q %q", terms, v)
 }
 
 func toConditionsProto(c Conditions) []*cpb.ConditionSet {
@@ -132,10 +206,11 @@ func toConditionsProto(c Conditions) []*cpb.ConditionSet {
 		}
 		for _, cand := range cor {
 			clause := &cpb.Condition{
-				Type:   string(cand.Type),
-				Source: string(cand.Source),
-				Value:  string(cand.Value),
-				By:     string(cand.By),
+				Type:     string(cand.Type),
+				Source:   string(cand.Source),
+				Value:    string(cand.Value),
+				By:       string(cand.By),
+				DuoTerms: cand.DUOTerms,
 			}
 			cs.AllOf = append(cs.AllOf, clause)
 		}
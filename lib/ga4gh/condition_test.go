@@ -0,0 +1,69 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"testing"
+)
+
+func TestCheckCondition_DUOTerms(t *testing.T) {
+	tests := []struct {
+		desc  string
+		terms []string
+		value Value
+		match bool
+	}{
+		{
+			desc:  "same term",
+			terms: []string{"DUO:0000006"},
+			value: "DUO:0000006",
+			match: true,
+		},
+		{
+			desc:  "narrower term",
+			terms: []string{"DUO:0000011", "DUO:0000006"},
+			value: "DUO:0000007",
+			match: true,
+		},
+		{
+			desc:  "broader term",
+			terms: []string{"DUO:0000006"},
+			value: "DUO:0000042",
+			match: false,
+		},
+		{
+			desc:  "unknown term",
+			terms: []string{"DUO:0000006"},
+			value: "DUO:0000006x",
+			match: false,
+		},
+		{
+			desc:  "no terms",
+			value: "DUO:0000042",
+			match: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := Condition{Type: "ResearchPurpose", DUOTerms: tc.terms}
+			a := Assertion{Type: "ResearchPurpose", Value: tc.value}
+			err := CheckCondition(c, a)
+			if tc.match != (err == nil) {
+				t.Errorf("CheckCondition(%+v, %+v) = %v, want match %v", c, a, err, tc.match)
+			}
+		})
+	}
+}
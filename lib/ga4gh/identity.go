@@ -63,6 +63,9 @@ type OldClaimCondition struct {
 	Value  []string `json:"value,omitempty"`
 	Source []string `json:"source,omitempty"`
 	By     []string `json:"by,omitempty"`
+	// DUOTerms are Data Use Ontology terms which the value must be, or be a
+	// narrower term of, see MatchDUOTerms.
+	DUOTerms []string `json:"duo_terms,omitempty"`
 }
 
 // VisaRejection is filled in by a policy engine to understand why a visa was rejected.
@@ -323,6 +326,7 @@ func toOldClaimConditions(conditions Conditions) (map[string]OldClaimCondition,
 			}
 			oldCond.By = append(oldCond.By, parts[1])
 		}
+		oldCond.DUOTerms = append(oldCond.DUOTerms, cond.DUOTerms...)
 		out[ctyp] = oldCond
 	}
 	return out, nil
@@ -17,6 +17,7 @@ package ga4gh
 import (
 	"fmt"
 
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
 	"gopkg.in/square/go-jose.v2/jwt" /* copybara-comment */
 )
 
@@ -24,6 +25,16 @@ var (
 	// JWTEmptyJKU is for visa issuers who do not wish to set a "jku" header.
 	// See https://tools.ietf.org/html/rfc7515#section-4.1.2 for details.
 	JWTEmptyJKU = ""
+
+	// DefaultSigningAlgorithms are the asymmetric JWT signing algorithms
+	// accepted when a service does not configure its own. "none" and the HMAC
+	// algorithms, which would use a public key as shared secret, are excluded.
+	DefaultSigningAlgorithms = []string{
+		string(jose.RS256), string(jose.RS384), string(jose.RS512),
+		string(jose.PS256), string(jose.PS384), string(jose.PS512),
+		string(jose.ES256), string(jose.ES384), string(jose.ES512),
+		string(jose.EdDSA),
+	}
 )
 
 // StdClaims contains the standard claims.
@@ -53,3 +64,46 @@ func NewStdClaimsFromJWT(token string) (*StdClaims, error) {
 	}
 	return d, nil
 }
+
+// CheckSigningAlgorithm returns an error if a serialized JWT is not signed with
+// one of the allowed algorithms, DefaultSigningAlgorithms if empty.
+// Does not verify the signature.
+func CheckSigningAlgorithm(token string, allowed []string) error {
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return fmt.Errorf("ParseSigned() failed: %v", err)
+	}
+	return checkHeaderAlgorithm(tok, allowed)
+}
+
+// CheckSigningAlgorithms returns an error if one of the configured signing
+// algorithms of a service is not one of DefaultSigningAlgorithms, such as
+// "none", an HMAC algorithm or a misspelled name.
+func CheckSigningAlgorithms(algs []string) error {
+	supported := make(map[string]bool)
+	for _, alg := range DefaultSigningAlgorithms {
+		supported[alg] = true
+	}
+	for _, alg := range algs {
+		if !supported[alg] {
+			return fmt.Errorf("signing algorithm %q is not one of the supported asymmetric algorithms %v", alg, DefaultSigningAlgorithms)
+		}
+	}
+	return nil
+}
+
+func checkHeaderAlgorithm(tok *jwt.JSONWebToken, allowed []string) error {
+	if len(tok.Headers) != 1 {
+		return fmt.Errorf("jwt invalid header")
+	}
+	if len(allowed) == 0 {
+		allowed = DefaultSigningAlgorithms
+	}
+	alg := tok.Headers[0].Algorithm
+	for _, a := range allowed {
+		if a == alg {
+			return nil
+		}
+	}
+	return fmt.Errorf("signing algorithm %q not allowed", alg)
+}
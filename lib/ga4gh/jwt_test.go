@@ -97,6 +97,17 @@ func TestNewStdClaimsFromJWT_BadJWTFails(t *testing.T) {
 	}
 }
 
+func TestCheckSigningAlgorithms(t *testing.T) {
+	if err := CheckSigningAlgorithms([]string{"RS256", "ES256", "EdDSA"}); err != nil {
+		t.Errorf("CheckSigningAlgorithms() of asymmetric algorithms failed: %v", err)
+	}
+	for _, alg := range []string{"none", "HS256", "rs256", ""} {
+		if err := CheckSigningAlgorithms([]string{"RS256", alg}); err == nil {
+			t.Errorf("CheckSigningAlgorithms([RS256 %q]) succeeded, want error", alg)
+		}
+	}
+}
+
 func Test_payloadFromJWT(t *testing.T) {
 	j := fakeJWT1
 	got, err := payloadFromJWT(j)
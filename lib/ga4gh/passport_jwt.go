@@ -0,0 +1,150 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/square/go-jose.v2/jwt" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+)
+
+const (
+	// TokenExchangeGrantType is the grant type of OAuth 2.0 token exchange.
+	// See https://tools.ietf.org/html/rfc8693
+	TokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// AccessTokenType is the token type of access tokens in token exchange.
+	AccessTokenType = "urn:ietf:params:oauth:token-type:access_token"
+	// SelfContainedPassportTokenType is the token type of passport JWTs in
+	// token exchange, as per GA4GH AAI v1.2.
+	SelfContainedPassportTokenType = "urn:ga4gh:token-type:self-contained-passport"
+	// PassportJWTType is the "typ" header of passport JWTs.
+	PassportJWTType = "vnd.ga4gh.passport+jwt"
+
+	jwtHeaderType = "typ"
+)
+
+// SelfContainedPassport is a GA4GH Passport as a signed JWT containing the
+// visas, issued by a broker in exchange of an access token.
+// See https://github.com/ga4gh/data-security/blob/master/AAI/AAIConnectProfile.md
+type SelfContainedPassport struct {
+	// jwt for the passport.
+	jwt SelfContainedPassportJWT
+
+	// "jku" header of the passport, if any.
+	jku string
+
+	// data is unmarshalled data contained in the passport jwt.
+	data *SelfContainedPassportData
+}
+
+// SelfContainedPassportJWT is a JWT containing a GA4GH Passport.
+type SelfContainedPassportJWT string
+
+// SelfContainedPassportData is used to create a new SelfContainedPassport.
+type SelfContainedPassportData struct {
+	// StdClaims is embeded for standard JWT claims.
+	StdClaims
+
+	// Visas contains the visas of the passport.
+	Visas []VisaJWT `json:"ga4gh_passport_v1"`
+}
+
+// NewSelfContainedPassportFromJWT creates a new SelfContainedPassport from a
+// given JWT. Returns error if the JWT does not have the passport "typ" header
+// or is not signed with one of DefaultSigningAlgorithms.
+// Does not verify the signature on the JWT.
+func NewSelfContainedPassportFromJWT(j SelfContainedPassportJWT) (*SelfContainedPassport, error) {
+	tok, err := jwt.ParseSigned(string(j))
+	if err != nil {
+		return nil, fmt.Errorf("ParseSigned() failed: %v", err)
+	}
+	if err := checkHeaderAlgorithm(tok, nil); err != nil {
+		return nil, err
+	}
+
+	h := tok.Headers[0].ExtraHeaders
+	if typ, _ := h[jwtHeaderType].(string); typ != PassportJWTType {
+		return nil, fmt.Errorf("jwt type %q is not %q", typ, PassportJWTType)
+	}
+	jku := JWTEmptyJKU
+	if v, ok := h[jwtHeaderJKU]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("casting jku to string failed")
+		}
+		jku = s
+	}
+
+	d := &SelfContainedPassportData{}
+	if err := tok.UnsafeClaimsWithoutVerification(d); err != nil {
+		return nil, fmt.Errorf("UnsafeClaimsWithoutVerification() failed: %v", err)
+	}
+
+	return &SelfContainedPassport{
+		jwt:  j,
+		jku:  jku,
+		data: d,
+	}, nil
+}
+
+// NewSelfContainedPassportFromData creates a new SelfContainedPassport signed
+// by the signer. The passport keys are found at jku if not empty, or else
+// with the OIDC discovery of the issuer.
+func NewSelfContainedPassportFromData(ctx context.Context, d *SelfContainedPassportData, jku string, signer kms.Signer) (*SelfContainedPassport, error) {
+	header := map[string]string{jwtHeaderType: PassportJWTType}
+	if jku != JWTEmptyJKU {
+		header[jwtHeaderJKU] = jku
+	}
+	signed, err := signer.SignJWT(ctx, d, header)
+	if err != nil {
+		return nil, fmt.Errorf("SignJWT() failed: %v", err)
+	}
+	return &SelfContainedPassport{
+		jwt:  SelfContainedPassportJWT(signed),
+		jku:  jku,
+		data: d,
+	}, nil
+}
+
+// JWT returns the JWT of a SelfContainedPassport.
+func (p *SelfContainedPassport) JWT() SelfContainedPassportJWT {
+	return p.jwt
+}
+
+// JKU returns the JKU header of a SelfContainedPassport.
+func (p *SelfContainedPassport) JKU() string {
+	return p.jku
+}
+
+// Data returns the data of a SelfContainedPassport.
+func (p *SelfContainedPassport) Data() *SelfContainedPassportData {
+	return p.data
+}
+
+// Visas returns the visas of a SelfContainedPassport.
+// Returns error if a visa cannot be parsed.
+func (p *SelfContainedPassport) Visas() ([]*Visa, error) {
+	var out []*Visa
+	for i, j := range p.data.Visas {
+		v, err := NewVisaFromJWT(j)
+		if err != nil {
+			return nil, fmt.Errorf("visa %d: %v", i, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
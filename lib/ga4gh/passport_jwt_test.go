@@ -0,0 +1,97 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+)
+
+func fakeSelfContainedPassportData(t *testing.T) *SelfContainedPassportData {
+	t.Helper()
+
+	_, visa := fakeVisaDataAndJWT(t)
+	return &SelfContainedPassportData{
+		StdClaims: StdClaims{
+			Issuer:    "fake-broker",
+			Subject:   "fake-subject",
+			IssuedAt:  fakeStart(),
+			ExpiresAt: fakeEnd(),
+		},
+		Visas: []VisaJWT{visa},
+	}
+}
+
+func TestSelfContainedPassport_RoundTrip(t *testing.T) {
+	d := fakeSelfContainedPassportData(t)
+	signer := localsign.New(&testkeys.Default)
+	jku := "https://broker.example.com/jwks"
+
+	p, err := NewSelfContainedPassportFromData(context.Background(), d, jku, signer)
+	if err != nil {
+		t.Fatalf("NewSelfContainedPassportFromData() failed: %v", err)
+	}
+
+	got, err := NewSelfContainedPassportFromJWT(p.JWT())
+	if err != nil {
+		t.Fatalf("NewSelfContainedPassportFromJWT() failed: %v", err)
+	}
+	if diff := cmp.Diff(d, got.Data()); diff != "" {
+		t.Errorf("Data() returned diff (-want +got):\n%s", diff)
+	}
+	if got.JKU() != jku {
+		t.Errorf("JKU() = %q, want %q", got.JKU(), jku)
+	}
+
+	visas, err := got.Visas()
+	if err != nil {
+		t.Fatalf("Visas() failed: %v", err)
+	}
+	if len(visas) != 1 || visas[0].JWT() != d.Visas[0] {
+		t.Errorf("Visas() = %v, want the visa of the passport", visas)
+	}
+}
+
+func TestNewSelfContainedPassportFromJWT_NoJKU(t *testing.T) {
+	d := fakeSelfContainedPassportData(t)
+	p, err := NewSelfContainedPassportFromData(context.Background(), d, JWTEmptyJKU, localsign.New(&testkeys.Default))
+	if err != nil {
+		t.Fatalf("NewSelfContainedPassportFromData() failed: %v", err)
+	}
+
+	got, err := NewSelfContainedPassportFromJWT(p.JWT())
+	if err != nil {
+		t.Fatalf("NewSelfContainedPassportFromJWT() failed: %v", err)
+	}
+	if got.JKU() != JWTEmptyJKU {
+		t.Errorf("JKU() = %q, want empty", got.JKU())
+	}
+}
+
+func TestNewSelfContainedPassportFromJWT_NotPassport(t *testing.T) {
+	// A visa or an access token is not a passport.
+	_, visa := fakeVisaDataAndJWT(t)
+	if _, err := NewSelfContainedPassportFromJWT(SelfContainedPassportJWT(visa)); err == nil {
+		t.Errorf("NewSelfContainedPassportFromJWT(visa) succeeded, want error")
+	}
+
+	if _, err := NewSelfContainedPassportFromJWT("invalid"); err == nil {
+		t.Errorf("NewSelfContainedPassportFromJWT(invalid) succeeded, want error")
+	}
+}
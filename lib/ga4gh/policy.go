@@ -133,16 +133,17 @@ func NewPolicy(i TrustedIssuers, s TrustedSources, allow Conditions, deny Condit
 // Presumes that the signatures on the Passport and its Visas have already been verified.
 func (p *Policy) Test(ctx context.Context, r *Passport) error {
 	glog.V(1).Info("Policy.Test")
+	ctx, t := StartTrace(ctx, TracePolicy, "")
 
 	// TODO: add to a passport visa tracker for all AccessTokenVisaFormat visas that were used to confirm access to check validity every hour (https://bit.ly/ga4gh-aai-profile#at-polling).
 	// Filter assertions in the passport by issuer and source.
 	vs := r.Visas
 	glog.V(1).Infof("Number of Visas in the passport: %v", len(vs))
 
-	vs = filterByIssuers(vs, p.issuers)
+	vs = filterByIssuers(vs, p.issuers, t)
 	glog.V(1).Infof("Number of Visas after filtering for trusted issuers: %v", len(vs))
 
-	vs = filterBySources(vs, p.sources)
+	vs = filterBySources(vs, p.sources, t)
 	glog.V(1).Infof("Number of Visas after filtering for trusted sources: %v", len(vs))
 
 	// TODO: can we check only the ones used in allow and deny?
@@ -151,14 +152,18 @@ func (p *Policy) Test(ctx context.Context, r *Passport) error {
 	}
 
 	if p.deny != nil {
-		if err := CheckConditions(ctx, p.deny, vs, p.verifier); err == nil {
+		dctx, dt := StartTrace(ctx, TraceDeny, "")
+		if err := CheckConditions(dctx, p.deny, vs, p.verifier); err == nil {
+			dt.SetResult(true)
 			return fmt.Errorf("policy deny is satisfied")
 		}
 	}
 
-	if err := CheckConditions(ctx, p.allow, vs, p.verifier); err != nil {
+	actx, at := StartTrace(ctx, TraceAllow, "")
+	if err := CheckConditions(actx, p.allow, vs, p.verifier); err != nil {
 		return fmt.Errorf("policy allow is not satisfied")
 	}
+	at.SetResult(true)
 
 	// Check the conditions of the Visas that were used.
 	// TODO: The specs only requires checking conditions on Visas that
@@ -171,27 +176,34 @@ func (p *Policy) Test(ctx context.Context, r *Passport) error {
 		}
 	}
 
+	t.SetResult(true)
 	return nil
 }
 
-func filterByIssuers(vs []*Visa, t TrustedIssuers) []*Visa {
+func filterByIssuers(vs []*Visa, t TrustedIssuers, trace *Trace) []*Visa {
 	glog.V(1).Info("filterByIssuers")
 	var filtered []*Visa
 	for _, v := range vs {
 		if HasTrustedIssuer(v, t) {
 			filtered = append(filtered, v)
+			continue
 		}
+		d := v.Data()
+		trace.RejectVisa(d.Issuer, d.Assertion, UntrustedIssuerReason, "iss", fmt.Sprintf("issuer %q is not trusted for source %q", d.Issuer, d.Assertion.Source))
 	}
 	return filtered
 }
 
-func filterBySources(vs []*Visa, t TrustedSources) []*Visa {
+func filterBySources(vs []*Visa, t TrustedSources, trace *Trace) []*Visa {
 	glog.V(1).Info("filterBySources")
 	var filtered []*Visa
 	for _, v := range vs {
 		if HasTrustedSource(v, t) {
 			filtered = append(filtered, v)
+			continue
 		}
+		d := v.Data()
+		trace.RejectVisa(d.Issuer, d.Assertion, UntrustedSourceReason, "visa.source", fmt.Sprintf("source %q is not trusted for visa type %q and value %q", d.Assertion.Source, d.Assertion.Type, d.Assertion.Value))
 	}
 	return filtered
 }
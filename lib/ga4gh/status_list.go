@@ -0,0 +1,245 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
+)
+
+// Statuses of visas in a status list.
+// See https://datatracker.ietf.org/doc/draft-ietf-oauth-status-list/
+const (
+	StatusValid     = 0
+	StatusInvalid   = 1
+	StatusSuspended = 2
+)
+
+// Reasons of RejectedVisa for visas rejected by their status list.
+const (
+	VisaRevokedReason           = "visa_revoked"
+	VisaSuspendedReason         = "visa_suspended"
+	StatusListUnavailableReason = "status_list_unavailable"
+	StatusListUntrustedReason   = "status_list_untrusted"
+)
+
+// maxStatusListSize is the maximum size of a decompressed status list, which
+// holds 128M statuses of 1 bit.
+const maxStatusListSize = 16 << 20
+
+// VisaStatus is the "status" claim of a visa, referencing the entry of the
+// visa in a status list of its issuer.
+type VisaStatus struct {
+	StatusList *StatusListReference `json:"status_list,omitempty"`
+}
+
+// StatusListReference is the index of a visa in the status list at URI.
+type StatusListReference struct {
+	Index int    `json:"idx"`
+	URI   string `json:"uri"`
+}
+
+// StatusList is a list of statuses of Bits bits each, the statuses are
+// packed into bytes starting with the least significant bits, compressed
+// with zlib and base64url encoded into List.
+type StatusList struct {
+	Bits int    `json:"bits"`
+	List string `json:"lst"`
+
+	// statuses is the decoded List.
+	statuses []byte
+}
+
+// StatusListFetcher fetches the status list at a URI.
+type StatusListFetcher interface {
+	FetchStatusList(ctx context.Context, uri string) (*StatusList, error)
+}
+
+// NewStatusList creates a StatusList of the given statuses.
+func NewStatusList(bits int, statuses []int) (*StatusList, error) {
+	if err := checkStatusBits(bits); err != nil {
+		return nil, err
+	}
+	packed := make([]byte, (len(statuses)*bits+7)/8)
+	for i, s := range statuses {
+		if s < 0 || s >= 1<<uint(bits) {
+			return nil, fmt.Errorf("status %d of index %d does not fit in %d bits", s, i, bits)
+		}
+		pos := i * bits
+		packed[pos/8] |= byte(s << uint(pos%8))
+	}
+
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(packed); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &StatusList{
+		Bits:     bits,
+		List:     base64.RawURLEncoding.EncodeToString(buf.Bytes()),
+		statuses: packed,
+	}, nil
+}
+
+// UnmarshalJSON decodes the statuses of the list once, such that Status may be
+// called concurrently.
+func (l *StatusList) UnmarshalJSON(b []byte) error {
+	type plain StatusList
+	p := (*plain)(l)
+	if err := json.Unmarshal(b, p); err != nil {
+		return err
+	}
+	statuses, err := decodeStatuses(l.List)
+	if err != nil {
+		return err
+	}
+	l.statuses = statuses
+	return nil
+}
+
+// Status returns the status at index i of the list.
+func (l *StatusList) Status(i int) (int, error) {
+	if err := checkStatusBits(l.Bits); err != nil {
+		return 0, err
+	}
+	statuses := l.statuses
+	if statuses == nil {
+		var err error
+		if statuses, err = decodeStatuses(l.List); err != nil {
+			return 0, err
+		}
+	}
+
+	if i < 0 || i >= len(statuses)*8/l.Bits {
+		return 0, fmt.Errorf("index %d out of range of the status list", i)
+	}
+	pos := i * l.Bits
+	return int(statuses[pos/8]>>uint(pos%8)) & (1<<uint(l.Bits) - 1), nil
+}
+
+func decodeStatuses(list string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(list)
+	if err != nil {
+		return nil, fmt.Errorf("decoding status list: %v", err)
+	}
+	r, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing status list: %v", err)
+	}
+	defer r.Close()
+	statuses, err := ioutil.ReadAll(io.LimitReader(r, maxStatusListSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing status list: %v", err)
+	}
+	if len(statuses) > maxStatusListSize {
+		return nil, fmt.Errorf("status list exceeds %d bytes", maxStatusListSize)
+	}
+	return statuses, nil
+}
+
+func checkStatusBits(bits int) error {
+	switch bits {
+	case 1, 2, 4, 8:
+		return nil
+	}
+	return fmt.Errorf("status list bits %d is not one of 1, 2, 4 or 8", bits)
+}
+
+// CheckVisaStatus checks the visa is not revoked or suspended in its status
+// list, if any. The status list must be at the host of the visa issuer.
+// The errors have the reason of the rejection, see errutil.ErrorReason.
+func CheckVisaStatus(ctx context.Context, v *Visa, f StatusListFetcher) error {
+	d := v.Data()
+	if d.Status == nil || d.Status.StatusList == nil {
+		return nil
+	}
+	ref := d.Status.StatusList
+
+	if err := checkStatusListURI(ref.URI, d.Issuer); err != nil {
+		return errutil.WithErrorReason(StatusListUntrustedReason, status.Errorf(codes.Unauthenticated, "%v", err))
+	}
+
+	l, err := f.FetchStatusList(ctx, ref.URI)
+	if err != nil {
+		return errutil.WithErrorReason(StatusListUnavailableReason, status.Errorf(codes.Unavailable, "fetching status list %q failed: %v", ref.URI, err))
+	}
+	s, err := l.Status(ref.Index)
+	if err != nil {
+		return errutil.WithErrorReason(StatusListUnavailableReason, status.Errorf(codes.Unavailable, "status list %q: %v", ref.URI, err))
+	}
+
+	switch s {
+	case StatusValid:
+		return nil
+	case StatusSuspended:
+		return errutil.WithErrorReason(VisaSuspendedReason, status.Errorf(codes.PermissionDenied, "visa is suspended by its issuer %q", d.Issuer))
+	default:
+		return errutil.WithErrorReason(VisaRevokedReason, status.Errorf(codes.PermissionDenied, "visa is revoked by its issuer %q", d.Issuer))
+	}
+}
+
+func checkStatusListURI(uri, issuer string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("status list url parse failed: %v", err)
+	}
+	iss, err := url.Parse(issuer)
+	if err != nil {
+		return fmt.Errorf("issuer url parse failed: %v", err)
+	}
+	if u.Host != iss.Host {
+		return fmt.Errorf("status list %q does not have same host with visa issuer", uri)
+	}
+	if !httputils.IsHTTPS(uri) && !httputils.IsLocalhost(uri) {
+		return fmt.Errorf("status list %q does not use https", uri)
+	}
+	return nil
+}
+
+// StatusCheckingVerifier wraps the verifier f to also reject visas which are
+// revoked or suspended in their status list.
+func StatusCheckingVerifier(f JWTVerifier, lists StatusListFetcher) JWTVerifier {
+	if f == nil {
+		f = defaultVerifier
+	}
+	if lists == nil {
+		return f
+	}
+	return func(ctx context.Context, jwt, iss, jku string) error {
+		if err := f(ctx, jwt, iss, jku); err != nil {
+			return err
+		}
+		v, err := NewVisaFromJWT(VisaJWT(jwt))
+		if err != nil {
+			return err
+		}
+		return CheckVisaStatus(ctx, v, lists)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+)
+
+const (
+	fakeStatusIssuer = "https://issuer.example.org"
+	fakeStatusList   = "https://issuer.example.org/statuslists/1"
+)
+
+// fakeStatusLists is a StatusListFetcher of lists of 2 bits statuses.
+type fakeStatusLists map[string][]int
+
+func (f fakeStatusLists) FetchStatusList(ctx context.Context, uri string) (*StatusList, error) {
+	statuses, ok := f[uri]
+	if !ok {
+		return nil, fmt.Errorf("status list %q not found", uri)
+	}
+	return NewStatusList(2, statuses)
+}
+
+func TestStatusList(t *testing.T) {
+	for _, bits := range []int{1, 2, 4, 8} {
+		t.Run(fmt.Sprintf("%d bits", bits), func(t *testing.T) {
+			var statuses []int
+			for i := 0; i < 20; i++ {
+				statuses = append(statuses, i%(1<<uint(bits)))
+			}
+			l, err := NewStatusList(bits, statuses)
+			if err != nil {
+				t.Fatalf("NewStatusList() failed: %v", err)
+			}
+
+			// Decode the list as fetched from an issuer.
+			b, err := json.Marshal(l)
+			if err != nil {
+				t.Fatalf("json.Marshal() failed: %v", err)
+			}
+			got := &StatusList{}
+			if err := json.Unmarshal(b, got); err != nil {
+				t.Fatalf("json.Unmarshal() failed: %v", err)
+			}
+
+			for i, want := range statuses {
+				s, err := got.Status(i)
+				if err != nil {
+					t.Fatalf("Status(%d) failed: %v", i, err)
+				}
+				if s != want {
+					t.Errorf("Status(%d) = %d, want %d", i, s, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusList_Error(t *testing.T) {
+	if _, err := NewStatusList(3, []int{0}); err == nil {
+		t.Errorf("NewStatusList(3, _) succeeded, want error")
+	}
+	if _, err := NewStatusList(1, []int{2}); err == nil {
+		t.Errorf("NewStatusList(1, [2]) succeeded, want error")
+	}
+
+	l, err := NewStatusList(1, []int{1})
+	if err != nil {
+		t.Fatalf("NewStatusList() failed: %v", err)
+	}
+	if _, err := l.Status(8); err == nil {
+		t.Errorf("Status(8) of a list of 8 statuses succeeded, want error")
+	}
+	wide, err := NewStatusList(8, []int{1})
+	if err != nil {
+		t.Fatalf("NewStatusList() failed: %v", err)
+	}
+	// The bit position of the index overflows.
+	if _, err := wide.Status(1 << 60); err == nil {
+		t.Errorf("Status(1<<60) succeeded, want error")
+	}
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(make([]byte, maxStatusListSize+1))
+	w.Close()
+	large := &StatusList{Bits: 1, List: base64.RawURLEncoding.EncodeToString(buf.Bytes())}
+	if _, err := large.Status(0); err == nil {
+		t.Errorf("Status() of a list exceeding %d bytes succeeded, want error", maxStatusListSize)
+	}
+	if err := json.Unmarshal([]byte(`{"bits":1,"lst":"invalid"}`), &StatusList{}); err == nil {
+		t.Errorf("json.Unmarshal() of invalid list succeeded, want error")
+	}
+}
+
+func newStatusVisa(t *testing.T, ref *StatusListReference) *Visa {
+	t.Helper()
+
+	d := &VisaData{
+		StdClaims: StdClaims{
+			Issuer:    fakeStatusIssuer,
+			Subject:   "alice",
+			ExpiresAt: fakeEnd(),
+		},
+		Scope: "openid",
+		Assertion: Assertion{
+			Type:   ControlledAccessGrants,
+			Value:  "https://dataset.example.org",
+			Source: "https://dac.example.org",
+		},
+	}
+	if ref != nil {
+		d.Status = &VisaStatus{StatusList: ref}
+	}
+	v, err := NewVisaFromData(context.Background(), d, JWTEmptyJKU, localsign.New(&testkeys.Default))
+	if err != nil {
+		t.Fatalf("NewVisaFromData() failed: %v", err)
+	}
+	return v
+}
+
+func TestCheckVisaStatus(t *testing.T) {
+	lists := fakeStatusLists{fakeStatusList: {StatusValid, StatusInvalid, StatusSuspended}}
+
+	tests := []struct {
+		name   string
+		ref    *StatusListReference
+		reason string
+	}{
+		{
+			name: "no status",
+		},
+		{
+			name: "valid",
+			ref:  &StatusListReference{Index: 0, URI: fakeStatusList},
+		},
+		{
+			name:   "revoked",
+			ref:    &StatusListReference{Index: 1, URI: fakeStatusList},
+			reason: VisaRevokedReason,
+		},
+		{
+			name:   "suspended",
+			ref:    &StatusListReference{Index: 2, URI: fakeStatusList},
+			reason: VisaSuspendedReason,
+		},
+		{
+			name:   "index out of range",
+			ref:    &StatusListReference{Index: 100, URI: fakeStatusList},
+			reason: StatusListUnavailableReason,
+		},
+		{
+			name:   "list not found",
+			ref:    &StatusListReference{Index: 0, URI: fakeStatusIssuer + "/statuslists/2"},
+			reason: StatusListUnavailableReason,
+		},
+		{
+			name:   "list of other host",
+			ref:    &StatusListReference{Index: 0, URI: "https://other.example.org/statuslists/1"},
+			reason: StatusListUntrustedReason,
+		},
+		{
+			name:   "list without https",
+			ref:    &StatusListReference{Index: 0, URI: "http://issuer.example.org/statuslists/1"},
+			reason: StatusListUntrustedReason,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckVisaStatus(context.Background(), newStatusVisa(t, tc.ref), lists)
+			if got := errutil.ErrorReason(err); got != tc.reason {
+				t.Errorf("CheckVisaStatus() = %v, want reason %q", err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestVisasToOldClaims_Revoked(t *testing.T) {
+	lists := fakeStatusLists{fakeStatusList: {StatusValid, StatusInvalid}}
+	valid := newStatusVisa(t, &StatusListReference{Index: 0, URI: fakeStatusList})
+	revoked := newStatusVisa(t, &StatusListReference{Index: 1, URI: fakeStatusList})
+
+	claims, rejected, err := VisasToOldClaims(context.Background(), []VisaJWT{valid.JWT(), revoked.JWT()}, StatusCheckingVerifier(nil, lists))
+	if err != nil {
+		t.Fatalf("VisasToOldClaims() failed: %v", err)
+	}
+	if n := len(claims[string(ControlledAccessGrants)]); n != 1 {
+		t.Errorf("len(claims) = %d, want 1", n)
+	}
+	if len(rejected) != 1 || rejected[0].Rejection.Reason != VisaRevokedReason {
+		t.Errorf("rejected = %+v, want the revoked visa", rejected)
+	}
+}
+
+func TestPolicyTest_Revoked(t *testing.T) {
+	ctx := context.Background()
+	lists := fakeStatusLists{fakeStatusList: {StatusInvalid}}
+	allow := Conditions{{{
+		Type:  ControlledAccessGrants,
+		Value: "const:https://dataset.example.org",
+	}}}
+	issuers := TrustedIssuers{fakeStatusIssuer: nil}
+	sources := TrustedSources{"https://dac.example.org": nil}
+	passport := &Passport{Visas: []*Visa{newStatusVisa(t, &StatusListReference{Index: 0, URI: fakeStatusList})}}
+
+	policy, err := NewPolicy(issuers, sources, allow, nil, nil)
+	if err != nil {
+		t.Fatalf("NewPolicy() failed: %v", err)
+	}
+	if err := policy.Test(ctx, passport); err != nil {
+		t.Fatalf("policy.Test(passport) without status check failed: %v", err)
+	}
+
+	policy, err = NewPolicy(issuers, sources, allow, nil, StatusCheckingVerifier(nil, lists))
+	if err != nil {
+		t.Fatalf("NewPolicy() failed: %v", err)
+	}
+	if err := policy.Test(ctx, passport); err == nil {
+		t.Errorf("policy.Test(passport) with revoked visa succeeded, want error")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"context"
+)
+
+// Kinds of Trace nodes.
+const (
+	TracePassport   = "passport"
+	TraceResource   = "resource"
+	TracePolicy     = "policy"
+	TraceAllow      = "allow"
+	TraceDeny       = "deny"
+	TraceOr         = "or"
+	TraceAnd        = "and"
+	TraceClaim      = "claim"
+	TraceConditions = "conditions"
+	TraceClause     = "clause"
+	TraceLiteral    = "literal"
+)
+
+// Reasons of TraceVisa for visas not used by policies, see also the reasons
+// of RejectedVisa.
+const (
+	UntrustedIssuerReason    = "untrusted_issuer"
+	UntrustedSourceReason    = "untrusted_source"
+	VisaByRejectedReason     = "visa_by_rejected"
+	VisaSourceRejectedReason = "visa_source_rejected"
+	VisaValueRejectedReason  = "visa_value_rejected"
+	VerifyFailedReason       = "verify_failed"
+)
+
+// Trace is the evaluation of a policy, or of a part of it, and of the visas
+// it considered. Evaluation short-circuits, a Trace only has the parts which
+// were evaluated.
+type Trace struct {
+	Kind     string       `json:"kind"`
+	Name     string       `json:"name,omitempty"`
+	Result   bool         `json:"result"`
+	Visas    []*TraceVisa `json:"visas,omitempty"`
+	Children []*Trace     `json:"children,omitempty"`
+}
+
+// TraceVisa is a visa considered by a Trace, and why it was used or not.
+type TraceVisa struct {
+	Issuer    string         `json:"iss,omitempty"`
+	Assertion Assertion      `json:"assertion,omitempty"`
+	Matched   bool           `json:"matched"`
+	Rejection *VisaRejection `json:"rejection,omitempty"`
+}
+
+// traceKey is the context key of the current Trace.
+type traceKey struct{}
+
+// WithTrace returns a ctx recording the evaluation of policies with it into
+// the returned Trace. A Trace is not safe for concurrent evaluations.
+func WithTrace(ctx context.Context, kind, name string) (context.Context, *Trace) {
+	t := &Trace{Kind: kind, Name: name}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// StartTrace adds a child to the Trace of the ctx, and returns a ctx recording
+// into the child. If the ctx has no Trace, it returns the ctx and a nil Trace,
+// which methods ignore.
+func StartTrace(ctx context.Context, kind, name string) (context.Context, *Trace) {
+	parent, ok := ctx.Value(traceKey{}).(*Trace)
+	if !ok || parent == nil {
+		return ctx, nil
+	}
+	t := &Trace{Kind: kind, Name: name}
+	parent.Children = append(parent.Children, t)
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// SetResult sets the result of the evaluation.
+func (t *Trace) SetResult(result bool) {
+	if t == nil {
+		return
+	}
+	t.Result = result
+}
+
+// AcceptVisa records the visa as used by the evaluation.
+func (t *Trace) AcceptVisa(issuer string, a Assertion) {
+	if t == nil {
+		return
+	}
+	t.Visas = append(t.Visas, &TraceVisa{Issuer: issuer, Assertion: a, Matched: true})
+}
+
+// RejectVisa records the visa as not used by the evaluation, see
+// Identity.RejectVisa.
+func (t *Trace) RejectVisa(issuer string, a Assertion, reason, field, message string) {
+	if t == nil {
+		return
+	}
+	t.Visas = append(t.Visas, &TraceVisa{
+		Issuer:    issuer,
+		Assertion: a,
+		Rejection: &VisaRejection{
+			Reason:      reason,
+			Field:       field,
+			Description: message,
+		},
+	})
+}
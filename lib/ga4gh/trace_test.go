@@ -0,0 +1,138 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ga4gh
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+)
+
+// traceReasons lists the visas of the trace as "path of kinds:reason", the
+// reason of matched visas is "matched".
+func traceReasons(t *Trace, path string) []string {
+	path += "/" + t.Kind
+	var out []string
+	for _, v := range t.Visas {
+		reason := "matched"
+		if v.Rejection != nil {
+			reason = v.Rejection.Reason
+		}
+		out = append(out, fmt.Sprintf("%s:%s", path, reason))
+	}
+	for _, c := range t.Children {
+		out = append(out, traceReasons(c, path)...)
+	}
+	return out
+}
+
+func TestPolicyTest_Trace(t *testing.T) {
+	issuers := TrustedIssuers{
+		Issuer(testkeys.VisaIssuer0): nil,
+		Issuer(testkeys.VisaIssuer1): map[Source]bool{"": true},
+	}
+	sources := TrustedSources{
+		"stanford": nil,
+	}
+	allow := Conditions{{{
+		Type:  AffiliationAndRole,
+		Value: "const:alice@stanford.edu",
+	}}}
+
+	issuer0 := testkeys.Keys[testkeys.VisaIssuer0]
+	issuer1 := testkeys.Keys[testkeys.VisaIssuer1]
+	id0 := ID{Issuer: issuer0.ID, Subject: "alice"}
+	id1 := ID{Issuer: issuer1.ID, Subject: "alice"}
+	untrustedIssuer := newVisa(t, issuer1, id1, Assertion{Type: AffiliationAndRole, Value: "alice@stanford.edu", Source: "stanford"}, "openid", "")
+	untrustedSource := newVisa(t, issuer0, id0, Assertion{Type: AffiliationAndRole, Value: "alice@stanford.edu", Source: "berkeley"}, "openid", "")
+	badSignature := newVisa(t, issuer0, id0, Assertion{Type: AffiliationAndRole, Value: "alice@stanford.edu", Source: "stanford"}, "openid", "")
+	passport := &Passport{Visas: []*Visa{untrustedIssuer, untrustedSource, badSignature}}
+
+	verifier := func(ctx context.Context, jwt, iss, jku string) error {
+		if jwt == string(badSignature.JWT()) {
+			return fmt.Errorf("bad signature")
+		}
+		return nil
+	}
+	policy, err := NewPolicy(issuers, sources, allow, nil, verifier)
+	if err != nil {
+		t.Fatalf("NewPolicy() failed: %v", err)
+	}
+
+	ctx, trace := WithTrace(context.Background(), TracePassport, "alice")
+	if err := policy.Test(ctx, passport); err == nil {
+		t.Fatalf("policy.Test(passport) succeeded, want error")
+	}
+
+	want := []string{
+		"/passport/policy:" + UntrustedIssuerReason,
+		"/passport/policy:" + UntrustedSourceReason,
+		"/passport/policy/allow/conditions/clause/literal:" + VerifyFailedReason,
+	}
+	if d := cmp.Diff(want, traceReasons(trace, "")); len(d) > 0 {
+		t.Errorf("trace visas (-want, +got):\n%s", d)
+	}
+	if trace.Children[0].Result {
+		t.Errorf("policy trace result = true, want false")
+	}
+}
+
+func TestCheckConditions_Trace(t *testing.T) {
+	allow := Conditions{
+		{{Type: ControlledAccessGrants, Value: "const:https://dataset.example.org"}},
+		{{Type: AffiliationAndRole, Value: "const:alice@stanford.edu"}},
+	}
+
+	issuer := testkeys.Keys[testkeys.VisaIssuer0]
+	id := ID{Issuer: issuer.ID, Subject: "alice"}
+	otherValue := newVisa(t, issuer, id, Assertion{Type: AffiliationAndRole, Value: "bob@stanford.edu", Source: "stanford"}, "openid", "")
+	matched := newVisa(t, issuer, id, Assertion{Type: AffiliationAndRole, Value: "alice@stanford.edu", Source: "stanford"}, "openid", "")
+
+	ctx, trace := WithTrace(context.Background(), TracePolicy, "")
+	if err := CheckConditions(ctx, allow, []*Visa{otherValue, matched}, defaultVerifier); err != nil {
+		t.Fatalf("CheckConditions() failed: %v", err)
+	}
+
+	want := []string{
+		"/policy/conditions/clause/literal:" + VisaValueRejectedReason,
+		"/policy/conditions/clause/literal:matched",
+	}
+	if d := cmp.Diff(want, traceReasons(trace, "")); len(d) > 0 {
+		t.Errorf("trace visas (-want, +got):\n%s", d)
+	}
+
+	clauses := trace.Children[0].Children
+	if len(clauses) != 2 || clauses[0].Result || !clauses[1].Result {
+		t.Errorf("clauses = %+v, want the first clause failed and the second one satisfied", clauses)
+	}
+	if got, want := clauses[1].Children[0].Name, "type=AffiliationAndRole value=const:alice@stanford.edu"; got != want {
+		t.Errorf("literal name = %q, want %q", got, want)
+	}
+}
+
+func TestStartTrace_NoTrace(t *testing.T) {
+	ctx := context.Background()
+	got, trace := StartTrace(ctx, TracePolicy, "")
+	if got != ctx || trace != nil {
+		t.Errorf("StartTrace() without trace = %v, %v, want the ctx and nil", got, trace)
+	}
+	// Methods of a nil trace are no-ops.
+	trace.SetResult(true)
+	trace.AcceptVisa("", Assertion{})
+	trace.RejectVisa("", Assertion{}, "", "", "")
+}
@@ -54,11 +54,15 @@ type VisaData struct {
 
 	// Assertion contains the Visa Assertion.
 	Assertion Assertion `json:"ga4gh_visa_v1,omitempty"`
+
+	// Status references the entry of the Visa in a status list of its issuer,
+	// used to revoke the Visa before it expires. See CheckVisaStatus.
+	Status *VisaStatus `json:"status,omitempty"`
 }
 
 // NewVisaFromJWT creates a new Visa from a given JWT.
-// Returns error if the JWT is not the JWT of a Visa.
-// Does not verify the signature on the JWT.
+// Returns error if the JWT is not the JWT of a Visa or is not signed with one
+// of DefaultSigningAlgorithms. Does not verify the signature on the JWT.
 func NewVisaFromJWT(j VisaJWT) (*Visa, error) {
 	glog.V(1).Infof("NewVisaFromJWT(%+v)", j)
 	d, jku, err := visaDataFromJWT(j)
@@ -149,8 +153,8 @@ func visaDataFromJWT(j VisaJWT) (*VisaData, string, error) {
 		return nil, JWTEmptyJKU, fmt.Errorf("UnsafeClaimsWithoutVerification() failed: %v", err)
 	}
 
-	if len(tok.Headers) != 1 {
-		return nil, JWTEmptyJKU, fmt.Errorf("jwt invalid header")
+	if err := checkHeaderAlgorithm(tok, nil); err != nil {
+		return nil, JWTEmptyJKU, err
 	}
 
 	if len(tok.Headers[0].ExtraHeaders) == 0 {
@@ -16,12 +16,20 @@ package ga4gh
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
 	glog "github.com/golang/glog" /* copybara-comment */
 	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2/jwt" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/filesign" /* copybara-comment: filesign */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
 )
@@ -76,6 +84,51 @@ func TestNewVisaFromJWT(t *testing.T) {
 	}
 }
 
+func TestNewVisaFromJWT_Algorithms(t *testing.T) {
+	d := fakeVisaData()
+	ctx := context.Background()
+
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+	}
+	signer, err := filesign.NewFromKeys([]*filesign.Key{{ID: "ec", Private: ec}})
+	if err != nil {
+		t.Fatalf("filesign.NewFromKeys() failed: %v", err)
+	}
+	es256, err := signer.SignJWT(ctx, d, nil)
+	if err != nil {
+		t.Fatalf("SignJWT() failed: %v", err)
+	}
+	if _, err := NewVisaFromJWT(VisaJWT(es256)); err != nil {
+		t.Errorf("NewVisaFromJWT(ES256) failed: %v", err)
+	}
+	if err := CheckSigningAlgorithm(es256, []string{"RS256"}); err == nil {
+		t.Errorf("CheckSigningAlgorithm(ES256, [RS256]) succeeded, want error")
+	}
+
+	// A public key must not be usable as HMAC secret.
+	hmac, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("public-key")}, nil)
+	if err != nil {
+		t.Fatalf("jose.NewSigner() failed: %v", err)
+	}
+	hs256, err := jwt.Signed(hmac).Claims(d).CompactSerialize()
+	if err != nil {
+		t.Fatalf("CompactSerialize() failed: %v", err)
+	}
+	payload, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+
+	for _, tok := range []string{hs256, none} {
+		if _, err := NewVisaFromJWT(VisaJWT(tok)); err == nil {
+			t.Errorf("NewVisaFromJWT(%q) succeeded, want error", tok)
+		}
+	}
+}
+
 func TestVisaJSONFormat(t *testing.T) {
 	_, j := fakeVisaDataAndJWT(t)
 	got, err := payloadFromJWT(string(j))
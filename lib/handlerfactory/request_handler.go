@@ -16,6 +16,7 @@
 package handlerfactory
 
 import (
+	"fmt"
 	"net/http"
 	"regexp"
 	"runtime/debug"
@@ -24,6 +25,7 @@ import (
 	"google.golang.org/grpc/codes" /* copybara-comment */
 	"google.golang.org/grpc/status" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/globalflags" /* copybara-comment: globalflags */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
@@ -64,14 +66,26 @@ type Service interface {
 	Save(r *http.Request, tx storage.Tx, name string, vars map[string]string, desc, typeName string) error
 }
 
+// ETagger is implemented by services that support conditional requests.
+// The If-Match header of PUT, PATCH and DELETE requests is checked against
+// the etag of the item found by LookupItem, and the ETag header of responses
+// is set to the etag of the item after GET, POST, PUT and PATCH requests.
+type ETagger interface {
+	// ETag returns the etag of the current item, or "" if there is none.
+	ETag() string
+}
+
 // MakeHandler created a HTTP handler wrapper around a given service.
 func MakeHandler(s storage.Store, opts *Options) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		resp, err := Process(s, opts, r)
+		resp, etag, err := process(s, opts, r)
 		if err != nil {
 			httputils.WriteError(w, err)
 			return
 		}
+		if len(etag) > 0 {
+			w.Header().Set("ETag", httputils.ETagHeader(etag))
+		}
 		if resp != nil {
 			httputils.WriteResp(w, resp)
 		}
@@ -79,7 +93,13 @@ func MakeHandler(s storage.Store, opts *Options) http.HandlerFunc {
 }
 
 // Process computes the response for a request.
-func Process(s storage.Store, opts *Options, r *http.Request) (_ proto.Message, ferr error) {
+func Process(s storage.Store, opts *Options, r *http.Request) (proto.Message, error) {
+	resp, _, err := process(s, opts, r)
+	return resp, err
+}
+
+// process computes the response and the etag of the item for a request.
+func process(s storage.Store, opts *Options, r *http.Request) (_ proto.Message, _ string, ferr error) {
 	defer func() {
 		if c := recover(); c != nil {
 			glog.Errorf("CRASH %s %s: %v\n%s", r.Method, r.URL.Path, c, string(debug.Stack()))
@@ -103,20 +123,20 @@ func Process(s storage.Store, opts *Options, r *http.Request) (_ proto.Message,
 	case http.MethodDelete:
 		op = hi.Remove
 	default:
-		return nil, status.Errorf(codes.InvalidArgument, "request method not supported: %q", r.Method)
+		return nil, "", status.Errorf(codes.InvalidArgument, "request method not supported: %q", r.Method)
 	}
 
 	// TODO: move inside each service and don't pass NameChecker here.
 	name, vars, err := ValidateResourceName(r, opts.NameField, opts.NameChecker)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		return nil, "", status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 	typ := opts.TypeName
 	desc := r.Method + " " + typ
 
 	tx, err := s.Tx(r.Method != http.MethodGet)
 	if err != nil {
-		return nil, status.Errorf(codes.Unavailable, "service dependencies not available; try again later")
+		return nil, "", status.Errorf(codes.Unavailable, "service dependencies not available; try again later")
 	}
 	defer func() {
 		err := tx.Finish()
@@ -128,13 +148,13 @@ func Process(s storage.Store, opts *Options, r *http.Request) (_ proto.Message,
 	// Get rid of Setup and move creation of transaction inside service methods.
 	//
 	if _, err = hi.Setup(r, tx); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// TODO: Replace NormalizeInput with a ParseReq that returns a request proto message.
 	// TODO: Explicitly pass the message to the service methods.
 	if err := hi.NormalizeInput(r, name, vars); err != nil {
-		return nil, toStatusErr(codes.InvalidArgument, err, r)
+		return nil, "", toStatusErr(codes.InvalidArgument, err, r)
 	}
 
 	// TODO: get rid of LookupItem and move this inside the service methods.
@@ -142,30 +162,48 @@ func Process(s storage.Store, opts *Options, r *http.Request) (_ proto.Message,
 	switch r.Method {
 	case http.MethodPost:
 		if exists {
-			return nil, status.Errorf(codes.AlreadyExists, "%s already exists: %q", typ, name)
+			return nil, "", status.Errorf(codes.AlreadyExists, "%s already exists: %q", typ, name)
 		}
 	case http.MethodGet, http.MethodPatch, http.MethodPut, http.MethodDelete:
 		if !exists {
 			if opts.HasNamedIdentifiers {
-				return nil, status.Errorf(codes.NotFound, "%s not found: %q", typ, name)
+				return nil, "", status.Errorf(codes.NotFound, "%s not found: %q", typ, name)
 			}
-			return nil, status.Errorf(codes.NotFound, "%s not found", typ)
+			return nil, "", status.Errorf(codes.NotFound, "%s not found", typ)
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPatch, http.MethodPut, http.MethodDelete:
+		if etag := currentETag(hi); len(etag) > 0 && !httputils.MatchETag(r.Header.Get("If-Match"), etag) {
+			return nil, "", errutil.NewPreconditionFailedError(fmt.Sprintf("%s has been modified: %q", typ, name))
 		}
 	}
 
 	if r.Method == http.MethodGet {
 		resp, err := op(r, name)
 		if err != nil {
-			return nil, toStatusErr(codes.InvalidArgument, err, r)
+			return nil, "", toStatusErr(codes.InvalidArgument, err, r)
 		}
-		return resp, nil
+		return resp, currentETag(hi), nil
 	}
 
 	resp, err := RunRMWTx(r, tx, op, hi.CheckIntegrity, hi.Save, name, vars, typ, desc)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return resp, nil
+	if r.Method == http.MethodDelete {
+		return resp, "", nil
+	}
+	return resp, currentETag(hi), nil
+}
+
+// currentETag returns the etag of the item of a service, if supported.
+func currentETag(s Service) string {
+	if e, ok := s.(ETagger); ok {
+		return e.ETag()
+	}
+	return ""
 }
 
 // ValidateResourceName checks if the resource name is valid.
@@ -394,3 +394,90 @@ func (s *serviceGetCrash) Get(r *http.Request, name string) (proto.Message, erro
 	fmt.Println(a[1])
 	return nil, nil
 }
+
+func TestETag(t *testing.T) {
+	extractVars = extractVarsFake
+
+	s := fakestore.New()
+	hf := &Options{
+		TypeName:            "duration",
+		NameField:           "duration",
+		PathPrefix:          "durations/{duration}",
+		HasNamedIdentifiers: true,
+		NameChecker:         map[string]*regexp.Regexp{"duration": regexp.MustCompile(".*")},
+		Service: func() Service {
+			return &serviceETag{fakeService: fakeService{store: s}}
+		},
+	}
+	h := MakeHandler(s, hf)
+
+	if err := s.Write("resource", "master", "user", "fake-duration-id", storage.LatestRev, &dpb.Duration{Seconds: 60}, nil); err != nil {
+		t.Fatalf("store.Write() failed: %v", err)
+	}
+	v1 := httputils.ETagHeader(storage.ETag(&dpb.Duration{Seconds: 60}))
+	v2 := httputils.ETagHeader(storage.ETag(&dpb.Duration{Seconds: 120}))
+
+	tests := []struct {
+		name     string
+		method   string
+		ifMatch  string
+		wantCode int
+		wantETag string
+	}{
+		{name: "get", method: http.MethodGet, wantCode: http.StatusOK, wantETag: v1},
+		{name: "put stale", method: http.MethodPut, ifMatch: v2, wantCode: http.StatusPreconditionFailed},
+		{name: "put", method: http.MethodPut, ifMatch: v1, wantCode: http.StatusOK, wantETag: v2},
+		{name: "delete stale", method: http.MethodDelete, ifMatch: v1, wantCode: http.StatusPreconditionFailed},
+		{name: "delete any", method: http.MethodDelete, ifMatch: "*", wantCode: http.StatusOK},
+	}
+	for _, tc := range tests {
+		r := httputils.MustNewReq(tc.method, "https://example.org/durations/fake-duration-id", httputils.MustEncodeProto(&dpb.Duration{Seconds: 120}))
+		if len(tc.ifMatch) > 0 {
+			r.Header.Set("If-Match", tc.ifMatch)
+		}
+		w := httputils.NewFakeWriter()
+		h.ServeHTTP(w, r)
+
+		code := w.Code
+		if code == 0 {
+			code = http.StatusOK
+		}
+		if code != tc.wantCode {
+			t.Errorf("%s: code = %d, wants %d", tc.name, code, tc.wantCode)
+		}
+		if got := w.Headers.Get("ETag"); got != tc.wantETag {
+			t.Errorf("%s: ETag = %q, wants %q", tc.name, got, tc.wantETag)
+		}
+	}
+}
+
+type serviceETag struct {
+	fakeService
+	etag string
+}
+
+func (s *serviceETag) LookupItem(r *http.Request, name string, vars map[string]string) bool {
+	d := &dpb.Duration{}
+	if err := s.store.ReadTx("resource", "master", "user", vars["duration"], storage.LatestRev, d, s.tx); err != nil {
+		return false
+	}
+	s.etag = storage.ETag(d)
+	return true
+}
+
+func (s *serviceETag) Put(r *http.Request, name string) (proto.Message, error) {
+	vars := extractVars(r)
+	d := &dpb.Duration{}
+	if err := httputils.DecodeProtoReq(d, r); err != nil {
+		return nil, err
+	}
+	if err := s.store.WriteIfMatchTx("resource", "master", "user", vars["duration"], storage.LatestRev, s.etag, d, nil, s.tx); err != nil {
+		return nil, err
+	}
+	s.etag = storage.ETag(d)
+	return d, nil
+}
+
+func (s *serviceETag) ETag() string {
+	return s.etag
+}
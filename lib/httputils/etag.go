@@ -0,0 +1,46 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputils
+
+// This file contains the utilities for conditional requests (RFC 7232).
+
+import (
+	"strings"
+)
+
+// ETagHeader formats an etag as a strong entity tag for the ETag header.
+func ETagHeader(etag string) string {
+	return `"` + etag + `"`
+}
+
+// MatchETag checks if the value of an If-Match header matches the etag of the
+// current item using the strong comparison. An empty etag means that the item
+// does not exist, so it only matches an empty header.
+func MatchETag(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if len(header) == 0 {
+		return true
+	}
+	if len(etag) == 0 {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == ETagHeader(etag) {
+			return true
+		}
+	}
+	return false
+}
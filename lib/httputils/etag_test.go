@@ -0,0 +1,82 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputils
+
+import "testing"
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		want   bool
+	}{
+		{
+			name:   "no header",
+			header: "",
+			etag:   "abc",
+			want:   true,
+		},
+		{
+			name:   "match",
+			header: `"abc"`,
+			etag:   "abc",
+			want:   true,
+		},
+		{
+			name:   "mismatch",
+			header: `"abd"`,
+			etag:   "abc",
+			want:   false,
+		},
+		{
+			name:   "list",
+			header: `"abd", "abc"`,
+			etag:   "abc",
+			want:   true,
+		},
+		{
+			name:   "any",
+			header: "*",
+			etag:   "abc",
+			want:   true,
+		},
+		{
+			name:   "any not exists",
+			header: "*",
+			etag:   "",
+			want:   false,
+		},
+		{
+			name:   "weak",
+			header: `W/"abc"`,
+			etag:   "abc",
+			want:   false,
+		},
+		{
+			name:   "unquoted",
+			header: "abc",
+			etag:   "abc",
+			want:   false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := MatchETag(tc.header, tc.etag); got != tc.want {
+				t.Errorf("MatchETag(%q, %q) = %v, want %v", tc.header, tc.etag, got, tc.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"google.golang.org/grpc/status" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
 
 	glog "github.com/golang/glog" /* copybara-comment */
 )
@@ -68,7 +69,11 @@ func WriteError(w http.ResponseWriter, err error) {
 	}
 	glog.InfoDepth(1, err)
 	st := status.Convert(err)
-	w.WriteHeader(HTTPStatus(st.Code()))
+	code := HTTPStatus(st.Code())
+	if errutil.PreconditionFailed(err) {
+		code = http.StatusPreconditionFailed
+	}
+	w.WriteHeader(code)
 	WriteResp(w, st.Proto())
 }
 
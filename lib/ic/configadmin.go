@@ -50,6 +50,7 @@ type config struct {
 	s     *Service
 	input *pb.ConfigRequest
 	cfg   *pb.IcConfig
+	etag  string
 	id    *ga4gh.Identity
 }
 
@@ -61,6 +62,7 @@ func (c *config) Setup(r *http.Request, tx storage.Tx) (int, error) {
 }
 func (c *config) LookupItem(r *http.Request, name string, vars map[string]string) bool {
 	// Trival name as there is only one config.
+	c.etag = strconv.FormatInt(c.cfg.Revision, 10)
 	return true
 }
 func (c *config) NormalizeInput(r *http.Request, name string, vars map[string]string) error {
@@ -133,6 +135,7 @@ func (c *config) Save(r *http.Request, tx storage.Tx, name string, vars map[stri
 	if err := c.s.saveConfig(c.input.Item, desc, typeName, r, c.id, c.cfg, c.input.Item, c.input.Modification, tx); err != nil {
 		return err
 	}
+	c.etag = strconv.FormatInt(c.input.Item.Revision, 10)
 	secrets, err := c.s.loadSecrets(tx)
 	if err != nil {
 		return err
@@ -146,6 +149,11 @@ func (c *config) Save(r *http.Request, tx storage.Tx, name string, vars map[stri
 	return nil
 }
 
+// ETag returns the revision of the config.
+func (c *config) ETag() string {
+	return c.etag
+}
+
 // HTTP handler for ".../config/identityProviders/{name}"
 func (s *Service) configIdpFactory() *handlerfactory.Options {
 	return &handlerfactory.Options{
@@ -324,6 +332,7 @@ type configOptions struct {
 	item  *pb.ConfigOptions
 	save  *pb.ConfigOptions
 	cfg   *pb.IcConfig
+	etag  string
 	id    *ga4gh.Identity
 	tx    storage.Tx
 }
@@ -338,6 +347,7 @@ func (c *configOptions) Setup(r *http.Request, tx storage.Tx) (int, error) {
 
 func (c *configOptions) LookupItem(r *http.Request, name string, vars map[string]string) bool {
 	c.item = c.cfg.Options
+	c.etag = strconv.FormatInt(c.cfg.Revision, 10)
 	return true
 }
 
@@ -402,9 +412,15 @@ func (c *configOptions) Save(r *http.Request, tx storage.Tx, name string, vars m
 	if err := c.s.saveConfig(c.cfg, desc, typeName, r, c.id, c.item, c.save, c.input.Modification, c.tx); err != nil {
 		return err
 	}
+	c.etag = strconv.FormatInt(c.cfg.Revision, 10)
 	return nil
 }
 
+// ETag returns the revision of the config.
+func (c *configOptions) ETag() string {
+	return c.etag
+}
+
 // HTTP handler for ".../config/clients/{name}"
 // ....
 
@@ -467,21 +483,25 @@ func (s *Service) ConfigReset(w http.ResponseWriter, r *http.Request) {
 		if getRealm(r) != storage.DefaultRealm {
 			return
 		}
-		conf, err := s.loadConfig(nil, storage.DefaultRealm)
-		if err != nil {
-			httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
-			return
-		}
-
-		secrets, err := s.loadSecrets(nil)
-		if err != nil {
-			httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
+		if err := s.syncHydraClients(); err != nil {
+			httputils.WriteError(w, err)
 			return
 		}
+	}
+}
 
-		if _, err := s.syncToHydra(conf.Clients, secrets.ClientSecrets, 0, nil); err != nil {
-			httputils.WriteError(w, status.Errorf(codes.Unavailable, "%v", err))
-			return
-		}
+// syncHydraClients syncs the clients of the default realm to Hydra.
+func (s *Service) syncHydraClients() error {
+	conf, err := s.loadConfig(nil, storage.DefaultRealm)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
 	}
+	secrets, err := s.loadSecrets(nil)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	if _, err := s.syncToHydra(conf.Clients, secrets.ClientSecrets, 0, nil); err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return nil
 }
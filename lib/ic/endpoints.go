@@ -35,7 +35,8 @@ const (
 	acceptInformationReleasePath = "/identity/inforelease/accept"
 	// Redirected here from claim release consent page.
 	rejectInformationReleasePath = "/identity/inforelease/reject"
-	// Proxy hydra token endpoint.
+	// Proxy hydra token endpoint, except for the token exchange grant which
+	// exchanges access tokens for passport JWTs.
 	oauthTokenPath = "/oauth2/token"
 	// Hydra's auth endpoint.
 	oauthAuthPath = "oauth2/auth"
@@ -69,6 +70,10 @@ const (
 	// Required permission: trusted client with "sync" scope defined in the config.
 	syncClientsPath = "/identity/v1alpha/{realm}/clients:sync"
 
+	// Backup: GET exports the items of a realm as a zip archive, POST restores them.
+	// Required permission: admin
+	backupPath = "/identity/v1alpha/{realm}/backup"
+
 	// ConfigHistory: history of configuration changes.
 	// Required permission: admin
 	configHistoryPath         = "/identity/v1alpha/{realm}/config/history"
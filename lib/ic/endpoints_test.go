@@ -52,7 +52,7 @@ var (
 
 		// consent management endpoints
 		"GET /identity/v1alpha/{realm}/users/{user}/consents",
-		"DELETE /identity/v1alpha/{realm}/users/{user}/consents/{consent_id}",
+		"DELETE|GET /identity/v1alpha/{realm}/users/{user}/consents/{consent_id}",
 
 		// token management endpoints
 		"GET /identity/v1alpha/users/{user}/tokens",
@@ -82,6 +82,8 @@ var (
 		"/identity/v1alpha/{realm}/config",
 		"/identity/v1alpha/{realm}/config/clients/{name}",
 		"/identity/v1alpha/{realm}/config/options",
+		"GET /identity/v1alpha/{realm}/backup",
+		"POST /identity/v1alpha/{realm}/backup",
 		"GET /identity/v1alpha/{realm}/config/history",
 		"GET /identity/v1alpha/{realm}/config/history/{name}",
 		"GET /identity/v1alpha/{realm}/config/reset",
@@ -40,6 +40,7 @@ import (
 	"github.com/pborman/uuid" /* copybara-comment */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/auditlogsapi" /* copybara-comment: auditlogsapi */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/auth" /* copybara-comment: auth */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache" /* copybara-comment: cache */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/check" /* copybara-comment: check */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cli" /* copybara-comment: cli */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/consentsapi" /* copybara-comment: consentsapi */
@@ -48,15 +49,18 @@ import (
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/hydraproxy" /* copybara-comment: hydraproxy */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/lockstore" /* copybara-comment: lockstore */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/oathclients" /* copybara-comment: oathclients */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/permissions" /* copybara-comment: permissions */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/scim" /* copybara-comment: scim */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret" /* copybara-comment: secret */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/srcutil" /* copybara-comment: srcutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/strutil" /* copybara-comment: strutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/timeutil" /* copybara-comment: timeutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/tokensapi" /* copybara-comment: tokensapi */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/translator" /* copybara-comment: translator */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/verifier" /* copybara-comment: verifier */
 
 	glog "github.com/golang/glog" /* copybara-comment */
 	lgrpcpb "google.golang.org/genproto/googleapis/logging/v2" /* copybara-comment: logging_go_grpc */
@@ -176,6 +180,9 @@ type Service struct {
 	tokenProviders             []tokensapi.TokenProvider
 	auditlogs                  *auditlogsapi.AuditLogs
 	checker                    *auth.Checker
+	secrets                    secret.SecretProvider
+	cache                      func() cache.Client
+	jwksCache                  *verifier.JWKSCache
 }
 
 type ServiceHandler struct {
@@ -220,6 +227,13 @@ type Options struct {
 	// ConsentDashboardURL is url to frontend consent dashboard, will replace
 	// ${USER_ID} with userID.
 	ConsentDashboardURL string
+	// SecretProvider: resolves the secret references of the service secrets,
+	// see lib/secret. Optional if the secrets hold raw values only.
+	SecretProvider secret.SecretProvider
+	// Cache: returns a cache client, optional. Clients are closed after use.
+	Cache func() cache.Client
+	// JWKSCache: shares the keys of token issuers among verifiers, optional.
+	JWKSCache *verifier.JWKSCache
 }
 
 // NewService create new IC service.
@@ -277,6 +291,9 @@ func New(r *mux.Router, params *Options) *Service {
 		cliAcceptHandler:           cliAcceptHandler,
 		consentDashboardURL:        params.ConsentDashboardURL,
 		auditlogs:                  auditlogsapi.NewAuditLogs(params.SDLC, params.AuditLogProject, params.ServiceName),
+		secrets:                    params.SecretProvider,
+		cache:                      params.Cache,
+		jwksCache:                  params.JWKSCache,
 	}
 
 	if s.httpClient == nil {
@@ -323,9 +340,10 @@ func New(r *mux.Router, params *Options) *Service {
 	}
 
 	s.syncToHydra(cfg.Clients, secrets.ClientSecrets, 30*time.Second, nil)
+	s.watchTranslators(ctx)
 
 	a := authChecker{s: s}
-	checker := auth.NewChecker(s.logger, s.getIssuerString(), permissions.New(s.store), a.fetchClientSecrets, a.transformIdentity, false, nil)
+	checker := auth.NewChecker(s.logger, s.getIssuerString(), permissions.New(s.store), a.fetchClientSecrets, a.transformIdentity, false, s.cache, verifier.JWKSCacheOption(s.jwksCache))
 
 	s.checker = checker
 
@@ -617,12 +635,14 @@ func (s *Service) handlerSetupNoAuth(tx storage.Tx, r *http.Request, item proto.
 	return cfg, http.StatusOK, nil
 }
 
+// handlerSetup returns the config, the stored secrets to modify and the
+// identity of the request.
 func (s *Service) handlerSetup(tx storage.Tx, r *http.Request, scope string, item proto.Message) (*pb.IcConfig, *pb.IcSecrets, *ga4gh.Identity, int, error) {
 	cfg, st, err := s.handlerSetupNoAuth(tx, r, item)
 	if err != nil {
 		return nil, nil, nil, st, err
 	}
-	secrets, err := s.loadSecrets(tx)
+	secrets, err := s.loadStoredSecrets(tx)
 	if err != nil {
 		return nil, nil, nil, http.StatusServiceUnavailable, status.Errorf(codes.Unavailable, "%v", err)
 	}
@@ -718,9 +738,14 @@ func (s *Service) syncToHydra(clients map[string]*cpb.Client, secrets map[string
 		return nil, fmt.Errorf("hydra sync has completed recently or is active")
 	}
 	if tx == nil {
-		// Is a new tx (i.e. ltx didn't override tx)
+		// Is a new tx (i.e. ltx didn't override tx), otherwise the lock is
+		// released when tx finishes.
 		defer ltx.Finish()
 	}
+	// Do not overwrite the clients pushed by a newer holder of the lock.
+	if err := lockstore.CheckFencingToken(ltx); err != nil {
+		return nil, err
+	}
 	state, err := oathclients.SyncClients(s.httpClient, s.hydraAdminURL, clients, secrets)
 	if err != nil {
 		glog.Errorf("failed to sync hydra clients: %v", err)
@@ -996,6 +1021,26 @@ func (s *Service) getIssuerTranslator(ctx context.Context, issuer string, cfg *p
 	return t, err
 }
 
+// watchTranslators drops the cached translators when the config or the secrets
+// they are created from change, in this or another instance, until ctx is done.
+func (s *Service) watchTranslators(ctx context.Context) {
+	for _, datatype := range []string{storage.ConfigDatatype, storage.SecretsDatatype} {
+		changes, err := s.store.Watch(ctx, datatype, storage.AllRealms)
+		if err != nil {
+			glog.Errorf("watching changes of %q failed, translators are not refreshed: %v", datatype, err)
+			continue
+		}
+		go func() {
+			for range changes {
+				s.translators.Range(func(issuer, _ interface{}) bool {
+					s.translators.Delete(issuer)
+					return true
+				})
+			}
+		}()
+	}
+}
+
 func (s *Service) createIssuerTranslator(ctx context.Context, cfgIdp *cpb.IdentityProvider, secrets *pb.IcSecrets) (translator.Translator, error) {
 	iss := cfgIdp.Issuer
 	publicKey := ""
@@ -1006,7 +1051,7 @@ func (s *Service) createIssuerTranslator(ctx context.Context, cfgIdp *cpb.Identi
 
 	selfIssuer := s.getIssuerString()
 
-	return translator.CreateTranslator(ctx, iss, cfgIdp.TranslateUsing, cfgIdp.ClientId, publicKey, selfIssuer, s.signer)
+	return translator.CreateTranslator(ctx, iss, cfgIdp.TranslateUsing, cfgIdp.ClientId, publicKey, selfIssuer, s.signer, verifier.JWKSCacheOption(s.jwksCache))
 }
 
 func (s *Service) checkConfigIntegrity(cfg *pb.IcConfig) error {
@@ -1206,7 +1251,37 @@ func (s *Service) saveConfig(cfg *pb.IcConfig, desc, resType string, r *http.Req
 	return nil
 }
 
+// loadSecrets loads the service secrets with the secret references resolved
+// to their values. The result must not be saved, use loadStoredSecrets to
+// modify the secrets.
 func (s *Service) loadSecrets(tx storage.Tx) (*pb.IcSecrets, error) {
+	stored, err := s.loadStoredSecrets(tx)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve on a copy, storage may cache the stored secrets.
+	secrets := proto.Clone(stored).(*pb.IcSecrets)
+	ctx := context.Background()
+	if err := secret.ResolveMap(ctx, s.secrets, secrets.ClientSecrets); err != nil {
+		return nil, err
+	}
+	if err := secret.ResolveMap(ctx, s.secrets, secrets.IdProviderSecrets); err != nil {
+		return nil, err
+	}
+	for _, k := range secrets.TokenKeys {
+		if k.PrivateKey, err = secret.Resolve(ctx, s.secrets, k.PrivateKey); err != nil {
+			return nil, err
+		}
+		if k.PublicKey, err = secret.Resolve(ctx, s.secrets, k.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+// loadStoredSecrets loads the service secrets as stored, secret references
+// are not resolved.
+func (s *Service) loadStoredSecrets(tx storage.Tx) (*pb.IcSecrets, error) {
 	secrets := &pb.IcSecrets{}
 	_, err := s.realmReadTx(storage.SecretsDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, storage.LatestRev, secrets, tx)
 	if err != nil {
@@ -1385,6 +1460,9 @@ func registerHandlers(r *mux.Router, s *Service) {
 	r.HandleFunc(configClientsPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.configClientFactory()), s.checker, auth.RequireAdminTokenClientCredential))
 	r.HandleFunc(configOptionsPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.configOptionsFactory()), s.checker, auth.RequireAdminTokenClientCredential))
 	r.HandleFunc(configResetPath, auth.MustWithAuth(s.ConfigReset, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
+	bh := s.backupHandler()
+	r.HandleFunc(backupPath, auth.MustWithAuth(bh.Export, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
+	r.HandleFunc(backupPath, auth.MustWithAuth(bh.Restore, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodPost)
 	r.HandleFunc(configHistoryPath, auth.MustWithAuth(s.ConfigHistory, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
 	r.HandleFunc(configHistoryRevisionPath, auth.MustWithAuth(s.ConfigHistoryRevision, s.checker, auth.RequireAdminTokenClientCredential)).Methods(http.MethodGet)
 
@@ -1411,7 +1489,7 @@ func registerHandlers(r *mux.Router, s *Service) {
 	// consents service endpoints
 	consentService := s.consentService()
 	r.HandleFunc(listConsentPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), consentsapi.ListConsentsFactory(consentService, listConsentPath)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet)
-	r.HandleFunc(deleteConsentPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), consentsapi.DeleteConsentFactory(consentService, deleteConsentPath, true)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodDelete)
+	r.HandleFunc(deleteConsentPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), consentsapi.DeleteConsentFactory(consentService, deleteConsentPath, true)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet, http.MethodDelete)
 
 	// audit logs endpoints
 	r.HandleFunc(auditlogsPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.store, auditlogsapi.ListAuditlogsPathFactory(auditlogsPath, s.auditlogs)), s.checker, auth.RequireUserTokenClientCredential)).Methods(http.MethodGet)
@@ -1420,6 +1498,9 @@ func registerHandlers(r *mux.Router, s *Service) {
 	r.HandleFunc(adminClaimsPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.adminClaimsFactory()), s.checker, auth.RequireAdminTokenClientCredential))
 	r.HandleFunc(adminTokenMetadataPath, auth.MustWithAuth(handlerfactory.MakeHandler(s.GetStore(), s.adminTokenMetadataFactory()), s.checker, auth.RequireAdminTokenClientCredential))
 
+	// token exchange of access tokens for passport JWTs
+	r.HandleFunc(oauthTokenPath, subjectTokenAsBearer(auth.MustWithAuth(s.TokenExchange, s.checker, auth.RequireUserTokenClientCredential))).Methods(http.MethodPost).MatcherFunc(isTokenExchange)
+
 	// proxy hydra oauth token endpoint
 	if s.hydraPublicURLProxy != nil {
 		r.HandleFunc(oauthTokenPath, s.hydraPublicURLProxy.HydraOAuthToken).Methods(http.MethodPost)
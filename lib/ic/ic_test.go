@@ -163,6 +163,24 @@ func TestHandlers(t *testing.T) {
 			Output:  `{"Resources":[{"id":"allowlisted","displayName":"Allowlisted Users"},{"id":"lab","displayName":"Lab Members"}],"startIndex":1,"itemsPerPage":2,"totalResults":2,"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"]}`,
 			Status:  http.StatusOK,
 		},
+		{
+			Name:    "Get SCIM groups - sort displayName descending",
+			Method:  "GET",
+			Path:    "/scim/v2/test/Groups",
+			Persona: "admin",
+			Params:  `sortBy=displayName&sortOrder=descending&startIndex=2&count=2`,
+			Output:  `{"Resources":[{"id":"lab","displayName":"Lab Members"},{"id":"auditors","displayName":"Auditors"}],"startIndex":2,"itemsPerPage":2,"totalResults":4,"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"]}`,
+			Status:  http.StatusOK,
+		},
+		{
+			Name:    "Get SCIM groups - sort unknown field",
+			Method:  "GET",
+			Path:    "/scim/v2/test/Groups",
+			Persona: "admin",
+			Params:  `sortBy=members`,
+			Output:  `^.*sort field.*not defined.*`,
+			Status:  http.StatusBadRequest,
+		},
 		{
 			Name:    "Get SCIM groups (non-admin)",
 			Method:  "GET",
@@ -442,7 +442,8 @@ func (s *Service) acceptInformationRelease(r *http.Request) (_, _ string, ferr e
 
 		rID := uuid.New()
 		rcp.RequestedScopes = strings.Split(state.Scope, " ")
-		err = s.store.WriteTx(storage.RememberedConsentDatatype, state.Realm, state.Subject, rID, storage.LatestRev, rcp, nil, tx)
+		// An empty etag makes sure that the new consent does not overwrite an existing one.
+		err = s.store.WriteIfMatchTx(storage.RememberedConsentDatatype, state.Realm, state.Subject, rID, storage.LatestRev, "", rcp, nil, tx)
 		if err != nil {
 			return challenge, "", status.Errorf(codes.Internal, "accept info release datastore write remember consent failed: %v", err)
 		}
@@ -0,0 +1,154 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ic
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/pborman/uuid" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/auth" /* copybara-comment: auth */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/translator" /* copybara-comment: translator */
+)
+
+const (
+	subjectTokenParam       = "subject_token"
+	subjectTokenTypeParam   = "subject_token_type"
+	requestedTokenTypeParam = "requested_token_type"
+	audienceParam           = "audience"
+)
+
+// tokenExchangeResponse is the response of the token exchange grant.
+// See https://tools.ietf.org/html/rfc8693#section-2.2.1
+type tokenExchangeResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+}
+
+// isTokenExchange matches requests of the token exchange grant on the token
+// endpoint, other grants are proxied to hydra.
+func isTokenExchange(r *http.Request, _ *mux.RouteMatch) bool {
+	return r.PostFormValue("grant_type") == ga4gh.TokenExchangeGrantType
+}
+
+// subjectTokenAsBearer passes the subject token of a token exchange to the
+// handler as its bearer token, so the auth checker verifies it.
+func subjectTokenAsBearer(handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tok := r.PostFormValue(subjectTokenParam)
+		if len(tok) == 0 {
+			httputils.WriteError(w, status.Errorf(codes.InvalidArgument, "missing %s", subjectTokenParam))
+			return
+		}
+		r.Header.Set(auth.UserAuthorizationHeader, "Bearer "+tok)
+		handler(w, r)
+	}
+}
+
+// TokenExchange exchanges an access token for a self-contained passport JWT
+// containing the visas released to the client, as per GA4GH AAI v1.2.
+func (s *Service) TokenExchange(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.tokenExchange(r)
+	if err != nil {
+		httputils.WriteError(w, err)
+		return
+	}
+	httputils.WriteNonProtoResp(w, resp)
+}
+
+func (s *Service) tokenExchange(r *http.Request) (*tokenExchangeResponse, error) {
+	if t := r.PostFormValue(subjectTokenTypeParam); t != ga4gh.AccessTokenType {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported %s %q", subjectTokenTypeParam, t)
+	}
+	if t := r.PostFormValue(requestedTokenTypeParam); len(t) > 0 && t != ga4gh.SelfContainedPassportTokenType {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported %s %q", requestedTokenTypeParam, t)
+	}
+
+	aud, err := s.passportAudience(r)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := auth.FromContext(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// Userinfo only returns the visas the user released to the client.
+	id := &ga4gh.Identity{Issuer: a.ID.Issuer, Subject: a.ID.Subject}
+	id, err = translator.FetchUserinfoClaims(r.Context(), s.httpClient, id, r.PostFormValue(subjectTokenParam), nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "fetching userinfo failed: %v", err)
+	}
+
+	now := time.Now().Unix()
+	d := &ga4gh.SelfContainedPassportData{
+		StdClaims: ga4gh.StdClaims{
+			Issuer:    s.getVisaIssuerString(),
+			Subject:   a.ID.Subject,
+			Audience:  aud,
+			IssuedAt:  now,
+			ExpiresAt: a.ID.Expiry,
+			ID:        uuid.New(),
+		},
+	}
+	for _, v := range id.VisaJWTs {
+		d.Visas = append(d.Visas, ga4gh.VisaJWT(v))
+	}
+
+	p, err := ga4gh.NewSelfContainedPassportFromData(r.Context(), d, s.visaIssuerJKU(), s.signer)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "signing passport failed: %v", err)
+	}
+
+	return &tokenExchangeResponse{
+		AccessToken:     string(p.JWT()),
+		IssuedTokenType: ga4gh.SelfContainedPassportTokenType,
+		TokenType:       "N_A",
+		ExpiresIn:       a.ID.Expiry - now,
+	}, nil
+}
+
+// passportAudience returns the audience of the passport requested. Services
+// accepting passports require their client id in the audience, so the audience
+// is required and must be the client ids of clients of the IC.
+func (s *Service) passportAudience(r *http.Request) (ga4gh.Audiences, error) {
+	aud := r.PostForm[audienceParam]
+	if len(aud) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "missing %s: the client id of the service the passport is for", audienceParam)
+	}
+	cfg, err := s.loadConfig(nil, storage.DefaultRealm)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "loading config failed: %v", err)
+	}
+	clients := make(map[string]bool)
+	for _, c := range cfg.Clients {
+		clients[c.ClientId] = true
+	}
+	for _, a := range aud {
+		if !clients[a] {
+			return nil, status.Errorf(codes.InvalidArgument, "%s %q is not the client id of a client of the service", audienceParam, a)
+		}
+	}
+	return ga4gh.Audiences(aud), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/persona" /* copybara-comment: persona */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+)
+
+func sendTokenExchange(t *testing.T, s *Service, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	form.Set("grant_type", ga4gh.TokenExchangeGrantType)
+	form.Set("client_id", testClientID)
+	form.Set("client_secret", testClientSecret)
+	r := httptest.NewRequest(http.MethodPost, "https://ic.example.com"+oauthTokenPath, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	s.Handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestTokenExchange(t *testing.T) {
+	s, _, _, _, iss, err := setupHydraTest()
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+	s.signer = localsign.New(&testkeys.Default)
+
+	pname := "dr_joe_elixir"
+	p := iss.Config().TestPersonas[pname]
+	tok, _, err := persona.NewAccessToken(pname, hydraURL, testClientID, persona.DefaultScope, p)
+	if err != nil {
+		t.Fatalf("persona.NewAccessToken(%q, %q, _, _) failed: %v", pname, hydraURL, err)
+	}
+
+	w := sendTokenExchange(t, s, url.Values{
+		"subject_token":        {string(tok)},
+		"subject_token_type":   {ga4gh.AccessTokenType},
+		"requested_token_type": {ga4gh.SelfContainedPassportTokenType},
+		"audience":             {testClientID},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("token exchange status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	resp := &tokenExchangeResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	if resp.IssuedTokenType != ga4gh.SelfContainedPassportTokenType {
+		t.Errorf("issued_token_type = %q, want %q", resp.IssuedTokenType, ga4gh.SelfContainedPassportTokenType)
+	}
+	if resp.ExpiresIn <= 0 {
+		t.Errorf("expires_in = %d, want positive", resp.ExpiresIn)
+	}
+
+	if err := ga4gh.VerifyTokenWithKey(testkeys.Default.Public, resp.AccessToken); err != nil {
+		t.Fatalf("VerifyTokenWithKey() failed: %v", err)
+	}
+	passport, err := ga4gh.NewSelfContainedPassportFromJWT(ga4gh.SelfContainedPassportJWT(resp.AccessToken))
+	if err != nil {
+		t.Fatalf("NewSelfContainedPassportFromJWT() failed: %v", err)
+	}
+	if got, want := passport.JKU(), s.visaIssuerJKU(); got != want {
+		t.Errorf("JKU() = %q, want %q", got, want)
+	}
+	d := passport.Data()
+	if d.Issuer != s.getVisaIssuerString() || d.Subject != pname {
+		t.Errorf("passport iss, sub = %q, %q, want %q, %q", d.Issuer, d.Subject, s.getVisaIssuerString(), pname)
+	}
+	if len(d.Audience) != 1 || d.Audience[0] != testClientID {
+		t.Errorf("passport aud = %v, want [%s]", d.Audience, testClientID)
+	}
+	visas, err := passport.Visas()
+	if err != nil {
+		t.Fatalf("Visas() failed: %v", err)
+	}
+	if len(visas) != len(p.Passport.Ga4GhAssertions) {
+		t.Errorf("len(Visas()) = %d, want %d", len(visas), len(p.Passport.Ga4GhAssertions))
+	}
+}
+
+func TestTokenExchange_Error(t *testing.T) {
+	s, _, _, _, iss, err := setupHydraTest()
+	if err != nil {
+		t.Fatalf("setupHydraTest() failed: %v", err)
+	}
+	s.signer = localsign.New(&testkeys.Default)
+
+	pname := "dr_joe_elixir"
+	tok, _, err := persona.NewAccessToken(pname, hydraURL, testClientID, persona.DefaultScope, iss.Config().TestPersonas[pname])
+	if err != nil {
+		t.Fatalf("persona.NewAccessToken(%q, %q, _, _) failed: %v", pname, hydraURL, err)
+	}
+
+	tests := []struct {
+		name string
+		form url.Values
+		want int
+	}{
+		{
+			name: "missing subject token",
+			form: url.Values{"subject_token_type": {ga4gh.AccessTokenType}, "audience": {testClientID}},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "invalid subject token",
+			form: url.Values{"subject_token": {"invalid"}, "subject_token_type": {ga4gh.AccessTokenType}, "audience": {testClientID}},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "unsupported subject token type",
+			form: url.Values{"subject_token": {string(tok)}, "subject_token_type": {"urn:ietf:params:oauth:token-type:id_token"}, "audience": {testClientID}},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported requested token type",
+			form: url.Values{"subject_token": {string(tok)}, "subject_token_type": {ga4gh.AccessTokenType}, "requested_token_type": {ga4gh.AccessTokenType}, "audience": {testClientID}},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "missing audience",
+			form: url.Values{"subject_token": {string(tok)}, "subject_token_type": {ga4gh.AccessTokenType}},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "audience not a client",
+			form: url.Values{"subject_token": {string(tok)}, "subject_token_type": {ga4gh.AccessTokenType}, "audience": {"https://dam.example.com"}},
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := sendTokenExchange(t, s, tc.form)
+			if w.Code != tc.want {
+				t.Errorf("token exchange status = %d, want %d: %s", w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
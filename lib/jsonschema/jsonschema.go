@@ -0,0 +1,392 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema validates JSON values against a subset of JSON schema.
+//
+// The supported keywords are:
+//   - "type", a type name or a list of them: "null", "boolean", "object",
+//     "array", "number", "string" and "integer".
+//   - "enum" and "const".
+//   - "pattern", "minLength" and "maxLength" for strings.
+//   - "minimum" and "maximum" for numbers.
+//   - "items", "minItems" and "maxItems" for arrays.
+//   - "properties", "required" and "additionalProperties" for objects.
+//   - "$schema", "$id", "title", "description" and "examples", which are
+//     annotations and do not constrain values.
+//
+// Schemas with other keywords fail to compile, rather than accepting values
+// the author of the schema meant to reject.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	types = map[string]bool{
+		"null":    true,
+		"boolean": true,
+		"object":  true,
+		"array":   true,
+		"number":  true,
+		"string":  true,
+		"integer": true,
+	}
+
+	annotations = map[string]bool{
+		"$schema":     true,
+		"$id":         true,
+		"title":       true,
+		"description": true,
+		"examples":    true,
+	}
+)
+
+// Schema is a compiled JSON schema.
+type Schema struct {
+	types                []string
+	enum                 []interface{}
+	constant             interface{}
+	hasConst             bool
+	pattern              *regexp.Regexp
+	minLength            *int
+	maxLength            *int
+	minimum              *float64
+	maximum              *float64
+	items                *Schema
+	minItems             *int
+	maxItems             *int
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+}
+
+// ValidationError is a value not conforming to a schema.
+type ValidationError struct {
+	// Path is the JSON pointer of the non-conforming part of the value, for
+	// example "/terms/0".
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Compile parses a JSON schema.
+func Compile(schema string) (*Schema, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(schema), &v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return compile(v, "")
+}
+
+// Validate checks a value decoded by encoding/json conforms to the schema.
+func (s *Schema) Validate(v interface{}) error {
+	return s.validate(v, "")
+}
+
+// ValidateJSON checks a JSON text conforms to the schema.
+func (s *Schema) ValidateJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	return s.validate(v, "")
+}
+
+func compile(v interface{}, path string) (*Schema, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, schemaErr(path, "schema must be an object")
+	}
+	s := &Schema{}
+	for _, k := range sortedKeys(m) {
+		kv := m[k]
+		kp := path + "/" + k
+		var err error
+		switch k {
+		case "type":
+			s.types, err = compileTypes(kv, kp)
+		case "enum":
+			list, ok := kv.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, schemaErr(kp, "must be a non-empty array")
+			}
+			s.enum = list
+		case "const":
+			s.constant = kv
+			s.hasConst = true
+		case "pattern":
+			str, ok := kv.(string)
+			if !ok {
+				return nil, schemaErr(kp, "must be a string")
+			}
+			s.pattern, err = regexp.Compile(str)
+			if err != nil {
+				return nil, schemaErr(kp, fmt.Sprintf("invalid regular expression: %v", err))
+			}
+		case "minLength":
+			s.minLength, err = compileCount(kv, kp)
+		case "maxLength":
+			s.maxLength, err = compileCount(kv, kp)
+		case "minimum":
+			s.minimum, err = compileNumber(kv, kp)
+		case "maximum":
+			s.maximum, err = compileNumber(kv, kp)
+		case "items":
+			s.items, err = compile(kv, kp)
+		case "minItems":
+			s.minItems, err = compileCount(kv, kp)
+		case "maxItems":
+			s.maxItems, err = compileCount(kv, kp)
+		case "properties":
+			props, ok := kv.(map[string]interface{})
+			if !ok {
+				return nil, schemaErr(kp, "must be an object")
+			}
+			s.properties = make(map[string]*Schema)
+			for name, p := range props {
+				if s.properties[name], err = compile(p, kp+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.required, err = compileStrings(kv, kp)
+		case "additionalProperties":
+			if b, ok := kv.(bool); ok {
+				s.noAdditional = !b
+				break
+			}
+			s.additionalProperties, err = compile(kv, kp)
+		default:
+			if !annotations[k] {
+				return nil, schemaErr(kp, "keyword not supported")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func compileTypes(v interface{}, path string) ([]string, error) {
+	var list []string
+	if str, ok := v.(string); ok {
+		list = []string{str}
+	} else {
+		var err error
+		if list, err = compileStrings(v, path); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range list {
+		if !types[t] {
+			return nil, schemaErr(path, fmt.Sprintf("unknown type %q", t))
+		}
+	}
+	return list, nil
+}
+
+func compileStrings(v interface{}, path string) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, schemaErr(path, "must be an array of strings")
+	}
+	var out []string
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, schemaErr(path, "must be an array of strings")
+		}
+		out = append(out, str)
+	}
+	return out, nil
+}
+
+func compileCount(v interface{}, path string) (*int, error) {
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, schemaErr(path, "must be a non-negative integer")
+	}
+	n := int(f)
+	return &n, nil
+}
+
+func compileNumber(v interface{}, path string) (*float64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, schemaErr(path, "must be a number")
+	}
+	return &f, nil
+}
+
+func schemaErr(path, msg string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("schema %s: %s", path, msg)
+}
+
+func (s *Schema) validate(v interface{}, path string) error {
+	if len(s.types) > 0 && !hasType(v, s.types) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%s is not of type %s", typeOf(v), strings.Join(s.types, " or "))}
+	}
+	if s.hasConst && !reflect.DeepEqual(v, s.constant) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be %s", toJSON(s.constant))}
+	}
+	if len(s.enum) > 0 && !inEnum(v, s.enum) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be one of %s", toJSON(s.enum))}
+	}
+
+	switch x := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(x)
+		if s.minLength != nil && n < *s.minLength {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("length %d is less than %d", n, *s.minLength)}
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("length %d is more than %d", n, *s.maxLength)}
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%q does not match pattern %q", x, s.pattern.String())}
+		}
+
+	case float64:
+		if s.minimum != nil && x < *s.minimum {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%v is less than %v", x, *s.minimum)}
+		}
+		if s.maximum != nil && x > *s.maximum {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%v is more than %v", x, *s.maximum)}
+		}
+
+	case []interface{}:
+		if s.minItems != nil && len(x) < *s.minItems {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%d items are less than %d", len(x), *s.minItems)}
+		}
+		if s.maxItems != nil && len(x) > *s.maxItems {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%d items are more than %d", len(x), *s.maxItems)}
+		}
+		if s.items != nil {
+			for i, item := range x {
+				if err := s.items.validate(item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := x[name]; !ok {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+			}
+		}
+		for _, name := range sortedKeys(x) {
+			p, ok := s.properties[name]
+			if !ok {
+				if s.noAdditional {
+					return &ValidationError{Path: path, Message: fmt.Sprintf("property %q is not allowed", name)}
+				}
+				p = s.additionalProperties
+			}
+			if p == nil {
+				continue
+			}
+			if err := p.validate(x[name], path+"/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasType(v interface{}, list []string) bool {
+	t := typeOf(v)
+	for _, want := range list {
+		if want == t {
+			return true
+		}
+		if want == "number" && t == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON schema type of a value decoded by encoding/json,
+// numbers without a fractional part are "integer".
+func typeOf(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(v, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func toJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// escapePointer escapes a property name for a JSON pointer, see RFC 6901.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+)
+
+const consentSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "description": "a DUO consent code with modifiers",
+  "type": "object",
+  "properties": {
+    "code": {"type": "string", "pattern": "^DUO:[0-9]{7}$"},
+    "modifiers": {
+      "type": "array",
+      "items": {"enum": ["DUO:0000019", "DUO:0000026"]},
+      "maxItems": 2
+    },
+    "version": {"type": "integer", "minimum": 1}
+  },
+  "required": ["code"],
+  "additionalProperties": false
+}`
+
+func TestValidateJSON(t *testing.T) {
+	s, err := Compile(consentSchema)
+	if err != nil {
+		t.Fatalf("Compile() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		wantErr  bool
+		wantPath string
+	}{
+		{
+			name:  "minimal",
+			value: `{"code": "DUO:0000007"}`,
+		},
+		{
+			name:  "all properties",
+			value: `{"code": "DUO:0000007", "modifiers": ["DUO:0000019"], "version": 2}`,
+		},
+		{
+			name:     "not an object",
+			value:    `"DUO:0000007"`,
+			wantErr:  true,
+			wantPath: "",
+		},
+		{
+			name:     "missing required",
+			value:    `{"version": 2}`,
+			wantErr:  true,
+			wantPath: "",
+		},
+		{
+			name:     "pattern mismatch",
+			value:    `{"code": "DUO:7"}`,
+			wantErr:  true,
+			wantPath: "/code",
+		},
+		{
+			name:     "enum mismatch",
+			value:    `{"code": "DUO:0000007", "modifiers": ["DUO:0000019", "DUO:0000042"]}`,
+			wantErr:  true,
+			wantPath: "/modifiers/1",
+		},
+		{
+			name:     "too many items",
+			value:    `{"code": "DUO:0000007", "modifiers": ["DUO:0000019", "DUO:0000019", "DUO:0000026"]}`,
+			wantErr:  true,
+			wantPath: "/modifiers",
+		},
+		{
+			name:     "not an integer",
+			value:    `{"code": "DUO:0000007", "version": 1.5}`,
+			wantErr:  true,
+			wantPath: "/version",
+		},
+		{
+			name:     "less than minimum",
+			value:    `{"code": "DUO:0000007", "version": 0}`,
+			wantErr:  true,
+			wantPath: "/version",
+		},
+		{
+			name:     "additional property",
+			value:    `{"code": "DUO:0000007", "extra": true}`,
+			wantErr:  true,
+			wantPath: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.ValidateJSON([]byte(tc.value))
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateJSON(%s) failed: %v", tc.value, err)
+				}
+				return
+			}
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("ValidateJSON(%s) = %v, want *ValidationError", tc.value, err)
+			}
+			if verr.Path != tc.wantPath {
+				t.Errorf("ValidateJSON(%s) path = %q, want %q", tc.value, verr.Path, tc.wantPath)
+			}
+		})
+	}
+}
+
+func TestValidate_String(t *testing.T) {
+	s, err := Compile(`{"type": "string", "minLength": 2, "maxLength": 4, "const": "abc"}`)
+	if err != nil {
+		t.Fatalf("Compile() failed: %v", err)
+	}
+	if err := s.Validate("abc"); err != nil {
+		t.Errorf("Validate(abc) failed: %v", err)
+	}
+	for _, v := range []interface{}{"a", "abcde", "abd", 3.0, nil} {
+		if err := s.Validate(v); err == nil {
+			t.Errorf("Validate(%v) succeeded, want error", v)
+		}
+	}
+}
+
+func TestCompile_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "not JSON", schema: `{`},
+		{name: "not an object", schema: `"string"`},
+		{name: "unknown type", schema: `{"type": "date"}`},
+		{name: "invalid pattern", schema: `{"pattern": "("}`},
+		{name: "negative count", schema: `{"maxLength": -1}`},
+		{name: "empty enum", schema: `{"enum": []}`},
+		{name: "invalid property schema", schema: `{"properties": {"a": 1}}`},
+		{name: "unsupported keyword", schema: `{"oneOf": [{"type": "string"}]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Compile(tc.schema); err == nil {
+				t.Errorf("Compile(%s) succeeded, want error", tc.schema)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filesign contains a jwt signer with keys of PEM files, for
+// deployments without a cloud KMS.
+//
+// Keys are read from a PEM file with one or more private keys, or from all
+// "*.pem" files of a directory. A PEM block may have the headers:
+//
+//   Key-Id: the "kid" of the key, defaults to its JWK SHA-256 thumbprint.
+//   Not-Before: RFC3339 time from which the key is used to sign.
+//   Not-After: RFC3339 time after which the key is no longer used to sign.
+//
+// RSA keys sign with RS256, ECDSA keys with ES256, ES384 or ES512 depending on
+// their curve, and Ed25519 keys with EdDSA. Keys of different algorithms can
+// be mixed, e.g. to move from RS256 to ES256 with a rotation.
+//
+// Every key of the source is published in PublicKeys, and tokens are signed
+// with the key of the latest Not-Before which is valid. To rotate, add a new
+// key with a Not-Before far enough in the future for verifiers to refresh
+// their cached keys, and remove the old key once tokens it signed expired.
+package filesign
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+const (
+	// HeaderKeyID is the PEM header for the key ID.
+	HeaderKeyID = "Key-Id"
+	// HeaderNotBefore is the PEM header for the time the key is used from.
+	HeaderNotBefore = "Not-Before"
+	// HeaderNotAfter is the PEM header for the time the key is used until.
+	HeaderNotAfter = "Not-After"
+)
+
+// Key is a signing key.
+type Key struct {
+	ID string
+	// Private is an *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey.
+	Private crypto.Signer
+	// Algorithm is set from the type of the key.
+	Algorithm jose.SignatureAlgorithm
+	// NotBefore and NotAfter bound the time the key signs. Zero if unbounded.
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// validAt returns true if the key signs at the given time.
+func (k *Key) validAt(now time.Time) bool {
+	return !now.Before(k.NotBefore) && (k.NotAfter.IsZero() || now.Before(k.NotAfter))
+}
+
+// Client signs jwt with the keys of a PEM file or directory.
+type Client struct {
+	path string
+	now  func() time.Time
+
+	mu   sync.RWMutex
+	keys []*Key
+}
+
+// New returns a Client for the keys of a PEM file or a directory of PEM files.
+func New(path string) (*Client, error) {
+	c := &Client{path: path, now: time.Now}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewFromKeys returns a Client for a fixed set of keys.
+func NewFromKeys(keys []*Key) (*Client, error) {
+	c := &Client{now: time.Now}
+	if err := c.setKeys(keys); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the keys of the file or directory again. The current keys
+// are kept if the keys cannot be read.
+func (c *Client) Reload() error {
+	if c.path == "" {
+		return nil
+	}
+	keys, err := readKeys(c.path)
+	if err != nil {
+		return err
+	}
+	return c.setKeys(keys)
+}
+
+// Run reloads the keys at every interval until the context is done. Typically
+// this will be on its own go routine.
+func (c *Client) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(); err != nil {
+				glog.Errorf("reloading signing keys of %q failed: %v", c.path, err)
+			}
+		}
+	}
+}
+
+func (c *Client) setKeys(keys []*Key) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no signing keys")
+	}
+	ids := map[string]bool{}
+	for _, k := range keys {
+		if ids[k.ID] {
+			return fmt.Errorf("duplicate signing key ID %q", k.ID)
+		}
+		ids[k.ID] = true
+		alg, err := kms.SigningAlgorithm(k.Private.Public())
+		if err != nil {
+			return fmt.Errorf("signing key %q: %v", k.ID, err)
+		}
+		k.Algorithm = alg
+	}
+	// Order by latest Not-Before, for the first valid key to be the signing key.
+	sorted := append([]*Key{}, keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].NotBefore.Equal(sorted[j].NotBefore) {
+			return sorted[i].NotBefore.After(sorted[j].NotBefore)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = sorted
+	return nil
+}
+
+// current returns the key to sign with.
+func (c *Client) current() (*Key, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := c.now()
+	for _, k := range c.keys {
+		if k.validAt(now) {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("no signing key is valid at %v", now.Format(time.RFC3339))
+}
+
+// PublicKeys returns the public keys of all keys, the current signing key first.
+func (c *Client) PublicKeys() *jose.JSONWebKeySet {
+	cur, _ := c.current()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ks := &jose.JSONWebKeySet{}
+	if cur != nil {
+		ks.Keys = append(ks.Keys, publicKey(cur))
+	}
+	for _, k := range c.keys {
+		if k != cur {
+			ks.Keys = append(ks.Keys, publicKey(k))
+		}
+	}
+	return ks
+}
+
+// SignJWT signs the given claims return the jwt string.
+func (c *Client) SignJWT(ctx context.Context, claims interface{}, header map[string]string) (string, error) {
+	cur, err := c.current()
+	if err != nil {
+		return "", err
+	}
+	key := jose.SigningKey{
+		Algorithm: cur.Algorithm,
+		Key:       cur.Private,
+	}
+
+	opt := &jose.SignerOptions{}
+	opt.WithType("JWT")
+
+	if header == nil {
+		header = map[string]string{}
+	}
+	header["kid"] = cur.ID
+	for k, v := range header {
+		opt.WithHeader(jose.HeaderKey(k), v)
+	}
+
+	signer, err := jose.NewSigner(key, opt)
+	if err != nil {
+		return "", fmt.Errorf("failed to create signer: %v", err)
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := signer.Sign(b)
+	if err != nil {
+		return "", err
+	}
+
+	return res.CompactSerialize()
+}
+
+func publicKey(k *Key) jose.JSONWebKey {
+	return jose.JSONWebKey{
+		Key:       k.Private.Public(),
+		Algorithm: string(k.Algorithm),
+		Use:       "sig",
+		KeyID:     k.ID,
+	}
+}
+
+// readKeys reads the keys of a PEM file or of the PEM files of a directory.
+func readKeys(path string) ([]*Key, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing keys: %v", err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.pem"))
+		if err != nil {
+			return nil, fmt.Errorf("listing signing keys of %q: %v", path, err)
+		}
+	}
+	var keys []*Key
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading signing keys: %v", err)
+		}
+		ks, err := ParseKeys(b)
+		if err != nil {
+			return nil, fmt.Errorf("signing keys of %q: %v", f, err)
+		}
+		keys = append(keys, ks...)
+	}
+	return keys, nil
+}
+
+// ParseKeys parses the private keys of PEM data and their headers.
+func ParseKeys(data []byte) ([]*Key, error) {
+	var keys []*Key
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+		k, err := parseKey(block)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM private key found")
+	}
+	return keys, nil
+}
+
+func parseKey(block *pem.Block) (*Key, error) {
+	var pri interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		pri, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		pri, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		pri, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %v", err)
+	}
+	signer, ok := pri.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("private key %T cannot sign", pri)
+	}
+	if _, err := kms.SigningAlgorithm(signer.Public()); err != nil {
+		return nil, err
+	}
+
+	k := &Key{ID: block.Headers[HeaderKeyID], Private: signer}
+	if k.ID == "" {
+		tp, err := (&jose.JSONWebKey{Key: signer.Public()}).Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("key thumbprint: %v", err)
+		}
+		k.ID = base64.RawURLEncoding.EncodeToString(tp)
+	}
+	if k.NotBefore, err = parseTime(block.Headers[HeaderNotBefore]); err != nil {
+		return nil, fmt.Errorf("key %q: invalid %s: %v", k.ID, HeaderNotBefore, err)
+	}
+	if k.NotAfter, err = parseTime(block.Headers[HeaderNotAfter]); err != nil {
+		return nil, fmt.Errorf("key %q: invalid %s: %v", k.ID, HeaderNotAfter, err)
+	}
+	return k, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
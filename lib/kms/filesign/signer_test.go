@@ -0,0 +1,252 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2/jwt" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+)
+
+func writeKey(t *testing.T, dir, name string, key testkeys.Key, headers map[string]string) {
+	t.Helper()
+	b := pem.EncodeToMemory(&pem.Block{
+		Type:    "RSA PRIVATE KEY",
+		Headers: headers,
+		Bytes:   x509.MarshalPKCS1PrivateKey(key.Private),
+	})
+	if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0600); err != nil {
+		t.Fatalf("WriteFile(%q) failed: %v", name, err)
+	}
+}
+
+func signedKeyID(t *testing.T, c *Client) string {
+	t.Helper()
+	tok, err := c.SignJWT(context.Background(), jwt.Claims{Subject: "sub"}, nil)
+	if err != nil {
+		t.Fatalf("SignJWT() failed: %v", err)
+	}
+	parsed, err := jwt.ParseSigned(tok)
+	if err != nil {
+		t.Fatalf("jwt.ParseSigned() failed: %v", err)
+	}
+	kid := parsed.Headers[0].KeyID
+	keys := c.PublicKeys().Key(kid)
+	if len(keys) != 1 {
+		t.Fatalf("PublicKeys().Key(%q) returned %d keys, want 1", kid, len(keys))
+	}
+	claims := jwt.Claims{}
+	if err := parsed.Claims(keys[0].Key, &claims); err != nil {
+		t.Fatalf("verifying token with published key failed: %v", err)
+	}
+	return kid
+}
+
+func publishedKeyIDs(c *Client) []string {
+	var ids []string
+	for _, k := range c.PublicKeys().Keys {
+		ids = append(ids, k.KeyID)
+	}
+	return ids
+}
+
+func TestRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesign")
+	if err != nil {
+		t.Fatalf("TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	rotate := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	writeKey(t, dir, "old.pem", testkeys.Keys[testkeys.VisaIssuer0], map[string]string{HeaderKeyID: "old"})
+	writeKey(t, dir, "new.pem", testkeys.Keys[testkeys.VisaIssuer1], map[string]string{HeaderKeyID: "new", HeaderNotBefore: rotate.Format(time.RFC3339)})
+	// Files without the .pem extension are ignored.
+	if err := ioutil.WriteFile(filepath.Join(dir, "README"), []byte("keys"), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+
+	c, err := New(dir)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	now := rotate.Add(-time.Hour)
+	c.now = func() time.Time { return now }
+
+	if got := signedKeyID(t, c); got != "old" {
+		t.Errorf("signing key before rotation = %q, want old", got)
+	}
+	if diff := cmp.Diff([]string{"old", "new"}, publishedKeyIDs(c)); diff != "" {
+		t.Errorf("published keys before rotation (-want +got):\n%s", diff)
+	}
+
+	now = rotate
+	if got := signedKeyID(t, c); got != "new" {
+		t.Errorf("signing key after rotation = %q, want new", got)
+	}
+	if diff := cmp.Diff([]string{"new", "old"}, publishedKeyIDs(c)); diff != "" {
+		t.Errorf("published keys after rotation (-want +got):\n%s", diff)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "old.pem")); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"new"}, publishedKeyIDs(c)); diff != "" {
+		t.Errorf("published keys after removal (-want +got):\n%s", diff)
+	}
+
+	// Keys are kept if the reload fails.
+	writeKey(t, dir, "dup.pem", testkeys.Keys[testkeys.VisaIssuer0], map[string]string{HeaderKeyID: "new"})
+	if err := c.Reload(); err == nil {
+		t.Errorf("Reload() with duplicate key ID succeeded, want error")
+	}
+	if diff := cmp.Diff([]string{"new"}, publishedKeyIDs(c)); diff != "" {
+		t.Errorf("published keys after failed reload (-want +got):\n%s", diff)
+	}
+}
+
+func TestNotAfter(t *testing.T) {
+	end := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewFromKeys([]*Key{{ID: "k", Private: testkeys.Default.Private, NotAfter: end}})
+	if err != nil {
+		t.Fatalf("NewFromKeys() failed: %v", err)
+	}
+	c.now = func() time.Time { return end }
+	if _, err := c.SignJWT(context.Background(), jwt.Claims{}, nil); err == nil {
+		t.Errorf("SignJWT() after Not-After succeeded, want error")
+	}
+	if diff := cmp.Diff([]string{"k"}, publishedKeyIDs(c)); diff != "" {
+		t.Errorf("published keys (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	b := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(testkeys.Default.Private)})
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(testkeys.Default.Private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() failed: %v", err)
+	}
+	b = append(b, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Headers: map[string]string{HeaderKeyID: "pkcs8"}, Bytes: pkcs8})...)
+
+	keys, err := ParseKeys(b)
+	if err != nil {
+		t.Fatalf("ParseKeys() failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("ParseKeys() returned %d keys, want 2", len(keys))
+	}
+	// The default key ID is the JWK thumbprint.
+	if keys[0].ID == "" || keys[0].ID == keys[1].ID || keys[1].ID != "pkcs8" {
+		t.Errorf("key IDs = %q, %q, want thumbprint and pkcs8", keys[0].ID, keys[1].ID)
+	}
+
+	invalid := []string{
+		"",
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("x")})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("x")})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Headers: map[string]string{HeaderNotBefore: "yesterday"}, Bytes: x509.MarshalPKCS1PrivateKey(testkeys.Default.Private)})),
+	}
+	for _, in := range invalid {
+		if _, err := ParseKeys([]byte(in)); err == nil {
+			t.Errorf("ParseKeys(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+	}
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+	}
+	_, ed, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() failed: %v", err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(p256)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		block *pem.Block
+		alg   jose.SignatureAlgorithm
+	}{
+		{name: "RSA", block: &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(testkeys.Default.Private)}, alg: jose.RS256},
+		{name: "P-256", block: &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}, alg: jose.ES256},
+		{name: "P-384", block: pkcs8Block(t, p384), alg: jose.ES384},
+		{name: "Ed25519", block: pkcs8Block(t, ed), alg: jose.EdDSA},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := ParseKeys(pem.EncodeToMemory(tc.block))
+			if err != nil {
+				t.Fatalf("ParseKeys() failed: %v", err)
+			}
+			c, err := NewFromKeys(keys)
+			if err != nil {
+				t.Fatalf("NewFromKeys() failed: %v", err)
+			}
+			if got := c.PublicKeys().Keys[0].Algorithm; got != string(tc.alg) {
+				t.Errorf("published algorithm = %q, want %q", got, tc.alg)
+			}
+			tok, err := c.SignJWT(context.Background(), jwt.Claims{Subject: "sub"}, nil)
+			if err != nil {
+				t.Fatalf("SignJWT() failed: %v", err)
+			}
+			parsed, err := jwt.ParseSigned(tok)
+			if err != nil {
+				t.Fatalf("jwt.ParseSigned() failed: %v", err)
+			}
+			if got := parsed.Headers[0].Algorithm; got != string(tc.alg) {
+				t.Errorf("token algorithm = %q, want %q", got, tc.alg)
+			}
+			claims := jwt.Claims{}
+			if err := parsed.Claims(c.PublicKeys().Keys[0].Key, &claims); err != nil {
+				t.Errorf("verifying token with published key failed: %v", err)
+			}
+		})
+	}
+}
+
+func pkcs8Block(t *testing.T, key crypto.Signer) *pem.Block {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() failed: %v", err)
+	}
+	return &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+}
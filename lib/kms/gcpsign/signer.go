@@ -12,18 +12,23 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package gcpsign contains a client of GCP Cloud KMS RSA256 asymmetric signning.
+// Package gcpsign contains a client of GCP Cloud KMS RS256, ES256 and ES384
+// asymmetric signning.
 package gcpsign
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/asn1"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"math/big"
 	"sort"
 
 	"github.com/cenkalti/backoff" /* copybara-comment */
@@ -42,17 +47,38 @@ const (
 	maxPendingKeyRetries = 5
 )
 
+var (
+	// kmsAlgorithms are the KMS key algorithms of the supported signing algorithms.
+	kmsAlgorithms = map[jose.SignatureAlgorithm]rpb.CryptoKeyVersion_CryptoKeyVersionAlgorithm{
+		jose.RS256: rpb.CryptoKeyVersion_RSA_SIGN_PKCS1_2048_SHA256,
+		jose.ES256: rpb.CryptoKeyVersion_EC_SIGN_P256_SHA256,
+		jose.ES384: rpb.CryptoKeyVersion_EC_SIGN_P384_SHA384,
+	}
+)
+
 // Client of GCP CloudKMS asymmetric signning service.
-// We use CloudKMS to sign JWT and epxose RSA public keys in KMS for verify.
+// We use CloudKMS to sign JWT and epxose RSA or EC public keys in KMS for verify.
 type Client struct {
+	alg            jose.SignatureAlgorithm
 	cryptoKeyID    string
 	currentVersion string
 	client         *kms.KeyManagementClient
 	publicKeys     *jose.JSONWebKeySet
 }
 
-// New returns Client.
+// New returns Client signing with RS256.
 func New(ctx context.Context, projectID, keyRingLocation, keyRingName, keyName string, client *kms.KeyManagementClient) (*Client, error) {
+	return NewWithAlgorithm(ctx, projectID, keyRingLocation, keyRingName, keyName, jose.RS256, client)
+}
+
+// NewWithAlgorithm returns Client signing with the given algorithm, one of
+// RS256, ES256 or ES384. The key is created with the matching KMS algorithm.
+func NewWithAlgorithm(ctx context.Context, projectID, keyRingLocation, keyRingName, keyName string, alg jose.SignatureAlgorithm, client *kms.KeyManagementClient) (*Client, error) {
+	kmsAlg, ok := kmsAlgorithms[alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
 	// Try create key ring.
 	createRingReq := &kmspb.CreateKeyRingRequest{
 		Parent:    locationName(projectID, keyRingLocation),
@@ -69,7 +95,7 @@ func New(ctx context.Context, projectID, keyRingLocation, keyRingName, keyName s
 		CryptoKey: &rpb.CryptoKey{
 			Purpose: rpb.CryptoKey_ASYMMETRIC_SIGN,
 			VersionTemplate: &rpb.CryptoKeyVersionTemplate{
-				Algorithm: rpb.CryptoKeyVersion_RSA_SIGN_PKCS1_2048_SHA256,
+				Algorithm: kmsAlg,
 			},
 		},
 	}
@@ -85,11 +111,11 @@ func New(ctx context.Context, projectID, keyRingLocation, keyRingName, keyName s
 
 	// Ensure key is use for symmetric encryption and use correct algorithm.
 	if key.Purpose != rpb.CryptoKey_ASYMMETRIC_SIGN ||
-		key.VersionTemplate.Algorithm != rpb.CryptoKeyVersion_RSA_SIGN_PKCS1_2048_SHA256 {
+		key.VersionTemplate.Algorithm != kmsAlg {
 		return nil, fmt.Errorf("key %q has incorrect purpose %q or algorithm %q", cryptoKeyName(projectID, keyRingLocation, keyRingName, keyName), key.Purpose.String(), key.VersionTemplate.Algorithm.String())
 	}
 
-	c := &Client{cryptoKeyID: cryptoKeyName(projectID, keyRingLocation, keyRingName, keyName), client: client}
+	c := &Client{alg: alg, cryptoKeyID: cryptoKeyName(projectID, keyRingLocation, keyRingName, keyName), client: client}
 	if err := c.updateKeys(ctx); err != nil {
 		return nil, err
 	}
@@ -136,15 +162,23 @@ func (s *Client) updateKeys(ctx context.Context) error {
 			return fmt.Errorf("ParsePKIXPublicKey() failed: %v", err)
 		}
 
-		publicKey, ok := parsed.(*rsa.PublicKey)
-		if !ok {
-			return fmt.Errorf("Public key is not rsa public key")
+		switch parsed.(type) {
+		case *rsa.PublicKey:
+			if s.alg != jose.RS256 {
+				return fmt.Errorf("public key is a rsa public key, want %s", s.alg)
+			}
+		case *ecdsa.PublicKey:
+			if s.alg != jose.ES256 && s.alg != jose.ES384 {
+				return fmt.Errorf("public key is an ecdsa public key, want %s", s.alg)
+			}
+		default:
+			return fmt.Errorf("unsupported public key type %T", parsed)
 		}
 
 		s.publicKeys.Keys = append(s.publicKeys.Keys, jose.JSONWebKey{
 			KeyID:     id,
-			Key:       publicKey,
-			Algorithm: "RS256",
+			Key:       parsed,
+			Algorithm: string(s.alg),
 			Use:       "sig",
 		})
 	}
@@ -203,7 +237,7 @@ func (s *Client) SignJWT(ctx context.Context, claims interface{}, header map[str
 	}
 
 	key := jose.SigningKey{
-		Algorithm: jose.RS256,
+		Algorithm: s.alg,
 		Key:       sig,
 	}
 
@@ -249,31 +283,63 @@ func (sig *signer) Public() *jose.JSONWebKey {
 
 // Algs returns a list of supported signing algorithms.
 func (sig *signer) Algs() []jose.SignatureAlgorithm {
-	return []jose.SignatureAlgorithm{jose.RS256}
+	return []jose.SignatureAlgorithm{sig.c.alg}
 }
 
 // SignPayload signs a payload with the current signing key using the given
 // algorithm.
 func (sig *signer) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
-	if alg != jose.RS256 {
-		return nil, fmt.Errorf("only support RS256")
+	if alg != sig.c.alg {
+		return nil, fmt.Errorf("only support %s", sig.c.alg)
 	}
 
-	h := sha256.New()
-	h.Write(payload)
-	b := h.Sum(nil)
+	digest := &kmspb.Digest{}
+	if alg == jose.ES384 {
+		h := crypto.SHA384.New()
+		h.Write(payload)
+		digest.Digest = &kmspb.Digest_Sha384{Sha384: h.Sum(nil)}
+	} else {
+		h := sha256.New()
+		h.Write(payload)
+		digest.Digest = &kmspb.Digest_Sha256{Sha256: h.Sum(nil)}
+	}
 
 	res, err := sig.c.client.AsymmetricSign(sig.ctx, &kmspb.AsymmetricSignRequest{
 		Name:   sig.c.currentVersion,
-		Digest: &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: b}},
+		Digest: digest,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	switch alg {
+	case jose.ES256:
+		return ecdsaJWSSignature(res.Signature, 32)
+	case jose.ES384:
+		return ecdsaJWSSignature(res.Signature, 48)
+	}
 	return res.Signature, nil
 }
 
+// ecdsaJWSSignature converts an ASN.1 DER ECDSA signature of KMS to the fixed
+// size R || S of JWS. See https://tools.ietf.org/html/rfc7518#section-3.4
+func ecdsaJWSSignature(der []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("parsing ecdsa signature failed: %v", err)
+	}
+	r, s := sig.R.Bytes(), sig.S.Bytes()
+	if len(r) > size || len(s) > size {
+		return nil, fmt.Errorf("invalid ecdsa signature size")
+	}
+	out := make([]byte, 2*size)
+	copy(out[size-len(r):size], r)
+	copy(out[2*size-len(s):], s)
+	return out, nil
+}
+
 func locationName(proj, loc string) string {
 	return "projects/" + proj + "/locations/" + loc
 }
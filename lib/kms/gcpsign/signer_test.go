@@ -16,7 +16,13 @@ package gcpsign
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
 	"encoding/base64"
+	"encoding/pem"
 	"math/big"
 	"net"
 	"strings"
@@ -214,6 +220,70 @@ func TestClient_SignJWT(t *testing.T) {
 	}
 }
 
+func TestClient_SignJWT_ES(t *testing.T) {
+	tests := []struct {
+		alg    jose.SignatureAlgorithm
+		curve  elliptic.Curve
+		kmsAlg rpb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+	}{
+		{alg: jose.ES256, curve: elliptic.P256(), kmsAlg: rpb.CryptoKeyVersion_EC_SIGN_P256_SHA256},
+		{alg: jose.ES384, curve: elliptic.P384(), kmsAlg: rpb.CryptoKeyVersion_EC_SIGN_P384_SHA384},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.alg), func(t *testing.T) {
+			ctx := context.Background()
+			client, stub := setup(t)
+
+			key, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			if err != nil {
+				t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+			}
+			der, err := x509.MarshalPKIXPublicKey(key.Public())
+			if err != nil {
+				t.Fatalf("MarshalPKIXPublicKey() failed: %v", err)
+			}
+			stub.getKeyResp.VersionTemplate.Algorithm = tc.kmsAlg
+			stub.getPubKeyResp = &rpb.PublicKey{Pem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}
+			stub.signKey = key
+
+			s, err := NewWithAlgorithm(ctx, project, location, keyring, keyname, tc.alg, client)
+			if err != nil {
+				t.Fatalf("NewWithAlgorithm() failed: %v", err)
+			}
+			if got := stub.createKeyReq.CryptoKey.VersionTemplate.Algorithm; got != tc.kmsAlg {
+				t.Errorf("created key algorithm = %v, want %v", got, tc.kmsAlg)
+			}
+			if got := s.PublicKeys().Keys[0].Algorithm; got != string(tc.alg) {
+				t.Errorf("public key algorithm = %q, want %q", got, tc.alg)
+			}
+
+			rawTok, err := s.SignJWT(ctx, jwt.Claims{Subject: "sub"}, nil)
+			if err != nil {
+				t.Fatalf("SignJWT() failed: %v", err)
+			}
+			tok, err := jwt.ParseSigned(rawTok)
+			if err != nil {
+				t.Fatalf("jwt.ParseSigned() failed: %v", err)
+			}
+			if got := tok.Headers[0].Algorithm; got != string(tc.alg) {
+				t.Errorf("token algorithm = %q, want %q", got, tc.alg)
+			}
+			claims := jwt.Claims{}
+			if err := tok.Claims(s.PublicKeys().Keys[0].Key, &claims); err != nil {
+				t.Errorf("verifying token with public key failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewWithAlgorithm_Unsupported(t *testing.T) {
+	client, _ := setup(t)
+
+	if _, err := NewWithAlgorithm(context.Background(), project, location, keyring, keyname, jose.HS256, client); err == nil {
+		t.Errorf("NewWithAlgorithm(HS256) succeeded, want error")
+	}
+}
+
 // stubKMS is a mock KMS server for testing.
 type stubKMS struct {
 	kmsgrpc.KeyManagementServiceServer
@@ -241,6 +311,8 @@ type stubKMS struct {
 	signReq  *kmspb.AsymmetricSignRequest
 	signResp *kmspb.AsymmetricSignResponse
 	signErr  error
+	// signKey if set signs the digest instead of returning signResp.
+	signKey *ecdsa.PrivateKey
 }
 
 func (s *stubKMS) CreateKeyRing(ctx context.Context, req *kmspb.CreateKeyRingRequest) (*rpb.KeyRing, error) {
@@ -265,5 +337,20 @@ func (s *stubKMS) GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyReque
 }
 func (s *stubKMS) AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest) (*kmspb.AsymmetricSignResponse, error) {
 	s.signReq = proto.Clone(req).(*kmspb.AsymmetricSignRequest)
+	if s.signKey != nil {
+		digest := req.Digest.GetSha256()
+		if digest == nil {
+			digest = req.Digest.GetSha384()
+		}
+		r, ss, err := ecdsa.Sign(rand.Reader, s.signKey, digest)
+		if err != nil {
+			return nil, err
+		}
+		der, err := asn1.Marshal(struct{ R, S *big.Int }{r, ss})
+		if err != nil {
+			return nil, err
+		}
+		return &kmspb.AsymmetricSignResponse{Signature: der}, nil
+	}
 	return proto.Clone(s.signResp).(*kmspb.AsymmetricSignResponse), s.signErr
 }
@@ -17,6 +17,12 @@ package kms
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
 
 	"gopkg.in/square/go-jose.v2" /* copybara-comment */
 )
@@ -32,3 +38,26 @@ type Signer interface {
 	PublicKeys() *jose.JSONWebKeySet
 	SignJWT(ctx context.Context, claims interface{}, header map[string]string) (string, error)
 }
+
+// SigningAlgorithm returns the JWT signing algorithm for a public key: RS256
+// for RSA, ES256, ES384 or ES512 for ECDSA on P-256, P-384 or P-521, and EdDSA
+// for Ed25519 keys.
+func SigningAlgorithm(pub crypto.PublicKey) (jose.SignatureAlgorithm, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return jose.RS256, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jose.ES256, nil
+		case elliptic.P384():
+			return jose.ES384, nil
+		case elliptic.P521():
+			return jose.ES512, nil
+		}
+		return "", fmt.Errorf("unsupported ECDSA curve %q", k.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return jose.EdDSA, nil
+	}
+	return "", fmt.Errorf("unsupported signing key type %T", pub)
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localcrypt contains a local AES-GCM envelope encryption for
+// deployments without a cloud KMS.
+//
+// Data is encrypted with a random data encryption key (DEK) which is itself
+// encrypted with a key encryption key (KEK) of a keyring. The keyring is JSON:
+//
+//   {
+//     "primary": 2,
+//     "keys": [
+//       {"version": 1, "key": "<base64 AES key>"},
+//       {"version": 2, "key": "<base64 AES key>"}
+//     ]
+//   }
+//
+// Keys are 16, 24 or 32 bytes, e.g. from "openssl rand -base64 32". New data
+// is encrypted with the primary key, the highest version if not set. The key
+// version is part of the ciphertext, so data encrypted with older keys of the
+// keyring can still be decrypted after a rotation. To rotate, add a new key to
+// the keyring of all replicas, then make it the primary key and rewrap the
+// stored data. Old keys can be removed once no data uses them anymore.
+package localcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	// formatVersion is the first byte of the ciphertext.
+	formatVersion = 1
+	// headerLen is the length of the format version, key version and wrapped
+	// DEK length of the ciphertext.
+	headerLen = 1 + 4 + 2
+	// dekLen is the length of the AES-256 data encryption keys.
+	dekLen = 32
+)
+
+// Keyring is the JSON representation of the key encryption keys.
+type Keyring struct {
+	// Primary is the version of the key used to encrypt. Defaults to the
+	// highest version.
+	Primary uint32 `json:"primary,omitempty"`
+	Keys    []*Key `json:"keys"`
+}
+
+// Key is a version of a key encryption key.
+type Key struct {
+	Version uint32 `json:"version"`
+	// Key is the base64 standard encoding of the AES key.
+	Key string `json:"key"`
+}
+
+// Client encrypts data with the keys of a keyring.
+type Client struct {
+	primary uint32
+	keks    map[uint32]cipher.AEAD
+}
+
+// New returns a Client for a JSON keyring.
+func New(keyring []byte) (*Client, error) {
+	ring := &Keyring{}
+	if err := json.Unmarshal(keyring, ring); err != nil {
+		return nil, fmt.Errorf("invalid keyring: %v", err)
+	}
+	return NewFromKeyring(ring)
+}
+
+// NewFromFile returns a Client for the JSON keyring of a file.
+func NewFromFile(path string) (*Client, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading keyring %q: %v", path, err)
+	}
+	return New(b)
+}
+
+// NewFromKeyring returns a Client for a keyring.
+func NewFromKeyring(ring *Keyring) (*Client, error) {
+	if len(ring.Keys) == 0 {
+		return nil, fmt.Errorf("keyring has no keys")
+	}
+	c := &Client{keks: map[uint32]cipher.AEAD{}}
+	for _, k := range ring.Keys {
+		if k.Version == 0 {
+			return nil, fmt.Errorf("keyring key version must be positive")
+		}
+		if _, ok := c.keks[k.Version]; ok {
+			return nil, fmt.Errorf("keyring has duplicate key version %d", k.Version)
+		}
+		key, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("keyring key version %d: invalid base64: %v", k.Version, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("keyring key version %d: %v", k.Version, err)
+		}
+		c.keks[k.Version] = aead
+		if ring.Primary == 0 && k.Version > c.primary {
+			c.primary = k.Version
+		}
+	}
+	if ring.Primary != 0 {
+		if _, ok := c.keks[ring.Primary]; !ok {
+			return nil, fmt.Errorf("keyring primary key version %d not found", ring.Primary)
+		}
+		c.primary = ring.Primary
+	}
+	return c, nil
+}
+
+// PrimaryVersion returns the version of the key used to encrypt.
+func (c *Client) PrimaryVersion() uint32 {
+	return c.primary
+}
+
+// Encrypt data with a new data encryption key wrapped by the primary key.
+func (c *Client) Encrypt(ctx context.Context, data []byte, additionalAuthData string) ([]byte, error) {
+	dek := make([]byte, dekLen)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("generating data key: %v", err)
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.wrap(dek)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, out, data, []byte(additionalAuthData))
+}
+
+// Decrypt data encrypted with any key of the keyring.
+func (c *Client) Decrypt(ctx context.Context, encrypted []byte, additionalAuthData string) ([]byte, error) {
+	dek, body, err := c.unwrap(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, body, []byte(additionalAuthData))
+}
+
+// KeyVersion returns the version of the key the data is encrypted with.
+func (c *Client) KeyVersion(encrypted []byte) (uint32, error) {
+	if len(encrypted) < headerLen || encrypted[0] != formatVersion {
+		return 0, fmt.Errorf("invalid ciphertext")
+	}
+	return binary.BigEndian.Uint32(encrypted[1:5]), nil
+}
+
+// Rewrap re-encrypts the data encryption key of encrypted data with the
+// primary key. The data itself, and so its additional authenticated data, is
+// unchanged. Data already encrypted with the primary key is returned as is.
+func (c *Client) Rewrap(ctx context.Context, encrypted []byte) ([]byte, error) {
+	v, err := c.KeyVersion(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if v == c.primary {
+		return encrypted, nil
+	}
+	dek, body, err := c.unwrap(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.wrap(dek)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, body...), nil
+}
+
+// wrap returns the header and wrapped data encryption key of a ciphertext.
+func (c *Client) wrap(dek []byte) ([]byte, error) {
+	header := make([]byte, headerLen)
+	header[0] = formatVersion
+	binary.BigEndian.PutUint32(header[1:5], c.primary)
+	kek := c.keks[c.primary]
+	binary.BigEndian.PutUint16(header[5:7], uint16(kek.NonceSize()+dekLen+kek.Overhead()))
+	// The format and key version are authenticated with the wrapped data key,
+	// so they cannot be changed.
+	return seal(kek, header, dek, header[:5])
+}
+
+// unwrap returns the data encryption key and the encrypted data of a ciphertext.
+func (c *Client) unwrap(encrypted []byte) ([]byte, []byte, error) {
+	v, err := c.KeyVersion(encrypted)
+	if err != nil {
+		return nil, nil, err
+	}
+	kek, ok := c.keks[v]
+	if !ok {
+		return nil, nil, fmt.Errorf("key version %d not in keyring", v)
+	}
+	n := int(binary.BigEndian.Uint16(encrypted[5:7]))
+	if len(encrypted) < headerLen+n {
+		return nil, nil, fmt.Errorf("invalid ciphertext")
+	}
+	dek, err := open(kek, encrypted[headerLen:headerLen+n], encrypted[:5])
+	if err != nil {
+		return nil, nil, fmt.Errorf("unwrapping data key with key version %d: %v", v, err)
+	}
+	return dek, encrypted[headerLen+n:], nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal appends a random nonce and the sealed plaintext to dst.
+func seal(aead cipher.AEAD, dst, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %v", err)
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, aad), nil
+}
+
+// open opens a nonce followed by sealed data.
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	n := aead.NonceSize()
+	plain, err := aead.Open(nil, sealed[:n], sealed[n:], aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting: %v", err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+var (
+	key1 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	key2 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 16))
+)
+
+func newClient(t *testing.T, ring *Keyring) *Client {
+	t.Helper()
+	c, err := NewFromKeyring(ring)
+	if err != nil {
+		t.Fatalf("NewFromKeyring() failed: %v", err)
+	}
+	return c
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	c, err := New([]byte(`{"keys":[{"version":1,"key":"` + key1 + `"}]}`))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	data := []byte("some data")
+	encrypted, err := c.Encrypt(ctx, data, "aad")
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if bytes.Contains(encrypted, data) {
+		t.Errorf("Encrypt() = %q, contains the plaintext", encrypted)
+	}
+	got, err := c.Decrypt(ctx, encrypted, "aad")
+	if err != nil {
+		t.Fatalf("Decrypt() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Decrypt() = %q, want %q", got, data)
+	}
+
+	if _, err := c.Decrypt(ctx, encrypted, "other"); err == nil {
+		t.Errorf("Decrypt() with other additional auth data succeeded, want error")
+	}
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := c.Decrypt(ctx, tampered, "aad"); err == nil {
+		t.Errorf("Decrypt() of tampered data succeeded, want error")
+	}
+	if _, err := c.Decrypt(ctx, []byte("short"), "aad"); err == nil {
+		t.Errorf("Decrypt() of invalid data succeeded, want error")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	old := newClient(t, &Keyring{Keys: []*Key{{Version: 1, Key: key1}}})
+	encrypted, err := old.Encrypt(ctx, []byte("data"), "aad")
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+
+	// A new key is added and becomes primary as the highest version.
+	rotated := newClient(t, &Keyring{Keys: []*Key{{Version: 1, Key: key1}, {Version: 2, Key: key2}}})
+	if got := rotated.PrimaryVersion(); got != 2 {
+		t.Errorf("PrimaryVersion() = %d, want 2", got)
+	}
+	if got, err := rotated.Decrypt(ctx, encrypted, "aad"); err != nil || string(got) != "data" {
+		t.Errorf("Decrypt() of data of the old key = %q, %v, want %q", got, err, "data")
+	}
+
+	rewrapped, err := rotated.Rewrap(ctx, encrypted)
+	if err != nil {
+		t.Fatalf("Rewrap() failed: %v", err)
+	}
+	if v, err := rotated.KeyVersion(rewrapped); err != nil || v != 2 {
+		t.Errorf("KeyVersion() of rewrapped data = %d, %v, want 2", v, err)
+	}
+	again, err := rotated.Rewrap(ctx, rewrapped)
+	if err != nil {
+		t.Fatalf("Rewrap() failed: %v", err)
+	}
+	if !bytes.Equal(again, rewrapped) {
+		t.Errorf("Rewrap() of data of the primary key changed the data")
+	}
+
+	// Once the old key is removed only the rewrapped data can be decrypted.
+	latest := newClient(t, &Keyring{Keys: []*Key{{Version: 2, Key: key2}}})
+	if got, err := latest.Decrypt(ctx, rewrapped, "aad"); err != nil || string(got) != "data" {
+		t.Errorf("Decrypt() of rewrapped data = %q, %v, want %q", got, err, "data")
+	}
+	if _, err := latest.Decrypt(ctx, encrypted, "aad"); err == nil {
+		t.Errorf("Decrypt() of data of a removed key succeeded, want error")
+	}
+}
+
+func TestPrimary(t *testing.T) {
+	ctx := context.Background()
+	// A new key can be distributed before it is used.
+	c := newClient(t, &Keyring{Primary: 1, Keys: []*Key{{Version: 1, Key: key1}, {Version: 2, Key: key2}}})
+	encrypted, err := c.Encrypt(ctx, []byte("data"), "")
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if v, err := c.KeyVersion(encrypted); err != nil || v != 1 {
+		t.Errorf("KeyVersion() = %d, %v, want 1", v, err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		ring string
+	}{
+		{name: "invalid json", ring: `{`},
+		{name: "no keys", ring: `{"keys":[]}`},
+		{name: "version zero", ring: `{"keys":[{"version":0,"key":"` + key1 + `"}]}`},
+		{name: "duplicate version", ring: `{"keys":[{"version":1,"key":"` + key1 + `"},{"version":1,"key":"` + key2 + `"}]}`},
+		{name: "invalid base64", ring: `{"keys":[{"version":1,"key":"!"}]}`},
+		{name: "invalid key size", ring: `{"keys":[{"version":1,"key":"AAAA"}]}`},
+		{name: "unknown primary", ring: `{"primary":3,"keys":[{"version":1,"key":"` + key1 + `"}]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New([]byte(tc.ring)); err == nil {
+				t.Errorf("New(%s) succeeded, want error", tc.ring)
+			}
+		})
+	}
+}
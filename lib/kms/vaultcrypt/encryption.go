@@ -0,0 +1,101 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vaultcrypt contains a client of the HashiCorp Vault Transit secrets
+// engine for encryption.
+// See: https://www.vaultproject.io/api-docs/secret/transit
+package vaultcrypt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/vault" /* copybara-comment: vault */
+)
+
+// Client of Vault Transit encryption with a key of type aes256-gcm96.
+// Ciphertexts carry the key version, so data encrypted before a rotation of
+// the key can still be decrypted.
+type Client struct {
+	client *vault.Client
+	mount  string
+	key    string
+}
+
+// New returns a Client encrypting with the named key of the Transit engine
+// mounted at the given path, such as "transit". The key is created if it does
+// not exist.
+func New(ctx context.Context, client *vault.Client, mount, key string) (*Client, error) {
+	c := &Client{client: client, mount: mount, key: key}
+
+	info := &struct {
+		Type string `json:"type"`
+	}{}
+	err := client.Read(ctx, c.path("keys"), info)
+	if vault.IsNotFound(err) {
+		if err := client.Write(ctx, c.path("keys"), map[string]string{"type": "aes256-gcm96"}, nil); err != nil {
+			return nil, fmt.Errorf("creating vault transit key %q failed: %v", key, err)
+		}
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading vault transit key %q failed: %v", key, err)
+	}
+	if info.Type != "aes256-gcm96" {
+		return nil, fmt.Errorf("vault transit key %q has type %q, want aes256-gcm96", key, info.Type)
+	}
+	return c, nil
+}
+
+func (c *Client) path(op string) string {
+	return c.mount + "/" + op + "/" + c.key
+}
+
+type transitResponse struct {
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Encrypt data with the latest version of the key.
+func (c *Client) Encrypt(ctx context.Context, data []byte, additionalAuthData string) ([]byte, error) {
+	req := map[string]string{
+		"plaintext":       base64.StdEncoding.EncodeToString(data),
+		"associated_data": base64.StdEncoding.EncodeToString([]byte(additionalAuthData)),
+	}
+	resp := &transitResponse{}
+	if err := c.client.Write(ctx, c.path("encrypt"), req, resp); err != nil {
+		return nil, fmt.Errorf("vault transit encrypt failed: %v", err)
+	}
+	// The ciphertext is of the form "vault:v<version>:<base64>".
+	return []byte(resp.Ciphertext), nil
+}
+
+// Decrypt data encrypted with any version of the key which is not below the
+// minimum decryption version of the key.
+func (c *Client) Decrypt(ctx context.Context, encrypted []byte, additionalAuthData string) ([]byte, error) {
+	req := map[string]string{
+		"ciphertext":      string(encrypted),
+		"associated_data": base64.StdEncoding.EncodeToString([]byte(additionalAuthData)),
+	}
+	resp := &transitResponse{}
+	if err := c.client.Write(ctx, c.path("decrypt"), req, resp); err != nil {
+		return nil, fmt.Errorf("vault transit decrypt failed: %v", err)
+	}
+	b, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("decoding vault transit plaintext: %v", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultcrypt
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakevault" /* copybara-comment: fakevault */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/httptestclient" /* copybara-comment: httptestclient */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/vault" /* copybara-comment: vault */
+)
+
+func setup(t *testing.T) (*fakevault.Server, *vault.Client) {
+	t.Helper()
+	f := fakevault.New("token")
+	c, err := vault.New(&vault.Config{
+		Address:    "http://vault.example.com",
+		Token:      "token",
+		HTTPClient: httptestclient.New(f),
+	})
+	if err != nil {
+		t.Fatalf("vault.New() failed: %v", err)
+	}
+	return f, c
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	f, vc := setup(t)
+
+	// The key is created on first use.
+	c, err := New(ctx, vc, fakevault.TransitMount, "dam")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	data := []byte("This is a message.")
+	encrypted, err := c.Encrypt(ctx, data, "aad")
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if !strings.HasPrefix(string(encrypted), "vault:v1:") {
+		t.Errorf("Encrypt() = %s, wants key version 1", encrypted)
+	}
+
+	if err := f.RotateKey("dam"); err != nil {
+		t.Fatalf("RotateKey() failed: %v", err)
+	}
+	rotated, err := c.Encrypt(ctx, data, "aad")
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if !strings.HasPrefix(string(rotated), "vault:v2:") {
+		t.Errorf("Encrypt() after rotation = %s, wants key version 2", rotated)
+	}
+
+	for _, e := range [][]byte{encrypted, rotated} {
+		got, err := c.Decrypt(ctx, e, "aad")
+		if err != nil {
+			t.Fatalf("Decrypt() failed: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("Decrypt() = %s, want %s", got, data)
+		}
+	}
+
+	if _, err := c.Decrypt(ctx, encrypted, "other"); err == nil {
+		t.Errorf("Decrypt() with other additional authenticated data wants error")
+	}
+
+	if err := f.SetMinDecryptionVersion("dam", 2); err != nil {
+		t.Fatalf("SetMinDecryptionVersion() failed: %v", err)
+	}
+	if _, err := c.Decrypt(ctx, encrypted, "aad"); err == nil {
+		t.Errorf("Decrypt() with disabled key version wants error")
+	}
+}
+
+func TestNew_WrongKeyType(t *testing.T) {
+	ctx := context.Background()
+	f, vc := setup(t)
+	if err := f.CreateKey("signing", "ecdsa-p256"); err != nil {
+		t.Fatalf("CreateKey() failed: %v", err)
+	}
+
+	if _, err := New(ctx, vc, fakevault.TransitMount, "signing"); err == nil {
+		t.Errorf("New() with signing key wants error")
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vaultsign contains a jwt signer with keys of the HashiCorp Vault
+// Transit secrets engine.
+// See: https://www.vaultproject.io/api-docs/secret/transit
+package vaultsign
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/vault" /* copybara-comment: vault */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+var (
+	// hashAlgorithms are the Vault hash algorithms of the signing algorithms.
+	hashAlgorithms = map[jose.SignatureAlgorithm]string{
+		jose.RS256: "sha2-256",
+		jose.ES256: "sha2-256",
+		jose.ES384: "sha2-384",
+		jose.ES512: "sha2-512",
+		// Ed25519 signs the input itself.
+		jose.EdDSA: "",
+	}
+)
+
+// Client signs jwt with an asymmetric key of Vault Transit. Every enabled
+// version of the key is published in PublicKeys, and tokens are signed with
+// the latest version known at the last refresh.
+type Client struct {
+	client *vault.Client
+	mount  string
+	key    string
+
+	mu      sync.RWMutex
+	alg     jose.SignatureAlgorithm
+	version int
+	keys    *jose.JSONWebKeySet
+}
+
+// New returns a Client signing with the named key of the Transit engine
+// mounted at the given path, such as "transit". The key must exist and be of
+// an RSA, ECDSA or ed25519 type.
+func New(ctx context.Context, client *vault.Client, mount, key string) (*Client, error) {
+	c := &Client{client: client, mount: mount, key: key}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type keyInfo struct {
+	Type                 string `json:"type"`
+	LatestVersion        int    `json:"latest_version"`
+	MinDecryptionVersion int    `json:"min_decryption_version"`
+	Keys                 map[string]struct {
+		PublicKey string `json:"public_key"`
+	} `json:"keys"`
+}
+
+// Refresh reads the key versions of Vault, e.g. after a rotation of the key.
+func (c *Client) Refresh(ctx context.Context) error {
+	info := &keyInfo{}
+	if err := c.client.Read(ctx, c.mount+"/keys/"+c.key, info); err != nil {
+		return fmt.Errorf("reading vault transit key %q failed: %v", c.key, err)
+	}
+
+	var versions []int
+	for v := range info.Keys {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("vault transit key %q has invalid version %q", c.key, v)
+		}
+		if version >= info.MinDecryptionVersion {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("vault transit key %q has no enabled version", c.key)
+	}
+	// The latest version first.
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	keys := &jose.JSONWebKeySet{}
+	var alg jose.SignatureAlgorithm
+	for _, version := range versions {
+		pub, err := parsePublicKey(info.Type, info.Keys[strconv.Itoa(version)].PublicKey)
+		if err != nil {
+			return fmt.Errorf("vault transit key %q version %d: %v", c.key, version, err)
+		}
+		alg, err = kms.SigningAlgorithm(pub)
+		if err != nil {
+			return fmt.Errorf("vault transit key %q version %d: %v", c.key, version, err)
+		}
+		keys.Keys = append(keys.Keys, jose.JSONWebKey{
+			KeyID:     c.keyID(version),
+			Key:       pub,
+			Algorithm: string(alg),
+			Use:       "sig",
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alg = alg
+	c.version = versions[0]
+	c.keys = keys
+	return nil
+}
+
+// Run refreshes the key versions at every interval until the context is
+// done. Typically this will be on its own go routine.
+func (c *Client) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				glog.Errorf("refreshing vault signing keys failed: %v", err)
+			}
+		}
+	}
+}
+
+// keyID use sha256 hash the key name and version to protect the key name.
+func (c *Client) keyID(version int) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", c.mount, c.key, version)))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// PublicKeys of the enabled key versions.
+func (c *Client) PublicKeys() *jose.JSONWebKeySet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.keys
+}
+
+// SignJWT signs the given claims return the jwt string.
+func (c *Client) SignJWT(ctx context.Context, claims interface{}, header map[string]string) (string, error) {
+	c.mu.RLock()
+	sig := &signer{c: c, ctx: ctx, alg: c.alg, version: c.version, pub: c.keys.Keys[0]}
+	c.mu.RUnlock()
+
+	key := jose.SigningKey{
+		Algorithm: sig.alg,
+		Key:       sig,
+	}
+
+	opt := &jose.SignerOptions{}
+	opt.WithType("JWT")
+
+	if header == nil {
+		header = map[string]string{}
+	}
+	header["kid"] = sig.pub.KeyID
+	for k, v := range header {
+		opt.WithHeader(jose.HeaderKey(k), v)
+	}
+
+	signer, err := jose.NewSigner(key, opt)
+	if err != nil {
+		return "", fmt.Errorf("failed to create signer: %v", err)
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := signer.Sign(b)
+	if err != nil {
+		return "", err
+	}
+
+	return res.CompactSerialize()
+}
+
+// signer implements jose.OpaqueSigner interface for a key version.
+type signer struct {
+	c       *Client
+	ctx     context.Context
+	alg     jose.SignatureAlgorithm
+	version int
+	pub     jose.JSONWebKey
+}
+
+// Public returns the public key of the signing key version.
+func (sig *signer) Public() *jose.JSONWebKey {
+	return &sig.pub
+}
+
+// Algs returns a list of supported signing algorithms.
+func (sig *signer) Algs() []jose.SignatureAlgorithm {
+	return []jose.SignatureAlgorithm{sig.alg}
+}
+
+// SignPayload signs a payload with the key version in Vault.
+func (sig *signer) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	if alg != sig.alg {
+		return nil, fmt.Errorf("only support %s", sig.alg)
+	}
+	path := sig.c.mount + "/sign/" + sig.c.key
+	if h := hashAlgorithms[alg]; h != "" {
+		path += "/" + h
+	}
+	req := map[string]interface{}{
+		"input":       base64.StdEncoding.EncodeToString(payload),
+		"key_version": sig.version,
+		// JWS marshaling returns ECDSA signatures as R || S instead of ASN.1.
+		"marshaling_algorithm": "jws",
+	}
+	if alg == jose.RS256 {
+		req["signature_algorithm"] = "pkcs1v15"
+	}
+	resp := &struct {
+		Signature string `json:"signature"`
+	}{}
+	if err := sig.c.client.Write(sig.ctx, path, req, resp); err != nil {
+		return nil, fmt.Errorf("vault transit sign failed: %v", err)
+	}
+
+	// The signature is of the form "vault:v<version>:<base64url>".
+	parts := strings.SplitN(resp.Signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("invalid vault signature format")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding vault signature: %v", err)
+	}
+	return b, nil
+}
+
+// parsePublicKey parses the public key of a key version of Vault Transit.
+func parsePublicKey(keyType, key string) (interface{}, error) {
+	if keyType == "ed25519" {
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("decoding ed25519 public key: %v", err)
+		}
+		if len(b) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key size %d", len(b))
+		}
+		return ed25519.PublicKey(b), nil
+	}
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, fmt.Errorf("key type %q has no PEM public key", keyType)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("ParsePKIXPublicKey() failed: %v", err)
+	}
+	return pub, nil
+}
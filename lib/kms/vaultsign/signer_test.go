@@ -0,0 +1,154 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultsign
+
+import (
+	"context"
+	"testing"
+
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2/jwt" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakevault" /* copybara-comment: fakevault */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/httptestclient" /* copybara-comment: httptestclient */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/vault" /* copybara-comment: vault */
+)
+
+func setup(t *testing.T, typ string) (*fakevault.Server, *Client) {
+	t.Helper()
+	f := fakevault.New("token")
+	if err := f.CreateKey("visa", typ); err != nil {
+		t.Fatalf("CreateKey() failed: %v", err)
+	}
+	vc, err := vault.New(&vault.Config{
+		Address:    "http://vault.example.com",
+		Token:      "token",
+		HTTPClient: httptestclient.New(f),
+	})
+	if err != nil {
+		t.Fatalf("vault.New() failed: %v", err)
+	}
+	c, err := New(context.Background(), vc, fakevault.TransitMount, "visa")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return f, c
+}
+
+// verify verifies the token with the published key of its kid and returns
+// the kid.
+func verify(t *testing.T, c *Client, tok string) string {
+	t.Helper()
+	parsed, err := jwt.ParseSigned(tok)
+	if err != nil {
+		t.Fatalf("jwt.ParseSigned() failed: %v", err)
+	}
+	kid := parsed.Headers[0].KeyID
+	keys := c.PublicKeys().Key(kid)
+	if len(keys) != 1 {
+		t.Fatalf("PublicKeys() has %d keys of kid %q, want 1", len(keys), kid)
+	}
+	if got, want := parsed.Headers[0].Algorithm, keys[0].Algorithm; got != want {
+		t.Errorf("alg = %s, want %s", got, want)
+	}
+	claims := jwt.Claims{}
+	if err := parsed.Claims(keys[0].Key, &claims); err != nil {
+		t.Fatalf("verifying token with published key failed: %v", err)
+	}
+	if claims.Subject != "sub" {
+		t.Errorf("sub = %q, want sub", claims.Subject)
+	}
+	return kid
+}
+
+func TestAlgorithms(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want jose.SignatureAlgorithm
+	}{
+		{typ: "rsa-2048", want: jose.RS256},
+		{typ: "ecdsa-p256", want: jose.ES256},
+		{typ: "ecdsa-p384", want: jose.ES384},
+		{typ: "ed25519", want: jose.EdDSA},
+	}
+	for _, tc := range tests {
+		t.Run(tc.typ, func(t *testing.T) {
+			_, c := setup(t, tc.typ)
+			tok, err := c.SignJWT(context.Background(), jwt.Claims{Subject: "sub"}, map[string]string{"jku": "https://example.com/jwks"})
+			if err != nil {
+				t.Fatalf("SignJWT() failed: %v", err)
+			}
+			verify(t, c, tok)
+			if got := c.PublicKeys().Keys[0].Algorithm; got != string(tc.want) {
+				t.Errorf("Algorithm = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	f, c := setup(t, "ecdsa-p256")
+
+	tok, err := c.SignJWT(ctx, jwt.Claims{Subject: "sub"}, nil)
+	if err != nil {
+		t.Fatalf("SignJWT() failed: %v", err)
+	}
+	v1 := verify(t, c, tok)
+
+	if err := f.RotateKey("visa"); err != nil {
+		t.Fatalf("RotateKey() failed: %v", err)
+	}
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+	if n := len(c.PublicKeys().Keys); n != 2 {
+		t.Fatalf("PublicKeys() has %d keys, want 2", n)
+	}
+	tok, err = c.SignJWT(ctx, jwt.Claims{Subject: "sub"}, nil)
+	if err != nil {
+		t.Fatalf("SignJWT() failed: %v", err)
+	}
+	v2 := verify(t, c, tok)
+	if v1 == v2 {
+		t.Errorf("kid after rotation = %q, wants new kid", v2)
+	}
+	if got := c.PublicKeys().Keys[0].KeyID; got != v2 {
+		t.Errorf("first published kid = %q, want the signing kid %q", got, v2)
+	}
+
+	if err := f.SetMinDecryptionVersion("visa", 2); err != nil {
+		t.Fatalf("SetMinDecryptionVersion() failed: %v", err)
+	}
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+	if keys := c.PublicKeys().Key(v1); len(keys) != 0 {
+		t.Errorf("PublicKeys() includes disabled key version")
+	}
+}
+
+func TestNew_EncryptionKey(t *testing.T) {
+	f := fakevault.New("token")
+	if err := f.CreateKey("dam", "aes256-gcm96"); err != nil {
+		t.Fatalf("CreateKey() failed: %v", err)
+	}
+	vc, err := vault.New(&vault.Config{Address: "http://vault.example.com", Token: "token", HTTPClient: httptestclient.New(f)})
+	if err != nil {
+		t.Fatalf("vault.New() failed: %v", err)
+	}
+	if _, err := New(context.Background(), vc, fakevault.TransitMount, "dam"); err == nil {
+		t.Errorf("New() with encryption key wants error")
+	}
+}
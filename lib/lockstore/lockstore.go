@@ -0,0 +1,315 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lockstore provides a storage.Store decorator taking the locks of
+// LockTx from a storage.Locker, such as redis, so that the instances of a
+// service on a store without locks across instances do not run background
+// work concurrently.
+package lockstore
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	wpb "github.com/golang/protobuf/ptypes/wrappers" /* copybara-comment */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+const (
+	// DefaultTTL is the default time a lock is held at most if the transaction
+	// holding it does not finish, eg. if the instance crashes.
+	DefaultTTL = time.Minute
+)
+
+// Store is a storage.Store taking the locks of LockTx from a Locker before
+// the lock of the underlying store. The locks are released when the
+// transaction holding them finishes.
+type Store struct {
+	ctx    context.Context
+	store  storage.Store
+	locker storage.Locker
+	ttl    time.Duration
+}
+
+// New creates a Store locking with locker. Locks are held at most ttl, which
+// must be longer than the transactions holding them.
+func New(ctx context.Context, store storage.Store, locker storage.Locker, ttl time.Duration) *Store {
+	return &Store{
+		ctx:    ctx,
+		store:  store,
+		locker: locker,
+		ttl:    ttl,
+	}
+}
+
+// Tx is a transaction of the underlying store holding the leases of the locks
+// taken with it from the Locker. The leases are released when the transaction
+// finishes, whether it was created by Tx or LockTx.
+type Tx struct {
+	storage.Tx
+	ctx    context.Context
+	store  storage.Store
+	leases map[string]storage.Lease
+	// released is set once the leases were released, their fencing tokens
+	// remain available with FencingToken.
+	released bool
+}
+
+// Finish finishes the transaction and releases its locks.
+func (tx *Tx) Finish() error {
+	err := tx.Tx.Finish()
+	if tx.released {
+		return err
+	}
+	tx.released = true
+	for name, lease := range tx.leases {
+		if rerr := lease.Release(tx.ctx); rerr != nil {
+			// The lock may have been taken over while the transaction was running.
+			glog.Warningf("releasing lock %q with fencing token %d failed: %v", name, lease.Token(), rerr)
+		}
+	}
+	return err
+}
+
+// fence checks the locks held by the transaction were not taken over, i.e.
+// no fencing token stored for the locks is newer than the ones of its leases.
+// Tokens increase with each acquisition, so the stored token is the one the
+// transaction wrote on stores showing a transaction its own writes, or the one
+// of a previous holder on stores which do not, such as Datastore. A takeover
+// committed meanwhile conflicts with the fencing token written by the
+// transaction on the latter.
+func (tx *Tx) fence() error {
+	for name, lease := range tx.leases {
+		stored := &wpb.Int64Value{}
+		if err := tx.store.ReadTx(storage.LockFenceDatatype, storage.DefaultRealm, storage.DefaultUser, name, storage.LatestRev, stored, tx.Tx); err != nil {
+			if storage.ErrNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if stored.Value > lease.Token() {
+			return status.Errorf(codes.FailedPrecondition, "lock %q with fencing token %d was taken over by fencing token %d", name, lease.Token(), stored.Value)
+		}
+	}
+	return nil
+}
+
+// FencingToken returns the fencing token of the named lock held by a
+// transaction of the Store. Returns false if the transaction does not hold
+// the lock.
+func FencingToken(tx storage.Tx, lockName string) (int64, bool) {
+	t, ok := tx.(*Tx)
+	if !ok {
+		return 0, false
+	}
+	lease, ok := t.leases[lockName]
+	if !ok {
+		return 0, false
+	}
+	return lease.Token(), true
+}
+
+// CheckFencingToken returns a FailedPrecondition error if a lock held by the
+// transaction was taken over by another holder, after its lease expired.
+// Writes of the Store inside the transaction are checked, work outside the
+// store under the locks should be checked before it is done.
+func CheckFencingToken(tx storage.Tx) error {
+	t, ok := tx.(*Tx)
+	if !ok {
+		return nil
+	}
+	return t.fence()
+}
+
+// unwrap returns the transaction of the underlying store.
+func unwrap(tx storage.Tx) storage.Tx {
+	if t, ok := tx.(*Tx); ok {
+		return t.Tx
+	}
+	return tx
+}
+
+// Info returns the info of the underlying store.
+func (s *Store) Info() map[string]string {
+	return s.store.Info()
+}
+
+// Exists checks if data item with given key exists.
+func (s *Store) Exists(datatype, realm, user, id string, rev int64) (bool, error) {
+	return s.store.Exists(datatype, realm, user, id, rev)
+}
+
+// Read reads a data item of a given key.
+func (s *Store) Read(datatype, realm, user, id string, rev int64, content proto.Message) error {
+	return s.store.Read(datatype, realm, user, id, rev, content)
+}
+
+// ReadTx reads a data item of a given key inside a transaction.
+func (s *Store) ReadTx(datatype, realm, user, id string, rev int64, content proto.Message, tx storage.Tx) error {
+	return s.store.ReadTx(datatype, realm, user, id, rev, content, unwrap(tx))
+}
+
+// MultiReadTx reads a set of objects matching the input parameters and filters.
+func (s *Store) MultiReadTx(datatype, realm, user, id string, filters [][]storage.Filter, offset, pageSize int, typ proto.Message, tx storage.Tx) (*storage.Results, error) {
+	return s.store.MultiReadTx(datatype, realm, user, id, filters, offset, pageSize, typ, unwrap(tx))
+}
+
+// ReadHistory reads the history.
+func (s *Store) ReadHistory(datatype, realm, user, id string, content *[]proto.Message) error {
+	return s.store.ReadHistory(datatype, realm, user, id, content)
+}
+
+// ReadHistoryTx reads the history inside a transaction.
+func (s *Store) ReadHistoryTx(datatype, realm, user, id string, content *[]proto.Message, tx storage.Tx) error {
+	return s.store.ReadHistoryTx(datatype, realm, user, id, content, unwrap(tx))
+}
+
+// Write writes an item.
+func (s *Store) Write(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message) error {
+	return s.store.Write(datatype, realm, user, id, rev, content, history)
+}
+
+// WriteTx writes an item inside a transaction.
+func (s *Store) WriteTx(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message, tx storage.Tx) error {
+	if err := CheckFencingToken(tx); err != nil {
+		return err
+	}
+	return s.store.WriteTx(datatype, realm, user, id, rev, content, history, unwrap(tx))
+}
+
+// WriteIfMatchTx writes an item inside a transaction if the etag of its
+// latest revision matches.
+func (s *Store) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx storage.Tx) error {
+	if err := CheckFencingToken(tx); err != nil {
+		return err
+	}
+	return s.store.WriteIfMatchTx(datatype, realm, user, id, rev, etag, content, history, unwrap(tx))
+}
+
+// Delete deletes a record.
+func (s *Store) Delete(datatype, realm, user, id string, rev int64) error {
+	return s.store.Delete(datatype, realm, user, id, rev)
+}
+
+// DeleteTx deletes a record inside a transaction.
+func (s *Store) DeleteTx(datatype, realm, user, id string, rev int64, tx storage.Tx) error {
+	if err := CheckFencingToken(tx); err != nil {
+		return err
+	}
+	return s.store.DeleteTx(datatype, realm, user, id, rev, unwrap(tx))
+}
+
+// MultiDeleteTx deletes all records of a certain data type within a realm.
+func (s *Store) MultiDeleteTx(datatype, realm, user string, tx storage.Tx) error {
+	if err := CheckFencingToken(tx); err != nil {
+		return err
+	}
+	return s.store.MultiDeleteTx(datatype, realm, user, unwrap(tx))
+}
+
+// MultiDeleteHistoryTx deletes the history and old revisions of all records of a certain data type within a realm.
+func (s *Store) MultiDeleteHistoryTx(datatype, realm, user string, tx storage.Tx) error {
+	if err := CheckFencingToken(tx); err != nil {
+		return err
+	}
+	return s.store.MultiDeleteHistoryTx(datatype, realm, user, unwrap(tx))
+}
+
+// Wipe deletes all data within a realm.
+func (s *Store) Wipe(ctx context.Context, realm string, batchNum, maxEntries int) (int, error) {
+	return s.store.Wipe(ctx, realm, batchNum, maxEntries)
+}
+
+// Tx creates a new transaction.
+func (s *Store) Tx(update bool) (storage.Tx, error) {
+	tx, err := s.store.Tx(update)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, ctx: s.ctx, store: s.store}, nil
+}
+
+// LockTx returns a storage-wide lock by the given name, held by the instance
+// across the instances sharing the Locker. Returns nil if the lock is held or
+// was taken less than minFrequency ago. If tx is provided, it must be a
+// transaction of the Store: the lock is added to it, and released when it
+// finishes.
+//
+// The fencing token of the lock is written inside the transaction, and the
+// writes of the Store inside it fail once another holder took the lock over.
+func (s *Store) LockTx(lockName string, minFrequency time.Duration, tx storage.Tx) storage.Tx {
+	var caller *Tx
+	if tx != nil {
+		t, ok := tx.(*Tx)
+		if !ok {
+			glog.Errorf("acquiring lock %q failed: transaction %T is not a transaction of the store", lockName, tx)
+			return nil
+		}
+		if _, ok := t.leases[lockName]; ok {
+			// The transaction already holds the lock.
+			return t
+		}
+		caller = t
+	}
+
+	lease, err := s.locker.Lock(s.ctx, lockName, s.ttl, minFrequency)
+	if err != nil {
+		glog.Errorf("acquiring lock %q failed: %v", lockName, err)
+		return nil
+	}
+	if lease == nil {
+		return nil
+	}
+	release := func() {
+		if err := lease.Release(s.ctx); err != nil {
+			glog.Warningf("releasing lock %q failed: %v", lockName, err)
+		}
+	}
+
+	// minFrequency is checked by the Locker across instances.
+	locked := s.store.LockTx(lockName, 0, unwrap(tx))
+	if locked == nil {
+		release()
+		return nil
+	}
+	if err := s.store.WriteTx(storage.LockFenceDatatype, storage.DefaultRealm, storage.DefaultUser, lockName, storage.LatestRev, &wpb.Int64Value{Value: lease.Token()}, nil, locked); err != nil {
+		glog.Errorf("writing fencing token of lock %q failed: %v", lockName, err)
+		if caller == nil {
+			locked.Rollback()
+			locked.Finish()
+		}
+		release()
+		return nil
+	}
+
+	if caller == nil {
+		caller = &Tx{Tx: locked, ctx: s.ctx, store: s.store}
+	}
+	if caller.leases == nil {
+		caller.leases = make(map[string]storage.Lease)
+	}
+	caller.leases[lockName] = lease
+	return caller
+}
+
+// Watch returns a channel receiving the changes of items of the underlying store.
+func (s *Store) Watch(ctx context.Context, datatype, realm string) (<-chan *storage.Change, error) {
+	return s.store.Watch(ctx, datatype, realm)
+}
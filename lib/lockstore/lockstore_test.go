@@ -0,0 +1,293 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lockstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/alicebob/miniredis" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/cache/rediz" /* copybara-comment: rediz */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/storagetest" /* copybara-comment: storagetest */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakestore" /* copybara-comment: fakestore */
+
+	wpb "github.com/golang/protobuf/ptypes/wrappers" /* copybara-comment */
+)
+
+func newLocker(t *testing.T) (*miniredis.Miniredis, storage.Locker) {
+	t.Helper()
+
+	r, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis.Run() failed: %v", err)
+	}
+	p := rediz.NewPool(r.Addr())
+	t.Cleanup(func() {
+		p.Close()
+		r.Close()
+	})
+	return r, rediz.NewLocker(p, "test:")
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Store {
+		_, l := newLocker(t)
+		return New(context.Background(), fakestore.New(), l, DefaultTTL)
+	}, nil)
+}
+
+func TestStore_LockTx_AcrossInstances(t *testing.T) {
+	_, l := newLocker(t)
+	// The instances have separate stores without locks across them.
+	a := New(context.Background(), fakestore.New(), l, DefaultTTL)
+	b := New(context.Background(), fakestore.New(), l, DefaultTTL)
+
+	tx := a.LockTx("gc", 0, nil)
+	if tx == nil {
+		t.Fatalf("LockTx() = nil, want a transaction")
+	}
+	if other := b.LockTx("gc", 0, nil); other != nil {
+		other.Finish()
+		t.Fatalf("LockTx() of other instance while held = %v, want nil", other)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("Finish() failed: %v", err)
+	}
+
+	other := b.LockTx("gc", 0, nil)
+	if other == nil {
+		t.Fatalf("LockTx() of other instance after Finish() = nil, want a transaction")
+	}
+	defer other.Finish()
+
+	first, ok := FencingToken(tx, "gc")
+	if !ok {
+		t.Fatalf("FencingToken() of finished transaction = _, false, want true")
+	}
+	next, ok := FencingToken(other, "gc")
+	if !ok {
+		t.Fatalf("FencingToken() = _, false, want true")
+	}
+	if next <= first {
+		t.Errorf("fencing token %d after %d, want increasing tokens", next, first)
+	}
+}
+
+func TestStore_LockTx_MinFrequency(t *testing.T) {
+	r, l := newLocker(t)
+	a := New(context.Background(), fakestore.New(), l, DefaultTTL)
+	b := New(context.Background(), fakestore.New(), l, DefaultTTL)
+
+	tx := a.LockTx("gc", time.Hour, nil)
+	if tx == nil {
+		t.Fatalf("LockTx() = nil, want a transaction")
+	}
+	tx.Finish()
+
+	// The work was done by another instance less than minFrequency ago.
+	if other := b.LockTx("gc", time.Hour, nil); other != nil {
+		other.Finish()
+		t.Fatalf("LockTx() within minFrequency = %v, want nil", other)
+	}
+	r.FastForward(time.Hour)
+	other := b.LockTx("gc", time.Hour, nil)
+	if other == nil {
+		t.Fatalf("LockTx() after minFrequency = nil, want a transaction")
+	}
+	other.Finish()
+}
+
+func TestFencingToken_OtherTx(t *testing.T) {
+	s := fakestore.New()
+	tx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("Tx() failed: %v", err)
+	}
+	defer tx.Finish()
+
+	if _, ok := FencingToken(tx, "gc"); ok {
+		t.Errorf("FencingToken() of other transaction = _, true, want false")
+	}
+}
+
+func TestStore_LockTx_CallerTx(t *testing.T) {
+	_, l := newLocker(t)
+	a := New(context.Background(), fakestore.New(), l, DefaultTTL)
+	b := New(context.Background(), fakestore.New(), l, DefaultTTL)
+
+	tx, err := a.Tx(true)
+	if err != nil {
+		t.Fatalf("Tx() failed: %v", err)
+	}
+	if ltx := a.LockTx("hydra", 0, tx); ltx != tx {
+		t.Fatalf("LockTx() with tx = %v, want the tx", ltx)
+	}
+	if ltx := a.LockTx("hydra", 0, tx); ltx != tx {
+		t.Fatalf("LockTx() with tx already holding the lock = %v, want the tx", ltx)
+	}
+	if other := b.LockTx("hydra", 0, nil); other != nil {
+		other.Finish()
+		t.Fatalf("LockTx() of other instance while held = %v, want nil", other)
+	}
+
+	// Finishing the transaction of the caller releases the lock.
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("Finish() failed: %v", err)
+	}
+	other := b.LockTx("hydra", 0, nil)
+	if other == nil {
+		t.Fatalf("LockTx() of other instance after Finish() = nil, want a transaction")
+	}
+	other.Finish()
+}
+
+func TestStore_LockTx_OtherStoreTx(t *testing.T) {
+	_, l := newLocker(t)
+	fs := fakestore.New()
+	s := New(context.Background(), fs, l, DefaultTTL)
+
+	tx, err := fs.Tx(true)
+	if err != nil {
+		t.Fatalf("Tx() failed: %v", err)
+	}
+	defer tx.Finish()
+	if ltx := s.LockTx("hydra", 0, tx); ltx != nil {
+		t.Errorf("LockTx() with transaction of the underlying store = %v, want nil", ltx)
+	}
+}
+
+func TestStore_LockTx_Fencing(t *testing.T) {
+	_, l := newLocker(t)
+	fs := fakestore.New()
+	s := New(context.Background(), fs, l, DefaultTTL)
+
+	tx := s.LockTx("process", 0, nil)
+	if tx == nil {
+		t.Fatalf("LockTx() = nil, want a transaction")
+	}
+	defer tx.Finish()
+	token, ok := FencingToken(tx, "process")
+	if !ok {
+		t.Fatalf("FencingToken() = _, false, want true")
+	}
+	if err := CheckFencingToken(tx); err != nil {
+		t.Fatalf("CheckFencingToken() failed: %v", err)
+	}
+	state := &wpb.StringValue{Value: "state"}
+	if err := s.WriteTx(storage.ProcessDataType, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, state, nil, tx); err != nil {
+		t.Fatalf("WriteTx() of the holder failed: %v", err)
+	}
+
+	// Another instance took the lock over after the lease expired, and its
+	// fencing token is visible to the transaction.
+	if err := fs.WriteTx(storage.LockFenceDatatype, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, &wpb.Int64Value{Value: token + 1}, nil, unwrap(tx)); err != nil {
+		t.Fatalf("WriteTx() of fencing token failed: %v", err)
+	}
+	if err := CheckFencingToken(tx); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CheckFencingToken() after take over = %v, want FailedPrecondition", err)
+	}
+	if err := s.WriteTx(storage.ProcessDataType, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, state, nil, tx); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("WriteTx() after take over = %v, want FailedPrecondition", err)
+	}
+	if err := s.DeleteTx(storage.ProcessDataType, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, tx); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("DeleteTx() after take over = %v, want FailedPrecondition", err)
+	}
+}
+
+// committedReads is a store whose transactions do not see their own writes,
+// like Datastore.
+type committedReads struct {
+	*fakestore.Store
+}
+
+func (s committedReads) ReadTx(datatype, realm, user, id string, rev int64, content proto.Message, tx storage.Tx) error {
+	return s.Store.Read(datatype, realm, user, id, rev, content)
+}
+
+func TestStore_LockTx_CommittedReads(t *testing.T) {
+	_, l := newLocker(t)
+	fs := fakestore.New()
+	s := New(context.Background(), committedReads{fs}, l, DefaultTTL)
+
+	state := &wpb.StringValue{Value: "state"}
+	for i := 0; i < 2; i++ {
+		tx := s.LockTx("process", 0, nil)
+		if tx == nil {
+			t.Fatalf("LockTx() #%d = nil, want a transaction", i)
+		}
+		if err := s.WriteTx(storage.ProcessDataType, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, state, nil, tx); err != nil {
+			t.Errorf("WriteTx() of the holder #%d failed: %v", i, err)
+		}
+		if err := tx.Finish(); err != nil {
+			t.Fatalf("Finish() #%d failed: %v", i, err)
+		}
+	}
+
+	tx := s.LockTx("process", 0, nil)
+	if tx == nil {
+		t.Fatalf("LockTx() = nil, want a transaction")
+	}
+	defer tx.Finish()
+	token, _ := FencingToken(tx, "process")
+	// Another instance took the lock over and committed its fencing token.
+	if err := fs.Write(storage.LockFenceDatatype, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, &wpb.Int64Value{Value: token + 1}, nil); err != nil {
+		t.Fatalf("Write() of fencing token failed: %v", err)
+	}
+	if err := s.WriteTx(storage.ProcessDataType, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, state, nil, tx); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("WriteTx() after take over = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestStore_LockTx_ExpiredLease(t *testing.T) {
+	r, l := newLocker(t)
+	fs := fakestore.New()
+	a := New(context.Background(), fs, l, DefaultTTL)
+	b := New(context.Background(), fs, l, DefaultTTL)
+
+	stale := a.LockTx("process", 0, nil)
+	if stale == nil {
+		t.Fatalf("LockTx() = nil, want a transaction")
+	}
+	r.FastForward(DefaultTTL + time.Second)
+
+	other := b.LockTx("process", 0, nil)
+	if other == nil {
+		t.Fatalf("LockTx() after the lease expired = nil, want a transaction")
+	}
+	if err := other.Finish(); err != nil {
+		t.Fatalf("Finish() failed: %v", err)
+	}
+	token, _ := FencingToken(other, "process")
+
+	// The work of the stale holder does not commit.
+	state := &wpb.StringValue{Value: "stale"}
+	werr := a.WriteTx(storage.ProcessDataType, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, state, nil, stale)
+	ferr := stale.Finish()
+	if werr == nil && ferr == nil {
+		t.Errorf("WriteTx() and Finish() of the stale holder succeeded, want error")
+	}
+	stored := &wpb.Int64Value{}
+	if err := fs.Read(storage.LockFenceDatatype, storage.DefaultRealm, storage.DefaultUser, "process", storage.LatestRev, stored); err != nil {
+		t.Fatalf("Read() of fencing token failed: %v", err)
+	}
+	if stored.Value != token {
+		t.Errorf("stored fencing token = %d, want %d of the new holder", stored.Value, token)
+	}
+}
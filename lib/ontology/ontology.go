@@ -0,0 +1,158 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ontology provides term hierarchies of ontologies, such as the GA4GH
+// Data Use Ontology (DUO), to evaluate the subsumption of terms.
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/srcutil" /* copybara-comment: srcutil */
+)
+
+const (
+	// DUOPath is the path of the bundled term hierarchy of the Data Use
+	// Ontology, see https://github.com/EBISPOT/DUO.
+	DUOPath = "deploy/metadata/duo.json"
+)
+
+var (
+	duoOnce sync.Once
+	duo     *Ontology
+	duoErr  error
+)
+
+// Term is a term of an ontology, the fields follow the term stanzas of OBO.
+type Term struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name,omitempty"`
+	IsA  []string `json:"is_a,omitempty"`
+}
+
+// file is the JSON format of a term hierarchy.
+type file struct {
+	Ontology string  `json:"ontology"`
+	Terms    []*Term `json:"terms"`
+}
+
+// Ontology is a term hierarchy.
+type Ontology struct {
+	Name  string
+	terms map[string]*Term
+}
+
+// New creates an Ontology from its terms. The parents of terms must be terms
+// of the ontology, and the hierarchy must not have cycles.
+func New(name string, terms []*Term) (*Ontology, error) {
+	o := &Ontology{Name: name, terms: make(map[string]*Term)}
+	for _, t := range terms {
+		if len(t.ID) == 0 {
+			return nil, fmt.Errorf("ontology %q: term with empty id", name)
+		}
+		if _, ok := o.terms[t.ID]; ok {
+			return nil, fmt.Errorf("ontology %q: duplicate term %q", name, t.ID)
+		}
+		o.terms[t.ID] = t
+	}
+	for _, t := range terms {
+		for _, p := range t.IsA {
+			if _, ok := o.terms[p]; !ok {
+				return nil, fmt.Errorf("ontology %q: term %q is_a undefined term %q", name, t.ID, p)
+			}
+		}
+	}
+	visited := make(map[string]bool)
+	for _, t := range terms {
+		if err := o.checkCycle(t.ID, make(map[string]bool), visited); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *Ontology) checkCycle(id string, path, visited map[string]bool) error {
+	if path[id] {
+		return fmt.Errorf("ontology %q: term %q is_a cycle", o.Name, id)
+	}
+	if visited[id] {
+		return nil
+	}
+	path[id] = true
+	for _, p := range o.terms[id].IsA {
+		if err := o.checkCycle(p, path, visited); err != nil {
+			return err
+		}
+	}
+	delete(path, id)
+	visited[id] = true
+	return nil
+}
+
+// Load reads an Ontology from a JSON file, given its path relative to the root
+// of the module.
+func Load(path string) (*Ontology, error) {
+	b, err := srcutil.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading ontology %q failed: %v", path, err)
+	}
+	f := &file{}
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("parsing ontology %q failed: %v", path, err)
+	}
+	return New(f.Ontology, f.Terms)
+}
+
+// DUO returns the bundled Data Use Ontology, loaded on first use.
+func DUO() (*Ontology, error) {
+	duoOnce.Do(func() {
+		duo, duoErr = Load(DUOPath)
+	})
+	return duo, duoErr
+}
+
+// Term returns the term with the given id.
+func (o *Ontology) Term(id string) (*Term, bool) {
+	t, ok := o.terms[id]
+	return t, ok
+}
+
+// Subsumes returns true if the term is the ancestor term, or a narrower term
+// of it. Unknown terms are not subsumed by any term.
+func (o *Ontology) Subsumes(ancestor, id string) bool {
+	if _, ok := o.terms[ancestor]; !ok {
+		return false
+	}
+	visited := make(map[string]bool)
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == ancestor {
+			return true
+		}
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		t, ok := o.terms[cur]
+		if !ok {
+			continue
+		}
+		queue = append(queue, t.IsA...)
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ontology
+
+import (
+	"testing"
+)
+
+func TestDUO_Subsumes(t *testing.T) {
+	duo, err := DUO()
+	if err != nil {
+		t.Fatalf("DUO() failed: %v", err)
+	}
+
+	const (
+		gru = "DUO:0000042"
+		hmb = "DUO:0000006"
+		ds  = "DUO:0000007"
+		poa = "DUO:0000011"
+	)
+	tests := []struct {
+		name     string
+		ancestor string
+		term     string
+		want     bool
+	}{
+		{name: "same term", ancestor: hmb, term: hmb, want: true},
+		{name: "narrower term", ancestor: hmb, term: ds, want: true},
+		{name: "transitively narrower term", ancestor: gru, term: ds, want: true},
+		{name: "broader term", ancestor: ds, term: gru, want: false},
+		{name: "sibling term", ancestor: hmb, term: poa, want: false},
+		{name: "unknown term", ancestor: hmb, term: "DUO:9999999", want: false},
+		{name: "unknown ancestor", ancestor: "DUO:9999999", term: "DUO:9999999", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := duo.Subsumes(tc.ancestor, tc.term); got != tc.want {
+				t.Errorf("Subsumes(%q, %q) = %v, want %v", tc.ancestor, tc.term, got, tc.want)
+			}
+		})
+	}
+
+	if term, ok := duo.Term(ds); !ok || term.Name != "disease specific research" {
+		t.Errorf("Term(%q) = %+v, %v, want disease specific research", ds, term, ok)
+	}
+}
+
+func TestNew_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []*Term
+	}{
+		{
+			name:  "empty id",
+			terms: []*Term{{Name: "a"}},
+		},
+		{
+			name:  "duplicate term",
+			terms: []*Term{{ID: "A"}, {ID: "A"}},
+		},
+		{
+			name:  "undefined parent",
+			terms: []*Term{{ID: "A", IsA: []string{"B"}}},
+		},
+		{
+			name:  "cycle",
+			terms: []*Term{{ID: "A", IsA: []string{"C"}}, {ID: "B", IsA: []string{"A"}}, {ID: "C", IsA: []string{"B"}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New("test", tc.terms); err == nil {
+				t.Errorf("New(%+v) succeeded, want error", tc.terms)
+			}
+		})
+	}
+}
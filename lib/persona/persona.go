@@ -319,10 +319,11 @@ func populatePersonaVisas(ctx context.Context, pname, visaIssuer string, asserti
 				clauses := []ga4gh.Condition{}
 				for _, clause := range cond.AllOf {
 					c := ga4gh.Condition{
-						Type:   ga4gh.Type(clause.Type),
-						Value:  ga4gh.Pattern(clause.Value),
-						Source: ga4gh.Pattern(clause.Source),
-						By:     ga4gh.Pattern(clause.By),
+						Type:     ga4gh.Type(clause.Type),
+						Value:    ga4gh.Pattern(clause.Value),
+						Source:   ga4gh.Pattern(clause.Source),
+						By:       ga4gh.Pattern(clause.By),
+						DUOTerms: clause.DuoTerms,
 					}
 					clauses = append(clauses, c)
 				}
@@ -350,6 +351,7 @@ func populatePersonaVisas(ctx context.Context, pname, visaIssuer string, asserti
 			cValue := []string{}
 			cSource := []string{}
 			cBy := []string{}
+			cTerms := []string{}
 			for _, cond := range assert.AnyOfConditions {
 				for _, clause := range cond.AllOf {
 					cType = clause.Type
@@ -374,6 +376,7 @@ func populatePersonaVisas(ctx context.Context, pname, visaIssuer string, asserti
 					if len(clBy) > 0 {
 						cBy = append(cBy, clBy)
 					}
+					cTerms = append(cTerms, clause.DuoTerms...)
 				}
 			}
 			oldC := ga4gh.OldClaimCondition{}
@@ -386,6 +389,9 @@ func populatePersonaVisas(ctx context.Context, pname, visaIssuer string, asserti
 			if len(cBy) > 0 {
 				oldC.By = cBy
 			}
+			if len(cTerms) > 0 {
+				oldC.DUOTerms = cTerms
+			}
 			c.Condition[cType] = oldC
 		}
 		id.GA4GH[assert.Type] = append(id.GA4GH[assert.Type], c)
@@ -0,0 +1,328 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary itest runs some code against PostgreSQL.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	glog "github.com/golang/glog" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/pborman/uuid" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/pgstore" /* copybara-comment: pgstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	dpb "github.com/golang/protobuf/ptypes/duration" /* copybara-comment */
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+)
+
+var (
+	dsn             = flag.String("dsn", "postgres://postgres@localhost:5432/postgres?sslmode=disable", "PostgreSQL connection string")
+	fakeServiceName = "fake-service-name"
+	fakeConfigPath  = "fake-config-path"
+	fakeDataType    = "fake-datatype"
+	fakeRealm       = "fake-realm"
+	fakeUser        = "fake-user"
+)
+
+func main() {
+	ctx := context.Background()
+	flag.Parse()
+
+	db, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		glog.Exitf("sql.Open(...) failed: %v", err)
+	}
+	defer db.Close()
+
+	s := pgstore.New(db, fakeServiceName, fakeConfigPath)
+	if err := s.Init(ctx); err != nil {
+		glog.Exitf("store.Init(...) failed: %v", err)
+	}
+
+	scenarioSimple(ctx, s)
+	scenarioRevisionsAndHistory(ctx, s)
+	scenarioMultiRead(ctx, s)
+	scenarioTransactionsRollback(ctx, s)
+	scenarioTransactionsReadModifyWrite(ctx, s)
+	scenarioLock(ctx, s)
+	scenarioWipe(ctx, s)
+
+	fmt.Println("All tests passed.")
+}
+
+func scenarioSimple(ctx context.Context, s *pgstore.Store) {
+	// Scenario: write, read-check, read-modify-write, read-check, delete, read-check
+	id := "fake-id-simple-" + uuid.New()
+
+	if err := s.Write(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, &dpb.Duration{Seconds: 60}, nil); err != nil {
+		glog.Exitf("store.Write(...) failed: %v", err)
+	}
+	if ok, err := s.Exists(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev); err != nil || !ok {
+		glog.Exitf("store.Exists(...) = %v, %v, want true", ok, err)
+	}
+
+	{
+		// RMW
+		tx, err := s.Tx(true)
+		if err != nil {
+			glog.Exitf("store.Tx(true) failed: %v", err)
+		}
+		resp := &dpb.Duration{}
+		if err := s.ReadTx(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, resp, tx); err != nil {
+			glog.Exitf("store.ReadTx(...) failed: %v", err)
+		}
+		resp.Seconds = resp.Seconds + 60
+		if err := s.WriteTx(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, resp, nil, tx); err != nil {
+			glog.Exitf("store.WriteTx(...) failed: %v", err)
+		}
+		if err := tx.Finish(); err != nil {
+			glog.Exitf("tx.Finish() failed: %v", err)
+		}
+	}
+
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, got); err != nil {
+		glog.Exitf("store.Read(...) failed: %v", err)
+	}
+	if got.GetSeconds() != 120 {
+		glog.Exitf("store.Read(...) = %v, want %v", got, &dpb.Duration{Seconds: 120})
+	}
+
+	if err := s.Delete(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev); err != nil {
+		glog.Exitf("store.Delete(...) failed: %v", err)
+	}
+	if err := s.Read(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, &dpb.Duration{}); status.Code(err) != codes.NotFound || !storage.ErrNotFound(err) {
+		glog.Exitf("store.Read(...) = %v, want error with code %v", err, codes.NotFound)
+	}
+	if err := s.Delete(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev); status.Code(err) != codes.NotFound {
+		glog.Exitf("store.Delete(...) = %v, want error with code %v", err, codes.NotFound)
+	}
+}
+
+func scenarioRevisionsAndHistory(ctx context.Context, s *pgstore.Store) {
+	// Scenario: write two revisions with history, read each revision and the history in order.
+	id := "fake-id-history-" + uuid.New()
+
+	for rev := int64(1); rev <= 2; rev++ {
+		he := &cpb.HistoryEntry{Revision: rev, Desc: fmt.Sprintf("rev %d", rev)}
+		if err := s.Write(fakeDataType, fakeRealm, fakeUser, id, rev, &dpb.Duration{Seconds: rev}, he); err != nil {
+			glog.Exitf("store.Write(..., %d, ...) failed: %v", rev, err)
+		}
+	}
+
+	for _, rev := range []int64{1, 2, storage.LatestRev} {
+		want := rev
+		if rev == storage.LatestRev {
+			want = 2
+		}
+		got := &dpb.Duration{}
+		if err := s.Read(fakeDataType, fakeRealm, fakeUser, id, rev, got); err != nil {
+			glog.Exitf("store.Read(..., %d, ...) failed: %v", rev, err)
+		}
+		if got.GetSeconds() != want {
+			glog.Exitf("store.Read(..., %d, ...) = %v, want %d seconds", rev, got, want)
+		}
+	}
+
+	var history []proto.Message
+	if err := s.ReadHistory(fakeDataType, fakeRealm, fakeUser, id, &history); err != nil {
+		glog.Exitf("store.ReadHistory(...) failed: %v", err)
+	}
+	if len(history) != 2 {
+		glog.Exitf("store.ReadHistory(...) returned %d entries, want 2", len(history))
+	}
+	for i, h := range history {
+		if got := h.(*cpb.HistoryEntry).Revision; got != int64(i+1) {
+			glog.Exitf("store.ReadHistory(...)[%d].Revision = %d, want %d", i, got, i+1)
+		}
+	}
+}
+
+func scenarioMultiRead(ctx context.Context, s *pgstore.Store) {
+	// Scenario: write a few entities for different users, read them with filters and pagination.
+	datatype := "fake-datatype-multiread-" + uuid.New()
+	for i, user := range []string{"a", "b", "c", "d"} {
+		if err := s.Write(datatype, fakeRealm, user, "id", storage.LatestRev, &cpb.Account{Revision: int64(i), State: user}, nil); err != nil {
+			glog.Exitf("store.Write(...) failed: %v", err)
+		}
+	}
+	// Another realm must not be matched.
+	if err := s.Write(datatype, "other-realm", "e", "id", storage.LatestRev, &cpb.Account{State: "e"}, nil); err != nil {
+		glog.Exitf("store.Write(...) failed: %v", err)
+	}
+
+	results, err := s.MultiReadTx(datatype, fakeRealm, storage.MatchAllUsers, storage.MatchAllIDs, nil, 1, 2, &cpb.Account{}, nil)
+	if err != nil {
+		glog.Exitf("store.MultiReadTx(...) failed: %v", err)
+	}
+	if len(results.Entries) != 2 || results.MatchCount != 3 {
+		glog.Exitf("store.MultiReadTx(offset=1, pageSize=2) = %d entries, MatchCount %d, want 2 entries, MatchCount 3", len(results.Entries), results.MatchCount)
+	}
+
+	filters, err := storage.BuildFilters(`state eq "b" or state eq "d"`, map[string]func(p proto.Message) string{
+		"state": func(p proto.Message) string { return p.(*cpb.Account).State },
+	})
+	if err != nil {
+		glog.Exitf("storage.BuildFilters(...) failed: %v", err)
+	}
+	results, err = s.MultiReadTx(datatype, fakeRealm, storage.MatchAllUsers, storage.MatchAllIDs, filters, 1, 10, &cpb.Account{}, nil)
+	if err != nil {
+		glog.Exitf("store.MultiReadTx(...) failed: %v", err)
+	}
+	if len(results.Entries) != 1 || results.MatchCount != 1 {
+		glog.Exitf("store.MultiReadTx(filters, offset=1) = %d entries, MatchCount %d, want 1 entry, MatchCount 1", len(results.Entries), results.MatchCount)
+	}
+	if got := results.Entries[0].GroupID; got != "d" {
+		glog.Exitf("store.MultiReadTx(filters, offset=1) user = %q, want %q", got, "d")
+	}
+
+	if err := s.MultiDeleteTx(datatype, fakeRealm, storage.DefaultUser, nil); err != nil {
+		glog.Exitf("store.MultiDeleteTx(...) failed: %v", err)
+	}
+	results, err = s.MultiReadTx(datatype, storage.AllRealms, storage.MatchAllUsers, storage.MatchAllIDs, nil, 0, 10, &cpb.Account{}, nil)
+	if err != nil {
+		glog.Exitf("store.MultiReadTx(...) failed: %v", err)
+	}
+	if len(results.Entries) != 1 || results.Entries[0].Realm != "other-realm" {
+		glog.Exitf("store.MultiReadTx(...) after MultiDeleteTx() = %+v, want only the entry of %q", results.Entries, "other-realm")
+	}
+}
+
+func scenarioTransactionsRollback(ctx context.Context, s *pgstore.Store) {
+	// Scenario: write inside a transaction, rollback, the write is discarded.
+	id := "fake-id-rollback-" + uuid.New()
+
+	tx, err := s.Tx(true)
+	if err != nil {
+		glog.Exitf("store.Tx(true) failed: %v", err)
+	}
+	if err := s.WriteTx(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err != nil {
+		glog.Exitf("store.WriteTx(...) failed: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		glog.Exitf("tx.Rollback() failed: %v", err)
+	}
+	if err := tx.Finish(); err != nil {
+		glog.Exitf("tx.Finish() after Rollback() failed: %v", err)
+	}
+	if ok, err := s.Exists(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev); err != nil || ok {
+		glog.Exitf("store.Exists(...) after rollback = %v, %v, want false", ok, err)
+	}
+}
+
+func scenarioTransactionsReadModifyWrite(ctx context.Context, s *pgstore.Store) {
+	// Scenario: two concurrent RMW transactions, the second one waits for the first.
+	id := "fake-id-rmw-" + uuid.New()
+	if err := s.Write(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, &dpb.Duration{Seconds: 0}, nil); err != nil {
+		glog.Exitf("store.Write(...) failed: %v", err)
+	}
+
+	increment := func(done chan<- error) {
+		tx, err := s.Tx(true)
+		if err != nil {
+			done <- err
+			return
+		}
+		d := &dpb.Duration{}
+		if err := s.ReadTx(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, d, tx); err != nil {
+			tx.Rollback()
+			done <- err
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+		d.Seconds++
+		if err := s.WriteTx(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, d, nil, tx); err != nil {
+			done <- err
+			return
+		}
+		done <- tx.Finish()
+	}
+
+	done := make(chan error, 2)
+	go increment(done)
+	go increment(done)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			glog.Exitf("concurrent read-modify-write failed: %v", err)
+		}
+	}
+
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDataType, fakeRealm, fakeUser, id, storage.LatestRev, got); err != nil {
+		glog.Exitf("store.Read(...) failed: %v", err)
+	}
+	if got.GetSeconds() != 2 {
+		glog.Exitf("store.Read(...) = %v, want %v", got, &dpb.Duration{Seconds: 2})
+	}
+}
+
+func scenarioLock(ctx context.Context, s *pgstore.Store) {
+	// Scenario: a lock held by a transaction cannot be taken by another one until released.
+	name := "fake-lock-name-" + uuid.New()
+
+	tx1 := s.LockTx(name, 0, nil)
+	if tx1 == nil {
+		glog.Exitf("1st process: store.LockTx(...) failed")
+	}
+	if tx2 := s.LockTx(name, 0, nil); tx2 != nil {
+		glog.Exitf("another process: store.LockTx(...) should fail while the lock is held")
+	}
+	if err := tx1.Finish(); err != nil {
+		glog.Exitf("tx.Finish() failed: %v", err)
+	}
+
+	// Lock is released, but minFrequency has not passed yet.
+	if tx2 := s.LockTx(name, time.Hour, nil); tx2 != nil {
+		glog.Exitf("another process: store.LockTx(..., 1h, ...) should fail within minFrequency")
+	}
+	tx2 := s.LockTx(name, 0, nil)
+	if tx2 == nil {
+		glog.Exitf("another process: store.LockTx(...) failed after release")
+	}
+	tx2.Finish()
+}
+
+func scenarioWipe(ctx context.Context, s *pgstore.Store) {
+	// Scenario: wipe a realm with a maximum number of entries, then wipe the rest.
+	realm := "fake-realm-wipe-" + uuid.New()
+	for i := 0; i < 3; i++ {
+		he := &cpb.HistoryEntry{Revision: 1}
+		if err := s.Write(fakeDataType, realm, fakeUser, fmt.Sprintf("id-%d", i), 1, &dpb.Duration{Seconds: 1}, he); err != nil {
+			glog.Exitf("store.Write(...) failed: %v", err)
+		}
+	}
+	// 3 history, 3 revision 1 and 3 latest entries.
+	count, err := s.Wipe(ctx, realm, 0, 4)
+	if err != nil {
+		glog.Exitf("store.Wipe(..., 4) failed: %v", err)
+	}
+	if count != 4 {
+		glog.Exitf("store.Wipe(..., 4) = %d, want 4", count)
+	}
+	count, err = s.Wipe(ctx, realm, 1, 0)
+	if err != nil {
+		glog.Exitf("store.Wipe(...) failed: %v", err)
+	}
+	if count != 5 {
+		glog.Exitf("store.Wipe(...) = %d, want 5", count)
+	}
+}
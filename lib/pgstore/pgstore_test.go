@@ -0,0 +1,129 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/storagetest" /* copybara-comment: storagetest */
+
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+)
+
+// TestStore_Conformance requires a PostgreSQL database given by POSTGRES_DSN.
+// Each store uses its own service name so tests do not see each other's data.
+func TestStore_Conformance(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	n := 0
+	storagetest.Run(t, func() storage.Store {
+		n++
+		s := NewStore(ctx, dsn, fmt.Sprintf("storagetest-%d-%d", os.Getpid(), n), "fake-config-path")
+		t.Cleanup(func() {
+			s.Wipe(ctx, storage.AllRealms, 0, 0)
+			s.db.Close()
+		})
+		return s
+	}, &storagetest.Options{ChangeLog: true})
+}
+
+// TestStore_ChangeLog requires a PostgreSQL database given by POSTGRES_DSN.
+func TestStore_ChangeLog(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	s := NewStore(ctx, dsn, fmt.Sprintf("changelog-%d", os.Getpid()), "fake-config-path")
+	defer s.db.Close()
+	defer s.Wipe(ctx, storage.AllRealms, 0, 0)
+
+	count := func() int {
+		var n int
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM change_log WHERE service = $1`, s.service).Scan(&n); err != nil {
+			t.Fatalf("counting changes failed: %v", err)
+		}
+		return n
+	}
+
+	for _, id := range []string{"a", "b"} {
+		if err := s.Write("fake-datatype", "fake-realm", storage.DefaultUser, id, storage.LatestRev, &cpb.Client{}, nil); err != nil {
+			t.Fatalf("Write(%q) failed: %v", id, err)
+		}
+	}
+	// Locks are not added to the change log.
+	if tx := s.LockTx("fake-lock", 0, nil); tx != nil {
+		tx.Finish()
+	}
+	if got := count(); got != 2 {
+		t.Fatalf("change log has %d changes, want 2", got)
+	}
+
+	old := time.Now().Add(-2 * changeLogRetention).Unix()
+	if _, err := s.db.ExecContext(ctx, `UPDATE change_log SET modified = $1 WHERE service = $2 AND id = $3`, old, s.service, "a"); err != nil {
+		t.Fatalf("aging change failed: %v", err)
+	}
+	if n, err := s.PruneChangeLog(ctx); err != nil || n != 1 {
+		t.Errorf("PruneChangeLog() = %d, %v, want 1, nil", n, err)
+	}
+	if got := count(); got != 1 {
+		t.Errorf("change log has %d changes after pruning, want 1", got)
+	}
+
+	if _, err := s.Wipe(ctx, "fake-realm", 0, 0); err != nil {
+		t.Fatalf("Wipe() failed: %v", err)
+	}
+	if got := count(); got != 0 {
+		t.Errorf("change log has %d changes after Wipe(), want 0", got)
+	}
+}
+
+func TestIndexWhere(t *testing.T) {
+	fields := map[string]func(p proto.Message) string{
+		"username": func(p proto.Message) string { return "" },
+		"state":    func(p proto.Message) string { return "" },
+	}
+	filters, err := storage.BuildFilters(`(username eq "Alice" or username sw "b_%") and state eq "active" and username gt "ä"`, fields)
+	if err != nil {
+		t.Fatalf("storage.BuildFilters(...) failed: %v", err)
+	}
+	pushed, remainder := storage.PlanFilters(filters, func(p storage.Predicate) bool {
+		return pushable(storage.AccountDatatype, p)
+	})
+	if len(remainder) != 2 {
+		t.Errorf("PlanFilters(...) remainder = %+v, want the state and the non-ASCII clauses", remainder)
+	}
+
+	got, args := indexWhere(storage.AccountDatatype, pushed, []interface{}{"service", storage.AccountDatatype})
+	expr := `(COALESCE(lower(NULLIF(content, '')::jsonb #>> '{properties,subject}'), '') COLLATE "C")`
+	want := "(" + expr + " = $3 OR " + expr + " LIKE $4)"
+	if got != want {
+		t.Errorf("indexWhere(...) = %q, want %q", got, want)
+	}
+	wantArgs := []interface{}{"service", storage.AccountDatatype, "alice", `b\_\%%`}
+	if diff := cmp.Diff(wantArgs, args); diff != "" {
+		t.Errorf("indexWhere(...) args diff (-want +got):\n%s", diff)
+	}
+}
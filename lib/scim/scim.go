@@ -22,8 +22,12 @@ import (
 	"time"
 
 	"github.com/gorilla/mux" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 
 	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
@@ -117,6 +121,27 @@ func (s *Scim) RemoveAccountLookup(rev int64, realm, fedAcct string, r *http.Req
 
 // SaveAccount puts an internal account structure in storage.
 func (s *Scim) SaveAccount(oldAcct, newAcct *cpb.Account, desc, subject, realm string, r *http.Request, tx storage.Tx) error {
+	updateAccountProperties(oldAcct, newAcct)
+	if err := s.store.WriteTx(storage.AccountDatatype, realm, storage.DefaultUser, newAcct.Properties.Subject, newAcct.Revision, newAcct, storage.MakeConfigHistory(desc, storage.AccountDatatype, newAcct.Revision, newAcct.Properties.Modified, r, subject, oldAcct, newAcct), tx); err != nil {
+		return fmt.Errorf("service storage unavailable: %v, retry later", err)
+	}
+	return nil
+}
+
+// SaveAccountIfMatch puts an internal account structure in storage if the etag
+// of the stored account matches. See storage.CheckETagTx for the etag values.
+func (s *Scim) SaveAccountIfMatch(oldAcct, newAcct *cpb.Account, etag, desc, subject, realm string, r *http.Request, tx storage.Tx) error {
+	updateAccountProperties(oldAcct, newAcct)
+	if err := s.store.WriteIfMatchTx(storage.AccountDatatype, realm, storage.DefaultUser, newAcct.Properties.Subject, newAcct.Revision, etag, newAcct, storage.MakeConfigHistory(desc, storage.AccountDatatype, newAcct.Revision, newAcct.Properties.Modified, r, subject, oldAcct, newAcct), tx); err != nil {
+		if errutil.PreconditionFailed(err) {
+			return err
+		}
+		return fmt.Errorf("service storage unavailable: %v, retry later", err)
+	}
+	return nil
+}
+
+func updateAccountProperties(oldAcct, newAcct *cpb.Account) {
 	newAcct.Revision++
 	newAcct.Properties.Modified = float64(time.Now().UnixNano()) / 1e9
 	if newAcct.Properties.Created == 0 {
@@ -126,11 +151,6 @@ func (s *Scim) SaveAccount(oldAcct, newAcct *cpb.Account, desc, subject, realm s
 			newAcct.Properties.Created = newAcct.Properties.Modified
 		}
 	}
-
-	if err := s.store.WriteTx(storage.AccountDatatype, realm, storage.DefaultUser, newAcct.Properties.Subject, newAcct.Revision, newAcct, storage.MakeConfigHistory(desc, storage.AccountDatatype, newAcct.Revision, newAcct.Properties.Modified, r, subject, oldAcct, newAcct), tx); err != nil {
-		return fmt.Errorf("service storage unavailable: %v, retry later", err)
-	}
-	return nil
 }
 
 // LoadGroup loads a user group.
@@ -220,3 +240,13 @@ func getRealm(r *http.Request) string {
 	}
 	return storage.DefaultRealm
 }
+
+// buildSort returns the sort order of a list request given by the SCIM
+// "sortBy" and "sortOrder" query parameters, or nil if sortBy is not set.
+func buildSort(r *http.Request, fields map[string]func(p proto.Message) string) (*storage.Sort, error) {
+	order, err := storage.BuildSort(httputils.QueryParam(r, "sortBy"), httputils.QueryParam(r, "sortOrder"), fields)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return order, nil
+}
@@ -103,6 +103,7 @@ func GroupFactory(store storage.Store, groupPath string) *handlerfactory.Options
 // GroupHandler handles SCIM group requests.
 type GroupHandler struct {
 	item  *spb.Group
+	etag  string
 	save  *spb.Group
 	input *spb.Group
 	patch *spb.Patch
@@ -149,6 +150,7 @@ func (h *GroupHandler) LookupItem(r *http.Request, name string, vars map[string]
 	}
 
 	h.item = group
+	h.etag = storage.ETag(group)
 	return true
 }
 
@@ -194,6 +196,10 @@ func (h *GroupHandler) Get(r *http.Request, name string) (proto.Message, error)
 	if err != nil {
 		return nil, err
 	}
+	order, err := buildSort(r, scimMemberFilterMap)
+	if err != nil {
+		return nil, err
+	}
 	// "startIndex" is a 1-based starting location, to be converted to an offset for the query.
 	start := httputils.QueryParamInt(r, "startIndex")
 	if start == 0 {
@@ -206,7 +212,7 @@ func (h *GroupHandler) Get(r *http.Request, name string) (proto.Message, error)
 		max = storage.DefaultPageSize
 	}
 
-	results, err := h.store.MultiReadTx(storage.GroupMemberDatatype, getRealm(r), name, storage.MatchAllIDs, filters, offset, max, &spb.Member{}, h.tx)
+	results, err := storage.MultiReadSortedTx(h.store, storage.GroupMemberDatatype, getRealm(r), name, storage.MatchAllIDs, filters, order, offset, max, &spb.Member{}, h.tx)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +225,9 @@ func (h *GroupHandler) Get(r *http.Request, name string) (proto.Message, error)
 			keys = append(keys, member.Value)
 		}
 	}
-	sort.Strings(keys)
+	if order == nil {
+		sort.Strings(keys)
+	}
 	for _, key := range keys {
 		h.item.Members = append(h.item.Members, members[key])
 	}
@@ -330,7 +338,11 @@ func (h *GroupHandler) Remove(r *http.Request, name string) (proto.Message, erro
 	if err := h.store.MultiDeleteTx(storage.GroupMemberDatatype, getRealm(r), name, h.tx); err != nil {
 		return nil, err
 	}
-	return nil, h.store.DeleteTx(storage.GroupDatatype, getRealm(r), name, storage.DefaultID, storage.LatestRev, h.tx)
+	if err := h.store.DeleteTx(storage.GroupDatatype, getRealm(r), name, storage.DefaultID, storage.LatestRev, h.tx); err != nil {
+		return nil, err
+	}
+	h.etag = ""
+	return nil, nil
 }
 
 // CheckIntegrity checks that any modifications make sense before applying them.
@@ -344,7 +356,17 @@ func (h *GroupHandler) Save(r *http.Request, tx storage.Tx, name string, vars ma
 		return nil
 	}
 	h.save.Members = nil // members are stored separately.
-	return h.store.WriteTx(storage.GroupDatatype, getRealm(r), name, storage.DefaultID, storage.LatestRev, h.save, nil, h.tx)
+	// An empty etag makes sure that a new group does not overwrite an existing one.
+	if err := h.store.WriteIfMatchTx(storage.GroupDatatype, getRealm(r), name, storage.DefaultID, storage.LatestRev, h.etag, h.save, nil, h.tx); err != nil {
+		return err
+	}
+	h.etag = storage.ETag(h.save)
+	return nil
+}
+
+// ETag returns the etag of the group.
+func (h *GroupHandler) ETag() string {
+	return h.etag
 }
 
 func (h *GroupHandler) patchMember(object map[string]string, name string, idx int) (*spb.Member, error) {
@@ -455,6 +477,10 @@ func (h *GroupsHandler) Get(r *http.Request, name string) (proto.Message, error)
 	if err != nil {
 		return nil, err
 	}
+	order, err := buildSort(r, scimGroupsFilterMap)
+	if err != nil {
+		return nil, err
+	}
 	// "startIndex" is a 1-based starting location, to be converted to an offset for the query.
 	start := httputils.QueryParamInt(r, "startIndex")
 	if start == 0 {
@@ -467,7 +493,7 @@ func (h *GroupsHandler) Get(r *http.Request, name string) (proto.Message, error)
 		max = storage.DefaultPageSize
 	}
 
-	results, err := h.store.MultiReadTx(storage.GroupDatatype, getRealm(r), storage.MatchAllGroups, storage.MatchAllIDs, filters, offset, max, &spb.Group{}, h.tx)
+	results, err := storage.MultiReadSortedTx(h.store, storage.GroupDatatype, getRealm(r), storage.MatchAllGroups, storage.MatchAllIDs, filters, order, offset, max, &spb.Group{}, h.tx)
 	if err != nil {
 		return nil, err
 	}
@@ -480,7 +506,9 @@ func (h *GroupsHandler) Get(r *http.Request, name string) (proto.Message, error)
 			names = append(names, group.Id)
 		}
 	}
-	sort.Strings(names)
+	if order == nil {
+		sort.Strings(names)
+	}
 	var list []*spb.Group
 	for _, name := range names {
 		list = append(list, groups[name])
@@ -15,12 +15,16 @@
 package scim
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp" /* copybara-comment */
 	"github.com/google/go-cmp/cmp/cmpopts" /* copybara-comment */
 	"google.golang.org/protobuf/testing/protocmp" /* copybara-comment */
+	"github.com/golang/protobuf/jsonpb" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 
@@ -127,6 +131,29 @@ func TestRemoveAccountLookup(t *testing.T) {
 	}
 }
 
+func TestSaveAccountIfMatch(t *testing.T) {
+	user := "dr_joe_elixir"
+	realm := "test"
+	s := New(storage.NewMemoryStorage("ic-min", "testdata/config"))
+	acct, _, err := s.LoadAccount(user, realm, true, nil)
+	if err != nil {
+		t.Fatalf("LoadAccount(%q, %q, true, nil) failed: %v", user, realm, err)
+	}
+	etag := storage.ETag(acct)
+
+	save := proto.Clone(acct).(*cpb.Account)
+	if err := s.SaveAccountIfMatch(acct, save, etag, "", user, realm, nil, nil); err != nil {
+		t.Fatalf("SaveAccountIfMatch(acct, save, %q, ...) failed: %v", etag, err)
+	}
+
+	// The etag of the previous revision no longer matches.
+	save = proto.Clone(acct).(*cpb.Account)
+	err = s.SaveAccountIfMatch(acct, save, etag, "", user, realm, nil, nil)
+	if !errutil.PreconditionFailed(err) {
+		t.Errorf("SaveAccountIfMatch(acct, save, %q, ...) = %v, want precondition failed error", etag, err)
+	}
+}
+
 func TestLookupAccount(t *testing.T) {
 	user := "non-admin@example.org"
 	realm := "test"
@@ -300,3 +327,63 @@ func TestLoadGroupMembershipForUser(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexableFields(t *testing.T) {
+	tests := []struct {
+		datatype string
+		fields   map[string]func(p proto.Message) string
+		item     proto.Message
+	}{
+		{
+			datatype: storage.AccountDatatype,
+			fields:   scimUserFilterMap,
+			item: &cpb.Account{
+				Properties: &cpb.AccountProperties{Subject: "subject"},
+				Profile:    &cpb.AccountProfile{Name: "name", GivenName: "given", FamilyName: "family", MiddleName: "middle"},
+			},
+		},
+		{
+			datatype: storage.GroupDatatype,
+			fields:   scimGroupsFilterMap,
+			item:     &spb.Group{Id: "id", DisplayName: "display", ExternalId: "external"},
+		},
+		{
+			datatype: storage.GroupMemberDatatype,
+			fields:   scimMemberFilterMap,
+			item:     &spb.Member{Display: "display", Value: "value", ExtensionIssuer: "issuer", ExtensionSubject: "subject"},
+		},
+	}
+	for _, tc := range tests {
+		js, err := (&jsonpb.Marshaler{}).MarshalToString(tc.item)
+		if err != nil {
+			t.Fatalf("Marshal(%s) failed: %v", tc.datatype, err)
+		}
+		var content interface{}
+		if err := json.Unmarshal([]byte(js), &content); err != nil {
+			t.Fatalf("json.Unmarshal(%s) failed: %v", tc.datatype, err)
+		}
+		fields := storage.IndexableFields(tc.datatype)
+		if len(fields) == 0 {
+			t.Errorf("IndexableFields(%s) is empty", tc.datatype)
+		}
+		for field, path := range fields {
+			fn, ok := tc.fields[field]
+			if !ok {
+				t.Errorf("%s indexable field %q is not a SCIM filter field", tc.datatype, field)
+				continue
+			}
+			got := content
+			for _, name := range path {
+				m, ok := got.(map[string]interface{})
+				if !ok {
+					got = nil
+					break
+				}
+				got = m[name]
+			}
+			if want := fn(tc.item); got != want {
+				t.Errorf("%s field %q: value at JSON path %v = %v, want the filter value %q", tc.datatype, field, path, got, want)
+			}
+		}
+	}
+}
@@ -223,6 +223,11 @@ func (h *scimMe) Save(r *http.Request, tx storage.Tx, name string, vars map[stri
 	return h.user.Save(r, tx, name, vars, desc, typeName)
 }
 
+// ETag returns the etag of the account.
+func (h *scimMe) ETag() string {
+	return h.user.ETag()
+}
+
 //////////////////////////////////////////////////////////////////
 
 // UserFactory creates SCIM /Users/<id> request handlers
@@ -250,6 +255,7 @@ type scimUser struct {
 	domainURL string
 	userPath  string
 	item      *cpb.Account
+	etag      string
 	input     *spb.Patch
 	save      *cpb.Account
 	auth      *auth.Context
@@ -283,6 +289,7 @@ func (h *scimUser) LookupItem(r *http.Request, name string, vars map[string]stri
 		return false
 	}
 	h.item = acct
+	h.etag = storage.ETag(acct)
 	return true
 }
 
@@ -494,7 +501,16 @@ func (h *scimUser) Save(r *http.Request, tx storage.Tx, name string, vars map[st
 	if h.save == nil {
 		return nil
 	}
-	return h.s.SaveAccount(h.item, h.save, desc, h.auth.ID.Subject, getRealm(r), r, h.tx)
+	if err := h.s.SaveAccountIfMatch(h.item, h.save, h.etag, desc, h.auth.ID.Subject, getRealm(r), r, h.tx); err != nil {
+		return err
+	}
+	h.etag = storage.ETag(h.save)
+	return nil
+}
+
+// ETag returns the etag of the account.
+func (h *scimUser) ETag() string {
+	return h.etag
 }
 
 func (h *scimUser) linkEmail(r *http.Request) error {
@@ -598,6 +614,10 @@ func (h *scimUsers) Get(r *http.Request, name string) (proto.Message, error) {
 	if err != nil {
 		return nil, err
 	}
+	order, err := buildSort(r, scimUserFilterMap)
+	if err != nil {
+		return nil, err
+	}
 	// "startIndex" is a 1-based starting location, to be converted to an offset for the query.
 	start := httputils.QueryParamInt(r, "startIndex")
 	if start == 0 {
@@ -610,7 +630,7 @@ func (h *scimUsers) Get(r *http.Request, name string) (proto.Message, error) {
 		max = storage.DefaultPageSize
 	}
 
-	results, err := h.store.MultiReadTx(storage.AccountDatatype, getRealm(r), storage.MatchAllUsers, storage.MatchAllIDs, filters, offset, max, &cpb.Account{}, h.tx)
+	results, err := storage.MultiReadSortedTx(h.store, storage.AccountDatatype, getRealm(r), storage.MatchAllUsers, storage.MatchAllIDs, filters, order, offset, max, &cpb.Account{}, h.tx)
 	if err != nil {
 		return nil, err
 	}
@@ -622,7 +642,9 @@ func (h *scimUsers) Get(r *http.Request, name string) (proto.Message, error) {
 			subjects = append(subjects, acct.Properties.Subject)
 		}
 	}
-	sort.Strings(subjects)
+	if order == nil {
+		sort.Strings(subjects)
+	}
 	realm := getRealm(r)
 	var list []*spb.User
 	for _, sub := range subjects {
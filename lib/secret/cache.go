@@ -0,0 +1,70 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedProvider caches the secrets of a remote provider, since the secrets
+// are resolved on every load of the service secrets.
+type CachedProvider struct {
+	provider SecretProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+// NewCachedProvider creates a CachedProvider keeping secrets for the ttl.
+func NewCachedProvider(p SecretProvider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		provider: p,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+// GetSecret returns the cached secret or fetches it from the provider.
+func (c *CachedProvider) GetSecret(ctx context.Context, name, version string) (string, error) {
+	key := name + "/" + version
+	now := c.now()
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.value, nil
+	}
+
+	v, err := c.provider.GetSecret(ctx, name, version)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{value: v, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return v, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+)
+
+// EnvProvider provides secrets from env vars. The env var of a secret is the
+// prefix followed by the name in upper case with characters other than
+// letters and digits replaced by "_", for example "hydra-client" with prefix
+// "SECRET_" is SECRET_HYDRA_CLIENT. Only the latest version is available.
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates a EnvProvider.
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+// GetSecret returns the value of the env var of the secret.
+func (p *EnvProvider) GetSecret(ctx context.Context, name, version string) (string, error) {
+	if !isLatest(version) {
+		return "", status.Errorf(codes.InvalidArgument, "env secret %q has no version %q", name, version)
+	}
+	key := p.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return "", status.Errorf(codes.NotFound, "env var %q of secret %q not set", key, name)
+	}
+	return v, nil
+}
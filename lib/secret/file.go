@@ -0,0 +1,57 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+)
+
+// FileProvider provides secrets from files of a directory, such as a mounted
+// Kubernetes secret. The latest version of a secret is the file of its name,
+// other versions are in "<name>@<version>". A trailing newline is removed.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a FileProvider reading secrets of the directory.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// GetSecret returns the content of the file of the secret.
+func (p *FileProvider) GetSecret(ctx context.Context, name, version string) (string, error) {
+	clean := filepath.Clean("/" + name)
+	if clean == "/" || clean != "/"+name {
+		return "", status.Errorf(codes.InvalidArgument, "invalid file secret name %q", name)
+	}
+	if !isLatest(version) {
+		name += "@" + version
+	}
+	b, err := ioutil.ReadFile(filepath.Join(p.dir, name))
+	if os.IsNotExist(err) {
+		return "", status.Errorf(codes.NotFound, "secret file %q not found", name)
+	}
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "reading secret file %q failed: %v", name, err)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), nil
+}
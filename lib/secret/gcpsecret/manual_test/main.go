@@ -21,7 +21,7 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/secretmanager/apiv1" /* copybara-comment: secretmanager */
-	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret" /* copybara-comment: secret */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret/gcpsecret" /* copybara-comment: gcpsecret */
 
 	glog "github.com/golang/glog" /* copybara-comment */
 	rpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1" /* copybara-comment: resources_go_proto */
@@ -73,8 +73,8 @@ func main() {
 		glog.Fatalf("failed to add secret version: %v", err)
 	}
 
-	c := secret.New(client, *project)
-	got, err := c.GetSecret(ctx, key)
+	c := gcpsecret.New(client, *project)
+	got, err := c.GetSecret(ctx, key, "")
 	if err != nil {
 		glog.Fatalf("GetSecret() failed: %v", err)
 	}
@@ -12,14 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package secret contains helpers to access secrets in GCP secretmanager.
-package secret
+// Package gcpsecret contains a secret.SecretProvider of GCP secretmanager.
+package gcpsecret
 
 import (
 	"context"
 	"fmt"
 
 	"cloud.google.com/go/secretmanager/apiv1" /* copybara-comment: secretmanager */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/secret" /* copybara-comment: secret */
 
 	pb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1" /* copybara-comment: service_go_proto */
 )
@@ -38,9 +39,13 @@ func New(client *secretmanager.Client, project string) *Client {
 	}
 }
 
-// GetSecret get the latest version of given key of secret.
-func (s *Client) GetSecret(ctx context.Context, key string) (string, error) {
-	name := fmt.Sprintf("projects/%s/secrets/%s/versions/latest", s.project, key)
+// GetSecret get the given version of given key of secret, the latest version
+// if version is empty.
+func (s *Client) GetSecret(ctx context.Context, key, version string) (string, error) {
+	if version == "" {
+		version = secret.LatestVersion
+	}
+	name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", s.project, key, version)
 
 	req := &pb.AccessSecretVersionRequest{
 		Name: name,
@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+)
+
+// MemoryProvider keeps secrets in memory, for tests and local development.
+// Versions are numbered from "1".
+type MemoryProvider struct {
+	mu      sync.RWMutex
+	secrets map[string][]string
+}
+
+// NewMemoryProvider creates a MemoryProvider with the given secrets as
+// version 1.
+func NewMemoryProvider(secrets map[string]string) *MemoryProvider {
+	p := &MemoryProvider{secrets: map[string][]string{}}
+	for k, v := range secrets {
+		p.secrets[k] = []string{v}
+	}
+	return p
+}
+
+// AddVersion adds a new latest version to a secret and returns the version.
+func (p *MemoryProvider) AddVersion(name, value string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets[name] = append(p.secrets[name], value)
+	return strconv.Itoa(len(p.secrets[name]))
+}
+
+// GetSecret returns a version of the secret.
+func (p *MemoryProvider) GetSecret(ctx context.Context, name, version string) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	versions := p.secrets[name]
+	if len(versions) == 0 {
+		return "", status.Errorf(codes.NotFound, "secret %q not found", name)
+	}
+	if isLatest(version) {
+		return versions[len(versions)-1], nil
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil || v < 1 || v > len(versions) {
+		return "", status.Errorf(codes.NotFound, "secret %q has no version %q", name, version)
+	}
+	return versions[v-1], nil
+}
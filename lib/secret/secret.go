@@ -0,0 +1,109 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secret provides access to secrets kept outside of the storage layer.
+//
+// Values of the service secrets may reference a secret by URI instead of
+// holding the raw value:
+//
+//   secret://<name>
+//   secret://<name>/<version>
+//
+// The version defaults to the latest. If the name contains "/", the version
+// must be given, for example "secret://dam/hydra/latest".
+package secret
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+)
+
+const (
+	// URIPrefix is the prefix of secret references.
+	URIPrefix = "secret://"
+	// LatestVersion is the version of the latest value of a secret.
+	LatestVersion = "latest"
+)
+
+// SecretProvider provides the values of secrets.
+type SecretProvider interface {
+	// GetSecret returns a version of the secret, the latest if version is
+	// empty. It returns a NotFound status error if the secret does not exist.
+	GetSecret(ctx context.Context, name, version string) (string, error)
+}
+
+// IsURI returns true if the value is a secret reference.
+func IsURI(value string) bool {
+	return strings.HasPrefix(value, URIPrefix)
+}
+
+// ParseURI returns the name and version of a secret reference. The version
+// is empty if the reference has none.
+func ParseURI(uri string) (string, string, error) {
+	if !IsURI(uri) {
+		return "", "", status.Errorf(codes.InvalidArgument, "secret reference %q does not start with %q", uri, URIPrefix)
+	}
+	name, version := strings.TrimPrefix(uri, URIPrefix), ""
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name, version = name[:i], name[i+1:]
+		if version == "" {
+			return "", "", status.Errorf(codes.InvalidArgument, "secret reference %q has empty version", uri)
+		}
+	}
+	if name == "" {
+		return "", "", status.Errorf(codes.InvalidArgument, "secret reference %q has empty name", uri)
+	}
+	return name, version, nil
+}
+
+// Resolve returns the value of the secret if the value is a secret reference,
+// otherwise the value itself.
+func Resolve(ctx context.Context, p SecretProvider, value string) (string, error) {
+	if !IsURI(value) {
+		return value, nil
+	}
+	name, version, err := ParseURI(value)
+	if err != nil {
+		return "", err
+	}
+	if p == nil {
+		return "", status.Errorf(codes.FailedPrecondition, "secret reference %q found but no secret provider is configured", value)
+	}
+	v, err := p.GetSecret(ctx, name, version)
+	if err != nil {
+		return "", status.Errorf(status.Code(err), "resolving secret %q failed: %v", value, err)
+	}
+	return v, nil
+}
+
+// ResolveMap replaces the secret references in the values of the map with the
+// values of the secrets.
+func ResolveMap(ctx context.Context, p SecretProvider, m map[string]string) error {
+	for k, v := range m {
+		resolved, err := Resolve(ctx, p, v)
+		if err != nil {
+			return err
+		}
+		m[k] = resolved
+	}
+	return nil
+}
+
+// isLatest returns true if the version is the latest version.
+func isLatest(version string) bool {
+	return version == "" || version == LatestVersion
+}
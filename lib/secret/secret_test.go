@@ -0,0 +1,161 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+)
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		name    string
+		version string
+	}{
+		{uri: "secret://hydra", name: "hydra"},
+		{uri: "secret://hydra/3", name: "hydra", version: "3"},
+		{uri: "secret://dam/hydra#client_secret/latest", name: "dam/hydra#client_secret", version: "latest"},
+	}
+	for _, tc := range tests {
+		name, version, err := ParseURI(tc.uri)
+		if err != nil {
+			t.Fatalf("ParseURI(%q) failed: %v", tc.uri, err)
+		}
+		if name != tc.name || version != tc.version {
+			t.Errorf("ParseURI(%q) = %q, %q, want %q, %q", tc.uri, name, version, tc.name, tc.version)
+		}
+	}
+
+	for _, uri := range []string{"hydra", "secret://", "secret://hydra/", "secret:///3"} {
+		if _, _, err := ParseURI(uri); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ParseURI(%q) = %v, wants InvalidArgument", uri, err)
+		}
+	}
+}
+
+func TestResolveMap(t *testing.T) {
+	ctx := context.Background()
+	p := NewMemoryProvider(map[string]string{"a": "a1"})
+	p.AddVersion("a", "a2")
+
+	m := map[string]string{
+		"raw":    "raw-value",
+		"latest": "secret://a",
+		"v1":     "secret://a/1",
+	}
+	if err := ResolveMap(ctx, p, m); err != nil {
+		t.Fatalf("ResolveMap() failed: %v", err)
+	}
+	want := map[string]string{
+		"raw":    "raw-value",
+		"latest": "a2",
+		"v1":     "a1",
+	}
+	if d := cmp.Diff(want, m); d != "" {
+		t.Errorf("ResolveMap() (-want, +got):\n%s", d)
+	}
+
+	if _, err := Resolve(ctx, p, "secret://b"); status.Code(err) != codes.NotFound {
+		t.Errorf("Resolve() of missing secret = %v, wants NotFound", err)
+	}
+	if _, err := Resolve(ctx, nil, "secret://a"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Resolve() without provider = %v, wants FailedPrecondition", err)
+	}
+	if got, err := Resolve(ctx, nil, "raw"); err != nil || got != "raw" {
+		t.Errorf("Resolve() of raw value without provider = %q, %v, want raw", got, err)
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	ctx := context.Background()
+	os.Setenv("TEST_SECRET_HYDRA_CLIENT_1", "s")
+	defer os.Unsetenv("TEST_SECRET_HYDRA_CLIENT_1")
+	p := NewEnvProvider("TEST_SECRET_")
+
+	got, err := p.GetSecret(ctx, "hydra-client.1", "")
+	if err != nil {
+		t.Fatalf("GetSecret() failed: %v", err)
+	}
+	if got != "s" {
+		t.Errorf("GetSecret() = %q, want s", got)
+	}
+	if _, err := p.GetSecret(ctx, "hydra-client.1", "2"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetSecret() with version = %v, wants InvalidArgument", err)
+	}
+	if _, err := p.GetSecret(ctx, "missing", ""); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() of missing secret = %v, wants NotFound", err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatalf("TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "hydra"), []byte("latest\n"), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "hydra@1"), []byte("old"), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	p := NewFileProvider(dir)
+
+	for version, want := range map[string]string{"": "latest", "latest": "latest", "1": "old"} {
+		got, err := p.GetSecret(ctx, "hydra", version)
+		if err != nil {
+			t.Fatalf("GetSecret(hydra, %q) failed: %v", version, err)
+		}
+		if got != want {
+			t.Errorf("GetSecret(hydra, %q) = %q, want %q", version, got, want)
+		}
+	}
+	if _, err := p.GetSecret(ctx, "missing", ""); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() of missing secret = %v, wants NotFound", err)
+	}
+	if _, err := p.GetSecret(ctx, "../hydra", ""); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetSecret() outside of the directory = %v, wants InvalidArgument", err)
+	}
+}
+
+func TestCachedProvider(t *testing.T) {
+	ctx := context.Background()
+	p := NewMemoryProvider(map[string]string{"a": "a1"})
+	c := NewCachedProvider(p, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	if got, _ := c.GetSecret(ctx, "a", ""); got != "a1" {
+		t.Errorf("GetSecret() = %q, want a1", got)
+	}
+	p.AddVersion("a", "a2")
+	if got, _ := c.GetSecret(ctx, "a", ""); got != "a1" {
+		t.Errorf("GetSecret() before expiry = %q, want cached a1", got)
+	}
+	now = now.Add(time.Minute)
+	if got, _ := c.GetSecret(ctx, "a", ""); got != "a2" {
+		t.Errorf("GetSecret() after expiry = %q, want a2", got)
+	}
+}
@@ -0,0 +1,467 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup exports the items of a realm to a zip archive and restores them.
+//
+// The archive contains the items as JSON encoded protos named like the files
+// of a storage.FileStorage config root, e.g. "config_master_main_latest.json",
+// and a manifest listing the items.
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/golang/protobuf/jsonpb" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage/migrate" /* copybara-comment: migrate */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+)
+
+const (
+	// ManifestName is the name of the manifest file in an archive.
+	ManifestName = "manifest.json"
+
+	manifestVersion = 1
+	batchSize       = 100
+)
+
+var (
+	// realmDatatypes are the names of the datatypes included in a backup.
+	realmDatatypes = map[string]bool{
+		storage.ConfigDatatype:               true,
+		storage.SecretsDatatype:              true,
+		storage.PermissionsDatatype:          true,
+		storage.AccountDatatype:              true,
+		storage.AccountLookupDatatype:        true,
+		storage.GroupDatatype:                true,
+		storage.GroupMemberDatatype:          true,
+		storage.RememberedConsentDatatype:    true,
+		storage.LongRunningOperationDatatype: true,
+		storage.ProcessDataType:              true,
+	}
+
+	marshaler = jsonpb.Marshaler{Indent: "  "}
+)
+
+// Datatypes returns the datatypes included in a backup of a type of service, "dam" or "ic".
+func Datatypes(serviceType string) ([]migrate.Datatype, error) {
+	all, err := migrate.Datatypes(serviceType)
+	if err != nil {
+		return nil, err
+	}
+	var out []migrate.Datatype
+	for _, dt := range all {
+		if realmDatatypes[dt.Name] {
+			out = append(out, dt)
+		}
+	}
+	return out, nil
+}
+
+// Manifest describes the content of an archive.
+type Manifest struct {
+	Version int       `json:"version"`
+	Realm   string    `json:"realm"`
+	Created time.Time `json:"created"`
+	Items   []*Item   `json:"items"`
+}
+
+// Item describes an item of an archive.
+type Item struct {
+	Datatype string `json:"datatype"`
+	User     string `json:"user,omitempty"`
+	ID       string `json:"id"`
+	// Revisions lists the revisions of the item stored in the archive along
+	// with their history entries.
+	Revisions []int64 `json:"revisions,omitempty"`
+	History   bool    `json:"history,omitempty"`
+}
+
+// Export writes the items of the datatypes in the realm to a zip archive.
+// Items are read in a single transaction.
+func Export(ctx context.Context, store storage.Store, datatypes []migrate.Datatype, realm string, w io.Writer) (ferr error) {
+	tx, err := store.Tx(false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := tx.Finish()
+		if ferr == nil {
+			ferr = err
+		}
+	}()
+
+	zw := zip.NewWriter(w)
+	m := &Manifest{Version: manifestVersion, Realm: realm, Created: time.Now().UTC(), Items: []*Item{}}
+	for _, dt := range datatypes {
+		for offset := 0; ; {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			page, err := store.MultiReadTx(dt.Name, realm, storage.MatchAllUsers, storage.MatchAllIDs, nil, offset, storage.MaxPageSize, dt.Type, tx)
+			if err != nil {
+				return fmt.Errorf("reading %q: %v", dt.Name, err)
+			}
+			for _, e := range page.Entries {
+				item, err := exportItem(zw, store, dt, realm, e, tx)
+				if err != nil {
+					return err
+				}
+				m.Items = append(m.Items, item)
+			}
+			offset += len(page.Entries)
+			if len(page.Entries) < storage.MaxPageSize {
+				break
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(zw, ManifestName, b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func exportItem(zw *zip.Writer, store storage.Store, dt migrate.Datatype, realm string, e *storage.Entry, tx storage.Tx) (*Item, error) {
+	item := &Item{Datatype: dt.Name, User: e.GroupID, ID: e.ItemID}
+	if err := writeProto(zw, fileName(dt.Name, realm, e.GroupID, e.ItemID, storage.LatestRevName), e.Item); err != nil {
+		return nil, err
+	}
+
+	var history []proto.Message
+	if err := store.ReadHistoryTx(dt.Name, realm, e.GroupID, e.ItemID, &history, tx); err != nil && !storage.ErrNotFound(err) {
+		return nil, fmt.Errorf("reading history of %q %q: %v", dt.Name, e.ItemID, err)
+	}
+	if len(history) == 0 {
+		return item, nil
+	}
+
+	// The history file is a list of JSON objects separated by commas.
+	var buf bytes.Buffer
+	for i, h := range history {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		if err := marshaler.Marshal(&buf, h); err != nil {
+			return nil, err
+		}
+		rev := h.(*cpb.HistoryEntry).Revision
+		content := proto.Clone(dt.Type)
+		if err := store.ReadTx(dt.Name, realm, e.GroupID, e.ItemID, rev, content, tx); err != nil {
+			if !storage.ErrNotFound(err) {
+				return nil, fmt.Errorf("reading %q %q revision %d: %v", dt.Name, e.ItemID, rev, err)
+			}
+			glog.Warningf("%s %q in realm %q: content of revision %d not found", dt.Name, e.ItemID, realm, rev)
+			continue
+		}
+		if err := writeProto(zw, fileName(dt.Name, realm, e.GroupID, e.ItemID, fmt.Sprintf("%06d", rev)), content); err != nil {
+			return nil, err
+		}
+		item.Revisions = append(item.Revisions, rev)
+	}
+	if err := writeFile(zw, fileName(dt.Name, realm, e.GroupID, e.ItemID, storage.HistoryRevName), buf.Bytes()); err != nil {
+		return nil, err
+	}
+	item.History = true
+	return item, nil
+}
+
+// ImportOptions contains the parameters of an import.
+type ImportOptions struct {
+	// Replace deletes the items of the datatypes in the realm along with their
+	// history and revisions before the import, restoring the realm to the state
+	// of the archive.
+	Replace bool
+	// Validate checks the latest revision of each item of the archive, e.g. the
+	// integrity of a config, before anything is written to the realm.
+	Validate func(realm, datatype string, content proto.Message) error
+}
+
+// Import writes the items of the datatypes in a zip archive to the realm,
+// which may differ from the realm of the archive. Items of other datatypes in
+// the archive are ignored. Returns the number of items imported.
+//
+// The whole archive is read and validated before the realm is modified: an
+// invalid archive returns an InvalidArgument error and leaves the realm as is.
+// Items are then written in batches of separate transactions, after the delete
+// of a replace is committed: a failed write may leave the realm partially
+// restored, or empty, and the import can be run again to restore it.
+func Import(ctx context.Context, store storage.Store, datatypes []migrate.Datatype, realm string, zr *zip.Reader, opts *ImportOptions) (int, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	entries, err := load(zr, datatypes, realm, opts.Validate)
+	if err != nil {
+		return 0, err
+	}
+
+	if opts.Replace {
+		if err := deleteItems(store, datatypes, realm); err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	for len(entries) > 0 {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		n := batchSize
+		if n > len(entries) {
+			n = len(entries)
+		}
+		if err := importBatch(store, realm, entries[:n]); err != nil {
+			return count, err
+		}
+		count += n
+		entries = entries[n:]
+	}
+	return count, nil
+}
+
+func deleteItems(store storage.Store, datatypes []migrate.Datatype, realm string) (ferr error) {
+	tx, err := store.Tx(true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := tx.Finish()
+		if ferr == nil {
+			ferr = err
+		}
+	}()
+	for _, dt := range datatypes {
+		if err := store.MultiDeleteHistoryTx(dt.Name, realm, storage.MatchAllUsers, tx); err != nil {
+			return fmt.Errorf("deleting history of %q: %v", dt.Name, err)
+		}
+		if err := store.MultiDeleteTx(dt.Name, realm, storage.MatchAllUsers, tx); err != nil {
+			return fmt.Errorf("deleting %q: %v", dt.Name, err)
+		}
+	}
+	return nil
+}
+
+// entry is an item read from an archive.
+type entry struct {
+	item      *Item
+	content   proto.Message
+	revisions []*revision
+}
+
+// revision is a revision of an item read from an archive.
+type revision struct {
+	content proto.Message
+	history *cpb.HistoryEntry
+}
+
+// load reads the items of the datatypes in a zip archive and validates them
+// for an import into the realm.
+func load(zr *zip.Reader, datatypes []migrate.Datatype, realm string, validate func(string, string, proto.Message) error) ([]*entry, error) {
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	mf, ok := files[ManifestName]
+	if !ok {
+		return nil, invalidArchive("%s not found", ManifestName)
+	}
+	b, err := readFile(mf)
+	if err != nil {
+		return nil, invalidArchive("%v", err)
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, invalidArchive("manifest: %v", err)
+	}
+	if m.Version != manifestVersion {
+		return nil, invalidArchive("unsupported version %d", m.Version)
+	}
+
+	types := map[string]migrate.Datatype{}
+	for _, dt := range datatypes {
+		types[dt.Name] = dt
+	}
+	a := &archive{files: files, realm: m.Realm}
+	var entries []*entry
+	for _, item := range m.Items {
+		dt, ok := types[item.Datatype]
+		if !ok {
+			continue
+		}
+		e, err := a.readEntry(dt, item)
+		if err != nil {
+			return nil, err
+		}
+		if validate != nil {
+			// Validation may normalize the content, the archive is restored as is.
+			if err := validate(realm, item.Datatype, proto.Clone(e.content)); err != nil {
+				return nil, invalidArchive("%s %q: %v", item.Datatype, item.ID, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func importBatch(store storage.Store, realm string, entries []*entry) (ferr error) {
+	tx, err := store.Tx(true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if ferr != nil {
+			// Do not commit part of a batch.
+			tx.Rollback()
+			return
+		}
+		err := tx.Finish()
+		if ferr == nil {
+			ferr = err
+		}
+	}()
+
+	for _, e := range entries {
+		item := e.item
+		for _, rev := range e.revisions {
+			if err := store.WriteTx(item.Datatype, realm, item.User, item.ID, rev.history.Revision, rev.content, rev.history, tx); err != nil {
+				return fmt.Errorf("writing %q %q revision %d: %v", item.Datatype, item.ID, rev.history.Revision, err)
+			}
+		}
+		if err := store.WriteTx(item.Datatype, realm, item.User, item.ID, storage.LatestRev, e.content, nil, tx); err != nil {
+			return fmt.Errorf("writing %q %q: %v", item.Datatype, item.ID, err)
+		}
+	}
+	return nil
+}
+
+// invalidArchive returns an InvalidArgument error about the content of an archive.
+func invalidArchive(format string, args ...interface{}) error {
+	return status.Errorf(codes.InvalidArgument, "invalid archive: "+format, args...)
+}
+
+// archive reads the items of a zip archive.
+type archive struct {
+	files map[string]*zip.File
+	realm string
+}
+
+func (a *archive) readEntry(dt migrate.Datatype, item *Item) (*entry, error) {
+	e := &entry{item: item, content: proto.Clone(dt.Type)}
+	history := map[int64]*cpb.HistoryEntry{}
+	if item.History {
+		his, err := a.readHistory(item)
+		if err != nil {
+			return nil, err
+		}
+		for _, he := range his.History {
+			history[he.Revision] = he
+		}
+	}
+	for _, rev := range item.Revisions {
+		he, ok := history[rev]
+		if !ok {
+			return nil, invalidArchive("%s %q revision %d has no history entry", item.Datatype, item.ID, rev)
+		}
+		content := proto.Clone(dt.Type)
+		if err := a.readProto(fileName(item.Datatype, a.realm, item.User, item.ID, fmt.Sprintf("%06d", rev)), content); err != nil {
+			return nil, err
+		}
+		e.revisions = append(e.revisions, &revision{content: content, history: he})
+	}
+	if err := a.readProto(fileName(item.Datatype, a.realm, item.User, item.ID, storage.LatestRevName), e.content); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (a *archive) readProto(name string, content proto.Message) error {
+	f, ok := a.files[name]
+	if !ok {
+		return invalidArchive("%s not found", name)
+	}
+	b, err := readFile(f)
+	if err != nil {
+		return invalidArchive("%v", err)
+	}
+	content.Reset()
+	if err := jsonpb.Unmarshal(bytes.NewReader(b), content); err != nil {
+		return invalidArchive("file %s: %v", name, err)
+	}
+	return nil
+}
+
+func (a *archive) readHistory(item *Item) (*cpb.History, error) {
+	name := fileName(item.Datatype, a.realm, item.User, item.ID, storage.HistoryRevName)
+	f, ok := a.files[name]
+	if !ok {
+		return nil, invalidArchive("%s not found", name)
+	}
+	b, err := readFile(f)
+	if err != nil {
+		return nil, invalidArchive("%v", err)
+	}
+	his := &cpb.History{}
+	full := `{"history":[` + string(b) + "]}"
+	if err := jsonpb.UnmarshalString(full, his); err != nil {
+		return nil, invalidArchive("file %s: %v", name, err)
+	}
+	return his, nil
+}
+
+// fileName returns the name of a file like storage.FileStorage.
+func fileName(datatype, realm, user, id, rev string) string {
+	return fmt.Sprintf("%s_%s%s_%s_%s.json", datatype, realm, storage.UserFragment(user), id, rev)
+}
+
+func writeProto(zw *zip.Writer, name string, content proto.Message) error {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, content); err != nil {
+		return fmt.Errorf("encoding %s: %v", name, err)
+	}
+	return writeFile(zw, name, buf.Bytes())
+}
+
+func writeFile(zw *zip.Writer, name string, b []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	return err
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("reading archive file %s: %v", f.Name, err)
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,315 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"google.golang.org/protobuf/testing/protocmp" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakestore" /* copybara-comment: fakestore */
+
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+	dpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+func newStore(t *testing.T) storage.Store {
+	t.Helper()
+	s := fakestore.New()
+	for rev := int64(1); rev <= 2; rev++ {
+		cfg := &dpb.DamConfig{Revision: rev}
+		he := &cpb.HistoryEntry{Revision: rev, Desc: "update"}
+		if err := s.Write(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, rev, cfg, he); err != nil {
+			t.Fatalf("Write(config) failed: %v", err)
+		}
+	}
+	acct := &cpb.Account{Properties: &cpb.AccountProperties{Subject: "alice"}}
+	if err := s.Write(storage.AccountDatatype, "test", storage.DefaultUser, "alice", storage.LatestRev, acct, nil); err != nil {
+		t.Fatalf("Write(account) failed: %v", err)
+	}
+	if err := s.Write(storage.AccountDatatype, "other", storage.DefaultUser, "bob", storage.LatestRev, acct, nil); err != nil {
+		t.Fatalf("Write(account) failed: %v", err)
+	}
+	// Login states are not included in a backup.
+	if err := s.Write(storage.LoginStateDatatype, "test", storage.DefaultUser, "state", storage.LatestRev, &cpb.LoginState{}, nil); err != nil {
+		t.Fatalf("Write(login_state) failed: %v", err)
+	}
+	return s
+}
+
+func export(t *testing.T, s storage.Store, realm string) *zip.Reader {
+	t.Helper()
+	datatypes, err := Datatypes("dam")
+	if err != nil {
+		t.Fatalf("Datatypes() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Export(context.Background(), s, datatypes, realm, &buf); err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() failed: %v", err)
+	}
+	return zr
+}
+
+func TestExport(t *testing.T) {
+	zr := export(t, newStore(t), "test")
+
+	var got []string
+	for _, f := range zr.File {
+		got = append(got, f.Name)
+	}
+	want := []string{
+		"config_test_main_latest.json",
+		"config_test_main_000001.json",
+		"config_test_main_000002.json",
+		"config_test_main_history.json",
+		"account_test_alice_latest.json",
+		ManifestName,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Export() files diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestImport(t *testing.T) {
+	zr := export(t, newStore(t), "test")
+	datatypes, err := Datatypes("dam")
+	if err != nil {
+		t.Fatalf("Datatypes() failed: %v", err)
+	}
+
+	// Import into another realm of a new store.
+	s := fakestore.New()
+	n, err := Import(context.Background(), s, datatypes, "restored", zr, nil)
+	if err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Import() = %d, want 2", n)
+	}
+
+	tests := []struct {
+		datatype string
+		id       string
+		rev      int64
+		want     proto.Message
+	}{
+		{datatype: storage.ConfigDatatype, id: storage.DefaultID, rev: storage.LatestRev, want: &dpb.DamConfig{Revision: 2}},
+		{datatype: storage.ConfigDatatype, id: storage.DefaultID, rev: 1, want: &dpb.DamConfig{Revision: 1}},
+		{datatype: storage.AccountDatatype, id: "alice", rev: storage.LatestRev, want: &cpb.Account{Properties: &cpb.AccountProperties{Subject: "alice"}}},
+	}
+	for _, tc := range tests {
+		got := proto.Clone(tc.want)
+		got.Reset()
+		if err := s.Read(tc.datatype, "restored", storage.DefaultUser, tc.id, tc.rev, got); err != nil {
+			t.Fatalf("Read(%s, %s, %d) failed: %v", tc.datatype, tc.id, tc.rev, err)
+		}
+		if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Read(%s, %s, %d) diff (-want +got):\n%s", tc.datatype, tc.id, tc.rev, diff)
+		}
+	}
+
+	var history []proto.Message
+	if err := s.ReadHistory(storage.ConfigDatatype, "restored", storage.DefaultUser, storage.DefaultID, &history); err != nil {
+		t.Fatalf("ReadHistory() failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("ReadHistory() returned %d entries, want 2", len(history))
+	}
+}
+
+func TestImport_Replace(t *testing.T) {
+	s := newStore(t)
+	zr := export(t, s, "test")
+	datatypes, err := Datatypes("dam")
+	if err != nil {
+		t.Fatalf("Datatypes() failed: %v", err)
+	}
+
+	// Changes after the backup are removed by the restore.
+	if err := s.Write(storage.AccountDatatype, "test", storage.DefaultUser, "carol", storage.LatestRev, &cpb.Account{}, nil); err != nil {
+		t.Fatalf("Write(account) failed: %v", err)
+	}
+	if err := s.Write(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, 3, &dpb.DamConfig{Revision: 3}, &cpb.HistoryEntry{Revision: 3}); err != nil {
+		t.Fatalf("Write(config) failed: %v", err)
+	}
+	if _, err := Import(context.Background(), s, datatypes, "test", zr, &ImportOptions{Replace: true}); err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	checkRestored(t, s)
+
+	if ok, err := s.Exists(storage.AccountDatatype, "test", storage.DefaultUser, "carol", storage.LatestRev); err != nil || ok {
+		t.Errorf("Exists(account carol) = %v, %v, want false", ok, err)
+	}
+	if ok, err := s.Exists(storage.AccountDatatype, "test", storage.DefaultUser, "alice", storage.LatestRev); err != nil || !ok {
+		t.Errorf("Exists(account alice) = %v, %v, want true", ok, err)
+	}
+	// Other realms and datatypes are kept.
+	if ok, err := s.Exists(storage.AccountDatatype, "other", storage.DefaultUser, "bob", storage.LatestRev); err != nil || !ok {
+		t.Errorf("Exists(account bob) = %v, %v, want true", ok, err)
+	}
+	if ok, err := s.Exists(storage.LoginStateDatatype, "test", storage.DefaultUser, "state", storage.LatestRev); err != nil || !ok {
+		t.Errorf("Exists(login_state) = %v, %v, want true", ok, err)
+	}
+}
+
+// failingStore fails the writes of accounts while fail is set.
+type failingStore struct {
+	storage.Store
+	fail bool
+}
+
+func (s *failingStore) WriteTx(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message, tx storage.Tx) error {
+	if s.fail && datatype == storage.AccountDatatype {
+		return status.Errorf(codes.Unavailable, "unavailable")
+	}
+	return s.Store.WriteTx(datatype, realm, user, id, rev, content, history, tx)
+}
+
+func TestImport_Replace_Rerun(t *testing.T) {
+	s := &failingStore{Store: newStore(t)}
+	zr := export(t, s, "test")
+	datatypes, err := Datatypes("dam")
+	if err != nil {
+		t.Fatalf("Datatypes() failed: %v", err)
+	}
+	if err := s.Write(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, 3, &dpb.DamConfig{Revision: 3}, &cpb.HistoryEntry{Revision: 3}); err != nil {
+		t.Fatalf("Write(config) failed: %v", err)
+	}
+
+	// A failed import leaves the realm partially restored.
+	s.fail = true
+	if _, err := Import(context.Background(), s, datatypes, "test", zr, &ImportOptions{Replace: true}); err == nil {
+		t.Fatalf("Import() succeeded, want error")
+	}
+	if ok, err := s.Exists(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, storage.LatestRev); err != nil || ok {
+		t.Errorf("Exists(config) after failed import = %v, %v, want false", ok, err)
+	}
+
+	// Running the import again restores it.
+	s.fail = false
+	if _, err := Import(context.Background(), s, datatypes, "test", zr, &ImportOptions{Replace: true}); err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	checkRestored(t, s)
+}
+
+// checkRestored checks that the realm "test" of the store is in the state of newStore.
+func checkRestored(t *testing.T, s storage.Store) {
+	t.Helper()
+	cfg := &dpb.DamConfig{}
+	if err := s.Read(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, storage.LatestRev, cfg); err != nil {
+		t.Fatalf("Read(config) failed: %v", err)
+	}
+	if cfg.Revision != 2 {
+		t.Errorf("Read(config) revision = %d, want 2", cfg.Revision)
+	}
+	if err := s.Read(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, 3, &dpb.DamConfig{}); !storage.ErrNotFound(err) {
+		t.Errorf("Read(config revision 3) = %v, want not found error", err)
+	}
+	var history []proto.Message
+	if err := s.ReadHistory(storage.ConfigDatatype, "test", storage.DefaultUser, storage.DefaultID, &history); err != nil {
+		t.Fatalf("ReadHistory() failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("ReadHistory() returned %d entries, want 2", len(history))
+	}
+	if ok, err := s.Exists(storage.AccountDatatype, "test", storage.DefaultUser, "alice", storage.LatestRev); err != nil || !ok {
+		t.Errorf("Exists(account alice) = %v, %v, want true", ok, err)
+	}
+}
+
+func TestImport_InvalidArchive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip.Writer.Close() failed: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() failed: %v", err)
+	}
+	if _, err := Import(context.Background(), fakestore.New(), nil, "test", zr, nil); err == nil {
+		t.Errorf("Import() of an archive without manifest succeeded, want error")
+	}
+}
+
+func TestImport_ValidateBeforeReplace(t *testing.T) {
+	s := newStore(t)
+	zr := export(t, s, "test")
+	datatypes, err := Datatypes("dam")
+	if err != nil {
+		t.Fatalf("Datatypes() failed: %v", err)
+	}
+
+	validate := func(realm, datatype string, content proto.Message) error {
+		if realm != "test" {
+			t.Errorf("Validate() realm = %q, want %q", realm, "test")
+		}
+		if datatype == storage.ConfigDatatype {
+			return fmt.Errorf("invalid config")
+		}
+		return nil
+	}
+	if err := s.Write(storage.AccountDatatype, "test", storage.DefaultUser, "carol", storage.LatestRev, &cpb.Account{}, nil); err != nil {
+		t.Fatalf("Write(account) failed: %v", err)
+	}
+	_, err = Import(context.Background(), s, datatypes, "test", zr, &ImportOptions{Replace: true, Validate: validate})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Import() = %v, want InvalidArgument error", err)
+	}
+
+	// Nothing has been deleted.
+	if ok, err := s.Exists(storage.AccountDatatype, "test", storage.DefaultUser, "carol", storage.LatestRev); err != nil || !ok {
+		t.Errorf("Exists(account carol) = %v, %v, want true", ok, err)
+	}
+}
+
+func TestImport_MissingRevision(t *testing.T) {
+	zr := export(t, newStore(t), "test")
+	datatypes, err := Datatypes("dam")
+	if err != nil {
+		t.Fatalf("Datatypes() failed: %v", err)
+	}
+
+	// Drop the content of a revision listed in the manifest.
+	var files []*zip.File
+	for _, f := range zr.File {
+		if f.Name != "config_test_main_000001.json" {
+			files = append(files, f)
+		}
+	}
+	zr.File = files
+
+	s := newStore(t)
+	_, err = Import(context.Background(), s, datatypes, "test", zr, &ImportOptions{Replace: true})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Import() = %v, want InvalidArgument error", err)
+	}
+	if ok, err := s.Exists(storage.AccountDatatype, "test", storage.DefaultUser, "alice", storage.LatestRev); err != nil || !ok {
+		t.Errorf("Exists(account alice) = %v, %v, want true", ok, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux" /* copybara-comment */
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+)
+
+const (
+	// MaxArchiveSize is the maximum size of an archive uploaded to restore a realm.
+	MaxArchiveSize = 64 << 20
+)
+
+// Handler exports and restores the realm of the "{realm}" path variable of
+// requests.
+type Handler struct {
+	// ServiceType is the type of the service, "dam" or "ic".
+	ServiceType string
+	// ServiceName is the name of the service, used to name exported archives.
+	ServiceName string
+	Store       storage.Store
+	// Validate checks the items of an archive before a restore, see ImportOptions.
+	Validate func(realm, datatype string, content proto.Message) error
+	// Restored is called after a realm has been restored, e.g. to sync clients.
+	Restored func(realm string) error
+}
+
+// Export returns a zip archive of the items of a realm.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	datatypes, err := Datatypes(h.ServiceType)
+	if err != nil {
+		httputils.WriteError(w, status.Errorf(codes.Internal, "%v", err))
+		return
+	}
+	realm := getRealm(r)
+	var buf bytes.Buffer
+	if err := Export(r.Context(), h.Store, datatypes, realm, &buf); err != nil {
+		httputils.WriteError(w, status.Errorf(codes.Unavailable, "exporting realm %q: %v", realm, err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", h.ServiceName+"_"+realm+".zip"))
+	w.Write(buf.Bytes())
+}
+
+// Restore imports the items of a zip archive created by Export into a realm.
+// If the "replace" query parameter is true, the items of the realm are deleted
+// first, once the archive has been validated. A failed restore may leave the
+// realm partially restored and can be retried.
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	replace := false
+	if v := httputils.QueryParam(r, "replace"); len(v) > 0 {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			httputils.WriteError(w, status.Errorf(codes.InvalidArgument, "invalid replace parameter %q", v))
+			return
+		}
+		replace = b
+	}
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxArchiveSize))
+	if err != nil {
+		httputils.WriteError(w, status.Errorf(codes.InvalidArgument, "reading archive: %v", err))
+		return
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		httputils.WriteError(w, status.Errorf(codes.InvalidArgument, "invalid archive: %v", err))
+		return
+	}
+	datatypes, err := Datatypes(h.ServiceType)
+	if err != nil {
+		httputils.WriteError(w, status.Errorf(codes.Internal, "%v", err))
+		return
+	}
+	realm := getRealm(r)
+	if _, err := Import(r.Context(), h.Store, datatypes, realm, zr, &ImportOptions{Replace: replace, Validate: h.Validate}); err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			httputils.WriteError(w, err)
+			return
+		}
+		httputils.WriteError(w, status.Errorf(codes.Unavailable, "restoring realm %q: %v", realm, err))
+		return
+	}
+
+	if h.Restored != nil {
+		if err := h.Restored(realm); err != nil {
+			httputils.WriteError(w, err)
+			return
+		}
+	}
+}
+
+func getRealm(r *http.Request) string {
+	if realm, ok := mux.Vars(r)["realm"]; ok && len(realm) > 0 {
+		return realm
+	}
+	return storage.DefaultRealm
+}
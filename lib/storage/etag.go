@@ -0,0 +1,68 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+
+	protov2 "google.golang.org/protobuf/proto" /* copybara-comment */
+)
+
+const (
+	// AnyETag matches any existing item in WriteIfMatchTx.
+	AnyETag = "*"
+)
+
+// ETag returns the entity tag of the content of an item: a hash of its
+// deterministic encoding. Equal content always has the same etag.
+func ETag(content proto.Message) string {
+	b, err := protov2.MarshalOptions{Deterministic: true}.Marshal(proto.MessageV2(content))
+	if err != nil {
+		// Should not happen for messages read from or written to storage.
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:16])
+}
+
+// CheckETagTx checks the etag of the latest revision of an item inside a
+// transaction as needed by WriteIfMatchTx implementations. An empty etag
+// requires the item to not exist and AnyETag requires it to exist.
+// Returns an errutil.PreconditionFailed error if the check fails.
+func CheckETagTx(s Store, datatype, realm, user, id, etag string, typ proto.Message, tx Tx) error {
+	current := proto.Clone(typ)
+	current.Reset()
+	err := s.ReadTx(datatype, realm, user, id, LatestRev, current, tx)
+	if err != nil && !ErrNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	switch {
+	case etag == "" && exists:
+		return errutil.NewPreconditionFailedError(fmt.Sprintf("%s %q already exists", datatype, id))
+	case etag == "":
+		return nil
+	case !exists:
+		return errutil.NewPreconditionFailedError(fmt.Sprintf("%s %q does not exist", datatype, id))
+	case etag != AnyETag && etag != ETag(current):
+		return errutil.NewPreconditionFailedError(fmt.Sprintf("%s %q has been modified", datatype, id))
+	}
+	return nil
+}
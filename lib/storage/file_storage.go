@@ -192,6 +192,11 @@ func (f *FileStorage) WriteTx(datatype, realm, user, id string, rev int64, conte
 	return fmt.Errorf("file storage does not support WriteTx")
 }
 
+// WriteIfMatchTx writes a record with transaction if the etag matches.
+func (f *FileStorage) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx Tx) error {
+	return fmt.Errorf("file storage does not support WriteIfMatchTx")
+}
+
 // Delete a record.
 func (f *FileStorage) Delete(datatype, realm, user, id string, rev int64) error {
 	return fmt.Errorf("file storage does not support Delete")
@@ -207,6 +212,11 @@ func (f *FileStorage) MultiDeleteTx(datatype, realm, user string, tx Tx) error {
 	return fmt.Errorf("file storage does not support MultiDeleteTx")
 }
 
+// MultiDeleteHistoryTx deletes the history of all records of a certain data type within a realm.
+func (f *FileStorage) MultiDeleteHistoryTx(datatype, realm, user string, tx Tx) error {
+	return fmt.Errorf("file storage does not support MultiDeleteHistoryTx")
+}
+
 // Wipe deletes all records within a realm.
 func (f *FileStorage) Wipe(ctx context.Context, realm string, batchNum, maxEntries int) (int, error) {
 	return 0, fmt.Errorf("file storage does not support Wipe")
@@ -226,6 +236,16 @@ func (f *FileStorage) LockTx(lockName string, minFrequency time.Duration, tx Tx)
 	return nil
 }
 
+// Watch returns a channel that never receives changes since file storage is read-only.
+func (f *FileStorage) Watch(ctx context.Context, datatype, realm string) (<-chan *Change, error) {
+	ch := make(chan *Change)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
 type FileTx struct {
 	writer bool
 }
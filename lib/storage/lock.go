@@ -0,0 +1,39 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Locker provides named locks shared by the instances of a service, for
+// stores without locks across instances. See lib/lockstore.
+type Locker interface {
+	// Lock acquires the named lock for at most ttl, unless it was acquired less
+	// than minFrequency ago. Returns a nil Lease if the lock is not available.
+	Lock(ctx context.Context, name string, ttl, minFrequency time.Duration) (Lease, error)
+}
+
+// Lease is a lock held until it is released or its ttl expires.
+type Lease interface {
+	// Token is the fencing token of the lease. The tokens of a lock increase
+	// with each acquisition, so that a resource can reject the writes of a
+	// holder whose lease expired and was taken over by another.
+	Token() int64
+	// Release releases the lock. Returns a FailedPrecondition error if the
+	// lease had already expired.
+	Release(ctx context.Context) error
+}
@@ -21,12 +21,15 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"time"
 
 	"google.golang.org/grpc/codes" /* copybara-comment */
 	"google.golang.org/grpc/status" /* copybara-comment */
 	"github.com/golang/protobuf/jsonpb" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
+
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
 )
 
 const (
@@ -45,6 +48,7 @@ type MemoryStorage struct {
 	wipedRealms map[string]bool
 	lock        chan bool
 	lastLock    time.Time
+	hub         *ChangeHub
 }
 
 func NewMemoryStorage(service, path string) *MemoryStorage {
@@ -57,6 +61,7 @@ func NewMemoryStorage(service, path string) *MemoryStorage {
 		wipedRealms: make(map[string]bool),
 		lock:        make(chan bool, 1),
 		lastLock:    time.Unix(0, 0),
+		hub:         NewChangeHub(),
 	}
 }
 
@@ -74,7 +79,7 @@ func (m *MemoryStorage) Exists(datatype, realm, user, id string, rev int64) (boo
 	if _, ok := m.cache.GetEntity(fname); ok {
 		return true, nil
 	}
-	if m.deleted[fname] || m.wipedRealms[realm] {
+	if m.deleted[fname] || m.isWiped(realm) {
 		return false, nil
 	}
 	return m.fs.Exists(datatype, realm, user, id, rev)
@@ -107,7 +112,7 @@ func (m *MemoryStorage) ReadTx(datatype, realm, user, id string, rev int64, cont
 		return nil
 	}
 
-	if m.deleted[fname] || m.wipedRealms[realm] {
+	if m.deleted[fname] || m.isWiped(realm) {
 		return fmt.Errorf("not found: %q", fname)
 	}
 
@@ -139,8 +144,11 @@ func (m *MemoryStorage) MultiReadTx(datatype, realm, user, id string, filters []
 		pageSize = MaxPageSize
 	}
 	results := NewResults()
-	err := m.findPath(datatype, realm, user, id, typ, func(path, userMatch, idMatch string, p proto.Message) error {
-		if m.deleted[m.fname(datatype, realm, userMatch, idMatch, LatestRev)] || m.wipedRealms[realm] {
+	err := m.findPath(datatype, realm, user, id, typ, func(path, realmMatch, userMatch, idMatch string, p proto.Message) error {
+		if m.deleted[m.fname(datatype, realmMatch, userMatch, idMatch, LatestRev)] {
+			return nil
+		}
+		if _, ok := m.cache.GetEntity(path); !ok && m.isWiped(realmMatch) {
 			return nil
 		}
 		if id != MatchAllIDs && idMatch != id {
@@ -155,7 +163,7 @@ func (m *MemoryStorage) MultiReadTx(datatype, realm, user, id string, filters []
 		}
 		if pageSize > results.MatchCount {
 			results.Entries = append(results.Entries, &Entry{
-				Realm:   realm,
+				Realm:   realmMatch,
 				GroupID: userMatch,
 				ItemID:  idMatch,
 				Item:    p,
@@ -167,14 +175,16 @@ func (m *MemoryStorage) MultiReadTx(datatype, realm, user, id string, filters []
 	return results, err
 }
 
-func (m *MemoryStorage) findPath(datatype, realm, user, id string, typ proto.Message, fn func(string, string, string, proto.Message) error) error {
+// findPath calls fn for each file or cached entity matching the given key parts,
+// files first and then cached entities, each in path order.
+func (m *MemoryStorage) findPath(datatype, realm, user, id string, typ proto.Message, fn func(path, realm, user, id string, p proto.Message) error) error {
 	searchUser := user
 	if user == MatchAllUsers {
 		searchUser = "(.*)"
 	} else {
 		searchUser = "(" + user + ")"
 	}
-	searchRealm := realm
+	searchRealm := "(" + realm + ")"
 	if realm == AllRealms {
 		searchRealm = "(.*)"
 	}
@@ -189,7 +199,7 @@ func (m *MemoryStorage) findPath(datatype, realm, user, id string, typ proto.Mes
 	if err != nil {
 		return fmt.Errorf("file extract ID %q regexp error: %v", extractID, err)
 	}
-	defaultUserID := m.fs.fname(datatype, realm, DefaultUser, searchID, LatestRev)
+	defaultUserID := m.fs.fname(datatype, searchRealm, DefaultUser, searchID, LatestRev)
 	dure, err := regexp.Compile(defaultUserID)
 	if err != nil {
 		return fmt.Errorf("file extract ID %q regexp error: %v", defaultUserID, err)
@@ -204,7 +214,7 @@ func (m *MemoryStorage) findPath(datatype, realm, user, id string, typ proto.Mes
 	return extractFromCache(re, dure, user, cached, fn)
 }
 
-func extractFromPath(re, dure *regexp.Regexp, user, path string, info os.FileInfo, err error, typ proto.Message, cached map[string]proto.Message, fn func(string, string, string, proto.Message) error) error {
+func extractFromPath(re, dure *regexp.Regexp, user, path string, info os.FileInfo, err error, typ proto.Message, cached map[string]proto.Message, fn func(string, string, string, string, proto.Message) error) error {
 	if err != nil {
 		return err
 	}
@@ -214,7 +224,7 @@ func extractFromPath(re, dure *regexp.Regexp, user, path string, info os.FileInf
 	if _, ok := cached[path]; ok {
 		return nil
 	}
-	userMatch, idMatch := extractUserAndID(re, dure, user, path)
+	realmMatch, userMatch, idMatch := extractRealmUserAndID(re, dure, user, path)
 	if userMatch == "" && idMatch == "" {
 		return nil
 	}
@@ -230,33 +240,39 @@ func extractFromPath(re, dure *regexp.Regexp, user, path string, info os.FileInf
 			return fmt.Errorf("file %q invalid JSON: %v", path, err)
 		}
 	}
-	return fn(path, userMatch, idMatch, p)
+	return fn(path, realmMatch, userMatch, idMatch, p)
 }
 
-func extractUserAndID(re, dure *regexp.Regexp, user, path string) (string, string) {
+func extractRealmUserAndID(re, dure *regexp.Regexp, user, path string) (string, string, string) {
 	matches := re.FindStringSubmatch(path)
-	if len(matches) == 3 {
-		return matches[1], matches[2]
+	if len(matches) == 4 {
+		return matches[1], matches[2], matches[3]
 	}
 	if user == DefaultUser {
 		matches = dure.FindStringSubmatch(path)
-		if len(matches) == 2 {
-			return DefaultUser, matches[1]
+		if len(matches) == 3 {
+			return matches[1], DefaultUser, matches[2]
 		}
 	}
-	return "", ""
+	return "", "", ""
 }
 
-func extractFromCache(re, dure *regexp.Regexp, user string, cached map[string]proto.Message, fn func(string, string, string, proto.Message) error) error {
-	for path, content := range cached {
+func extractFromCache(re, dure *regexp.Regexp, user string, cached map[string]proto.Message, fn func(string, string, string, string, proto.Message) error) error {
+	// Visit the cache in a stable order to support pagination.
+	var paths []string
+	for path := range cached {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
 		if !re.MatchString(path) && !dure.MatchString(path) {
 			continue
 		}
-		userMatch, idMatch := extractUserAndID(re, dure, user, path)
+		realmMatch, userMatch, idMatch := extractRealmUserAndID(re, dure, user, path)
 		if userMatch == "" && idMatch == "" {
 			continue
 		}
-		if err := fn(path, userMatch, idMatch, content); err != nil {
+		if err := fn(path, realmMatch, userMatch, idMatch, cached[path]); err != nil {
 			return err
 		}
 	}
@@ -324,7 +340,9 @@ func (m *MemoryStorage) WriteTx(datatype, realm, user, id string, rev int64, con
 		return err
 	}
 
-	hlist = append(hlist, history)
+	if history != nil {
+		hlist = append(hlist, history)
+	}
 	hfname := m.historyName(datatype, realm, user, id)
 	m.cache.PutHistory(hfname, hlist)
 
@@ -338,10 +356,33 @@ func (m *MemoryStorage) WriteTx(datatype, realm, user, id string, rev int64, con
 	if _, ok := m.deleted[lname]; ok {
 		delete(m.deleted, lname)
 	}
+	m.record(tx, &Change{Datatype: datatype, Realm: realm, User: user, ID: id, Rev: rev, Op: ChangeWrite})
 
 	return nil
 }
 
+// WriteIfMatchTx writes inside a transaction if the etag of the item matches.
+func (m *MemoryStorage) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = m.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	if err := CheckETagTx(m, datatype, realm, user, id, etag, content, tx); err != nil {
+		return err
+	}
+	return m.WriteTx(datatype, realm, user, id, rev, content, history, tx)
+}
+
 // Delete a record.
 func (m *MemoryStorage) Delete(datatype, realm, user, id string, rev int64) error {
 	return m.DeleteTx(datatype, realm, user, id, rev, nil)
@@ -375,6 +416,7 @@ func (m *MemoryStorage) DeleteTx(datatype, realm, user, id string, rev int64, tx
 	m.cache.DeleteEntity(lname)
 	m.deleted[vname] = true
 	m.deleted[lname] = true
+	m.record(tx, &Change{Datatype: datatype, Realm: realm, User: user, ID: id, Rev: rev, Op: ChangeDelete})
 	return nil
 }
 
@@ -394,8 +436,43 @@ func (m *MemoryStorage) MultiDeleteTx(datatype, realm, user string, tx Tx) (ferr
 		}()
 	}
 
-	return m.findPath(datatype, realm, user, MatchAllIDs, nil, func(path, userMatch, idMatch string, p proto.Message) error {
-		return m.DeleteTx(datatype, realm, userMatch, idMatch, LatestRev, tx)
+	return m.findPath(datatype, realm, user, MatchAllIDs, nil, func(path, realmMatch, userMatch, idMatch string, p proto.Message) error {
+		return m.DeleteTx(datatype, realmMatch, userMatch, idMatch, LatestRev, tx)
+	})
+}
+
+// MultiDeleteHistoryTx deletes the history and old revisions of all records of a certain data type within a realm.
+func (m *MemoryStorage) MultiDeleteHistoryTx(datatype, realm, user string, tx Tx) (ferr error) {
+	if tx == nil {
+		var err error
+		tx, err = m.fs.Tx(false)
+		if err != nil {
+			return fmt.Errorf("file read lock error: %v", err)
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	return m.findPath(datatype, realm, user, MatchAllIDs, nil, func(path, realmMatch, userMatch, idMatch string, p proto.Message) error {
+		var history []proto.Message
+		if err := m.ReadHistoryTx(datatype, realmMatch, userMatch, idMatch, &history, tx); err != nil && !ErrNotFound(err) {
+			return err
+		}
+		for _, h := range history {
+			he, ok := h.(*cpb.HistoryEntry)
+			if !ok {
+				continue
+			}
+			vname := m.fname(datatype, realmMatch, userMatch, idMatch, he.Revision)
+			m.cache.DeleteEntity(vname)
+			m.deleted[vname] = true
+		}
+		m.cache.PutHistory(m.historyName(datatype, realmMatch, userMatch, idMatch), []proto.Message{})
+		return nil
 	})
 }
 
@@ -408,6 +485,26 @@ func (m *MemoryStorage) Wipe(ctx context.Context, realm string, batchNum, maxEnt
 	return count, nil
 }
 
+// Watch returns a channel receiving the changes committed after the call.
+func (m *MemoryStorage) Watch(ctx context.Context, datatype, realm string) (<-chan *Change, error) {
+	return m.hub.Watch(ctx, datatype, realm), nil
+}
+
+// record adds a change to be published when the transaction finishes.
+func (m *MemoryStorage) record(tx Tx, change *Change) {
+	mtx, ok := tx.(*MemTx)
+	if !ok {
+		m.hub.Publish(change)
+		return
+	}
+	mtx.changes = append(mtx.changes, change)
+}
+
+// isWiped returns true if file-based content for the realm has been hidden by Wipe.
+func (m *MemoryStorage) isWiped(realm string) bool {
+	return m.wipedRealms[realm] || m.wipedRealms[AllRealms]
+}
+
 func (m *MemoryStorage) Tx(update bool) (Tx, error) {
 	select {
 	case m.lock <- true:
@@ -440,8 +537,9 @@ func (m *MemoryStorage) LockTx(lockName string, minFrequency time.Duration, tx T
 }
 
 type MemTx struct {
-	update bool
-	ms     *MemoryStorage
+	update  bool
+	ms      *MemoryStorage
+	changes []*Change
 }
 
 // Finish attempts to commit a transaction.
@@ -451,11 +549,14 @@ func (tx *MemTx) Finish() error {
 	default:
 		panic("MAYBE BUG: Releasing a released TX.")
 	}
+	tx.ms.hub.Publish(tx.changes...)
+	tx.changes = nil
 	return nil
 }
 
 // Rollback attempts to rollback a transaction.
 func (tx *MemTx) Rollback() error {
+	tx.changes = nil
 	tx.ms.cache.Restore()
 	tx.ms.fs = NewFileStorage(tx.ms.service, tx.ms.path)
 	return nil
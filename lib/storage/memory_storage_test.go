@@ -18,6 +18,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+
 	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
 )
 
@@ -40,6 +42,31 @@ func TestMemoryStorageDelete(t *testing.T) {
 	}
 }
 
+func TestMemoryStorageMultiDeleteHistory(t *testing.T) {
+	store := NewMemoryStorage("ic-min", "testdata/config")
+	for rev := int64(1); rev <= 2; rev++ {
+		if err := store.Write(testStoreFileType, "test", DefaultUser, testFileID, rev, &cpb.TestPersona{}, &cpb.HistoryEntry{Revision: rev}); err != nil {
+			t.Fatalf("Write(..., %d, ...) failed: %v", rev, err)
+		}
+	}
+	if err := store.MultiDeleteHistoryTx(testStoreFileType, "test", MatchAllUsers, nil); err != nil {
+		t.Fatalf("MultiDeleteHistoryTx() failed: %v", err)
+	}
+	if err := store.Read(testStoreFileType, "test", DefaultUser, testFileID, LatestRev, &cpb.TestPersona{}); err != nil {
+		t.Errorf("reading latest revision: want success, got error: %v", err)
+	}
+	if err := store.Read(testStoreFileType, "test", DefaultUser, testFileID, 1, &cpb.TestPersona{}); err == nil {
+		t.Errorf("reading deleted revision: want error, got success")
+	}
+	var history []proto.Message
+	if err := store.ReadHistory(testStoreFileType, "test", DefaultUser, testFileID, &history); err != nil {
+		t.Fatalf("ReadHistory() failed: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("ReadHistory() returned %d entries, want 0", len(history))
+	}
+}
+
 func TestMemoryStorageMultiRead(t *testing.T) {
 	store := NewMemoryStorage("ic-min", "testdata/config")
 	results, err := store.MultiReadTx(AccountDatatype, "test", MatchAllUsers, MatchAllIDs, nil, 0, 100, &cpb.Account{}, nil)
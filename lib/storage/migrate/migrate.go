@@ -0,0 +1,379 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate copies the items and history of a service from one
+// storage.Store to another.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+	dpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+	ipb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/ic/v1" /* copybara-comment: go_proto */
+	ppb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/process/v1" /* copybara-comment: go_proto */
+	spb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/scim/v2" /* copybara-comment: go_proto */
+	cspb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/store/consents" /* copybara-comment: go_proto */
+	topb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/store/tokens" /* copybara-comment: go_proto */
+)
+
+const (
+	// DefaultBatchSize is the default number of items written per transaction.
+	DefaultBatchSize = 100
+)
+
+// Datatype is a datatype to migrate and the type of its items.
+type Datatype struct {
+	Name string
+	Type proto.Message
+}
+
+var (
+	// commonDatatypes are the datatypes stored by both DAM and IC.
+	// Locks are specific to a store and are not migrated.
+	commonDatatypes = []Datatype{
+		{Name: storage.PermissionsDatatype, Type: &cpb.Permissions{}},
+		{Name: storage.AccountDatatype, Type: &cpb.Account{}},
+		{Name: storage.AccountLookupDatatype, Type: &cpb.AccountLookup{}},
+		{Name: storage.GroupDatatype, Type: &spb.Group{}},
+		{Name: storage.GroupMemberDatatype, Type: &spb.Member{}},
+		{Name: storage.RememberedConsentDatatype, Type: &cspb.RememberedConsentPreference{}},
+		{Name: storage.PendingDeleteTokenDatatype, Type: &topb.PendingDeleteToken{}},
+		{Name: storage.CliAuthDatatype, Type: &cpb.CliState{}},
+		{Name: storage.ProcessDataType, Type: &ppb.Process{}},
+		{Name: storage.LongRunningOperationDatatype, Type: &ppb.Process_Work{}},
+	}
+
+	// DAMDatatypes are the datatypes stored by DAM.
+	DAMDatatypes = append([]Datatype{
+		{Name: storage.ConfigDatatype, Type: &dpb.DamConfig{}},
+		{Name: storage.SecretsDatatype, Type: &dpb.DamSecrets{}},
+		{Name: storage.ResourceTokenRequestStateDataType, Type: &dpb.ResourceTokenRequestState{}},
+	}, commonDatatypes...)
+
+	// ICDatatypes are the datatypes stored by IC.
+	ICDatatypes = append([]Datatype{
+		{Name: storage.ConfigDatatype, Type: &ipb.IcConfig{}},
+		{Name: storage.SecretsDatatype, Type: &ipb.IcSecrets{}},
+		{Name: storage.LoginStateDatatype, Type: &cpb.LoginState{}},
+		{Name: storage.TokensDatatype, Type: &ipb.TokenMetadata{}},
+	}, commonDatatypes...)
+)
+
+// Datatypes returns the datatypes stored by a type of service, "dam" or "ic".
+func Datatypes(serviceType string) ([]Datatype, error) {
+	switch serviceType {
+	case "dam":
+		return DAMDatatypes, nil
+	case "ic":
+		return ICDatatypes, nil
+	default:
+		return nil, fmt.Errorf("unknown service type %q", serviceType)
+	}
+}
+
+// Options contains the parameters of a migration.
+type Options struct {
+	// Datatypes are the datatypes to migrate.
+	Datatypes []Datatype
+	// Realm limits the migration to a realm. Defaults to storage.AllRealms.
+	Realm string
+	// BatchSize is the number of items written per transaction of the
+	// destination. Defaults to DefaultBatchSize.
+	BatchSize int
+	// DryRun reads the source without writing to the destination, which is
+	// not used and may be nil.
+	DryRun bool
+	// CheckpointFile, if set, records the datatypes migrated completely, which
+	// are skipped when the migration is run again after an interruption.
+	CheckpointFile string
+}
+
+// Count contains the number of items of a datatype and their checksum.
+type Count struct {
+	Items    int
+	History  int
+	Checksum string
+}
+
+// Report contains the results of a migration.
+type Report struct {
+	// Copied contains the counts of the source per datatype.
+	Copied map[string]*Count
+	// Skipped is the number of items which were already migrated.
+	Skipped int
+	// MissingRevisions is the number of history entries which could not be
+	// migrated because the source does not keep the content of their revision.
+	MissingRevisions int
+}
+
+// Mismatch describes a datatype with different content in source and destination.
+type Mismatch struct {
+	Datatype string
+	Src      *Count
+	Dst      *Count
+}
+
+func (m *Mismatch) String() string {
+	return fmt.Sprintf("%s: source has %d items, %d history entries, checksum %s; destination has %d items, %d history entries, checksum %s",
+		m.Datatype, m.Src.Items, m.Src.History, m.Src.Checksum, m.Dst.Items, m.Dst.History, m.Dst.Checksum)
+}
+
+// Migrate copies the items of the datatypes and their history entries from src to dst.
+// Revisions of items are copied along with their history entries.
+// Items already in dst with the same content and history are skipped, so an
+// interrupted migration can be run again.
+func Migrate(ctx context.Context, src, dst storage.Store, opts *Options) (*Report, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if batchSize > storage.MaxPageSize {
+		batchSize = storage.MaxPageSize
+	}
+	done, err := readCheckpoint(opts.CheckpointFile)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Copied: map[string]*Count{}}
+	for _, dt := range opts.Datatypes {
+		if done[dt.Name] {
+			glog.Infof("skipping datatype %q: already migrated", dt.Name)
+			continue
+		}
+		count := &Count{}
+		sums := []string{}
+		for offset := 0; ; {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			page, err := src.MultiReadTx(dt.Name, opts.Realm, storage.MatchAllUsers, storage.MatchAllIDs, nil, offset, batchSize, dt.Type, nil)
+			if err != nil {
+				return report, fmt.Errorf("reading %q at offset %d: %v", dt.Name, offset, err)
+			}
+			if err := copyBatch(src, dst, dt, page.Entries, opts.DryRun, report, count, &sums); err != nil {
+				return report, err
+			}
+			offset += len(page.Entries)
+			if len(page.Entries) < batchSize {
+				break
+			}
+		}
+		count.Checksum = checksum(sums)
+		report.Copied[dt.Name] = count
+		glog.Infof("datatype %q: %d items, %d history entries", dt.Name, count.Items, count.History)
+
+		if opts.DryRun {
+			continue
+		}
+		done[dt.Name] = true
+		if err := writeCheckpoint(opts.CheckpointFile, done); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// copyBatch copies entries in a single transaction of dst.
+func copyBatch(src, dst storage.Store, dt Datatype, entries []*storage.Entry, dryRun bool, report *Report, count *Count, sums *[]string) (ferr error) {
+	var tx storage.Tx
+	if !dryRun {
+		var err error
+		tx, err = dst.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	for _, e := range entries {
+		history, err := readHistory(src, dt.Name, e, nil)
+		if err != nil {
+			return err
+		}
+		count.Items++
+		count.History += len(history)
+		*sums = append(*sums, itemSum(e, len(history)))
+		if dryRun {
+			continue
+		}
+
+		same, err := migrated(dst, dt, e, len(history), tx)
+		if err != nil {
+			return err
+		}
+		if same {
+			report.Skipped++
+			continue
+		}
+
+		for _, h := range history {
+			he := h.(*cpb.HistoryEntry)
+			content := proto.Clone(dt.Type)
+			if err := src.ReadTx(dt.Name, e.Realm, e.GroupID, e.ItemID, he.Revision, content, nil); err != nil {
+				if !storage.ErrNotFound(err) {
+					return fmt.Errorf("reading %q %q revision %d: %v", dt.Name, e.ItemID, he.Revision, err)
+				}
+				glog.Warningf("%s %q in realm %q: content of revision %d not found, history entry not migrated", dt.Name, e.ItemID, e.Realm, he.Revision)
+				report.MissingRevisions++
+				continue
+			}
+			if err := dst.WriteTx(dt.Name, e.Realm, e.GroupID, e.ItemID, he.Revision, content, he, tx); err != nil {
+				return fmt.Errorf("writing %q %q revision %d: %v", dt.Name, e.ItemID, he.Revision, err)
+			}
+		}
+		if err := dst.WriteTx(dt.Name, e.Realm, e.GroupID, e.ItemID, storage.LatestRev, e.Item, nil, tx); err != nil {
+			return fmt.Errorf("writing %q %q: %v", dt.Name, e.ItemID, err)
+		}
+	}
+	return nil
+}
+
+// migrated checks if an item is already in dst with the same content and number of history entries.
+func migrated(dst storage.Store, dt Datatype, e *storage.Entry, history int, tx storage.Tx) (bool, error) {
+	current := proto.Clone(dt.Type)
+	if err := dst.ReadTx(dt.Name, e.Realm, e.GroupID, e.ItemID, storage.LatestRev, current, tx); err != nil {
+		if storage.ErrNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if storage.ETag(current) != storage.ETag(e.Item) {
+		return false, nil
+	}
+	h, err := readHistory(dst, dt.Name, e, tx)
+	if err != nil {
+		return false, err
+	}
+	return len(h) == history, nil
+}
+
+// Verify compares the number of items and history entries and the checksum
+// of the items of each datatype in src and dst.
+func Verify(ctx context.Context, src, dst storage.Store, datatypes []Datatype, realm string) ([]*Mismatch, error) {
+	var out []*Mismatch
+	for _, dt := range datatypes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s, err := countItems(src, dt, realm)
+		if err != nil {
+			return nil, fmt.Errorf("counting source %q: %v", dt.Name, err)
+		}
+		d, err := countItems(dst, dt, realm)
+		if err != nil {
+			return nil, fmt.Errorf("counting destination %q: %v", dt.Name, err)
+		}
+		if *s != *d {
+			out = append(out, &Mismatch{Datatype: dt.Name, Src: s, Dst: d})
+		}
+	}
+	return out, nil
+}
+
+func countItems(s storage.Store, dt Datatype, realm string) (*Count, error) {
+	count := &Count{}
+	sums := []string{}
+	for offset := 0; ; {
+		page, err := s.MultiReadTx(dt.Name, realm, storage.MatchAllUsers, storage.MatchAllIDs, nil, offset, storage.MaxPageSize, dt.Type, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page.Entries {
+			history, err := readHistory(s, dt.Name, e, nil)
+			if err != nil {
+				return nil, err
+			}
+			count.Items++
+			count.History += len(history)
+			sums = append(sums, itemSum(e, len(history)))
+		}
+		offset += len(page.Entries)
+		if len(page.Entries) < storage.MaxPageSize {
+			break
+		}
+	}
+	count.Checksum = checksum(sums)
+	return count, nil
+}
+
+func readHistory(s storage.Store, datatype string, e *storage.Entry, tx storage.Tx) ([]proto.Message, error) {
+	var history []proto.Message
+	if err := s.ReadHistoryTx(datatype, e.Realm, e.GroupID, e.ItemID, &history, tx); err != nil && !storage.ErrNotFound(err) {
+		return nil, fmt.Errorf("reading history of %q %q: %v", datatype, e.ItemID, err)
+	}
+	return history, nil
+}
+
+// itemSum returns a line identifying an item, its content and the size of its history.
+func itemSum(e *storage.Entry, history int) string {
+	return fmt.Sprintf("%s/%s/%s=%s/%d", e.Realm, e.GroupID, e.ItemID, storage.ETag(e.Item), history)
+}
+
+// checksum returns a hash of the lines independent of their order.
+func checksum(lines []string) string {
+	sort.Strings(lines)
+	h := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(h[:])
+}
+
+func readCheckpoint(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	if len(path) == 0 {
+		return done, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint file %q: %v", path, err)
+	}
+	if err := json.Unmarshal(b, &done); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %q: %v", path, err)
+	}
+	return done, nil
+}
+
+func writeCheckpoint(path string, done map[string]bool) error {
+	if len(path) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(done)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return fmt.Errorf("writing checkpoint file %q: %v", path, err)
+	}
+	return nil
+}
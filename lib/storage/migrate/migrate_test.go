@@ -0,0 +1,195 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/protobuf/testing/protocmp" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakestore" /* copybara-comment: fakestore */
+
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+	dpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+func newSource(t *testing.T) storage.Store {
+	t.Helper()
+	s := fakestore.New()
+	for rev := int64(1); rev <= 3; rev++ {
+		cfg := &dpb.DamConfig{Revision: rev}
+		he := &cpb.HistoryEntry{Revision: rev, Desc: "update"}
+		if err := s.Write(storage.ConfigDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, rev, cfg, he); err != nil {
+			t.Fatalf("Write(config) failed: %v", err)
+		}
+	}
+	for _, realm := range []string{"master", "test"} {
+		for _, id := range []string{"a", "b", "c"} {
+			acct := &cpb.Account{Properties: &cpb.AccountProperties{Subject: id}}
+			if err := s.Write(storage.AccountDatatype, realm, storage.DefaultUser, id, storage.LatestRev, acct, nil); err != nil {
+				t.Fatalf("Write(account) failed: %v", err)
+			}
+		}
+	}
+	return s
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	dst := fakestore.New()
+
+	report, err := Migrate(ctx, src, dst, &Options{Datatypes: DAMDatatypes, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	if got := report.Copied[storage.ConfigDatatype]; got.Items != 1 || got.History != 3 {
+		t.Errorf("Migrate() copied config %+v, want 1 item with 3 history entries", got)
+	}
+	if got := report.Copied[storage.AccountDatatype]; got.Items != 6 {
+		t.Errorf("Migrate() copied %d accounts, want 6", got.Items)
+	}
+
+	mismatches, err := Verify(ctx, src, dst, DAMDatatypes, storage.AllRealms)
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("Verify() = %v, want no mismatches", mismatches)
+	}
+
+	// Previous revisions are kept.
+	got := &dpb.DamConfig{}
+	if err := dst.Read(storage.ConfigDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, 2, got); err != nil {
+		t.Fatalf("Read(config, 2) failed: %v", err)
+	}
+	if diff := cmp.Diff(&dpb.DamConfig{Revision: 2}, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Read(config, 2) diff (-want +got):\n%s", diff)
+	}
+
+	// Migrated items are skipped when run again.
+	report, err = Migrate(ctx, src, dst, &Options{Datatypes: DAMDatatypes})
+	if err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	if report.Skipped != 7 {
+		t.Errorf("Migrate() again skipped %d items, want 7", report.Skipped)
+	}
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	dst := fakestore.New()
+
+	report, err := Migrate(ctx, src, nil, &Options{Datatypes: DAMDatatypes, DryRun: true})
+	if err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	if got := report.Copied[storage.AccountDatatype]; got.Items != 6 {
+		t.Errorf("Migrate() counted %d accounts, want 6", got.Items)
+	}
+
+	mismatches, err := Verify(ctx, src, dst, DAMDatatypes, storage.AllRealms)
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	if len(mismatches) != 2 {
+		t.Errorf("Verify() after dry run = %v, want mismatches of config and accounts", mismatches)
+	}
+}
+
+func TestMigrate_Realm(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	dst := fakestore.New()
+
+	if _, err := Migrate(ctx, src, dst, &Options{Datatypes: DAMDatatypes, Realm: "test"}); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	results, err := dst.MultiReadTx(storage.AccountDatatype, storage.AllRealms, storage.MatchAllUsers, storage.MatchAllIDs, nil, 0, storage.MaxPageSize, &cpb.Account{}, nil)
+	if err != nil {
+		t.Fatalf("MultiReadTx() failed: %v", err)
+	}
+	for _, e := range results.Entries {
+		if e.Realm != "test" {
+			t.Errorf("Migrate() copied account %q of realm %q, want only realm %q", e.ItemID, e.Realm, "test")
+		}
+	}
+	if len(results.Entries) != 3 {
+		t.Errorf("Migrate() copied %d accounts, want 3", len(results.Entries))
+	}
+}
+
+func TestMigrate_Checkpoint(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+
+	src := newSource(t)
+	dst := fakestore.New()
+	if _, err := Migrate(ctx, src, dst, &Options{Datatypes: DAMDatatypes, CheckpointFile: checkpoint}); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+
+	// Completed datatypes are not read again.
+	if err := src.Write(storage.AccountDatatype, "master", storage.DefaultUser, "d", storage.LatestRev, &cpb.Account{}, nil); err != nil {
+		t.Fatalf("Write(account) failed: %v", err)
+	}
+	report, err := Migrate(ctx, src, dst, &Options{Datatypes: DAMDatatypes, CheckpointFile: checkpoint})
+	if err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	if len(report.Copied) != 0 {
+		t.Errorf("Migrate() with checkpoint copied %v, want none", report.Copied)
+	}
+	if ok, err := dst.Exists(storage.AccountDatatype, "master", storage.DefaultUser, "d", storage.LatestRev); err != nil || ok {
+		t.Errorf("Exists(account d) = %v, %v, want false", ok, err)
+	}
+}
+
+func TestMigrate_MissingRevision(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t)
+	if err := src.Delete(storage.ConfigDatatype, storage.DefaultRealm, storage.DefaultUser, storage.DefaultID, 1); err != nil {
+		t.Fatalf("Delete(config, 1) failed: %v", err)
+	}
+	dst := fakestore.New()
+
+	report, err := Migrate(ctx, src, dst, &Options{Datatypes: DAMDatatypes})
+	if err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	if report.MissingRevisions != 1 {
+		t.Errorf("Migrate() MissingRevisions = %d, want 1", report.MissingRevisions)
+	}
+
+	mismatches, err := Verify(ctx, src, dst, DAMDatatypes, storage.AllRealms)
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	if len(mismatches) != 1 || mismatches[0].Datatype != storage.ConfigDatatype {
+		t.Errorf("Verify() = %v, want a mismatch of config", mismatches)
+	}
+}
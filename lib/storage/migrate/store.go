@@ -0,0 +1,52 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/boltstore" /* copybara-comment: boltstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/dsstore" /* copybara-comment: dsstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/pgstore" /* copybara-comment: pgstore */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+)
+
+// OpenStore opens the store of a service given as <type>:<param> for the
+// storage tools, where the param depends on the type:
+//   memory:<config_root>      the config files of the memory storage, changes
+//                             are not written to the files
+//   datastore:<project>       the GCP project of the Datastore
+//   postgres:<dsn>            the PostgreSQL connection string
+//   embedded:<file>           the bbolt database file
+func OpenStore(ctx context.Context, spec, service string) (storage.Store, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid store %q: want <type>:<param>", spec)
+	}
+	switch parts[0] {
+	case "memory":
+		return storage.NewMemoryStorage(service, parts[1]), nil
+	case "datastore":
+		return dsstore.NewStore(ctx, parts[1], service, ""), nil
+	case "postgres":
+		return pgstore.NewStore(ctx, parts[1], service, ""), nil
+	case "embedded":
+		return boltstore.NewStore(parts[1], service, ""), nil
+	default:
+		return nil, fmt.Errorf("unknown store type %q", parts[0])
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+var (
+	// indexableFields are the filter fields of datatypes which backends may
+	// evaluate on indexes, mapped to the path of the field value within the
+	// JSON encoding of the stored content.
+	indexableFields = map[string]map[string][]string{
+		AccountDatatype: {
+			"id":              {"properties", "subject"},
+			"externalid":      {"properties", "subject"},
+			"username":        {"properties", "subject"},
+			"displayname":     {"profile", "name"},
+			"name.givenname":  {"profile", "givenName"},
+			"name.familyname": {"profile", "familyName"},
+			"name.middlename": {"profile", "middleName"},
+		},
+		GroupDatatype: {
+			"id":          {"id"},
+			"displayname": {"displayName"},
+			"externalid":  {"externalId"},
+		},
+		GroupMemberDatatype: {
+			"member.display": {"display"},
+			"member.value":   {"value"},
+			"member.issuer":  {"issuer"},
+			"member.subject": {"subject"},
+		},
+	}
+)
+
+// Indexer is implemented by stores which evaluate the predicates of some
+// filter fields on indexes, see PlanFilters.
+type Indexer interface {
+	// IndexedFields returns the fields of the datatype which the store has
+	// indexed, mapped to the path of the field value within the JSON encoding of
+	// the stored content.
+	IndexedFields(datatype string) map[string][]string
+}
+
+// IndexableFields returns the filter fields of the datatype which backends may
+// evaluate on indexes, mapped to the path of the field value within the JSON
+// encoding of the stored content. For these fields, the field maps given to
+// BuildFilters must return the value at the path.
+func IndexableFields(datatype string) map[string][]string {
+	out := make(map[string][]string)
+	for field, path := range indexableFields[datatype] {
+		out[field] = append([]string(nil), path...)
+	}
+	return out
+}
+
+// Predicate is a single condition of a filter, exposed so that backends can
+// evaluate it on an index of the field instead of loading every entity.
+type Predicate struct {
+	// Field is the lowercase SCIM path name of the field, as given to BuildFilters.
+	Field string
+	// Compare is the lowercase SCIM compare operator, such as "eq" or "sw".
+	Compare string
+	// Value is the lowercase value to compare the lowercase field value with.
+	Value string
+}
+
+// Predicate returns the condition of the filter.
+func (f Filter) Predicate() Predicate {
+	return Predicate{Field: f.field, Compare: f.compare, Value: f.value}
+}
+
+// PlanFilters splits a CNF of filters into the clauses a backend can evaluate
+// itself and the remainder which it must still evaluate with MatchProtoFilters.
+// A clause is pushed down only if the backend can evaluate every one of its
+// OR conditions, as reported by pushable. Since pushed clauses are ANDed with
+// the remainder, matching the remainder on the entities selected by the
+// backend yields the same entities, and MatchCount, as matching all filters.
+func PlanFilters(cnfFilters [][]Filter, pushable func(p Predicate) bool) ([][]Predicate, [][]Filter) {
+	var pushed [][]Predicate
+	var remainder [][]Filter
+	for _, orFilters := range cnfFilters {
+		if len(orFilters) == 0 {
+			remainder = append(remainder, orFilters)
+			continue
+		}
+		var preds []Predicate
+		for _, f := range orFilters {
+			p := f.Predicate()
+			if !pushable(p) {
+				preds = nil
+				break
+			}
+			preds = append(preds, p)
+		}
+		if preds == nil {
+			remainder = append(remainder, orFilters)
+			continue
+		}
+		pushed = append(pushed, preds)
+	}
+	return pushed, remainder
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+)
+
+const (
+	// SortAscending is the SCIM sortOrder for ascending order, the default.
+	SortAscending = "ascending"
+	// SortDescending is the SCIM sortOrder for descending order.
+	SortDescending = "descending"
+)
+
+// Sort is the order of the entries returned by MultiReadSortedTx.
+type Sort struct {
+	// field is the lowercase SCIM path name of the field.
+	field      string
+	extract    func(p proto.Message) string
+	descending bool
+}
+
+// Sorter is implemented by stores which can return the entities of a
+// MultiReadTx in a sort order themselves.
+type Sorter interface {
+	// SortsBy reports if the store can sort the entities of the datatype by the field.
+	SortsBy(datatype, field string) bool
+	// MultiReadSortedTx is MultiReadTx with the entities in the sort order.
+	MultiReadSortedTx(datatype, realm, user, id string, filters [][]Filter, sort *Sort, offset, pageSize int, typ proto.Message, tx Tx) (*Results, error)
+}
+
+// BuildSort creates a sort order from SCIM "sortBy" and "sortOrder" parameters.
+// See: https://tools.ietf.org/html/rfc7644#section-3.4.2.3
+// The fields map is the same as the one given to BuildFilters. Returns nil if
+// sortBy is empty, for entries to be returned in storage order.
+func BuildSort(sortBy, sortOrder string, fields map[string]func(p proto.Message) string) (*Sort, error) {
+	if len(sortBy) == 0 {
+		if len(sortOrder) > 0 {
+			return nil, fmt.Errorf("sortOrder %q requires a sortBy field", sortOrder)
+		}
+		return nil, nil
+	}
+	field := strings.ToLower(sortBy)
+	fn, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("sort field %q not defined", sortBy)
+	}
+	s := &Sort{field: field, extract: fn}
+	switch strings.ToLower(sortOrder) {
+	case "", SortAscending:
+	case SortDescending:
+		s.descending = true
+	default:
+		return nil, fmt.Errorf("invalid sortOrder %q", sortOrder)
+	}
+	return s, nil
+}
+
+// Field returns the lowercase SCIM path name of the field to sort by.
+func (s *Sort) Field() string {
+	return s.field
+}
+
+// Descending returns true if entries are sorted in descending order.
+func (s *Sort) Descending() bool {
+	return s.descending
+}
+
+// Compare compares the field values of two messages case-insensitively, the
+// same way filters compare values, and returns -1, 0 or +1 if a sorts before,
+// with or after b respectively.
+func (s *Sort) Compare(a, b proto.Message) int {
+	c := strings.Compare(strings.ToLower(s.extract(a)), strings.ToLower(s.extract(b)))
+	if s.descending {
+		return -c
+	}
+	return c
+}
+
+// SortInt returns the value of an integer field for the field maps given to
+// BuildSort, such that the strings sort in the order of the integers.
+func SortInt(v int64) string {
+	// Flipping the sign bit orders negative values before positive values.
+	return fmt.Sprintf("%020d", uint64(v)^(1<<63))
+}
+
+// SortEntries sorts entries in the sort order. Entries with the same value are
+// ordered by realm, group and item ID for pagination to be stable.
+func SortEntries(entries []*Entry, s *Sort) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if c := s.Compare(entries[i].Item, entries[j].Item); c != 0 {
+			return c < 0
+		}
+		a, b := entries[i], entries[j]
+		if a.Realm != b.Realm {
+			return a.Realm < b.Realm
+		}
+		if a.GroupID != b.GroupID {
+			return a.GroupID < b.GroupID
+		}
+		return a.ItemID < b.ItemID
+	})
+}
+
+// MultiReadSortedTx reads a set of data entities matching the filters like
+// MultiReadTx, with the entities of the page in the sort order. If the store
+// is not a Sorter for the field, all matching entities are read and sorted in
+// memory before the page is taken. A nil sort reads in storage order.
+func MultiReadSortedTx(store Store, datatype, realm, user, id string, filters [][]Filter, s *Sort, offset, pageSize int, typ proto.Message, tx Tx) (_ *Results, ferr error) {
+	if s == nil {
+		return store.MultiReadTx(datatype, realm, user, id, filters, offset, pageSize, typ, tx)
+	}
+	if sorter, ok := store.(Sorter); ok && sorter.SortsBy(datatype, s.field) {
+		return sorter.MultiReadSortedTx(datatype, realm, user, id, filters, s, offset, pageSize, typ, tx)
+	}
+
+	if tx == nil {
+		var err error
+		tx, err = store.Tx(false)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			err := tx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	var all []*Entry
+	for {
+		page, err := store.MultiReadTx(datatype, realm, user, id, filters, len(all), MaxPageSize, typ, tx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Entries...)
+		// MatchCount includes the matches after the page.
+		if len(page.Entries) == 0 || len(page.Entries) >= page.MatchCount {
+			break
+		}
+	}
+	SortEntries(all, s)
+
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	results := NewResults()
+	if offset >= len(all) {
+		return results, nil
+	}
+	results.MatchCount = len(all) - offset
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	results.Entries = append(results.Entries, all[offset:end]...)
+	return results, nil
+}
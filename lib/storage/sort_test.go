@@ -0,0 +1,112 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+)
+
+var sortFields = map[string]func(p proto.Message) string{
+	"state": func(p proto.Message) string {
+		return p.(*cpb.Account).State
+	},
+}
+
+func TestBuildSort(t *testing.T) {
+	tests := []struct {
+		sortBy    string
+		sortOrder string
+		wantNil   bool
+		wantDesc  bool
+		wantErr   bool
+	}{
+		{wantNil: true},
+		{sortBy: "State", wantDesc: false},
+		{sortBy: "state", sortOrder: "Descending", wantDesc: true},
+		{sortBy: "state", sortOrder: "ascending", wantDesc: false},
+		{sortBy: "owner", wantErr: true},
+		{sortBy: "state", sortOrder: "up", wantErr: true},
+		{sortOrder: "descending", wantErr: true},
+	}
+	for _, tc := range tests {
+		s, err := BuildSort(tc.sortBy, tc.sortOrder, sortFields)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("BuildSort(%q, %q) succeeded, want error", tc.sortBy, tc.sortOrder)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("BuildSort(%q, %q) failed: %v", tc.sortBy, tc.sortOrder, err)
+		}
+		if tc.wantNil {
+			if s != nil {
+				t.Errorf("BuildSort(%q, %q) = %+v, want nil", tc.sortBy, tc.sortOrder, s)
+			}
+			continue
+		}
+		if s.Field() != "state" || s.Descending() != tc.wantDesc {
+			t.Errorf("BuildSort(%q, %q) = field %q, descending %v, want field %q, descending %v", tc.sortBy, tc.sortOrder, s.Field(), s.Descending(), "state", tc.wantDesc)
+		}
+	}
+}
+
+func TestMultiReadSortedTx(t *testing.T) {
+	store := NewMemoryStorage("ic-min", "testdata/config")
+	// More accounts than fit in a page to read them in several pages. The
+	// states repeat so that ties are ordered by user.
+	n := MaxPageSize + 10
+	for i := 0; i < n; i++ {
+		acct := &cpb.Account{State: fmt.Sprintf("S%d", i%3)}
+		if err := store.Write(AccountDatatype, "sort", fmt.Sprintf("u%04d", i), "main", LatestRev, acct, nil); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+	order, err := BuildSort("state", SortDescending, sortFields)
+	if err != nil {
+		t.Fatalf("BuildSort() failed: %v", err)
+	}
+
+	results, err := MultiReadSortedTx(store, AccountDatatype, "sort", MatchAllUsers, MatchAllIDs, nil, order, 1, 3, &cpb.Account{}, nil)
+	if err != nil {
+		t.Fatalf("MultiReadSortedTx() failed: %v", err)
+	}
+	if results.MatchCount != n-1 {
+		t.Errorf("MultiReadSortedTx() MatchCount = %d, want %d", results.MatchCount, n-1)
+	}
+	var got []string
+	for _, e := range results.Entries {
+		got = append(got, e.Item.(*cpb.Account).State+"/"+e.GroupID)
+	}
+	want := []string{"S2/u0005", "S2/u0008", "S2/u0011"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MultiReadSortedTx() entries diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortInt(t *testing.T) {
+	values := []int64{-62135236800, -1, 0, 1, 1600000000}
+	for i := 1; i < len(values); i++ {
+		if a, b := SortInt(values[i-1]), SortInt(values[i]); a >= b {
+			t.Errorf("SortInt(%d) = %q, SortInt(%d) = %q, want ascending", values[i-1], a, values[i], b)
+		}
+	}
+}
@@ -45,6 +45,7 @@ const (
 	GroupDatatype                     = "group"
 	GroupMemberDatatype               = "member"
 	LockDatatype                      = "lock"
+	LockFenceDatatype                 = "lock_fence"
 	LoginStateDatatype                = "login_state"
 	LongRunningOperationDatatype      = "lro"
 	ProcessDataType                   = "process"
@@ -107,13 +108,23 @@ type Store interface {
 	ReadHistoryTx(datatype, realm, user, id string, content *[]proto.Message, tx Tx) error
 	Write(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message) error
 	WriteTx(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message, tx Tx) error
+	// WriteIfMatchTx writes like WriteTx if the etag of the latest revision of the item
+	// matches, atomically within the transaction. See CheckETagTx for the etag values.
+	WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx Tx) error
 	Delete(datatype, realm, user, id string, rev int64) error
 	DeleteTx(datatype, realm, user, id string, rev int64, tx Tx) error
 	MultiDeleteTx(datatype, realm, user string, tx Tx) error
+	// MultiDeleteHistoryTx deletes the history entries and the revisions other than the
+	// latest of the items matching the input parameters, which MultiDeleteTx keeps.
+	MultiDeleteHistoryTx(datatype, realm, user string, tx Tx) error
 	// Wipe removes any items from a realm up to maxEntries (if > 0). Returns count of deleted items and error.
 	Wipe(ctx context.Context, realm string, batchNum, maxEntries int) (int, error)
 	Tx(update bool) (Tx, error)
 	LockTx(lockName string, minFrequency time.Duration, tx Tx) Tx
+	// Watch returns a channel receiving the changes of items of the datatype within
+	// the realm (AllRealms for all realms) committed after the call, in commit order.
+	// Wipe is not reported. The channel is closed when ctx is done.
+	Watch(ctx context.Context, datatype, realm string) (<-chan *Change, error)
 }
 
 type Tx interface {
@@ -126,7 +137,8 @@ type Tx interface {
 
 // Filter is a means to filter which entries are returned from MultiReadTx.
 type Filter struct {
-	// Field is the name of the field
+	// field is the lowercase SCIM path name of the field.
+	field   string
 	extract func(p proto.Message) string
 	compare string
 	value   string
@@ -194,7 +206,8 @@ func BuildFilters(str string, fields map[string]func(p proto.Message) string) ([
 			return nil, fmt.Errorf("invalid filter %q", str)
 		}
 		for _, m := range match {
-			fn, ok := fields[strings.ToLower(m[1])]
+			field := strings.ToLower(m[1])
+			fn, ok := fields[field]
 			if !ok {
 				return nil, fmt.Errorf("field %q not defined", m[1])
 			}
@@ -202,7 +215,7 @@ func BuildFilters(str string, fields map[string]func(p proto.Message) string) ([
 			if strings.HasPrefix(val, `"`) && strings.HasSuffix(val, `"`) {
 				val = val[1 : len(val)-1]
 			}
-			ors = append(ors, Filter{extract: fn, compare: strings.ToLower(m[2]), value: val})
+			ors = append(ors, Filter{field: field, extract: fn, compare: strings.ToLower(m[2]), value: val})
 		}
 		out = append(out, ors)
 	}
@@ -17,6 +17,7 @@ package storage
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
 )
 
@@ -534,3 +535,57 @@ func TestFilters_Errors(t *testing.T) {
 		}
 	}
 }
+
+func TestPlanFilters(t *testing.T) {
+	fields := map[string]func(p proto.Message) string{
+		"apple": func(p proto.Message) string {
+			return "APPLES"
+		},
+		"orange": func(p proto.Message) string {
+			return ""
+		},
+	}
+	indexed := func(p Predicate) bool {
+		return p.Field == "apple" && (p.Compare == "eq" || p.Compare == "sw")
+	}
+	tests := []struct {
+		input         string
+		wantPushed    [][]Predicate
+		wantRemainder int
+	}{
+		{
+			input:      `apple eq "apples"`,
+			wantPushed: [][]Predicate{{{Field: "apple", Compare: "eq", Value: "apples"}}},
+		},
+		{
+			input:      `Apple EQ "APPLES" or apple sw "mac"`,
+			wantPushed: [][]Predicate{{{Field: "apple", Compare: "eq", Value: "apples"}, {Field: "apple", Compare: "sw", Value: "mac"}}},
+		},
+		{
+			input:         `apple co "apples"`,
+			wantRemainder: 1,
+		},
+		{
+			input:         `apple eq "apples" or orange eq "foo"`,
+			wantRemainder: 1,
+		},
+		{
+			input:         `apple sw "app" and (apple co "les" or orange eq "foo")`,
+			wantPushed:    [][]Predicate{{{Field: "apple", Compare: "sw", Value: "app"}}},
+			wantRemainder: 1,
+		},
+	}
+	for _, tc := range tests {
+		f, err := BuildFilters(tc.input, fields)
+		if err != nil {
+			t.Fatalf("BuildFilters(%q, fields) failed: %v", tc.input, err)
+		}
+		pushed, remainder := PlanFilters(f, indexed)
+		if diff := cmp.Diff(tc.wantPushed, pushed); diff != "" {
+			t.Errorf("PlanFilters(%q) pushed diff (-want +got):\n%s", tc.input, diff)
+		}
+		if len(remainder) != tc.wantRemainder {
+			t.Errorf("PlanFilters(%q) remainder = %+v, want %d clauses", tc.input, remainder, tc.wantRemainder)
+		}
+	}
+}
@@ -0,0 +1,960 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storagetest provides a conformance test suite for storage.Store
+// implementations.
+//
+// Example:
+//   func TestStore_Conformance(t *testing.T) {
+//     storagetest.Run(t, func() storage.Store { return New() }, nil)
+//   }
+package storagetest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+	"google.golang.org/protobuf/testing/protocmp" /* copybara-comment */
+	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	dpb "github.com/golang/protobuf/ptypes/duration" /* copybara-comment */
+	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
+)
+
+const (
+	fakeDatatype = "fake-datatype"
+	fakeRealm    = "fake-realm"
+	otherRealm   = "other-realm"
+	fakeUser     = "fake-user"
+	fakeID       = "fake-id"
+)
+
+// Options describes the known limitations of a store under test.
+type Options struct {
+	// SingleTx is set if the store does not support concurrent transactions.
+	// Tests for LockTx contention are skipped.
+	SingleTx bool
+	// WipeAllRealms is set if Wipe removes the data of all realms and ignores
+	// maxEntries.
+	WipeAllRealms bool
+	// ChangeLog is set if the store keeps a change log per realm, which Wipe
+	// removes and counts along with the items.
+	ChangeLog bool
+	// NoReadOwnWrites is set if reads within a transaction do not see its own
+	// writes. Tests for reading own writes are skipped.
+	NoReadOwnWrites bool
+}
+
+// Run runs the conformance tests against stores created by newStore.
+// Each test gets a new empty store.
+func Run(t *testing.T, newStore func() storage.Store, opts *Options) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	tests := []struct {
+		name string
+		test func(t *testing.T, s storage.Store, opts *Options)
+	}{
+		{name: "WriteRead", test: testWriteRead},
+		{name: "ReadNotFound", test: testReadNotFound},
+		{name: "Revisions", test: testRevisions},
+		{name: "History", test: testHistory},
+		{name: "Delete", test: testDelete},
+		{name: "MultiReadTx", test: testMultiReadTx},
+		{name: "MultiReadTx_Pagination", test: testMultiReadTxPagination},
+		{name: "MultiDeleteTx", test: testMultiDeleteTx},
+		{name: "MultiDeleteHistoryTx", test: testMultiDeleteHistoryTx},
+		{name: "Wipe", test: testWipe},
+		{name: "Tx_ReadOwnWrites", test: testTxReadOwnWrites},
+		{name: "Tx_Rollback", test: testTxRollback},
+		{name: "Tx_MakeUpdate", test: testTxMakeUpdate},
+		{name: "LockTx", test: testLockTx},
+		{name: "LockTx_Contention", test: testLockTxContention},
+		{name: "WriteIfMatchTx", test: testWriteIfMatchTx},
+		{name: "WriteIfMatchTx_Contention", test: testWriteIfMatchTxContention},
+		{name: "Watch", test: testWatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newStore(), opts)
+		})
+	}
+}
+
+func testWriteRead(t *testing.T, s storage.Store, opts *Options) {
+	want := &dpb.Duration{Seconds: 60}
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, want, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.Read(...) diff (-want +got):\n%s", diff)
+	}
+
+	exists, err := s.Exists(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev)
+	if err != nil || !exists {
+		t.Errorf("store.Exists(...) = %v, %v, want true", exists, err)
+	}
+
+	// Overwrite replaces the content.
+	want = &dpb.Duration{Seconds: 120}
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, want, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.Read(...) after overwrite diff (-want +got):\n%s", diff)
+	}
+}
+
+func testReadNotFound(t *testing.T, s storage.Store, opts *Options) {
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		realm string
+		user  string
+		id    string
+	}{
+		{name: "other id", realm: fakeRealm, user: fakeUser, id: "other-id"},
+		{name: "other user", realm: fakeRealm, user: "other-user", id: fakeID},
+		{name: "other realm", realm: otherRealm, user: fakeUser, id: fakeID},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.Read(fakeDatatype, tc.realm, tc.user, tc.id, storage.LatestRev, &dpb.Duration{})
+			if err == nil || !storage.ErrNotFound(err) {
+				t.Errorf("store.Read(...) = %v, want not found error", err)
+			}
+			exists, err := s.Exists(fakeDatatype, tc.realm, tc.user, tc.id, storage.LatestRev)
+			if err != nil || exists {
+				t.Errorf("store.Exists(...) = %v, %v, want false", exists, err)
+			}
+		})
+	}
+}
+
+func testRevisions(t *testing.T, s storage.Store, opts *Options) {
+	for rev := int64(1); rev <= 3; rev++ {
+		if err := s.Write(fakeDatatype, fakeRealm, storage.DefaultUser, fakeID, rev, &dpb.Duration{Seconds: rev}, nil); err != nil {
+			t.Fatalf("store.Write(..., %d, ...) failed: %v", rev, err)
+		}
+	}
+
+	for _, rev := range []int64{1, 2, 3, storage.LatestRev} {
+		want := rev
+		if rev == storage.LatestRev {
+			want = 3
+		}
+		got := &dpb.Duration{}
+		if err := s.Read(fakeDatatype, fakeRealm, storage.DefaultUser, fakeID, rev, got); err != nil {
+			t.Fatalf("store.Read(..., %d, ...) failed: %v", rev, err)
+		}
+		if got.Seconds != want {
+			t.Errorf("store.Read(..., %d, ...) = %v, want %d seconds", rev, got, want)
+		}
+	}
+
+	err := s.Read(fakeDatatype, fakeRealm, storage.DefaultUser, fakeID, 4, &dpb.Duration{})
+	if err == nil || !storage.ErrNotFound(err) {
+		t.Errorf("store.Read(..., 4, ...) = %v, want not found error", err)
+	}
+}
+
+func testHistory(t *testing.T, s storage.Store, opts *Options) {
+	for rev := int64(1); rev <= 3; rev++ {
+		if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, rev, &dpb.Duration{Seconds: rev}, &cpb.HistoryEntry{Revision: rev}); err != nil {
+			t.Fatalf("store.Write(..., %d, ...) failed: %v", rev, err)
+		}
+	}
+	// A write without history does not add an entry.
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, 4, &dpb.Duration{Seconds: 4}, nil); err != nil {
+		t.Fatalf("store.Write(..., 4, ...) failed: %v", err)
+	}
+	// History of other items is not included.
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, "other-id", 1, &dpb.Duration{}, &cpb.HistoryEntry{Revision: 100}); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+
+	var got []proto.Message
+	if err := s.ReadHistory(fakeDatatype, fakeRealm, fakeUser, fakeID, &got); err != nil {
+		t.Fatalf("store.ReadHistory(...) failed: %v", err)
+	}
+	want := []proto.Message{
+		&cpb.HistoryEntry{Revision: 1},
+		&cpb.HistoryEntry{Revision: 2},
+		&cpb.HistoryEntry{Revision: 3},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.ReadHistory(...) diff (-want +got):\n%s", diff)
+	}
+
+	// Missing history is either not found or empty.
+	var missing []proto.Message
+	if err := s.ReadHistory(fakeDatatype, fakeRealm, fakeUser, "missing-id", &missing); err != nil && !storage.ErrNotFound(err) {
+		t.Errorf("store.ReadHistory(...) of missing item = %v, want not found error or success", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("store.ReadHistory(...) of missing item returned %d entries, want 0", len(missing))
+	}
+}
+
+func testDelete(t *testing.T, s storage.Store, opts *Options) {
+	for _, id := range []string{fakeID, "other-id"} {
+		if err := s.Write(fakeDatatype, fakeRealm, fakeUser, id, storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+			t.Fatalf("store.Write(...) failed: %v", err)
+		}
+	}
+
+	if err := s.Delete(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev); err != nil {
+		t.Fatalf("store.Delete(...) failed: %v", err)
+	}
+	exists, err := s.Exists(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev)
+	if err != nil || exists {
+		t.Errorf("store.Exists(...) of deleted item = %v, %v, want false", exists, err)
+	}
+	exists, err = s.Exists(fakeDatatype, fakeRealm, fakeUser, "other-id", storage.LatestRev)
+	if err != nil || !exists {
+		t.Errorf("store.Exists(...) of other item = %v, %v, want true", exists, err)
+	}
+
+	err = s.Delete(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev)
+	if err == nil || !storage.ErrNotFound(err) {
+		t.Errorf("store.Delete(...) of deleted item = %v, want not found error", err)
+	}
+
+	// Writing again after a delete brings the item back.
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{Seconds: 1}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) after rewrite failed: %v", err)
+	}
+	if got.Seconds != 1 {
+		t.Errorf("store.Read(...) after rewrite = %v, want 1 second", got)
+	}
+}
+
+// writeAccounts writes one account per user in fake-realm and one in other-realm.
+// The state and subject of each account are its user.
+func writeAccounts(t *testing.T, s storage.Store) {
+	t.Helper()
+	for _, user := range []string{"a", "b", "c", "d"} {
+		if err := s.Write(storage.AccountDatatype, fakeRealm, user, "main", storage.LatestRev, &cpb.Account{State: user, Properties: &cpb.AccountProperties{Subject: user}}, nil); err != nil {
+			t.Fatalf("store.Write(...) failed: %v", err)
+		}
+	}
+	if err := s.Write(storage.AccountDatatype, otherRealm, "e", "main", storage.LatestRev, &cpb.Account{State: "e", Properties: &cpb.AccountProperties{Subject: "e"}}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	// Items of other datatypes are never returned.
+	if err := s.Write(fakeDatatype, fakeRealm, "a", "main", storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+}
+
+func testMultiReadTx(t *testing.T, s storage.Store, opts *Options) {
+	writeAccounts(t, s)
+
+	filters, err := storage.BuildFilters(`state eq "b" or state eq "d"`, map[string]func(p proto.Message) string{
+		"state": func(p proto.Message) string { return p.(*cpb.Account).State },
+	})
+	if err != nil {
+		t.Fatalf("storage.BuildFilters(...) failed: %v", err)
+	}
+	// SCIM fields which backends may evaluate on an index, combined with a
+	// clause which is always matched in memory.
+	scimFilters, err := storage.BuildFilters(`(username eq "B" or username gt "c") and state ne "d"`, map[string]func(p proto.Message) string{
+		"username": func(p proto.Message) string { return p.(*cpb.Account).GetProperties().Subject },
+		"state":    func(p proto.Message) string { return p.(*cpb.Account).State },
+	})
+	if err != nil {
+		t.Fatalf("storage.BuildFilters(...) failed: %v", err)
+	}
+	prefixFilters, err := storage.BuildFilters(`username sw "a" or username sw "c"`, map[string]func(p proto.Message) string{
+		"username": func(p proto.Message) string { return p.(*cpb.Account).GetProperties().Subject },
+	})
+	if err != nil {
+		t.Fatalf("storage.BuildFilters(...) failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		realm     string
+		user      string
+		id        string
+		filters   [][]storage.Filter
+		offset    int
+		pageSize  int
+		want      []string
+		wantCount int
+	}{
+		{
+			name:      "all",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			pageSize:  10,
+			want:      []string{"fake-realm/a", "fake-realm/b", "fake-realm/c", "fake-realm/d"},
+			wantCount: 4,
+		},
+		{
+			name:      "all realms",
+			realm:     storage.AllRealms,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			pageSize:  10,
+			want:      []string{"fake-realm/a", "fake-realm/b", "fake-realm/c", "fake-realm/d", "other-realm/e"},
+			wantCount: 5,
+		},
+		{
+			name:      "user",
+			realm:     fakeRealm,
+			user:      "b",
+			id:        storage.MatchAllIDs,
+			pageSize:  10,
+			want:      []string{"fake-realm/b"},
+			wantCount: 1,
+		},
+		{
+			name:      "id",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        "main",
+			pageSize:  10,
+			want:      []string{"fake-realm/a", "fake-realm/b", "fake-realm/c", "fake-realm/d"},
+			wantCount: 4,
+		},
+		{
+			name:      "no match",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        "other-id",
+			pageSize:  10,
+			wantCount: 0,
+		},
+		{
+			name:      "filters",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			filters:   filters,
+			pageSize:  10,
+			want:      []string{"fake-realm/b", "fake-realm/d"},
+			wantCount: 2,
+		},
+		{
+			name:      "indexed and unindexed filters",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			filters:   scimFilters,
+			pageSize:  10,
+			want:      []string{"fake-realm/b"},
+			wantCount: 1,
+		},
+		{
+			name:      "indexed filters and offset",
+			realm:     storage.AllRealms,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			filters:   prefixFilters,
+			offset:    1,
+			pageSize:  10,
+			want:      []string{"fake-realm/c"},
+			wantCount: 1,
+		},
+		{
+			name:      "page size",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			pageSize:  2,
+			wantCount: 4,
+		},
+		{
+			name:      "offset",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			offset:    1,
+			pageSize:  2,
+			wantCount: 3,
+		},
+		{
+			name:      "offset past end",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			offset:    10,
+			pageSize:  2,
+			wantCount: 0,
+		},
+		{
+			name:      "filters and offset",
+			realm:     fakeRealm,
+			user:      storage.MatchAllUsers,
+			id:        storage.MatchAllIDs,
+			filters:   filters,
+			offset:    1,
+			pageSize:  10,
+			wantCount: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := s.MultiReadTx(storage.AccountDatatype, tc.realm, tc.user, tc.id, tc.filters, tc.offset, tc.pageSize, &cpb.Account{}, nil)
+			if err != nil {
+				t.Fatalf("store.MultiReadTx(...) failed: %v", err)
+			}
+			if results.MatchCount != tc.wantCount {
+				t.Errorf("store.MultiReadTx(...) MatchCount = %d, want %d", results.MatchCount, tc.wantCount)
+			}
+			wantLen := tc.wantCount
+			if wantLen > tc.pageSize {
+				wantLen = tc.pageSize
+			}
+			if len(results.Entries) != wantLen {
+				t.Errorf("store.MultiReadTx(...) returned %d entries, want %d", len(results.Entries), wantLen)
+			}
+			for _, e := range results.Entries {
+				if e.ItemID != "main" {
+					t.Errorf("store.MultiReadTx(...) entry ItemID = %q, want %q", e.ItemID, "main")
+				}
+				a, ok := e.Item.(*cpb.Account)
+				if !ok || a.State != e.GroupID {
+					t.Errorf("store.MultiReadTx(...) entry %s/%s Item = %v, want account with state %q", e.Realm, e.GroupID, e.Item, e.GroupID)
+				}
+			}
+			if tc.want == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, entryKeys(results)); diff != "" {
+				t.Errorf("store.MultiReadTx(...) entries diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testMultiReadTxPagination(t *testing.T, s storage.Store, opts *Options) {
+	writeAccounts(t, s)
+
+	all, err := s.MultiReadTx(storage.AccountDatatype, storage.AllRealms, storage.MatchAllUsers, storage.MatchAllIDs, nil, 0, storage.MaxPageSize, &cpb.Account{}, nil)
+	if err != nil {
+		t.Fatalf("store.MultiReadTx(...) failed: %v", err)
+	}
+
+	// Reading page by page visits every item once, in the same order.
+	var got []string
+	for offset := 0; offset < all.MatchCount; offset += 2 {
+		page, err := s.MultiReadTx(storage.AccountDatatype, storage.AllRealms, storage.MatchAllUsers, storage.MatchAllIDs, nil, offset, 2, &cpb.Account{}, nil)
+		if err != nil {
+			t.Fatalf("store.MultiReadTx(..., %d, 2, ...) failed: %v", offset, err)
+		}
+		if page.MatchCount != all.MatchCount-offset {
+			t.Errorf("store.MultiReadTx(..., %d, 2, ...) MatchCount = %d, want %d", offset, page.MatchCount, all.MatchCount-offset)
+		}
+		for _, e := range page.Entries {
+			got = append(got, e.Realm+"/"+e.GroupID)
+		}
+	}
+	var want []string
+	for _, e := range all.Entries {
+		want = append(want, e.Realm+"/"+e.GroupID)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("store.MultiReadTx(...) pages diff (-want +got):\n%s", diff)
+	}
+}
+
+// entryKeys returns the sorted realm/user of the result entries.
+func entryKeys(results *storage.Results) []string {
+	var out []string
+	for _, e := range results.Entries {
+		out = append(out, e.Realm+"/"+e.GroupID)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func testMultiDeleteTx(t *testing.T, s storage.Store, opts *Options) {
+	writeAccounts(t, s)
+
+	if err := s.MultiDeleteTx(storage.AccountDatatype, fakeRealm, storage.DefaultUser, nil); err != nil {
+		t.Fatalf("store.MultiDeleteTx(...) failed: %v", err)
+	}
+
+	results, err := s.MultiReadTx(storage.AccountDatatype, storage.AllRealms, storage.MatchAllUsers, storage.MatchAllIDs, nil, 0, 10, &cpb.Account{}, nil)
+	if err != nil {
+		t.Fatalf("store.MultiReadTx(...) failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"other-realm/e"}, entryKeys(results)); diff != "" {
+		t.Errorf("store.MultiReadTx(...) after MultiDeleteTx(...) diff (-want +got):\n%s", diff)
+	}
+	exists, err := s.Exists(fakeDatatype, fakeRealm, "a", "main", storage.LatestRev)
+	if err != nil || !exists {
+		t.Errorf("store.Exists(...) of other datatype = %v, %v, want true", exists, err)
+	}
+}
+
+func testMultiDeleteHistoryTx(t *testing.T, s storage.Store, opts *Options) {
+	for _, realm := range []string{fakeRealm, otherRealm} {
+		for rev := int64(1); rev <= 2; rev++ {
+			if err := s.Write(fakeDatatype, realm, fakeUser, fakeID, rev, &dpb.Duration{Seconds: rev}, &cpb.HistoryEntry{Revision: rev}); err != nil {
+				t.Fatalf("store.Write(..., %d, ...) failed: %v", rev, err)
+			}
+		}
+	}
+
+	if err := s.MultiDeleteHistoryTx(fakeDatatype, fakeRealm, storage.MatchAllUsers, nil); err != nil {
+		t.Fatalf("store.MultiDeleteHistoryTx(...) failed: %v", err)
+	}
+
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) of latest revision failed: %v", err)
+	}
+	if got.Seconds != 2 {
+		t.Errorf("store.Read(...) of latest revision = %v, want 2 seconds", got)
+	}
+	err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, 1, &dpb.Duration{})
+	if err == nil || !storage.ErrNotFound(err) {
+		t.Errorf("store.Read(..., 1, ...) of deleted revision = %v, want not found error", err)
+	}
+	var history []proto.Message
+	if err := s.ReadHistory(fakeDatatype, fakeRealm, fakeUser, fakeID, &history); err != nil && !storage.ErrNotFound(err) {
+		t.Fatalf("store.ReadHistory(...) failed: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("store.ReadHistory(...) after MultiDeleteHistoryTx(...) returned %d entries, want 0", len(history))
+	}
+
+	var other []proto.Message
+	if err := s.ReadHistory(fakeDatatype, otherRealm, fakeUser, fakeID, &other); err != nil {
+		t.Fatalf("store.ReadHistory(...) of other realm failed: %v", err)
+	}
+	if len(other) != 2 {
+		t.Errorf("store.ReadHistory(...) of other realm returned %d entries, want 2", len(other))
+	}
+}
+
+func testWipe(t *testing.T, s storage.Store, opts *Options) {
+	ctx := context.Background()
+	for _, realm := range []string{fakeRealm, otherRealm} {
+		for _, id := range []string{"a", "b", "c"} {
+			if err := s.Write(fakeDatatype, realm, storage.DefaultUser, id, 1, &dpb.Duration{}, &cpb.HistoryEntry{Revision: 1}); err != nil {
+				t.Fatalf("store.Write(...) failed: %v", err)
+			}
+		}
+	}
+
+	if opts.WipeAllRealms {
+		count, err := s.Wipe(ctx, fakeRealm, 0, 0)
+		if err != nil {
+			t.Fatalf("store.Wipe(...) failed: %v", err)
+		}
+		if count == 0 {
+			t.Errorf("store.Wipe(...) = 0, want > 0")
+		}
+	} else {
+		// Each item has a history, a revision and a latest entry.
+		count, err := s.Wipe(ctx, fakeRealm, 0, 4)
+		if err != nil {
+			t.Fatalf("store.Wipe(..., 4) failed: %v", err)
+		}
+		if count != 4 {
+			t.Errorf("store.Wipe(..., 4) = %d, want 4", count)
+		}
+		total := count
+		for batch := 1; count > 0 && batch < 10; batch++ {
+			count, err = s.Wipe(ctx, fakeRealm, batch, 4)
+			if err != nil {
+				t.Fatalf("store.Wipe(..., %d, 4) failed: %v", batch, err)
+			}
+			total += count
+		}
+		want := 9
+		if opts.ChangeLog {
+			// One change per write.
+			want += 3
+		}
+		if total != want {
+			t.Errorf("store.Wipe(...) removed %d entries in total, want %d", total, want)
+		}
+
+		exists, err := s.Exists(fakeDatatype, otherRealm, storage.DefaultUser, "a", storage.LatestRev)
+		if err != nil || !exists {
+			t.Errorf("store.Exists(...) in other realm after wipe = %v, %v, want true", exists, err)
+		}
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		exists, err := s.Exists(fakeDatatype, fakeRealm, storage.DefaultUser, id, storage.LatestRev)
+		if err != nil || exists {
+			t.Errorf("store.Exists(..., %q, ...) after wipe = %v, %v, want false", id, exists, err)
+		}
+	}
+	var history []proto.Message
+	if err := s.ReadHistory(fakeDatatype, fakeRealm, storage.DefaultUser, "a", &history); err != nil && !storage.ErrNotFound(err) {
+		t.Errorf("store.ReadHistory(...) after wipe = %v, want not found error or success", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("store.ReadHistory(...) after wipe returned %d entries, want 0", len(history))
+	}
+}
+
+func testTxReadOwnWrites(t *testing.T, s storage.Store, opts *Options) {
+	if opts.NoReadOwnWrites {
+		t.Skip("store does not read own writes within a transaction")
+	}
+	tx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("store.Tx(true) failed: %v", err)
+	}
+	if !tx.IsUpdate() {
+		t.Errorf("tx.IsUpdate() = false, want true")
+	}
+
+	want := &dpb.Duration{Seconds: 60}
+	if err := s.WriteTx(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, want, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) failed: %v", err)
+	}
+	got := &dpb.Duration{}
+	if err := s.ReadTx(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got, tx); err != nil {
+		t.Fatalf("store.ReadTx(...) in the same transaction failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.ReadTx(...) in the same transaction diff (-want +got):\n%s", diff)
+	}
+	results, err := s.MultiReadTx(fakeDatatype, fakeRealm, storage.MatchAllUsers, storage.MatchAllIDs, nil, 0, 10, &dpb.Duration{}, tx)
+	if err != nil {
+		t.Fatalf("store.MultiReadTx(...) in the same transaction failed: %v", err)
+	}
+	if results.MatchCount != 1 {
+		t.Errorf("store.MultiReadTx(...) in the same transaction MatchCount = %d, want 1", results.MatchCount)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) after commit failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.Read(...) after commit diff (-want +got):\n%s", diff)
+	}
+}
+
+func testTxRollback(t *testing.T, s storage.Store, opts *Options) {
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, "other-id", storage.LatestRev, &dpb.Duration{Seconds: 1}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+
+	tx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("store.Tx(true) failed: %v", err)
+	}
+	if err := s.WriteTx(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) failed: %v", err)
+	}
+	if err := s.WriteTx(fakeDatatype, fakeRealm, fakeUser, "other-id", storage.LatestRev, &dpb.Duration{Seconds: 2}, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) failed: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("tx.Rollback() failed: %v", err)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() after Rollback() failed: %v", err)
+	}
+
+	exists, err := s.Exists(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev)
+	if err != nil || exists {
+		t.Errorf("store.Exists(...) after rollback = %v, %v, want false", exists, err)
+	}
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, "other-id", storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) after rollback failed: %v", err)
+	}
+	if got.Seconds != 1 {
+		t.Errorf("store.Read(...) after rollback = %v, want 1 second", got)
+	}
+}
+
+func testTxMakeUpdate(t *testing.T, s storage.Store, opts *Options) {
+	tx, err := s.Tx(false)
+	if err != nil {
+		t.Fatalf("store.Tx(false) failed: %v", err)
+	}
+	if tx.IsUpdate() {
+		t.Errorf("tx.IsUpdate() = true, want false")
+	}
+	if err := tx.MakeUpdate(); err != nil {
+		t.Fatalf("tx.MakeUpdate() failed: %v", err)
+	}
+	if !tx.IsUpdate() {
+		t.Errorf("tx.IsUpdate() after MakeUpdate() = false, want true")
+	}
+	if err := s.WriteTx(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{Seconds: 1}, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) after MakeUpdate() failed: %v", err)
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+
+	exists, err := s.Exists(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev)
+	if err != nil || !exists {
+		t.Errorf("store.Exists(...) after commit = %v, %v, want true", exists, err)
+	}
+}
+
+func testLockTx(t *testing.T, s storage.Store, opts *Options) {
+	tx := s.LockTx("fake-lock", time.Hour, nil)
+	if tx == nil {
+		t.Fatalf("store.LockTx(..., 1h, nil) = nil, want a transaction")
+	}
+	if !tx.IsUpdate() {
+		t.Errorf("store.LockTx(...) returned a read-only transaction")
+	}
+	if err := tx.Finish(); err != nil {
+		t.Fatalf("tx.Finish() failed: %v", err)
+	}
+
+	if tx := s.LockTx("fake-lock", time.Hour, nil); tx != nil {
+		tx.Finish()
+		t.Errorf("store.LockTx(..., 1h, nil) within minFrequency = %v, want nil", tx)
+	}
+}
+
+func testLockTxContention(t *testing.T, s storage.Store, opts *Options) {
+	if opts.SingleTx {
+		t.Skip("store does not support concurrent transactions")
+	}
+
+	const (
+		workers  = 5
+		attempts = 200
+	)
+	// Each worker increments a counter while holding the lock.
+	// Attempts that lose the lock or fail to commit are retried.
+	var mu sync.Mutex
+	committed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < attempts; j++ {
+				if increment(s) {
+					mu.Lock()
+					committed++
+					mu.Unlock()
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if committed != workers {
+		t.Errorf("%d of %d workers committed", committed, workers)
+	}
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDatatype, fakeRealm, storage.DefaultUser, "counter", storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) failed: %v", err)
+	}
+	if got.Seconds != int64(committed) {
+		t.Errorf("counter = %d, want %d", got.Seconds, committed)
+	}
+}
+
+func testWriteIfMatchTx(t *testing.T, s storage.Store, opts *Options) {
+	v1 := &dpb.Duration{Seconds: 1}
+	v2 := &dpb.Duration{Seconds: 2}
+
+	steps := []struct {
+		name    string
+		etag    string
+		content *dpb.Duration
+		wantErr bool
+	}{
+		{name: "update missing item", etag: storage.ETag(v1), content: v1, wantErr: true},
+		{name: "update any missing item", etag: storage.AnyETag, content: v1, wantErr: true},
+		{name: "create", etag: "", content: v1},
+		{name: "create existing item", etag: "", content: v2, wantErr: true},
+		{name: "update with stale etag", etag: storage.ETag(v2), content: v2, wantErr: true},
+		{name: "update", etag: storage.ETag(v1), content: v2},
+		{name: "update any", etag: storage.AnyETag, content: v1},
+	}
+	want := &dpb.Duration{}
+	for _, step := range steps {
+		err := s.WriteIfMatchTx(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, step.etag, step.content, nil, nil)
+		if step.wantErr {
+			if !errutil.PreconditionFailed(err) {
+				t.Errorf("%s: store.WriteIfMatchTx(...) = %v, want precondition failed error", step.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: store.WriteIfMatchTx(...) failed: %v", step.name, err)
+		}
+		want = step.content
+	}
+
+	got := &dpb.Duration{}
+	if err := s.Read(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, got); err != nil {
+		t.Fatalf("store.Read(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("store.Read(...) diff (-want +got):\n%s", diff)
+	}
+}
+
+func testWriteIfMatchTxContention(t *testing.T, s storage.Store, opts *Options) {
+	if opts.SingleTx {
+		t.Skip("store does not support concurrent transactions")
+	}
+
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	etag := storage.ETag(&dpb.Duration{})
+
+	// All workers update the same revision, only one of them may succeed.
+	const workers = 5
+	var mu sync.Mutex
+	succeeded := 0
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := s.WriteIfMatchTx(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, etag, &dpb.Duration{Seconds: int64(i + 1)}, nil, nil)
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("%d of %d conditional writes succeeded, want 1", succeeded, workers)
+	}
+}
+
+func testWatch(t *testing.T, s storage.Store, opts *Options) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := s.Watch(ctx, fakeDatatype, fakeRealm)
+	if err != nil {
+		t.Fatalf("store.Watch(...) failed: %v", err)
+	}
+
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	// Changes of other datatypes and realms are not included.
+	if err := s.Write("other-datatype", fakeRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	if err := s.Write(fakeDatatype, otherRealm, fakeUser, fakeID, storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+	if err := s.Delete(fakeDatatype, fakeRealm, fakeUser, fakeID, storage.LatestRev); err != nil {
+		t.Fatalf("store.Delete(...) failed: %v", err)
+	}
+	// Changes rolled back are not included.
+	tx, err := s.Tx(true)
+	if err != nil {
+		t.Fatalf("store.Tx(true) failed: %v", err)
+	}
+	if err := s.WriteTx(fakeDatatype, fakeRealm, fakeUser, "rolled-back", storage.LatestRev, &dpb.Duration{}, nil, tx); err != nil {
+		t.Fatalf("store.WriteTx(...) failed: %v", err)
+	}
+	tx.Rollback()
+	tx.Finish()
+	if err := s.Write(fakeDatatype, fakeRealm, fakeUser, "last", storage.LatestRev, &dpb.Duration{}, nil); err != nil {
+		t.Fatalf("store.Write(...) failed: %v", err)
+	}
+
+	var got []*storage.Change
+	timeout := time.After(10 * time.Second)
+	for len(got) == 0 || got[len(got)-1].ID != "last" {
+		select {
+		case c := <-changes:
+			got = append(got, c)
+		case <-timeout:
+			t.Fatalf("store.Watch(...) timed out, received %+v", got)
+		}
+	}
+	want := []*storage.Change{
+		{Datatype: fakeDatatype, Realm: fakeRealm, User: fakeUser, ID: fakeID, Rev: storage.LatestRev, Op: storage.ChangeWrite},
+		{Datatype: fakeDatatype, Realm: fakeRealm, User: fakeUser, ID: fakeID, Rev: storage.LatestRev, Op: storage.ChangeDelete},
+		{Datatype: fakeDatatype, Realm: fakeRealm, User: fakeUser, ID: "last", Rev: storage.LatestRev, Op: storage.ChangeWrite},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("store.Watch(...) changes diff (-want +got):\n%s", diff)
+	}
+
+	// The channel is closed once the context is done.
+	cancel()
+	for {
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("store.Watch(...) channel not closed after cancel")
+		}
+	}
+}
+
+// increment adds one to the counter under the lock. Returns true if committed.
+func increment(s storage.Store) bool {
+	tx := s.LockTx("fake-lock", 0, nil)
+	if tx == nil {
+		return false
+	}
+	counter := &dpb.Duration{}
+	if err := s.ReadTx(fakeDatatype, fakeRealm, storage.DefaultUser, "counter", storage.LatestRev, counter, tx); err != nil && !storage.ErrNotFound(err) {
+		tx.Rollback()
+		tx.Finish()
+		return false
+	}
+	counter.Seconds++
+	if err := s.WriteTx(fakeDatatype, fakeRealm, storage.DefaultUser, "counter", storage.LatestRev, counter, nil, tx); err != nil {
+		tx.Rollback()
+		tx.Finish()
+		return false
+	}
+	return tx.Finish() == nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sync"
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+const (
+	// WatchBufferSize is the number of changes buffered for each watcher.
+	// Changes are dropped for watchers that fall further behind.
+	WatchBufferSize = 100
+)
+
+// ChangeOp is the kind of mutation of a Change.
+type ChangeOp string
+
+const (
+	// ChangeWrite is the op of changes made by writes.
+	ChangeWrite ChangeOp = "WRITE"
+	// ChangeDelete is the op of changes made by deletes.
+	ChangeDelete ChangeOp = "DELETE"
+)
+
+// Change is a committed mutation of an item in the store.
+type Change struct {
+	Datatype string
+	Realm    string
+	User     string
+	ID       string
+	Rev      int64
+	Op       ChangeOp
+}
+
+// Matches returns true if the change is about the given datatype and realm.
+// Use AllRealms to match all realms.
+func (c *Change) Matches(datatype, realm string) bool {
+	return c.Datatype == datatype && (realm == AllRealms || c.Realm == realm)
+}
+
+// Unwatched returns true for datatypes whose changes are not delivered to
+// watchers. Locks are written by every locked transaction, so stores do not
+// add their changes to change logs.
+func Unwatched(datatype string) bool {
+	return datatype == LockDatatype || datatype == LockFenceDatatype
+}
+
+// ChangeHub delivers changes to watchers within the process.
+// Stores that are not shared by several processes use it to implement Watch.
+type ChangeHub struct {
+	mu       sync.Mutex
+	watchers map[*watcher]bool
+}
+
+type watcher struct {
+	datatype string
+	realm    string
+	ch       chan *Change
+}
+
+// NewChangeHub creates a new ChangeHub.
+func NewChangeHub() *ChangeHub {
+	return &ChangeHub{watchers: make(map[*watcher]bool)}
+}
+
+// Watch returns a channel receiving the changes of items of the datatype
+// within the realm published after the call. The channel is closed when ctx
+// is done.
+func (h *ChangeHub) Watch(ctx context.Context, datatype, realm string) <-chan *Change {
+	w := &watcher{
+		datatype: datatype,
+		realm:    realm,
+		ch:       make(chan *Change, WatchBufferSize),
+	}
+	h.mu.Lock()
+	h.watchers[w] = true
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.watchers, w)
+		close(w.ch)
+	}()
+	return w.ch
+}
+
+// Publish delivers committed changes to the watchers. It never blocks: changes
+// are dropped for watchers which have WatchBufferSize changes pending.
+func (h *ChangeHub) Publish(changes ...*Change) {
+	if len(changes) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for w := range h.watchers {
+		for _, c := range changes {
+			if Unwatched(c.Datatype) || !c.Matches(w.datatype, w.realm) {
+				continue
+			}
+			select {
+			case w.ch <- c:
+			default:
+				glog.Warningf("storage watcher of %q in realm %q is too slow: dropped change of %q", w.datatype, w.realm, c.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp" /* copybara-comment */
+)
+
+func TestChangeHub(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewChangeHub()
+	realmChanges := h.Watch(ctx, "fake-datatype", "fake-realm")
+	allChanges := h.Watch(ctx, "fake-datatype", AllRealms)
+
+	h.Publish(
+		&Change{Datatype: "fake-datatype", Realm: "fake-realm", ID: "a", Op: ChangeWrite},
+		&Change{Datatype: "other-datatype", Realm: "fake-realm", ID: "b", Op: ChangeWrite},
+		&Change{Datatype: "fake-datatype", Realm: "other-realm", ID: "c", Op: ChangeDelete},
+	)
+	cancel()
+
+	tests := []struct {
+		name    string
+		changes <-chan *Change
+		want    []string
+	}{
+		{name: "realm", changes: realmChanges, want: []string{"a"}},
+		{name: "all realms", changes: allChanges, want: []string{"a", "c"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for c := range tc.changes {
+				got = append(got, c.ID)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("changes diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangeHub_Unwatched(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewChangeHub()
+	changes := h.Watch(ctx, LockDatatype, AllRealms)
+	h.Publish(&Change{Datatype: LockDatatype, Realm: DefaultRealm, ID: "gc", Op: ChangeWrite})
+	cancel()
+
+	for c := range changes {
+		t.Errorf("received change %+v of unwatched datatype", c)
+	}
+}
+
+func TestChangeHub_SlowWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewChangeHub()
+	changes := h.Watch(ctx, "fake-datatype", AllRealms)
+	// Publish never blocks: changes beyond the buffer are dropped.
+	for i := 0; i < WatchBufferSize+10; i++ {
+		h.Publish(&Change{Datatype: "fake-datatype", Realm: "fake-realm", ID: "a", Op: ChangeWrite})
+	}
+	cancel()
+
+	got := 0
+	for range changes {
+		got++
+	}
+	if got != WatchBufferSize {
+		t.Errorf("received %d changes, want %d", got, WatchBufferSize)
+	}
+}
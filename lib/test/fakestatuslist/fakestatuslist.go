@@ -0,0 +1,69 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakestatuslist provides in-memory visa status lists for testing.
+package fakestatuslist
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+)
+
+// bits is the number of bits per status of the lists.
+const bits = 2
+
+// Lists is a set of status lists, it implements ga4gh.StatusListFetcher.
+// Lists are created by setting a status in them.
+type Lists struct {
+	mu    sync.Mutex
+	lists map[string][]int
+}
+
+// New creates an empty set of status lists.
+func New() *Lists {
+	return &Lists{lists: map[string][]int{}}
+}
+
+// Set sets the status at index i of the list at uri.
+func (l *Lists) Set(uri string, i, status int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	statuses := l.lists[uri]
+	for len(statuses) <= i {
+		statuses = append(statuses, ga4gh.StatusValid)
+	}
+	statuses[i] = status
+	l.lists[uri] = statuses
+}
+
+// Revoke revokes the visa at index i of the list at uri.
+func (l *Lists) Revoke(uri string, i int) {
+	l.Set(uri, i, ga4gh.StatusInvalid)
+}
+
+// FetchStatusList returns the status list at uri.
+func (l *Lists) FetchStatusList(ctx context.Context, uri string) (*ga4gh.StatusList, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	statuses, ok := l.lists[uri]
+	if !ok {
+		return nil, fmt.Errorf("status list %q not found", uri)
+	}
+	return ga4gh.NewStatusList(bits, statuses)
+}
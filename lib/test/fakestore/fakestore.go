@@ -27,7 +27,14 @@ import (
 	"google.golang.org/grpc/codes" /* copybara-comment */
 	"google.golang.org/grpc/status" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
+	"github.com/golang/protobuf/ptypes" /* copybara-comment */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
+
+	tspb "github.com/golang/protobuf/ptypes/timestamp" /* copybara-comment */
+)
+
+const (
+	lockDatatype = "lock"
 )
 
 // Rev gives the string value of a revision.
@@ -47,11 +54,13 @@ type Store struct {
 	// performs its operations, and then writes it back to the chan.
 	// See https://bit.ly/37ANPk4
 	State chan State
+
+	hub *storage.ChangeHub
 }
 
 // New creates a new Store.
 func New() *Store {
-	f := &Store{State: make(chan State, 1)}
+	f := &Store{State: make(chan State, 1), hub: storage.NewChangeHub()}
 	state := State{
 		Data:    make(Data),
 		History: make(Data),
@@ -62,7 +71,9 @@ func New() *Store {
 
 // Tx creates a new transaction.
 func (s *Store) Tx(update bool) (storage.Tx, error) {
-	return NewTx(s.State, update), nil
+	tx := NewTx(s.State, update)
+	tx.hub = s.hub
+	return tx, nil
 }
 
 // Info returns information about the storage.
@@ -190,7 +201,7 @@ func (s *Store) multiRead(
 		if k.Datatype != datatype {
 			continue
 		}
-		if k.Realm != "" && k.Realm != realm {
+		if realm != storage.AllRealms && k.Realm != realm {
 			continue
 		}
 		if user != storage.MatchAllUsers && k.User != "" && k.User != user {
@@ -268,13 +279,21 @@ func (s *Store) readHistory(datatype, realm, user, id string, content *[]proto.M
 	}
 	key := Key{datatype, realm, user, id, ""}
 
-	var res []proto.Message
-	for k, v := range state.History {
-		k.Rev = ""
-		if k != key {
+	var keys []Key
+	for k := range state.History {
+		rk := k
+		rk.Rev = ""
+		if rk != key {
 			continue
 		}
-		res = append(res, proto.Clone(v))
+		keys = append(keys, k)
+	}
+	// Revisions are zero padded, so sorting them as strings gives the write order.
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Rev < keys[j].Rev })
+
+	var res []proto.Message
+	for _, k := range keys {
+		res = append(res, proto.Clone(state.History[k]))
 	}
 	*content = res
 	return nil
@@ -304,7 +323,35 @@ func (s *Store) WriteTx(datatype, realm, user, id string, rev int64, content pro
 
 	ntx.(*Tx).mu.Lock()
 	defer ntx.(*Tx).mu.Unlock()
-	return s.write(datatype, realm, user, id, rev, content, history, ntx.(*Tx).state)
+	if err := s.write(datatype, realm, user, id, rev, content, history, ntx.(*Tx).state); err != nil {
+		return err
+	}
+	ntx.(*Tx).record(&storage.Change{Datatype: datatype, Realm: realm, User: user, ID: id, Rev: rev, Op: storage.ChangeWrite})
+	return nil
+}
+
+// WriteIfMatchTx writes an item inside a transaction if the etag of the item matches.
+// Conflicting transactions fail to commit, which makes the check atomic.
+func (s *Store) WriteIfMatchTx(datatype, realm, user, id string, rev int64, etag string, content proto.Message, history proto.Message, tx storage.Tx) (ferr error) {
+	ntx := tx
+	if ntx == nil {
+		var err error
+		ntx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := ntx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	if err := storage.CheckETagTx(s, datatype, realm, user, id, etag, content, ntx); err != nil {
+		return err
+	}
+	return s.WriteTx(datatype, realm, user, id, rev, content, history, ntx)
 }
 
 func (s *Store) write(datatype, realm, user, id string, rev int64, content proto.Message, history proto.Message, state State) error {
@@ -345,13 +392,17 @@ func (s *Store) DeleteTx(datatype, realm, user, id string, rev int64, tx storage
 
 	ntx.(*Tx).mu.Lock()
 	defer ntx.(*Tx).mu.Unlock()
-	return s.delete(datatype, realm, user, id, rev, ntx.(*Tx).state)
+	if err := s.delete(datatype, realm, user, id, rev, ntx.(*Tx).state); err != nil {
+		return err
+	}
+	ntx.(*Tx).record(&storage.Change{Datatype: datatype, Realm: realm, User: user, ID: id, Rev: rev, Op: storage.ChangeDelete})
+	return nil
 }
 
 func (s *Store) delete(datatype, realm, user, id string, rev int64, state State) error {
 	key := Key{datatype, realm, user, id, Rev(rev)}
 	if _, ok := state.Data[key]; !ok {
-		return fmt.Errorf("not found")
+		return status.Errorf(codes.NotFound, "not found: %+v rev:%v", key, rev)
 	}
 	delete(state.Data, key)
 	return nil
@@ -376,12 +427,61 @@ func (s *Store) MultiDeleteTx(datatype, realm, user string, tx storage.Tx) (ferr
 
 	ntx.(*Tx).mu.Lock()
 	defer ntx.(*Tx).mu.Unlock()
-	return s.multiDelete(datatype, realm, user, ntx.(*Tx).state)
+	deleted, err := s.multiDelete(datatype, realm, user, ntx.(*Tx).state)
+	if err != nil {
+		return err
+	}
+	for _, k := range deleted {
+		ntx.(*Tx).record(&storage.Change{Datatype: k.Datatype, Realm: k.Realm, User: k.User, ID: k.ID, Rev: storage.LatestRev, Op: storage.ChangeDelete})
+	}
+	return nil
 }
 
-func (s *Store) multiDelete(datatype, realm, user string, state State) error {
+// multiDelete deletes the matching items. Returns the keys of the latest revisions deleted.
+func (s *Store) multiDelete(datatype, realm, user string, state State) ([]Key, error) {
+	var deleted []Key
 	for k := range state.Data {
-		if k.Datatype == datatype && k.Realm == realm && k.User == user {
+		if k.Datatype == datatype && k.Realm == realm && (user == storage.MatchAllUsers || k.User == user) {
+			delete(state.Data, k)
+			if k.Rev == storage.LatestRevName {
+				deleted = append(deleted, k)
+			}
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].ID < deleted[j].ID })
+	return deleted, nil
+}
+
+// MultiDeleteHistoryTx deletes the history and old revisions of items inside a transaction.
+func (s *Store) MultiDeleteHistoryTx(datatype, realm, user string, tx storage.Tx) (ferr error) {
+	ntx := tx
+	if ntx == nil {
+		var err error
+		ntx, err = s.Tx(true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := ntx.Finish()
+			if ferr == nil {
+				ferr = err
+			}
+		}()
+	}
+
+	ntx.(*Tx).mu.Lock()
+	defer ntx.(*Tx).mu.Unlock()
+	state := ntx.(*Tx).state
+	match := func(k Key) bool {
+		return k.Datatype == datatype && k.Realm == realm && (user == storage.MatchAllUsers || k.User == user)
+	}
+	for k := range state.History {
+		if match(k) {
+			delete(state.History, k)
+		}
+	}
+	for k := range state.Data {
+		if match(k) && k.Rev != storage.LatestRevName {
 			delete(state.Data, k)
 		}
 	}
@@ -403,28 +503,61 @@ func (s *Store) Wipe(ctx context.Context, realm string, batchNum, maxEntries int
 
 	ntx.(*Tx).mu.Lock()
 	defer ntx.(*Tx).mu.Unlock()
-	count, err := s.wipe(realm, ntx.(*Tx).state)
+	count, err := s.wipe(realm, maxEntries, ntx.(*Tx).state)
 	if err != nil {
 		return count, err
 	}
 	return count, nil
 }
 
-func (s *Store) wipe(realm string, state State) (int, error) {
+// wipe deletes history and then data in the realm, no more than maxEntries if positive.
+func (s *Store) wipe(realm string, maxEntries int, state State) (int, error) {
 	count := 0
-	for k := range state.Data {
-		if k.Realm == realm {
-			count++
-			delete(state.Data, k)
+	for _, d := range []Data{state.History, state.Data} {
+		for k := range d {
+			if maxEntries > 0 && count >= maxEntries {
+				return count, nil
+			}
+			if realm == storage.AllRealms || k.Realm == realm {
+				count++
+				delete(d, k)
+			}
 		}
 	}
 	return count, nil
 }
 
+// Watch returns a channel receiving the changes committed after the call.
+func (s *Store) Watch(ctx context.Context, datatype, realm string) (<-chan *storage.Change, error) {
+	return s.hub.Watch(ctx, datatype, realm), nil
+}
+
 // LockTx creates a lock with the give name.
+// Returns nil if the lock was taken less than minFrequency ago.
 func (s *Store) LockTx(lockName string, minFrequency time.Duration, tx storage.Tx) storage.Tx {
-	// TODO: not sure about the behavior for this one.
-	return tx
+	ntx := tx
+	if ntx == nil {
+		var err error
+		ntx, err = s.Tx(true)
+		if err != nil {
+			return nil
+		}
+	}
+
+	ntx.(*Tx).mu.Lock()
+	defer ntx.(*Tx).mu.Unlock()
+	state := ntx.(*Tx).state
+	key := Key{lockDatatype, storage.DefaultRealm, storage.DefaultUser, lockName, Rev(storage.LatestRev)}
+	if v, ok := state.Data[key]; ok {
+		if last, err := ptypes.Timestamp(v.(*tspb.Timestamp)); err == nil && time.Since(last) < minFrequency {
+			if tx == nil {
+				ntx.(*Tx).rolledBack = true
+			}
+			return nil
+		}
+	}
+	state.Data[key] = ptypes.TimestampNow()
+	return ntx
 }
 
 var _ storage.Store = &Store{}
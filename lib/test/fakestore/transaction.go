@@ -21,6 +21,7 @@ import (
 	glog "github.com/golang/glog" /* copybara-comment */
 	"cloud.google.com/go/datastore" /* copybara-comment: datastore */
 	"github.com/pborman/uuid" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 )
 
 // Tx is a fake transaction.
@@ -53,6 +54,11 @@ type Tx struct {
 	//   change is not rolled back, and
 	//   the Version at source matches the Version of state.
 	state State
+
+	// hub receives the changes made by the transaction when it is committed.
+	hub *storage.ChangeHub
+	// changes are the changes made by the transaction.
+	changes []*storage.Change
 }
 
 // NewTx creates a new transaction.
@@ -92,9 +98,17 @@ func (tx *Tx) Finish() error {
 	tx.state.Version = uuid.New()
 	tx.state.LastCommit = time.Now()
 	tx.source <- tx.state
+	if tx.hub != nil {
+		tx.hub.Publish(tx.changes...)
+	}
 	return nil
 }
 
+// record adds a change made by the transaction. Requires mu to be held.
+func (tx *Tx) record(change *storage.Change) {
+	tx.changes = append(tx.changes, change)
+}
+
 // Rollback attempts to rollback the transaction.
 func (tx *Tx) Rollback() error {
 	tx.mu.Lock()
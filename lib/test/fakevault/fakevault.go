@@ -0,0 +1,446 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakevault provides a minimal in-process fake of the HashiCorp Vault
+// HTTP API with a Transit and a KV version 2 secrets engine for testing.
+//
+// Example:
+//
+//   f := fakevault.New("token")
+//   client, err := vault.New(&vault.Config{
+//     Address:    "http://vault.example.com",
+//     Token:      "token",
+//     HTTPClient: httptestclient.New(f),
+//   })
+//
+package fakevault
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// TransitMount is the mount path of the Transit engine.
+	TransitMount = "transit"
+	// KVMount is the mount path of the KV engine.
+	KVMount = "secret"
+)
+
+type transitKey struct {
+	typ                  string
+	minDecryptionVersion int
+	// versions[i] is the key of version i+1, a []byte for aes256-gcm96 and a
+	// crypto.Signer otherwise.
+	versions []interface{}
+}
+
+// Server is a fake Vault, it implements http.Handler.
+type Server struct {
+	token string
+
+	mu   sync.Mutex
+	keys map[string]*transitKey
+	kv   map[string]map[string]interface{}
+}
+
+// New returns a fake Vault accepting the given token.
+func New(token string) *Server {
+	return &Server{
+		token: token,
+		keys:  map[string]*transitKey{},
+		kv:    map[string]map[string]interface{}{},
+	}
+}
+
+// CreateKey creates a Transit key of a type: aes256-gcm96, rsa-2048,
+// ecdsa-p256, ecdsa-p384 or ed25519.
+func (s *Server) CreateKey(name, typ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createKey(name, typ)
+}
+
+func (s *Server) createKey(name, typ string) error {
+	if _, ok := s.keys[name]; ok {
+		return fmt.Errorf("key %q exists", name)
+	}
+	k := &transitKey{typ: typ, minDecryptionVersion: 1}
+	if err := k.rotate(); err != nil {
+		return err
+	}
+	s.keys[name] = k
+	return nil
+}
+
+// RotateKey adds a new version to a Transit key.
+func (s *Server) RotateKey(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[name]
+	if !ok {
+		return fmt.Errorf("key %q not found", name)
+	}
+	return k.rotate()
+}
+
+// SetMinDecryptionVersion disables the versions of a Transit key below the
+// given version.
+func (s *Server) SetMinDecryptionVersion(name string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[name]
+	if !ok {
+		return fmt.Errorf("key %q not found", name)
+	}
+	k.minDecryptionVersion = version
+	return nil
+}
+
+// PutSecret sets the data of a KV secret.
+func (s *Server) PutSecret(path string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kv[path] = data
+}
+
+func (k *transitKey) rotate() error {
+	var key interface{}
+	var err error
+	switch k.typ {
+	case "aes256-gcm96":
+		b := make([]byte, 32)
+		_, err = rand.Read(b)
+		key = b
+	case "rsa-2048":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ecdsa-p256":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ecdsa-p384":
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return fmt.Errorf("unsupported key type %q", k.typ)
+	}
+	if err != nil {
+		return err
+	}
+	k.versions = append(k.versions, key)
+	return nil
+}
+
+// ServeHTTP handles requests of the Vault API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != s.token {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	req := map[string]interface{}{}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	if p := strings.TrimPrefix(path, KVMount+"/data/"); p != path && r.Method == http.MethodGet {
+		data, ok := s.kv[p]
+		if !ok {
+			writeError(w, http.StatusNotFound, "")
+			return
+		}
+		writeData(w, map[string]interface{}{"data": data})
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, TransitMount+"/"), "/")
+	if !strings.HasPrefix(path, TransitMount+"/") || len(parts) < 2 {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	op, name := parts[0], parts[1]
+	if op == "keys" && len(parts) == 2 && r.Method == http.MethodPost {
+		typ, _ := req["type"].(string)
+		if typ == "" {
+			typ = "aes256-gcm96"
+		}
+		if err := s.createKey(name, typ); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	k, ok := s.keys[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	var data interface{}
+	var err error
+	switch {
+	case op == "keys" && len(parts) == 2 && r.Method == http.MethodGet:
+		data, err = k.read()
+	case op == "keys" && len(parts) == 3 && parts[2] == "rotate" && r.Method == http.MethodPost:
+		err = k.rotate()
+	case op == "encrypt" && r.Method == http.MethodPost:
+		data, err = k.encrypt(req)
+	case op == "decrypt" && r.Method == http.MethodPost:
+		data, err = k.decrypt(req)
+	case op == "sign" && r.Method == http.MethodPost:
+		hash := "sha2-256"
+		if len(parts) == 3 {
+			hash = parts[2]
+		}
+		data, err = k.sign(hash, req)
+	default:
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeData(w, data)
+}
+
+func (k *transitKey) read() (interface{}, error) {
+	keys := map[string]interface{}{}
+	for i, key := range k.versions {
+		v := strconv.Itoa(i + 1)
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			keys[v] = 0
+			continue
+		}
+		if pub, ok := signer.Public().(ed25519.PublicKey); ok {
+			keys[v] = map[string]string{"public_key": base64.StdEncoding.EncodeToString(pub)}
+			continue
+		}
+		der, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		keys[v] = map[string]string{"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}
+	}
+	return map[string]interface{}{
+		"type":                   k.typ,
+		"latest_version":         len(k.versions),
+		"min_decryption_version": k.minDecryptionVersion,
+		"keys":                   keys,
+	}, nil
+}
+
+// version returns the requested key version, the latest by default.
+func (k *transitKey) version(req map[string]interface{}) (int, error) {
+	v := len(k.versions)
+	if f, ok := req["key_version"].(float64); ok && f > 0 {
+		v = int(f)
+	}
+	if v < k.minDecryptionVersion || v > len(k.versions) {
+		return 0, fmt.Errorf("invalid key version %d", v)
+	}
+	return v, nil
+}
+
+func (k *transitKey) gcm(version int) (cipher.AEAD, error) {
+	key, ok := k.versions[version-1].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("key type %q does not support encryption", k.typ)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeField(req map[string]interface{}, field string) ([]byte, error) {
+	s, _ := req[field].(string)
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", field, err)
+	}
+	return b, nil
+}
+
+func (k *transitKey) encrypt(req map[string]interface{}) (interface{}, error) {
+	v := len(k.versions)
+	aead, err := k.gcm(v)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decodeField(req, "plaintext")
+	if err != nil {
+		return nil, err
+	}
+	ad, err := decodeField(req, "associated_data")
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ct := aead.Seal(nonce, nonce, plaintext, ad)
+	return map[string]interface{}{
+		"ciphertext":  fmt.Sprintf("vault:v%d:%s", v, base64.StdEncoding.EncodeToString(ct)),
+		"key_version": v,
+	}, nil
+}
+
+func (k *transitKey) decrypt(req map[string]interface{}) (interface{}, error) {
+	s, _ := req["ciphertext"].(string)
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	v, err := strconv.Atoi(parts[1][1:])
+	if err != nil || v < k.minDecryptionVersion || v > len(k.versions) {
+		return nil, fmt.Errorf("invalid ciphertext key version")
+	}
+	aead, err := k.gcm(v)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(ct) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	ad, err := decodeField(req, "associated_data")
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, ct[:aead.NonceSize()], ct[aead.NonceSize():], ad)
+	if err != nil {
+		return nil, fmt.Errorf("cipher: message authentication failed")
+	}
+	return map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}, nil
+}
+
+func (k *transitKey) sign(hash string, req map[string]interface{}) (interface{}, error) {
+	v, err := k.version(req)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := k.versions[v-1].(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key type %q does not support signing", k.typ)
+	}
+	input, err := decodeField(req, "input")
+	if err != nil {
+		return nil, err
+	}
+
+	var sig []byte
+	switch key := signer.(type) {
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(key, input)
+	case *rsa.PrivateKey:
+		if alg, _ := req["signature_algorithm"].(string); alg != "pkcs1v15" {
+			return nil, fmt.Errorf("unsupported signature algorithm %q", alg)
+		}
+		h, digest, err := digest(hash, input)
+		if err != nil {
+			return nil, err
+		}
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, h, digest)
+		if err != nil {
+			return nil, err
+		}
+	case *ecdsa.PrivateKey:
+		if m, _ := req["marshaling_algorithm"].(string); m != "jws" {
+			return nil, fmt.Errorf("unsupported marshaling algorithm %q", m)
+		}
+		_, digest, err := digest(hash, input)
+		if err != nil {
+			return nil, err
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			return nil, err
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		sig = append(pad(r, size), pad(s, size)...)
+	}
+	return map[string]interface{}{
+		"signature":   fmt.Sprintf("vault:v%d:%s", v, base64.RawURLEncoding.EncodeToString(sig)),
+		"key_version": v,
+	}, nil
+}
+
+func digest(hash string, input []byte) (crypto.Hash, []byte, error) {
+	switch hash {
+	case "sha2-256":
+		d := sha256.Sum256(input)
+		return crypto.SHA256, d[:], nil
+	case "sha2-384":
+		d := sha512.Sum384(input)
+		return crypto.SHA384, d[:], nil
+	case "sha2-512":
+		d := sha512.Sum512(input)
+		return crypto.SHA512, d[:], nil
+	}
+	return 0, nil, fmt.Errorf("unsupported hash algorithm %q", hash)
+}
+
+func pad(n *big.Int, size int) []byte {
+	b := make([]byte, size)
+	nb := n.Bytes()
+	copy(b[size-len(nb):], nb)
+	return b
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	errs := []string{}
+	if msg != "" {
+		errs = append(errs, msg)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": errs})
+}
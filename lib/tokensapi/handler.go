@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/mux" /* copybara-comment */
@@ -27,6 +28,7 @@ import (
 	"google.golang.org/grpc/status" /* copybara-comment */
 	"github.com/golang/protobuf/proto" /* copybara-comment */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/handlerfactory" /* copybara-comment: handlerfactory */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/storage" /* copybara-comment: storage */
 
 	epb "github.com/golang/protobuf/ptypes/empty" /* copybara-comment */
@@ -40,6 +42,29 @@ var (
 
 	// httpClient to call http request.
 	httpClient = http.DefaultClient
+
+	// tokenSortFields maps the "sortBy" names of the list tokens request to the
+	// sortable value of the field.
+	tokenSortFields = map[string]func(p proto.Message) string{
+		"name": func(p proto.Message) string {
+			return p.(*tpb.Token).Name
+		},
+		"iss": func(p proto.Message) string {
+			return p.(*tpb.Token).Issuer
+		},
+		"exp": func(p proto.Message) string {
+			return storage.SortInt(p.(*tpb.Token).ExpiresAt)
+		},
+		"iat": func(p proto.Message) string {
+			return storage.SortInt(p.(*tpb.Token).IssuedAt)
+		},
+		"client.name": func(p proto.Message) string {
+			return p.(*tpb.Token).GetClient().GetName()
+		},
+		"type": func(p proto.Message) string {
+			return p.(*tpb.Token).Type
+		},
+	}
 )
 
 // Token is used in TokenProvider below.
@@ -114,9 +139,12 @@ func (s *listTokensHandler) Setup(r *http.Request, tx storage.Tx) (int, error) {
 
 func (s *listTokensHandler) Get(r *http.Request, name string) (proto.Message, error) {
 	userID := mux.Vars(r)["user"]
+	order, err := storage.BuildSort(httputils.QueryParam(r, "sortBy"), httputils.QueryParam(r, "sortOrder"), tokenSortFields)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	resp := &tpb.ListTokensResponse{}
-	var err error
 	for _, p := range s.providers {
 		var l []*Token
 		// user may only have tokens on some provider.
@@ -127,10 +155,19 @@ func (s *listTokensHandler) Get(r *http.Request, name string) (proto.Message, er
 		}
 	}
 
-	if len(resp.Tokens) > 0 {
-		return resp, nil
+	if len(resp.Tokens) == 0 {
+		return nil, err
+	}
+	if order != nil {
+		// Tokens with the same value are ordered by name for a stable order.
+		sort.SliceStable(resp.Tokens, func(i, j int) bool {
+			if c := order.Compare(resp.Tokens[i], resp.Tokens[j]); c != 0 {
+				return c < 0
+			}
+			return resp.Tokens[i].Name < resp.Tokens[j].Name
+		})
 	}
-	return nil, err
+	return resp, nil
 }
 
 // toToken convert to pb Token
@@ -29,9 +29,9 @@ type OIDCIdentityTranslator struct {
 }
 
 // NewOIDCIdentityTranslator creates a new OIDCIdentityTranslator with the provided issuer and
-// client ID.
-func NewOIDCIdentityTranslator(ctx context.Context, issuer, clientID string) (*OIDCIdentityTranslator, error) {
-	v, err := verifier.NewPassportVerifier(ctx, issuer, clientID)
+// client ID, and options of the passport verifier.
+func NewOIDCIdentityTranslator(ctx context.Context, issuer, clientID string, opts ...verifier.Option) (*OIDCIdentityTranslator, error) {
+	v, err := verifier.NewPassportVerifier(ctx, issuer, clientID, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms" /* copybara-comment: kms */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/verifier" /* copybara-comment: verifier */
 
 	dampb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
 )
@@ -47,12 +48,13 @@ func GetPassportTranslators() *dampb.PassportTranslatorsResponse {
 	}
 }
 
-// CreateTranslator creates a Translator for a particular token issuer.
-func CreateTranslator(ctx context.Context, iss, translateUsing, clientID, publicKey, selfIssuer string, signer kms.Signer) (Translator, error) {
+// CreateTranslator creates a Translator for a particular token issuer. The
+// options are passed to the verifier of OIDC tokens.
+func CreateTranslator(ctx context.Context, iss, translateUsing, clientID, publicKey, selfIssuer string, signer kms.Signer, opts ...verifier.Option) (Translator, error) {
 	var s Translator
 	var err error
 	if translateUsing == "" {
-		s, err = NewOIDCIdentityTranslator(ctx, iss, clientID)
+		s, err = NewOIDCIdentityTranslator(ctx, iss, clientID, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create identity translator: %v", err)
 		}
@@ -30,12 +30,14 @@ type Or []Validator
 // true.  If any of the invoked validators return an error then an error is
 // returned.
 func (or Or) Validate(ctx context.Context, identity *ga4gh.Identity) (bool, error) {
+	ctx, t := ga4gh.StartTrace(ctx, ga4gh.TraceOr, "")
 	for i, v := range or {
 		ok, err := v.Validate(ctx, identity)
 		if err != nil {
 			return false, fmt.Errorf("nested validator at index %d: %v", i, err)
 		}
 		if ok {
+			t.SetResult(true)
 			return true, nil
 		}
 	}
@@ -50,6 +52,7 @@ type And []Validator
 // Validate returns false if any of the wrapped validators return false.  If
 // any of the validators returns an error then an error is returned.
 func (and And) Validate(ctx context.Context, identity *ga4gh.Identity) (bool, error) {
+	ctx, t := ga4gh.StartTrace(ctx, ga4gh.TraceAnd, "")
 	for i, v := range and {
 		ok, err := v.Validate(ctx, identity)
 		if err != nil {
@@ -59,5 +62,6 @@ func (and And) Validate(ctx context.Context, identity *ga4gh.Identity) (bool, er
 			return false, nil
 		}
 	}
+	t.SetResult(true)
 	return true, nil
 }
@@ -23,6 +23,7 @@ import (
 
 	"bitbucket.org/creachadair/stringset" /* copybara-comment */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ontology" /* copybara-comment: ontology */
 )
 
 // contextKey is just an empty struct. It exists so RequestTTLInNanoFloat64 can be an immutable public variable with a unique type. It's immutable because nobody else can create a ContextKey, being unexported.
@@ -45,6 +46,9 @@ type ClaimValidator struct {
 	IsNot       bool
 	Sources     map[string]bool
 	By          map[string]bool
+	// Terms of the Ontology which values must be, or be narrower terms of.
+	Ontology *ontology.Ontology
+	Terms    []string
 }
 
 // NewClaimValidator creates a ClaimValidator instance.
@@ -87,27 +91,33 @@ func (c *ClaimValidator) Validate(ctx context.Context, identity *ga4gh.Identity)
 	if !ok {
 		ttl = 0
 	}
-	ret := c.validate(ttl, identity)
+	_, t := ga4gh.StartTrace(ctx, ga4gh.TraceClaim, c.Name)
+	ret := c.validate(ttl, identity, t)
 	if c.IsNot {
-		return !ret, nil
+		ret = !ret
 	}
+	t.SetResult(ret)
 	return ret, nil
 }
 
-func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity) bool {
+func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity, t *ga4gh.Trace) bool {
 	tnow := time.Now()
 	now := float64(tnow.Unix())
 	vs, ok := id.GA4GH[c.Name]
 	if !ok {
 		return false
 	}
+	reject := func(v ga4gh.OldClaim, reason, field, message string) {
+		id.RejectVisa(v.VisaData, v.TokenFormat, reason, field, message)
+		t.RejectVisa(v.Issuer, c.assertion(v), reason, field, message)
+	}
 	for _, v := range vs {
 		if v.Asserted > now {
-			id.RejectVisa(v.VisaData, v.TokenFormat, "visa_before_active", "visa.asserted", "visa is not yet active (visa.asserted is in the future)")
+			reject(v, "visa_before_active", "visa.asserted", "visa is not yet active (visa.asserted is in the future)")
 			continue
 		}
 		if v.Expires < now+ttl {
-			id.RejectVisa(v.VisaData, v.TokenFormat, "visa_expired", "exp", "visa expired")
+			reject(v, "visa_expired", "exp", "visa expired")
 			continue
 		}
 		// GA4GH AAI requires that visas in AccessTokenVisaFormat need to verify their validity
@@ -117,7 +127,7 @@ func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity) bool {
 			requestedExpiry := tnow.Add(time.Duration(ttl * 1e9)) // ttl seconds to nano
 			iat := time.Unix(v.VisaData.IssuedAt, 0)
 			if requestedExpiry.Sub(iat) > time.Hour {
-				id.RejectVisa(v.VisaData, v.TokenFormat, "access_token_visa_expiry", "jku", "access token visa format not supported for access more than 1 hour, use document visa format via jku instead")
+				reject(v, "access_token_visa_expiry", "jku", "access token visa format not supported for access more than 1 hour, use document visa format via jku instead")
 				continue
 			}
 		}
@@ -132,28 +142,37 @@ func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity) bool {
 					break
 				}
 			}
+			if !match && c.Ontology != nil {
+				for _, term := range c.Terms {
+					if c.Ontology.Subsumes(term, v.Value) {
+						match = true
+						break
+					}
+				}
+			}
 		}
 		if !match {
-			id.RejectVisa(v.VisaData, v.TokenFormat, "visa_value_rejected", "visa.value", fmt.Sprintf("visa value %q not accepted by the policy", v.Value))
+			reject(v, "visa_value_rejected", "visa.value", fmt.Sprintf("visa value %q not accepted by the policy", v.Value))
 			continue
 		}
 		if len(v.Source) == 0 {
-			id.RejectVisa(v.VisaData, v.TokenFormat, "visa_source_missing", "visa.source", "visa source is empty")
+			reject(v, "visa_source_missing", "visa.source", "visa source is empty")
 			continue
 		}
 		if len(c.Sources) > 0 {
 			if _, ok := c.Sources[v.Source]; !ok {
-				id.RejectVisa(v.VisaData, v.TokenFormat, "visa_source_rejected", "visa.source", fmt.Sprintf("visa source %q not accepted by the policy", v.Source))
+				reject(v, "visa_source_rejected", "visa.source", fmt.Sprintf("visa source %q not accepted by the policy", v.Source))
 				continue
 			}
 		}
 		if len(c.By) > 0 {
 			if _, ok := c.By[v.By]; !ok {
-				id.RejectVisa(v.VisaData, v.TokenFormat, "visa_by_rejected", "visa.by", fmt.Sprintf("visa by %q not accepted by the policy", v.By))
+				reject(v, "visa_by_rejected", "visa.by", fmt.Sprintf("visa by %q not accepted by the policy", v.By))
 				continue
 			}
 		}
 		if len(v.Condition) == 0 {
+			t.AcceptVisa(v.Issuer, c.assertion(v))
 			return true
 		}
 
@@ -162,7 +181,7 @@ func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity) bool {
 			match = false
 			idcList, ok := id.GA4GH[ck]
 			if !ok {
-				id.RejectVisa(v.VisaData, v.TokenFormat, "visa_by_rejected", "visa.by", fmt.Sprintf("visa by %q not accepted by the policy", v.By))
+				reject(v, "visa_by_rejected", "visa.by", fmt.Sprintf("visa by %q not accepted by the policy", v.By))
 				continue
 			}
 			for _, idc := range idcList {
@@ -178,6 +197,9 @@ func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity) bool {
 				if len(cv.By) > 0 && !stringset.Contains(cv.By, idc.By) {
 					continue
 				}
+				if ga4gh.MatchDUOTerms(cv.DUOTerms, idc.Value) != nil {
+					continue
+				}
 				match = true
 				break
 			}
@@ -186,8 +208,21 @@ func (c *ClaimValidator) validate(ttl float64, id *ga4gh.Identity) bool {
 			}
 		}
 		if match {
+			t.AcceptVisa(v.Issuer, c.assertion(v))
 			return true
 		}
+		t.RejectVisa(v.Issuer, c.assertion(v), "visa_condition_unmet", "visa.condition", "visa conditions are not met by other visas")
 	}
 	return false
 }
+
+// assertion is the assertion of the claim v of the validated type.
+func (c *ClaimValidator) assertion(v ga4gh.OldClaim) ga4gh.Assertion {
+	return ga4gh.Assertion{
+		Type:     ga4gh.Type(c.Name),
+		Value:    ga4gh.Value(v.Value),
+		Source:   ga4gh.Source(v.Source),
+		By:       ga4gh.By(v.By),
+		Asserted: ga4gh.Timestamp(v.Asserted),
+	}
+}
@@ -155,6 +155,42 @@ var (
 	}
 )
 
+// duoConditionIdentity has a visa with a condition on a research purpose visa
+// of disease specific research (DUO:0000007) by the given DUO term.
+func duoConditionIdentity(term string) *ga4gh.Identity {
+	return &ga4gh.Identity{
+		Issuer:  "https://issuer.org",
+		Subject: "subject1",
+		GA4GH: map[string][]ga4gh.OldClaim{
+			"ResearchPurpose": {
+				{
+					Value:       "DUO:0000007",
+					Source:      "https://source.org",
+					By:          "so",
+					Asserted:    testnowf - 3600,
+					Expires:     testnowf + 3600,
+					VisaData:    &ga4gh.VisaData{StdClaims: ga4gh.StdClaims{IssuedAt: testnow - 600}},
+					TokenFormat: ga4gh.DocumentVisaFormat,
+				},
+			},
+			"ControlledAccessGrants": {
+				{
+					Value:    "https://datasets.org/123",
+					Source:   "https://source.org",
+					By:       "dac",
+					Asserted: testnowf - 3600,
+					Expires:  testnowf + 10*3600,
+					Condition: map[string]ga4gh.OldClaimCondition{
+						"ResearchPurpose": {DUOTerms: []string{term}},
+					},
+					VisaData:    &ga4gh.VisaData{StdClaims: ga4gh.StdClaims{IssuedAt: testnow - 600}},
+					TokenFormat: ga4gh.DocumentVisaFormat,
+				},
+			},
+		},
+	}
+}
+
 func TestClaimValidator(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -290,6 +326,24 @@ func TestClaimValidator(t *testing.T) {
 			by:       []string{"dac"},
 			approved: false,
 		},
+		{
+			name:     "condition met by narrower duo term",
+			id:       duoConditionIdentity("DUO:0000006"),
+			claim:    "ControlledAccessGrants",
+			values:   []string{"https://datasets.org/123"},
+			sources:  []string{"https://source.org"},
+			by:       []string{"dac"},
+			approved: true,
+		},
+		{
+			name:     "condition unmet by other duo term",
+			id:       duoConditionIdentity("DUO:0000011"),
+			claim:    "ControlledAccessGrants",
+			values:   []string{"https://datasets.org/123"},
+			sources:  []string{"https://source.org"},
+			by:       []string{"dac"},
+			approved: false,
+		},
 		{
 			name:     "access token visa short lived",
 			id:       accessTokenVisaIdentity,
@@ -337,3 +391,31 @@ func strMap(strs []string) map[string]bool {
 	}
 	return out
 }
+
+func TestClaimValidator_Trace(t *testing.T) {
+	v, err := NewClaimValidator("BonaFide", []string{"https://bonafide.org/v1"}, "", strMap([]string{"https://source.org"}), nil)
+	if err != nil {
+		t.Fatalf("NewClaimValidator() failed: %v", err)
+	}
+	ctx, trace := ga4gh.WithTrace(context.Background(), ga4gh.TracePolicy, "bona_fide")
+	ok, err := Or{And{v}}.Validate(ctx, bonaFide2ndIdentity)
+	if err != nil || !ok {
+		t.Fatalf("Validate() = %v, %v, want true", ok, err)
+	}
+
+	or := trace.Children[0]
+	and := or.Children[0]
+	claim := and.Children[0]
+	if or.Kind != ga4gh.TraceOr || !or.Result || and.Kind != ga4gh.TraceAnd || !and.Result || claim.Kind != ga4gh.TraceClaim || !claim.Result {
+		t.Fatalf("trace = %+v, want or, and and claim validator traces with result true", trace)
+	}
+	if len(claim.Visas) != 2 {
+		t.Fatalf("claim trace visas = %+v, want 2 visas", claim.Visas)
+	}
+	if rv := claim.Visas[0]; rv.Matched || rv.Rejection.Reason != "visa_source_rejected" || rv.Assertion.Source != "https://badsource.com" {
+		t.Errorf("claim trace visa 0 = %+v, want visa of https://badsource.com rejected", rv)
+	}
+	if mv := claim.Visas[1]; !mv.Matched || mv.Assertion.Source != "https://source.org" {
+		t.Errorf("claim trace visa 1 = %+v, want visa of https://source.org matched", mv)
+	}
+}
@@ -40,17 +40,22 @@ func NewPolicy(allow Validator, disallow Validator) *Policy {
 // 2. the disallow is absent or it returns false.
 func (r Policy) Validate(ctx context.Context, identity *ga4gh.Identity) (bool, error) {
 	if r.Disallow != nil {
-		ok, err := r.Disallow.Validate(ctx, identity)
+		dctx, t := ga4gh.StartTrace(ctx, ga4gh.TraceDeny, "")
+		ok, err := r.Disallow.Validate(dctx, identity)
 		if err != nil {
 			return false, err
 		}
+		t.SetResult(ok)
 		if ok {
 			// Disallow is true, so validate is false (i.e. not allowed).
 			return false, nil
 		}
 	}
 	if r.Allow != nil {
-		return r.Allow.Validate(ctx, identity)
+		actx, t := ga4gh.StartTrace(ctx, ga4gh.TraceAllow, "")
+		ok, err := r.Allow.Validate(actx, identity)
+		t.SetResult(ok)
+		return ok, err
 	}
 	return true, nil
 }
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ontology" /* copybara-comment: ontology */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/strutil" /* copybara-comment: strutil */
 	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
 	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
@@ -69,10 +70,20 @@ func policyValidator(ctx context.Context, anyOf []*cpb.ConditionSet, defs map[st
 			if err != nil {
 				return nil, err
 			}
+			terms, err := expandTerms(clause.DuoTerms, args)
+			if err != nil {
+				return nil, err
+			}
 			v, err := NewClaimValidator(clause.Type, vals, "", srcs, by)
 			if err != nil {
 				return nil, err
 			}
+			if len(terms) > 0 {
+				if v.Ontology, err = ontology.DUO(); err != nil {
+					return nil, err
+				}
+				v.Terms = terms
+			}
 			vand = append(vand, v)
 		}
 		vor = append(vor, And(vand))
@@ -195,6 +206,69 @@ func expandField(input string) ([]string, error) {
 	return nil, fmt.Errorf("pattern type %q not supported", prefix)
 }
 
+// expandTerms replaces the variables of ontology terms with args.
+func expandTerms(terms []string, args map[string]string) ([]string, error) {
+	out := make([]string, 0, len(terms))
+	for i, term := range terms {
+		if len(term) == 0 {
+			return nil, fmt.Errorf("term %d is an empty string", i)
+		}
+		if args != nil {
+			var err error
+			if term, err = strutil.ReplaceVariables(term, args); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, term)
+	}
+	return out, nil
+}
+
+// concreteTerms returns the terms without variables, or all the terms with
+// the variables replaced by args if provided.
+func concreteTerms(terms []string, args map[string]string) ([]string, error) {
+	if args != nil {
+		return expandTerms(terms, args)
+	}
+	var out []string
+	for _, term := range terms {
+		vars, err := strutil.ExtractVariables(term)
+		if err != nil {
+			return nil, err
+		}
+		if len(vars) == 0 {
+			out = append(out, term)
+		}
+	}
+	return out, nil
+}
+
+// checkDUOTerms checks the DUO terms of a condition are terms of the bundled
+// DUO. Terms with variables are only checked once the variables are provided
+// by args.
+func checkDUOTerms(clause *cpb.Condition, args map[string]string) error {
+	if len(clause.DuoTerms) == 0 {
+		return nil
+	}
+	if len(clause.Value) > 0 {
+		return fmt.Errorf("value and duoTerms cannot both be set")
+	}
+	terms, err := concreteTerms(clause.DuoTerms, args)
+	if err != nil {
+		return err
+	}
+	duo, err := ontology.DUO()
+	if err != nil {
+		return err
+	}
+	for _, term := range terms {
+		if _, ok := duo.Term(term); !ok {
+			return fmt.Errorf("%q is not a DUO term", term)
+		}
+	}
+	return nil
+}
+
 // TODO: remove this helper function
 func toPattern(input string) string {
 	if !strings.Contains(input, "*") && !strings.Contains(input, "?") {
@@ -218,6 +292,84 @@ func validateVisaType(typ string, defs map[string]*pb.VisaType) error {
 	return nil
 }
 
+// checkClauseVisaType checks visas conforming to the constraints of the visa
+// type of the clause can meet the clause. Values with variables are only
+// checked once the variables are provided by args. Returns the field of the
+// clause which cannot be met.
+func checkClauseVisaType(clause *cpb.Condition, vt *pb.VisaType, sources map[string]*pb.TrustedSource, args map[string]string) (string, error) {
+	v, err := NewVisaTypeValidator(clause.Type, vt)
+	if err != nil {
+		return "type", err
+	}
+	if v == nil {
+		return "", nil
+	}
+
+	terms, err := concreteTerms(clause.DuoTerms, args)
+	if err != nil {
+		return "duoTerms", err
+	}
+	for _, term := range terms {
+		if err := v.CheckValue(term); err != nil {
+			return "duoTerms", fmt.Errorf("visa type %q %v", clause.Type, err)
+		}
+	}
+
+	vars, err := strutil.ExtractVariables(clause.Value)
+	if err != nil {
+		return "value", err
+	}
+	if args != nil || len(vars) == 0 {
+		vals, err := expandValues(clause.Value, args)
+		if err != nil {
+			return "value", err
+		}
+		for _, val := range vals {
+			if len(val) > 0 && val[0] == '^' {
+				// Regexp values may match conforming visas.
+				continue
+			}
+			if err := v.CheckValue(val); err != nil {
+				return "value", fmt.Errorf("visa type %q %v", clause.Type, err)
+			}
+		}
+	}
+
+	from, err := expandField(clause.Source)
+	if err != nil {
+		return "source", err
+	}
+	for _, f := range from {
+		srcs := []string{f}
+		if !strutil.IsURL(f) {
+			if source, ok := sources[f]; ok {
+				srcs = source.Sources
+			}
+		}
+		accepted := false
+		for _, src := range srcs {
+			if v.CheckSource(src) == nil {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return "source", fmt.Errorf("source %q is not accepted for visa type %q", f, clause.Type)
+		}
+	}
+
+	by, err := expandField(clause.By)
+	if err != nil {
+		return "by", err
+	}
+	for _, b := range by {
+		if err := v.CheckBy(b); err != nil {
+			return "by", err
+		}
+	}
+	return "", nil
+}
+
 // ValidatePolicy does basic validation for a policy and (optionally) the variable "args" that a policy instantiation uses.
 func ValidatePolicy(policy *pb.Policy, defs map[string]*pb.VisaType, sources map[string]*pb.TrustedSource, args map[string]string) (string, error) {
 	usedArgs := make(map[string]bool)
@@ -240,6 +392,9 @@ func ValidatePolicy(policy *pb.Policy, defs map[string]*pb.VisaType, sources map
 			if _, err := expandValues(clause.Value, valArgs); err != nil {
 				return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), "value"), err
 			}
+			if _, err := expandTerms(clause.DuoTerms, valArgs); err != nil {
+				return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), "duoTerms"), err
+			}
 			valArgs, err := strutil.ExtractVariables(clause.Value)
 			if err != nil {
 				return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), "value"), err
@@ -247,9 +402,24 @@ func ValidatePolicy(policy *pb.Policy, defs map[string]*pb.VisaType, sources map
 			for arg := range valArgs {
 				usedArgs[arg] = true
 			}
+			if err := checkDUOTerms(clause, args); err != nil {
+				return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), "duoTerms"), err
+			}
+			for _, term := range clause.DuoTerms {
+				termVars, err := strutil.ExtractVariables(term)
+				if err != nil {
+					return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), "duoTerms"), err
+				}
+				for arg := range termVars {
+					usedArgs[arg] = true
+				}
+			}
 			if _, err := expandBy(clause.By); err != nil {
 				return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), "by"), err
 			}
+			if field, err := checkClauseVisaType(clause, defs[clause.Type], sources, args); err != nil {
+				return httputils.StatusPath("anyOf", strconv.Itoa(i), "allOf", strconv.Itoa(j), field), err
+			}
 		}
 	}
 	for name, v := range policy.VariableDefinitions {
@@ -15,8 +15,11 @@
 package validator
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
 	cpb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/common/v1" /* copybara-comment: go_proto */
 	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
 )
@@ -91,9 +94,68 @@ func TestValidatePolicy(t *testing.T) {
 				"BAR": "bar",
 			},
 		},
+		{
+			name: "conforming to visa type",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:   "ConsentCode",
+					Value:  "split_pattern:DUO:0000007;DUO:00000*",
+					Source: "split_pattern:SourceGroup1;https://dac.example.org/ds1",
+					By:     "const:dac",
+				}}}},
+			},
+		},
+		{
+			name: "visa type variable policy",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:  "ConsentCode",
+					Value: "const:DUO:${CODE}",
+				}}}},
+				VariableDefinitions: map[string]*pb.VariableFormat{
+					"CODE": &pb.VariableFormat{
+						Regexp: "^[0-9]+$",
+						Ui: map[string]string{
+							"description": "Code",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "duo policy",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:     "VisaType1",
+					DuoTerms: []string{"DUO:0000006", "DUO:0000011"},
+				}}}},
+			},
+		},
+		{
+			name: "duo variable policy",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:     "VisaType1",
+					DuoTerms: []string{"${CONSENT}"},
+				}}}},
+				VariableDefinitions: map[string]*pb.VariableFormat{
+					"CONSENT": &pb.VariableFormat{
+						Regexp: "^DUO:[0-9]{7}$",
+						Ui: map[string]string{
+							"description": "Consent code",
+						},
+					},
+				},
+			},
+		},
 	}
 	defs := map[string]*pb.VisaType{
 		"VisaType1": &pb.VisaType{},
+		"ConsentCode": &pb.VisaType{
+			ValueRegex: "DUO:[0-9]{7}",
+			Sources:    []string{"source1.1", "^https://dac\\.example\\.org/.*$"},
+			By:         []string{"dac"},
+		},
 	}
 	sources := map[string]*pb.TrustedSource{
 		"SourceGroup1": &pb.TrustedSource{
@@ -260,9 +322,111 @@ func TestValidatePolicyErrors(t *testing.T) {
 				"BAR": "but",
 			},
 		},
+		{
+			name: "value not conforming to visa type",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:  "ConsentCode",
+					Value: "split_pattern:DUO:0000007;DUO:7",
+				}}}},
+			},
+		},
+		{
+			name: "variable value not conforming to visa type",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:  "ConsentCode",
+					Value: "const:DUO:${CODE}",
+				}}}},
+				VariableDefinitions: map[string]*pb.VariableFormat{
+					"CODE": &pb.VariableFormat{
+						Regexp: "^[0-9]+$",
+						Ui: map[string]string{
+							"description": "Code",
+						},
+					},
+				},
+			},
+			args: map[string]string{
+				"CODE": "7",
+			},
+		},
+		{
+			name: "source not accepted by visa type",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:   "ConsentCode",
+					Value:  "const:DUO:0000007",
+					Source: "split_pattern:SourceGroup1;SourceGroup2",
+				}}}},
+			},
+		},
+		{
+			name: "by not accepted by visa type",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:  "ConsentCode",
+					Value: "const:DUO:0000007",
+					By:    "split_pattern:dac;so",
+				}}}},
+			},
+		},
+		{
+			name: "unknown duo term",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:     "VisaType1",
+					DuoTerms: []string{"DUO:0000006", "disease specific research"},
+				}}}},
+			},
+		},
+		{
+			name: "duo terms along with value",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:     "VisaType1",
+					Value:    "const:DUO:0000006",
+					DuoTerms: []string{"DUO:0000006"},
+				}}}},
+			},
+		},
+		{
+			name: "undefined duo term variable",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:     "VisaType1",
+					DuoTerms: []string{"${CONSENT}"},
+				}}}},
+			},
+		},
+		{
+			name: "unknown duo term variable",
+			policy: &pb.Policy{
+				AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+					Type:     "VisaType1",
+					DuoTerms: []string{"${CONSENT}"},
+				}}}},
+				VariableDefinitions: map[string]*pb.VariableFormat{
+					"CONSENT": &pb.VariableFormat{
+						Regexp: "^DUO:[0-9]{7}$",
+						Ui: map[string]string{
+							"description": "Consent code",
+						},
+					},
+				},
+			},
+			args: map[string]string{
+				"CONSENT": "DUO:9999999",
+			},
+		},
 	}
 	defs := map[string]*pb.VisaType{
 		"VisaType1": &pb.VisaType{},
+		"ConsentCode": &pb.VisaType{
+			ValueRegex: "DUO:[0-9]{7}",
+			Sources:    []string{"source1.1", "^https://dac\\.example\\.org/.*$"},
+			By:         []string{"dac"},
+		},
 	}
 	sources := map[string]*pb.TrustedSource{
 		"SourceGroup1": &pb.TrustedSource{
@@ -278,3 +442,55 @@ func TestValidatePolicyErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildPolicyValidator_DUO(t *testing.T) {
+	policy := &pb.Policy{
+		AnyOf: []*cpb.ConditionSet{{AllOf: []*cpb.Condition{{
+			Type:     "ResearchPurpose",
+			DuoTerms: []string{"${CONSENT}"},
+		}}}},
+	}
+	defs := map[string]*pb.VisaType{
+		"ResearchPurpose": &pb.VisaType{},
+	}
+	// The dataset is consented for health or medical or biomedical research.
+	args := map[string]string{"CONSENT": "DUO:0000006"}
+	v, err := BuildPolicyValidator(context.Background(), policy, defs, nil, args)
+	if err != nil {
+		t.Fatalf("BuildPolicyValidator() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		purpose string
+		want    bool
+	}{
+		{name: "same term", purpose: "DUO:0000006", want: true},
+		{name: "disease specific research is narrower", purpose: "DUO:0000007", want: true},
+		{name: "general research use is broader", purpose: "DUO:0000042", want: false},
+		{name: "population origins research is unrelated", purpose: "DUO:0000011", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id := &ga4gh.Identity{
+				GA4GH: map[string][]ga4gh.OldClaim{
+					"ResearchPurpose": {{
+						Value:       tc.purpose,
+						Source:      "https://dac.example.org",
+						By:          "dac",
+						Asserted:    float64(time.Now().Unix()) - 3600,
+						Expires:     float64(time.Now().Unix()) + 3600,
+						TokenFormat: ga4gh.DocumentVisaFormat,
+					}},
+				},
+			}
+			got, err := v.Validate(context.Background(), id)
+			if err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Validate() of purpose %q = %v, want %v", tc.purpose, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/httputils" /* copybara-comment: httputils */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/jsonschema" /* copybara-comment: jsonschema */
+	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+// Reasons of visas rejected for not conforming to their visa type.
+const (
+	VisaValueInvalidReason  = "visa_value_invalid"
+	VisaSourceInvalidReason = "visa_source_invalid"
+	VisaByInvalidReason     = "visa_by_invalid"
+)
+
+// VisaTypeValidator checks visas conform to the constraints of their visa type,
+// see pb.VisaType.
+type VisaTypeValidator struct {
+	Name    string
+	value   *regexp.Regexp
+	schema  *jsonschema.Schema
+	sources *valueMatcher
+	by      *valueMatcher
+}
+
+// NewVisaTypeValidator creates a VisaTypeValidator for the visa type, or
+// returns nil if the visa type does not constrain visas.
+func NewVisaTypeValidator(name string, vt *pb.VisaType) (*VisaTypeValidator, error) {
+	v, _, err := newVisaTypeValidator(name, vt)
+	return v, err
+}
+
+// VisaTypeValidators creates the VisaTypeValidators of the visa types which
+// constrain visas.
+func VisaTypeValidators(defs map[string]*pb.VisaType) (map[string]*VisaTypeValidator, error) {
+	out := make(map[string]*VisaTypeValidator)
+	for name, vt := range defs {
+		v, err := NewVisaTypeValidator(name, vt)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			out[name] = v
+		}
+	}
+	return out, nil
+}
+
+// ValidateVisaType does basic validation of the constraints of a visa type.
+func ValidateVisaType(name string, vt *pb.VisaType) (string, error) {
+	_, path, err := newVisaTypeValidator(name, vt)
+	return path, err
+}
+
+func newVisaTypeValidator(name string, vt *pb.VisaType) (*VisaTypeValidator, string, error) {
+	if len(vt.ValueRegex) == 0 && len(vt.ValueSchema) == 0 && len(vt.Sources) == 0 && len(vt.By) == 0 {
+		return nil, "", nil
+	}
+	v := &VisaTypeValidator{Name: name}
+	if len(vt.ValueRegex) > 0 {
+		re, err := regexp.Compile("^(?:" + vt.ValueRegex + ")$")
+		if err != nil {
+			return nil, httputils.StatusPath("valueRegex"), fmt.Errorf("visa type %q invalid regular expression %q: %v", name, vt.ValueRegex, err)
+		}
+		v.value = re
+	}
+	if len(vt.ValueSchema) > 0 {
+		schema, err := jsonschema.Compile(vt.ValueSchema)
+		if err != nil {
+			return nil, httputils.StatusPath("valueSchema"), fmt.Errorf("visa type %q invalid JSON schema: %v", name, err)
+		}
+		v.schema = schema
+	}
+	var err error
+	if v.sources, err = newValueMatcher(vt.Sources); err != nil {
+		return nil, httputils.StatusPath("sources"), fmt.Errorf("visa type %q sources: %v", name, err)
+	}
+	if v.by, err = newValueMatcher(vt.By); err != nil {
+		return nil, httputils.StatusPath("by"), fmt.Errorf("visa type %q by: %v", name, err)
+	}
+	return v, "", nil
+}
+
+// CheckValue checks a visa value conforms to the visa type. Values which are
+// JSON objects or arrays are checked against the JSON schema of the visa type
+// as such, other values as JSON strings.
+func (v *VisaTypeValidator) CheckValue(value string) error {
+	if v.value != nil && !v.value.MatchString(value) {
+		return fmt.Errorf("value %q does not match %q", value, v.value.String())
+	}
+	if v.schema == nil {
+		return nil
+	}
+	var parsed interface{} = value
+	if trimmed := strings.TrimSpace(value); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
+			return fmt.Errorf("value is not valid JSON: %v", err)
+		}
+	}
+	if err := v.schema.Validate(parsed); err != nil {
+		return fmt.Errorf("value does not conform to the schema: %v", err)
+	}
+	return nil
+}
+
+// CheckSource checks a visa source is accepted by the visa type.
+func (v *VisaTypeValidator) CheckSource(source string) error {
+	if !v.sources.match(source) {
+		return fmt.Errorf("source %q is not accepted for visa type %q", source, v.Name)
+	}
+	return nil
+}
+
+// CheckBy checks a visa "by" is accepted by the visa type.
+func (v *VisaTypeValidator) CheckBy(by string) error {
+	if !v.by.match(by) {
+		return fmt.Errorf("by %q is not accepted for visa type %q", by, v.Name)
+	}
+	return nil
+}
+
+// Check checks a visa conforms to the visa type. Returns the reason and the
+// field of the visa which does not conform.
+func (v *VisaTypeValidator) Check(claim ga4gh.OldClaim) (string, string, error) {
+	if err := v.CheckValue(claim.Value); err != nil {
+		return VisaValueInvalidReason, "visa.value", err
+	}
+	if err := v.CheckSource(claim.Source); err != nil {
+		return VisaSourceInvalidReason, "visa.source", err
+	}
+	if err := v.CheckBy(claim.By); err != nil {
+		return VisaByInvalidReason, "visa.by", err
+	}
+	return "", "", nil
+}
+
+// valueMatcher matches constants, or regular expressions for entries starting
+// with "^" and ending with "$". A nil valueMatcher matches any value.
+type valueMatcher struct {
+	constants map[string]bool
+	regexps   []*regexp.Regexp
+}
+
+func newValueMatcher(entries []string) (*valueMatcher, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	m := &valueMatcher{constants: make(map[string]bool)}
+	for i, e := range entries {
+		if len(e) == 0 {
+			return nil, fmt.Errorf("entry %d is an empty string", i)
+		}
+		if e[0] != '^' {
+			m.constants[e] = true
+			continue
+		}
+		if e[len(e)-1] != '$' {
+			return nil, fmt.Errorf("regular expression %q is missing string terminator %q", e, "$")
+		}
+		re, err := regexp.Compile(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", e, err)
+		}
+		m.regexps = append(m.regexps, re)
+	}
+	return m, nil
+}
+
+func (m *valueMatcher) match(value string) bool {
+	if m == nil || m.constants[value] {
+		return true
+	}
+	for _, re := range m.regexps {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
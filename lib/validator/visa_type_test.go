@@ -0,0 +1,146 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	pb "github.com/GoogleCloudPlatform/healthcare-federated-access-services/proto/dam/v1" /* copybara-comment: go_proto */
+)
+
+func TestVisaTypeValidator_Check(t *testing.T) {
+	vt := &pb.VisaType{
+		ValueSchema: `{
+			"type": "object",
+			"properties": {"code": {"type": "string", "pattern": "^DUO:[0-9]{7}$"}},
+			"required": ["code"]
+		}`,
+		Sources: []string{"https://dac.example.org", "^https://[a-z]+\\.example\\.edu$"},
+		By:      []string{"dac"},
+	}
+	v, err := NewVisaTypeValidator("ConsentCode", vt)
+	if err != nil {
+		t.Fatalf("NewVisaTypeValidator() failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		claim      ga4gh.OldClaim
+		wantReason string
+		wantField  string
+	}{
+		{
+			name:  "conforming",
+			claim: ga4gh.OldClaim{Value: `{"code": "DUO:0000007"}`, Source: "https://stanford.example.edu", By: "dac"},
+		},
+		{
+			name:       "value not JSON",
+			claim:      ga4gh.OldClaim{Value: "DUO:0000007", Source: "https://dac.example.org", By: "dac"},
+			wantReason: VisaValueInvalidReason,
+			wantField:  "visa.value",
+		},
+		{
+			name:       "value not conforming",
+			claim:      ga4gh.OldClaim{Value: `{"code": "DUO:7"}`, Source: "https://dac.example.org", By: "dac"},
+			wantReason: VisaValueInvalidReason,
+			wantField:  "visa.value",
+		},
+		{
+			name:       "source not accepted",
+			claim:      ga4gh.OldClaim{Value: `{"code": "DUO:0000007"}`, Source: "https://example.com", By: "dac"},
+			wantReason: VisaSourceInvalidReason,
+			wantField:  "visa.source",
+		},
+		{
+			name:       "by not accepted",
+			claim:      ga4gh.OldClaim{Value: `{"code": "DUO:0000007"}`, Source: "https://dac.example.org", By: "self"},
+			wantReason: VisaByInvalidReason,
+			wantField:  "visa.by",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, field, err := v.Check(tc.claim)
+			if (err != nil) != (len(tc.wantReason) > 0) {
+				t.Fatalf("Check(%+v) = %v, want error %v", tc.claim, err, len(tc.wantReason) > 0)
+			}
+			if reason != tc.wantReason || field != tc.wantField {
+				t.Errorf("Check(%+v) = (%q, %q), want (%q, %q)", tc.claim, reason, field, tc.wantReason, tc.wantField)
+			}
+		})
+	}
+}
+
+func TestVisaTypeValidator_CheckValueRegex(t *testing.T) {
+	v, err := NewVisaTypeValidator("ConsentCode", &pb.VisaType{ValueRegex: "DUO:[0-9]{7}"})
+	if err != nil {
+		t.Fatalf("NewVisaTypeValidator() failed: %v", err)
+	}
+	if err := v.CheckValue("DUO:0000007"); err != nil {
+		t.Errorf("CheckValue(DUO:0000007) failed: %v", err)
+	}
+	// The regular expression must match the whole value.
+	if err := v.CheckValue("DUO:00000071"); err == nil {
+		t.Errorf("CheckValue(DUO:00000071) succeeded, want error")
+	}
+}
+
+func TestNewVisaTypeValidator_Unconstrained(t *testing.T) {
+	v, err := NewVisaTypeValidator("VisaType1", &pb.VisaType{Ui: map[string]string{"label": "Visa Type 1"}})
+	if err != nil || v != nil {
+		t.Errorf("NewVisaTypeValidator() = (%v, %v), want (nil, nil)", v, err)
+	}
+}
+
+func TestValidateVisaType_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		vt       *pb.VisaType
+		wantPath string
+	}{
+		{
+			name:     "invalid value regex",
+			vt:       &pb.VisaType{ValueRegex: "DUO:[0-9"},
+			wantPath: "valueRegex",
+		},
+		{
+			name:     "invalid value schema",
+			vt:       &pb.VisaType{ValueSchema: `{"type": "date"}`},
+			wantPath: "valueSchema",
+		},
+		{
+			name:     "unterminated source regex",
+			vt:       &pb.VisaType{Sources: []string{"^https://.*"}},
+			wantPath: "sources",
+		},
+		{
+			name:     "empty by",
+			vt:       &pb.VisaType{By: []string{""}},
+			wantPath: "by",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := ValidateVisaType("ConsentCode", tc.vt)
+			if err == nil {
+				t.Fatalf("ValidateVisaType(%+v) succeeded, want error", tc.vt)
+			}
+			if path != tc.wantPath {
+				t.Errorf("ValidateVisaType(%+v) path = %q, want %q", tc.vt, path, tc.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultKVField is the field of a KV secret read if the key has none.
+	DefaultKVField = "value"
+)
+
+// KV reads secrets of a version 2 KV secrets engine.
+// See: https://www.vaultproject.io/api-docs/secret/kv/kv-v2
+type KV struct {
+	client *Client
+	mount  string
+}
+
+// NewKV returns a KV for the secrets engine mounted at the given path, such
+// as "secret".
+func NewKV(client *Client, mount string) *KV {
+	return &KV{client: client, mount: strings.Trim(mount, "/")}
+}
+
+// GetSecret gets a field of a version of a secret, the latest if version is
+// empty or "latest". The key is the path of the secret, optionally followed
+// by "#" and the field, which defaults to DefaultKVField. For example
+// "dam/hydra#client_secret".
+func (s *KV) GetSecret(ctx context.Context, key, version string) (string, error) {
+	path, field := key, DefaultKVField
+	if i := strings.LastIndex(key, "#"); i >= 0 {
+		path, field = key[:i], key[i+1:]
+	}
+	path = strings.Trim(path, "/")
+	if path == "" || field == "" {
+		return "", fmt.Errorf("invalid secret key %q", key)
+	}
+
+	query := ""
+	if version != "" && version != "latest" {
+		if _, err := strconv.Atoi(version); err != nil {
+			return "", fmt.Errorf("invalid secret version %q", version)
+		}
+		query = "?version=" + version
+	}
+
+	out := &struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := s.client.Read(ctx, s.mount+"/data/"+path+query, out); err != nil {
+		return "", err
+	}
+	v, ok := out.Data[field]
+	if !ok {
+		return "", fmt.Errorf("secret %q has no field %q", path, field)
+	}
+	str, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("field %q of secret %q is not a string", field, path)
+	}
+	return str, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vault contains a minimal client of the HashiCorp Vault HTTP API and
+// a client of its KV secrets engine.
+// See: https://www.vaultproject.io/api-docs
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxResponseSize is the maximum size of a response read from Vault.
+	maxResponseSize = 4 << 20
+)
+
+// Config of a Client. Address and Token are typically from the standard
+// VAULT_ADDR and VAULT_TOKEN environment variables.
+type Config struct {
+	// Address of Vault, such as "https://vault.example.com:8200".
+	Address string
+	// Token to authenticate to Vault.
+	Token string
+	// Namespace of Vault Enterprise, optional.
+	Namespace string
+	// HTTPClient to make requests with, defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Client of the Vault HTTP API.
+type Client struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// New returns a Client.
+func New(cfg *Config) (*Client, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("vault token is required")
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{
+		addr:      strings.TrimSuffix(cfg.Address, "/"),
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		client:    client,
+	}, nil
+}
+
+// response is the envelope of the responses of the Vault API.
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []string        `json:"errors"`
+}
+
+// Error is an error response of Vault.
+type Error struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// IsNotFound returns true if err is a Vault response for a missing path.
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// Read reads the data of a path, such as "transit/keys/my-key", into out.
+func (c *Client) Read(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// Write writes the request to a path and reads the data of the response into
+// out if not nil.
+func (c *Client) Write(ctx context.Context, path string, req, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, req, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, req, out interface{}) error {
+	var body []byte
+	if req != nil {
+		var err error
+		body, err = json.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("encoding vault request: %v", err)
+		}
+	}
+	r, err := http.NewRequest(method, c.addr+"/v1/"+strings.TrimPrefix(path, "/"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r = r.WithContext(ctx)
+	r.Header.Set("X-Vault-Token", c.token)
+	if c.namespace != "" {
+		r.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if req != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(r)
+	if err != nil {
+		return fmt.Errorf("vault request %s %q failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("reading vault response of %q: %v", path, err)
+	}
+
+	res := &response{}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, res); err != nil {
+			return fmt.Errorf("decoding vault response of %q: %v", path, err)
+		}
+	}
+	if resp.StatusCode >= 300 {
+		return &Error{StatusCode: resp.StatusCode, Errors: res.Errors}
+	}
+	if out == nil {
+		return nil
+	}
+	if len(res.Data) == 0 {
+		return fmt.Errorf("vault response of %q has no data", path)
+	}
+	if err := json.Unmarshal(res.Data, out); err != nil {
+		return fmt.Errorf("decoding vault data of %q: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/fakevault" /* copybara-comment: fakevault */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/test/httptestclient" /* copybara-comment: httptestclient */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/vault" /* copybara-comment: vault */
+)
+
+func newClient(t *testing.T, f *fakevault.Server, token string) *vault.Client {
+	t.Helper()
+	c, err := vault.New(&vault.Config{
+		Address:    "http://vault.example.com/",
+		Token:      token,
+		HTTPClient: httptestclient.New(f),
+	})
+	if err != nil {
+		t.Fatalf("vault.New() failed: %v", err)
+	}
+	return c
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := vault.New(&vault.Config{Token: "t"}); err == nil {
+		t.Errorf("vault.New() without address wants error")
+	}
+	if _, err := vault.New(&vault.Config{Address: "http://vault.example.com"}); err == nil {
+		t.Errorf("vault.New() without token wants error")
+	}
+}
+
+func TestKV_GetSecret(t *testing.T) {
+	ctx := context.Background()
+	f := fakevault.New("token")
+	f.PutSecret("dam/hydra", map[string]interface{}{
+		"value":         "v",
+		"client_secret": "s",
+		"port":          1234,
+	})
+	kv := vault.NewKV(newClient(t, f, "token"), fakevault.KVMount)
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "default field", key: "dam/hydra", want: "v"},
+		{name: "field", key: "dam/hydra#client_secret", want: "s"},
+		{name: "leading slash", key: "/dam/hydra#client_secret", want: "s"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := kv.GetSecret(ctx, tc.key, "")
+			if err != nil {
+				t.Fatalf("GetSecret(%q) failed: %v", tc.key, err)
+			}
+			if got != tc.want {
+				t.Errorf("GetSecret(%q) = %q, want %q", tc.key, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKV_GetSecret_Error(t *testing.T) {
+	ctx := context.Background()
+	f := fakevault.New("token")
+	f.PutSecret("dam/hydra", map[string]interface{}{"port": 1234})
+	kv := vault.NewKV(newClient(t, f, "token"), fakevault.KVMount)
+
+	for _, key := range []string{"", "dam/hydra#", "dam/hydra", "dam/hydra#port", "dam/missing"} {
+		if _, err := kv.GetSecret(ctx, key, ""); err == nil {
+			t.Errorf("GetSecret(%q) wants error", key)
+		}
+	}
+
+	_, err := kv.GetSecret(ctx, "dam/missing", "")
+	if !vault.IsNotFound(err) {
+		t.Errorf("GetSecret() of missing secret = %v, wants not found", err)
+	}
+}
+
+func TestClient_PermissionDenied(t *testing.T) {
+	f := fakevault.New("token")
+	kv := vault.NewKV(newClient(t, f, "wrong"), fakevault.KVMount)
+
+	_, err := kv.GetSecret(context.Background(), "dam/hydra", "")
+	e, ok := err.(*vault.Error)
+	if !ok || e.StatusCode != http.StatusForbidden {
+		t.Errorf("GetSecret() = %v, wants vault error with status %d", err, http.StatusForbidden)
+	}
+}
@@ -39,6 +39,23 @@ func (s *passportAudienceVerifier) Verify(claims *ga4gh.StdClaims, opts ...Optio
 	return fmt.Errorf("token does not have required audience")
 }
 
+type selfContainedPassportAudienceVerifier struct {
+	clientID string
+}
+
+func (s *selfContainedPassportAudienceVerifier) Verify(claims *ga4gh.StdClaims, opts ...Option) error {
+	// A passport grants access to the services in its audience, so it is
+	// rejected unless its audience contains the given client.
+	if len(s.clientID) == 0 {
+		return fmt.Errorf("token audience cannot be verified: no client id is set to allow")
+	}
+	if stringset.Contains([]string(claims.Audience), s.clientID) {
+		return nil
+	}
+
+	return fmt.Errorf("token does not have required audience")
+}
+
 type visaAudienceVerifier struct {
 	prefix string
 }
@@ -27,4 +27,5 @@ const (
 	errExpired              = "token:expired"
 	errFutureToken          = "token:future_token"
 	errUserinfoInvalidToken = "token:userinfo_invalid_token"
+	errUntrustedJKU         = "token:untrusted_jku"
 )
@@ -27,15 +27,18 @@ import (
 type jkuVisaSigVerifier struct {
 	issuer string
 	jku    string
+	algs   []string
 	keyset oidc.KeySet
 }
 
-// newJkuVisaSigVerifier creates a extractClaimsAndVerifyToken for jku jwt visa tokens.
-func newJkuVisaSigVerifier(ctx context.Context, issuer, jku string) *jkuVisaSigVerifier {
+// newJkuVisaSigVerifier creates a extractClaimsAndVerifyToken for jku jwt visa
+// tokens signed with one of the given algorithms.
+func newJkuVisaSigVerifier(ctx context.Context, issuer, jku string, opts []Option) *jkuVisaSigVerifier {
 	return &jkuVisaSigVerifier{
 		issuer: issuer,
 		jku:    jku,
-		keyset: oidc.NewRemoteKeySet(ctx, jku),
+		algs:   signingAlgorithms(opts),
+		keyset: keySet(ctx, jku, opts),
 	}
 }
 
@@ -56,6 +59,10 @@ func (s *jkuVisaSigVerifier) PreviewClaimsBeforeVerification(ctx context.Context
 }
 
 func (s *jkuVisaSigVerifier) VerifySig(ctx context.Context, token string) error {
+	// The key set verifies the signature with any algorithm of the keys.
+	if err := ga4gh.CheckSigningAlgorithm(token, s.algs); err != nil {
+		return err
+	}
 	_, err := s.keyset.VerifySignature(ctx, token)
 	return err
 }
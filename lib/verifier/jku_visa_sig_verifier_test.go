@@ -398,3 +398,40 @@ func TestJKUVerifier_Verify_Visa_Aud(t *testing.T) {
 		}
 	})
 }
+
+func TestJKUVerifier_Verify_Fail_AlgorithmNotAllowed(t *testing.T) {
+	f, cleanup := newFix(t)
+	defer cleanup()
+
+	key := f.Issuer0.Keys[0]
+	d := &ga4gh.VisaData{
+		StdClaims: ga4gh.StdClaims{
+			Issuer:    f.Issuer0.URL,
+			Subject:   subject,
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	issuer := f.Issuer0.URL
+	jku := jkuURL(issuer)
+	signer := localsign.New(&key)
+	visa, err := ga4gh.NewVisaFromData(context.Background(), d, jku, signer)
+	if err != nil {
+		t.Fatalf("ga4gh.NewVisaFromData() failed: %v", err)
+	}
+
+	// Make calls by oidc package use the fake HTTP client.
+	ctx := oidc.ClientContext(context.Background(), f.HTTP.Client)
+
+	v, err := NewVisaVerifier(ctx, issuer, jku, "", AlgorithmsOption([]string{"ES256"}))
+	if err != nil {
+		t.Fatalf("NewVisaVerifier() failed: %v", err)
+	}
+
+	err = v.Verify(ctx, string(visa.JWT()), jku)
+	if err == nil {
+		t.Fatalf("Verify() should fail for RS256 visa when only ES256 is allowed")
+	}
+	if errutil.ErrorReason(err) != errInvalidSignature {
+		t.Errorf("ErrorReason() = %s want %s", errutil.ErrorReason(err), errInvalidSignature)
+	}
+}
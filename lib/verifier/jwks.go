@@ -0,0 +1,310 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2" /* copybara-comment */
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"github.com/coreos/go-oidc" /* copybara-comment */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+const (
+	defaultJWKSRefreshInterval    = 10 * time.Minute
+	defaultJWKSMinRefetchInterval = 30 * time.Second
+	defaultJWKSMaxStale           = 24 * time.Hour
+
+	// jwksFetchTimeout bounds a fetch of a key set in the background.
+	jwksFetchTimeout = 30 * time.Second
+)
+
+// JWKSCacheOptions configures a JWKSCache. Zero values use the defaults.
+type JWKSCacheOptions struct {
+	// RefreshInterval is how often key sets are refreshed, keys fetched longer
+	// ago are stale. Defaults to 10 minutes.
+	RefreshInterval time.Duration
+	// MinRefetchInterval is the minimum time between two fetches of a key set,
+	// protecting issuers from refetches caused by tokens with unknown key ids.
+	// Defaults to 30 seconds.
+	MinRefetchInterval time.Duration
+	// MaxStale is how long stale keys are still used while their issuer is
+	// unavailable. Defaults to 24 hours.
+	MaxStale time.Duration
+}
+
+// JWKSCache is a cache of JSON web key sets shared by verifiers, see
+// JWKSCacheOption. Stale keys are used while they are refreshed in the
+// background, and until MaxStale when the issuer is down.
+type JWKSCache struct {
+	opts JWKSCacheOptions
+	now  func() time.Time
+
+	mu   sync.Mutex
+	sets map[jwksKey]*jwks
+}
+
+// jwksKey identifies a key set. Key sets fetched with different HTTP clients
+// are kept apart, clients may reach different servers.
+type jwksKey struct {
+	url    string
+	client *http.Client
+}
+
+// NewJWKSCache creates a JWKSCache. Run refreshes the key sets in the
+// background.
+func NewJWKSCache(opts JWKSCacheOptions) *JWKSCache {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = defaultJWKSRefreshInterval
+	}
+	if opts.MinRefetchInterval <= 0 {
+		opts.MinRefetchInterval = defaultJWKSMinRefetchInterval
+	}
+	if opts.MaxStale <= 0 {
+		opts.MaxStale = defaultJWKSMaxStale
+	}
+	return &JWKSCache{
+		opts: opts,
+		now:  time.Now,
+		sets: map[jwksKey]*jwks{},
+	}
+}
+
+// KeySet returns the key set at the given URL. Keys are fetched with the HTTP
+// client of the ctx, see oidc.ClientContext.
+func (c *JWKSCache) KeySet(ctx context.Context, jwksURL string) oidc.KeySet {
+	client := http.DefaultClient
+	if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		client = hc
+	}
+	key := jwksKey{url: jwksURL, client: client}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sets[key]
+	if !ok {
+		s = &jwks{cache: c, url: jwksURL, client: client}
+		c.sets[key] = s
+	}
+	return s
+}
+
+// Run refreshes the stale key sets every RefreshInterval until ctx is done.
+func (c *JWKSCache) Run(ctx context.Context) {
+	t := time.NewTicker(c.opts.RefreshInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.refresh(ctx)
+		}
+	}
+}
+
+// refresh fetches the stale key sets.
+func (c *JWKSCache) refresh(ctx context.Context) {
+	c.mu.Lock()
+	var sets []*jwks
+	for _, s := range c.sets {
+		sets = append(sets, s)
+	}
+	c.mu.Unlock()
+
+	for _, s := range sets {
+		if _, fresh, _ := s.current(); fresh {
+			continue
+		}
+		if _, err := s.refetch(ctx); err != nil {
+			glog.Warningf("refreshing keys of %q failed: %v", s.url, err)
+		}
+	}
+}
+
+// jwks is a key set in a JWKSCache, it implements oidc.KeySet.
+type jwks struct {
+	cache  *JWKSCache
+	url    string
+	client *http.Client
+
+	// fetchMu serializes fetches, such that concurrent misses share a fetch.
+	fetchMu sync.Mutex
+
+	mu         sync.Mutex
+	keys       []jose.JSONWebKey
+	fetched    time.Time
+	attempted  time.Time
+	err        error
+	refreshing bool
+}
+
+// VerifySignature implements oidc.KeySet.
+func (s *jwks) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	jws, err := jose.ParseSigned(jwt)
+	if err != nil {
+		return nil, fmt.Errorf("malformed jwt: %v", err)
+	}
+	// JWTs signed with multiple signatures are not supported.
+	keyID := ""
+	for _, sig := range jws.Signatures {
+		keyID = sig.Header.KeyID
+		break
+	}
+
+	keys, fresh, usable := s.current()
+	if usable {
+		if payload, ok := verifyWithKeys(jws, keyID, keys); ok {
+			if !fresh {
+				s.refreshInBackground()
+			}
+			return payload, nil
+		}
+	}
+
+	// Unknown key id or no usable keys.
+	keys, err = s.refetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if payload, ok := verifyWithKeys(jws, keyID, keys); ok {
+		return payload, nil
+	}
+	return nil, errors.New("failed to verify token signature")
+}
+
+func verifyWithKeys(jws *jose.JSONWebSignature, keyID string, keys []jose.JSONWebKey) ([]byte, bool) {
+	for _, key := range keys {
+		if keyID == "" || key.KeyID == keyID {
+			if payload, err := jws.Verify(&key); err == nil {
+				return payload, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// current returns the keys, whether they are fresh and whether they may be
+// used.
+func (s *jwks) current() ([]jose.JSONWebKey, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetched.IsZero() {
+		return nil, false, false
+	}
+	age := s.cache.now().Sub(s.fetched)
+	return s.keys, age < s.cache.opts.RefreshInterval, age < s.cache.opts.MaxStale
+}
+
+// refetch fetches the keys unless they were fetched within
+// MinRefetchInterval, and returns the usable keys.
+func (s *jwks) refetch(ctx context.Context) ([]jose.JSONWebKey, error) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	s.mu.Lock()
+	limited := !s.attempted.IsZero() && s.cache.now().Sub(s.attempted) < s.cache.opts.MinRefetchInterval
+	lastErr := s.err
+	s.mu.Unlock()
+
+	if !limited {
+		lastErr = s.fetch(ctx)
+	}
+
+	keys, _, usable := s.current()
+	if usable {
+		return keys, nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("keys expired")
+	}
+	return nil, fmt.Errorf("fetching keys from %q: %v", s.url, lastErr)
+}
+
+// refreshInBackground refetches the keys without blocking the caller.
+func (s *jwks) refreshInBackground() {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			s.refreshing = false
+			s.mu.Unlock()
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+		defer cancel()
+		if _, err := s.refetch(ctx); err != nil {
+			glog.Warningf("refreshing keys of %q failed: %v", s.url, err)
+		}
+	}()
+}
+
+// fetch gets the keys from the issuer, on failure the previous keys are kept.
+func (s *jwks) fetch(ctx context.Context) error {
+	keys, err := s.get(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.cache.now()
+	s.attempted = now
+	s.err = err
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.fetched = now
+	return nil
+}
+
+func (s *jwks) get(ctx context.Context) ([]jose.JSONWebKey, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("decoding keys: %v", err)
+	}
+	return set.Keys, nil
+}
@@ -0,0 +1,280 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2" /* copybara-comment */
+	"github.com/coreos/go-oidc" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/testkeys" /* copybara-comment: testkeys */
+)
+
+// fakeJWKS serves a key set and counts the requests.
+type fakeJWKS struct {
+	mu       sync.Mutex
+	keys     []jose.JSONWebKey
+	down     bool
+	requests int
+}
+
+func (f *fakeJWKS) setKeys(keys ...testkeys.Component) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = nil
+	for _, k := range keys {
+		key := testkeys.Keys[k]
+		f.keys = append(f.keys, localsign.New(&key).PublicKeys().Keys...)
+	}
+}
+
+func (f *fakeJWKS) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *fakeJWKS) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func (f *fakeJWKS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+	if f.down {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	json.NewEncoder(w).Encode(&jose.JSONWebKeySet{Keys: f.keys})
+}
+
+// fakeClock is a clock moved by the test.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type jwksFix struct {
+	jwks   *fakeJWKS
+	server *httptest.Server
+	clock  *fakeClock
+	cache  *JWKSCache
+	ctx    context.Context
+}
+
+func newJWKSFix(t *testing.T) *jwksFix {
+	t.Helper()
+
+	f := &fakeJWKS{}
+	f.setKeys(testkeys.VisaIssuer0)
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	clock := &fakeClock{now: time.Unix(1600000000, 0)}
+	c := NewJWKSCache(JWKSCacheOptions{
+		RefreshInterval:    10 * time.Minute,
+		MinRefetchInterval: 30 * time.Second,
+		MaxStale:           time.Hour,
+	})
+	c.now = clock.Now
+
+	return &jwksFix{
+		jwks:   f,
+		server: server,
+		clock:  clock,
+		cache:  c,
+		ctx:    oidc.ClientContext(context.Background(), server.Client()),
+	}
+}
+
+func signedToken(t *testing.T, k testkeys.Component) string {
+	t.Helper()
+
+	key := testkeys.Keys[k]
+	tok, err := localsign.New(&key).SignJWT(context.Background(), &ga4gh.StdClaims{Subject: subject}, nil)
+	if err != nil {
+		t.Fatalf("SignJWT() failed: %v", err)
+	}
+	return tok
+}
+
+// waitForRequests waits for background fetches.
+func waitForRequests(t *testing.T, f *fakeJWKS, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for f.count() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("requests = %d, want %d", f.count(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestJWKSCache_FetchOnce(t *testing.T) {
+	f := newJWKSFix(t)
+	tok := signedToken(t, testkeys.VisaIssuer0)
+
+	for i := 0; i < 3; i++ {
+		// Key sets of a URL are shared.
+		ks := f.cache.KeySet(f.ctx, f.server.URL)
+		if _, err := ks.VerifySignature(f.ctx, tok); err != nil {
+			t.Fatalf("VerifySignature() %d failed: %v", i, err)
+		}
+	}
+	if got := f.jwks.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestJWKSCache_UnknownKeyID(t *testing.T) {
+	f := newJWKSFix(t)
+	ks := f.cache.KeySet(f.ctx, f.server.URL)
+	tok := signedToken(t, testkeys.VisaIssuer1)
+
+	if _, err := ks.VerifySignature(f.ctx, tok); err == nil {
+		t.Fatalf("VerifySignature() with unknown key wants error")
+	}
+	if got := f.jwks.count(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+
+	// Unknown key ids do not refetch within MinRefetchInterval.
+	for i := 0; i < 3; i++ {
+		if _, err := ks.VerifySignature(f.ctx, tok); err == nil {
+			t.Fatalf("VerifySignature() with unknown key wants error")
+		}
+	}
+	if got := f.jwks.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	// The issuer rotated its keys.
+	f.jwks.setKeys(testkeys.VisaIssuer0, testkeys.VisaIssuer1)
+	f.clock.Advance(30 * time.Second)
+	if _, err := ks.VerifySignature(f.ctx, tok); err != nil {
+		t.Fatalf("VerifySignature() of rotated key failed: %v", err)
+	}
+	if got := f.jwks.count(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestJWKSCache_StaleWhileRevalidate(t *testing.T) {
+	f := newJWKSFix(t)
+	ks := f.cache.KeySet(f.ctx, f.server.URL)
+	tok := signedToken(t, testkeys.VisaIssuer0)
+
+	if _, err := ks.VerifySignature(f.ctx, tok); err != nil {
+		t.Fatalf("VerifySignature() failed: %v", err)
+	}
+
+	// Stale keys are used while the issuer is down, and refreshed in the
+	// background.
+	f.jwks.setDown(true)
+	f.clock.Advance(11 * time.Minute)
+	if _, err := ks.VerifySignature(f.ctx, tok); err != nil {
+		t.Fatalf("VerifySignature() with stale keys failed: %v", err)
+	}
+	waitForRequests(t, f.jwks, 2)
+
+	// Stale keys are not used after MaxStale.
+	f.clock.Advance(time.Hour)
+	if _, err := ks.VerifySignature(f.ctx, tok); err == nil {
+		t.Fatalf("VerifySignature() after MaxStale wants error")
+	}
+
+	// The issuer is back.
+	f.jwks.setDown(false)
+	f.clock.Advance(30 * time.Second)
+	if _, err := ks.VerifySignature(f.ctx, tok); err != nil {
+		t.Fatalf("VerifySignature() failed: %v", err)
+	}
+}
+
+func TestJWKSCache_Refresh(t *testing.T) {
+	f := newJWKSFix(t)
+	ks := f.cache.KeySet(f.ctx, f.server.URL)
+	tok := signedToken(t, testkeys.VisaIssuer0)
+
+	if _, err := ks.VerifySignature(f.ctx, tok); err != nil {
+		t.Fatalf("VerifySignature() failed: %v", err)
+	}
+
+	// Fresh keys are not refreshed.
+	f.cache.refresh(context.Background())
+	if got := f.jwks.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	f.clock.Advance(10 * time.Minute)
+	f.cache.refresh(context.Background())
+	if got := f.jwks.count(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestJWKSCacheOption_SharedByVerifiers(t *testing.T) {
+	f := newJWKSFix(t)
+
+	key := testkeys.Keys[testkeys.VisaIssuer0]
+	issuer := "https://issuer.example.com"
+	visa, err := ga4gh.NewVisaFromData(context.Background(), &ga4gh.VisaData{
+		StdClaims: ga4gh.StdClaims{
+			Issuer:    issuer,
+			Subject:   subject,
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}, f.server.URL, localsign.New(&key))
+	if err != nil {
+		t.Fatalf("ga4gh.NewVisaFromData() failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		v, err := NewVisaVerifier(f.ctx, issuer, f.server.URL, "", JWKSCacheOption(f.cache))
+		if err != nil {
+			t.Fatalf("NewVisaVerifier() failed: %v", err)
+		}
+		if err := v.Verify(f.ctx, string(visa.JWT()), f.server.URL); err != nil {
+			t.Fatalf("Verify() %d failed: %v", i, err)
+		}
+	}
+	if got := f.jwks.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
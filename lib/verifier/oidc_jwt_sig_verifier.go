@@ -31,19 +31,30 @@ type oidcJwtSigVerifier struct {
 	verifier *oidc.IDTokenVerifier
 }
 
-// newOIDCSigVerifier creates a new oidc tok extractClaimsAndVerifyToken.
-func newOIDCSigVerifier(ctx context.Context, issuer string) (*oidcJwtSigVerifier, error) {
+// newOIDCSigVerifier creates a new oidc tok extractClaimsAndVerifyToken
+// accepting tokens signed with one of the algorithms of the options.
+func newOIDCSigVerifier(ctx context.Context, issuer string, opts []Option) (*oidcJwtSigVerifier, error) {
 	p, err := oidc.NewProvider(ctx, issuer)
 	if err != nil {
 		return nil, errutil.WithErrorReason(errCreateVerifierFailed, status.Errorf(codes.Unavailable, "create oidc failed, usually caused by service does not able reach to Hydra jwks endpoint: %v", err))
 	}
 
-	v := p.Verifier(&oidc.Config{
+	var meta struct {
+		JWKSURL string `json:"jwks_uri"`
+	}
+	if err := p.Claims(&meta); err != nil {
+		return nil, errutil.WithErrorReason(errCreateVerifierFailed, status.Errorf(codes.Unavailable, "reading oidc discovery failed: %v", err))
+	}
+
+	v := oidc.NewVerifier(issuer, keySet(ctx, meta.JWKSURL, opts), &oidc.Config{
 		// Skip client claims check if no client claims passed in.
 		SkipClientIDCheck: true,
 		// Expire check and issuer check will do explicitly.
 		SkipExpiryCheck: true,
 		SkipIssuerCheck: true,
+		// The signature is verified with the keys of the issuer for any of the
+		// algorithms, instead of the algorithms advertised by the issuer.
+		SupportedSigningAlgs: signingAlgorithms(opts),
 	})
 
 	return &oidcJwtSigVerifier{
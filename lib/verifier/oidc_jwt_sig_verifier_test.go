@@ -118,7 +118,7 @@ func TestOIDCVerifier_Verify_Fail_WrongIssuerURL(t *testing.T) {
 	// Make calls by oidc package use the fake HTTP client.
 	ctx := oidc.ClientContext(context.Background(), f.HTTP.Client)
 
-	if _, err := newOIDCSigVerifier(ctx, f.HTTP.Server.URL+"/wrong"); err == nil {
+	if _, err := newOIDCSigVerifier(ctx, f.HTTP.Server.URL+"/wrong", nil); err == nil {
 		t.Errorf("NewOIDCVerifier() wants err")
 	}
 
@@ -0,0 +1,72 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes" /* copybara-comment */
+	"google.golang.org/grpc/status" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+)
+
+// SelfContainedPassportVerifier verifies self-contained passport JWTs, see
+// ga4gh.SelfContainedPassport.
+type SelfContainedPassportVerifier struct {
+	jku string
+	tok extractClaimsAndVerifyToken
+	aud *selfContainedPassportAudienceVerifier
+}
+
+// NewSelfContainedPassportVerifier creates a verifier of the passport JWTs of
+// an issuer. The keys of passports with a "jku" header are found at jku, which
+// must be under the issuer URL. Otherwise they are found with the OIDC
+// discovery of the issuer. Passports with audiences must be for clientID.
+func NewSelfContainedPassportVerifier(ctx context.Context, issuer, jku, clientID string, opts ...Option) (*SelfContainedPassportVerifier, error) {
+	v := &SelfContainedPassportVerifier{
+		jku: jku,
+		aud: &selfContainedPassportAudienceVerifier{clientID: clientID},
+	}
+	if len(jku) > 0 {
+		if !strings.HasPrefix(jku, normalizeIssuer(issuer)+"/") {
+			return nil, errutil.WithErrorReason(errUntrustedJKU, status.Errorf(codes.Unauthenticated, "jku %q is not at issuer %q", jku, issuer))
+		}
+		v.tok = newJkuVisaSigVerifier(ctx, issuer, jku, opts)
+		return v, nil
+	}
+
+	tok, err := newOIDCSigVerifier(ctx, issuer, opts)
+	if err != nil {
+		return nil, err
+	}
+	v.tok = tok
+	return v, nil
+}
+
+// Verify verifies type, signature, timestamp, issuer, jku and audiences of a
+// passport JWT.
+func (s *SelfContainedPassportVerifier) Verify(ctx context.Context, token string) error {
+	p, err := ga4gh.NewSelfContainedPassportFromJWT(ga4gh.SelfContainedPassportJWT(token))
+	if err != nil {
+		return errutil.WithErrorReason(errParseFailed, status.Errorf(codes.Unauthenticated, "NewSelfContainedPassportFromJWT() failed: %v", err))
+	}
+	if p.JKU() != s.jku {
+		return errutil.WithErrorReason(errUntrustedJKU, status.Errorf(codes.Unauthenticated, "passport jku %q does not match the verifier", p.JKU()))
+	}
+
+	return verify(ctx, s.tok, s.aud, token, nil)
+}
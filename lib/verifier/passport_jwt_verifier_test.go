@@ -0,0 +1,135 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/kms/localsign" /* copybara-comment: localsign */
+)
+
+func TestSelfContainedPassportVerifier(t *testing.T) {
+	f, cleanup := newFix(t)
+	defer cleanup()
+
+	ctx := oidc.ClientContext(context.Background(), f.HTTP.Client)
+	key := f.Issuer0.Keys[0]
+	signer := localsign.New(&key)
+	jku := jkuURL(f.Issuer0.URL)
+
+	passport := func(jku string, d *ga4gh.SelfContainedPassportData) string {
+		t.Helper()
+		p, err := ga4gh.NewSelfContainedPassportFromData(context.Background(), d, jku, signer)
+		if err != nil {
+			t.Fatalf("ga4gh.NewSelfContainedPassportFromData() failed: %v", err)
+		}
+		return string(p.JWT())
+	}
+	data := func(aud ...string) *ga4gh.SelfContainedPassportData {
+		return &ga4gh.SelfContainedPassportData{
+			StdClaims: ga4gh.StdClaims{
+				Issuer:    f.Issuer0.URL,
+				Subject:   subject,
+				Audience:  aud,
+				ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			},
+		}
+	}
+	visa, err := ga4gh.NewVisaFromData(context.Background(), &ga4gh.VisaData{StdClaims: data().StdClaims}, jku, signer)
+	if err != nil {
+		t.Fatalf("ga4gh.NewVisaFromData() failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		jku    string
+		token  string
+		reason string
+	}{
+		{
+			name:  "jku",
+			jku:   jku,
+			token: passport(jku, data(client)),
+		},
+		{
+			name:  "oidc",
+			token: passport(ga4gh.JWTEmptyJKU, data(client)),
+		},
+		{
+			name:  "audiences",
+			jku:   jku,
+			token: passport(jku, data("other", client)),
+		},
+		{
+			name:   "empty audience",
+			jku:    jku,
+			token:  passport(jku, data()),
+			reason: errInvalidAudience,
+		},
+		{
+			name:   "other audience",
+			jku:    jku,
+			token:  passport(jku, data("other")),
+			reason: errInvalidAudience,
+		},
+		{
+			name:   "other jku",
+			jku:    jku,
+			token:  passport(ga4gh.JWTEmptyJKU, data(client)),
+			reason: errUntrustedJKU,
+		},
+		{
+			name:   "not a passport",
+			jku:    jku,
+			token:  string(visa.JWT()),
+			reason: errParseFailed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewSelfContainedPassportVerifier(ctx, f.Issuer0.URL, tc.jku, client)
+			if err != nil {
+				t.Fatalf("NewSelfContainedPassportVerifier() failed: %v", err)
+			}
+			err = v.Verify(ctx, tc.token)
+			if tc.reason == "" {
+				if err != nil {
+					t.Errorf("Verify() failed: %v", err)
+				}
+				return
+			}
+			if got := errutil.ErrorReason(err); got != tc.reason {
+				t.Errorf("Verify() = %v, want reason %s", err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestNewSelfContainedPassportVerifier_UntrustedJKU(t *testing.T) {
+	f, cleanup := newFix(t)
+	defer cleanup()
+
+	ctx := oidc.ClientContext(context.Background(), f.HTTP.Client)
+	_, err := NewSelfContainedPassportVerifier(ctx, f.Issuer0.URL, jkuURL(f.Issuer1.URL), client)
+	if got := errutil.ErrorReason(err); got != errUntrustedJKU {
+		t.Errorf("NewSelfContainedPassportVerifier() with jku of other issuer = %v, want reason %s", err, errUntrustedJKU)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+
+	glog "github.com/golang/glog" /* copybara-comment */
+)
+
+const (
+	defaultStatusListMaxAge   = 5 * time.Minute
+	defaultStatusListMaxStale = time.Hour
+
+	statusListContentType = "application/statuslist+json"
+)
+
+// StatusListCacheOptions configures a StatusListCache. Zero values use the
+// defaults.
+type StatusListCacheOptions struct {
+	// MaxAge is how long a status list is used before it is fetched again,
+	// which bounds how long a revoked visa is still accepted.
+	// Defaults to 5 minutes.
+	MaxAge time.Duration
+	// MaxStale is how long a status list is still used while its issuer is
+	// unavailable. Defaults to 1 hour.
+	MaxStale time.Duration
+}
+
+// StatusListCache fetches and caches the status lists of visa issuers, it
+// implements ga4gh.StatusListFetcher.
+type StatusListCache struct {
+	opts StatusListCacheOptions
+	now  func() time.Time
+
+	mu    sync.Mutex
+	lists map[statusListKey]*statusList
+}
+
+// statusListKey identifies a status list, see jwksKey.
+type statusListKey struct {
+	uri    string
+	client *http.Client
+}
+
+// statusList is a status list in a StatusListCache.
+type statusList struct {
+	// fetchMu serializes fetches, such that concurrent misses share a fetch.
+	fetchMu sync.Mutex
+
+	mu      sync.Mutex
+	list    *ga4gh.StatusList
+	fetched time.Time
+}
+
+// NewStatusListCache creates a StatusListCache.
+func NewStatusListCache(opts StatusListCacheOptions) *StatusListCache {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultStatusListMaxAge
+	}
+	if opts.MaxStale <= 0 {
+		opts.MaxStale = defaultStatusListMaxStale
+	}
+	return &StatusListCache{
+		opts:  opts,
+		now:   time.Now,
+		lists: map[statusListKey]*statusList{},
+	}
+}
+
+// FetchStatusList returns the status list at uri. Lists are fetched with the
+// HTTP client of the ctx, see oidc.ClientContext.
+func (c *StatusListCache) FetchStatusList(ctx context.Context, uri string) (*ga4gh.StatusList, error) {
+	client := http.DefaultClient
+	if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		client = hc
+	}
+	key := statusListKey{uri: uri, client: client}
+
+	c.mu.Lock()
+	s, ok := c.lists[key]
+	if !ok {
+		s = &statusList{}
+		c.lists[key] = s
+	}
+	c.mu.Unlock()
+
+	if l, fresh, _ := c.current(s); fresh {
+		return l, nil
+	}
+
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	// Fetched by a concurrent miss.
+	if l, fresh, _ := c.current(s); fresh {
+		return l, nil
+	}
+
+	l, err := getStatusList(ctx, client, uri)
+	if err != nil {
+		if stale, _, usable := c.current(s); usable {
+			glog.Warningf("fetching status list %q failed, using stale list: %v", uri, err)
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.list = l
+	s.fetched = c.now()
+	s.mu.Unlock()
+	return l, nil
+}
+
+// current returns the list, whether it is fresh and whether it may be used.
+func (c *StatusListCache) current(s *statusList) (*ga4gh.StatusList, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.list == nil {
+		return nil, false, false
+	}
+	age := c.now().Sub(s.fetched)
+	return s.list, age < c.opts.MaxAge, age < c.opts.MaxStale
+}
+
+func getStatusList(ctx context.Context, client *http.Client, uri string) (*ga4gh.StatusList, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", statusListContentType)
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+
+	var out struct {
+		StatusList *ga4gh.StatusList `json:"status_list"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decoding status list: %v", err)
+	}
+	if out.StatusList == nil {
+		return nil, fmt.Errorf("decoding status list: missing status_list")
+	}
+	return out.StatusList, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Google LLC.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc" /* copybara-comment */
+	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
+)
+
+type statusListServer struct {
+	statuses []int
+	down     bool
+	fetches  int
+}
+
+func (s *statusListServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.fetches++
+	if s.down {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+		return
+	}
+	l, err := ga4gh.NewStatusList(1, s.statuses)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", statusListContentType)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status_list": l})
+}
+
+func TestStatusListCache(t *testing.T) {
+	s := &statusListServer{statuses: []int{ga4gh.StatusValid}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	now := time.Now()
+	c := NewStatusListCache(StatusListCacheOptions{MaxAge: time.Minute, MaxStale: time.Hour})
+	c.now = func() time.Time { return now }
+	ctx := oidc.ClientContext(context.Background(), srv.Client())
+	uri := srv.URL + "/statuslists/1"
+
+	status := func() int {
+		t.Helper()
+		l, err := c.FetchStatusList(ctx, uri)
+		if err != nil {
+			t.Fatalf("FetchStatusList() failed: %v", err)
+		}
+		st, err := l.Status(0)
+		if err != nil {
+			t.Fatalf("Status(0) failed: %v", err)
+		}
+		return st
+	}
+
+	if got := status(); got != ga4gh.StatusValid {
+		t.Errorf("status = %d, want %d", got, ga4gh.StatusValid)
+	}
+
+	// The revocation is seen once the list is older than MaxAge.
+	s.statuses = []int{ga4gh.StatusInvalid}
+	if got := status(); got != ga4gh.StatusValid || s.fetches != 1 {
+		t.Errorf("status = %d after %d fetches, want cached status %d after 1 fetch", got, s.fetches, ga4gh.StatusValid)
+	}
+	now = now.Add(time.Minute)
+	if got := status(); got != ga4gh.StatusInvalid {
+		t.Errorf("status after MaxAge = %d, want %d", got, ga4gh.StatusInvalid)
+	}
+
+	// The stale list is used while the issuer is down, until MaxStale.
+	s.down = true
+	now = now.Add(30 * time.Minute)
+	if got := status(); got != ga4gh.StatusInvalid {
+		t.Errorf("status while issuer down = %d, want %d", got, ga4gh.StatusInvalid)
+	}
+	now = now.Add(time.Hour)
+	if _, err := c.FetchStatusList(ctx, uri); err == nil {
+		t.Errorf("FetchStatusList() after MaxStale while issuer down succeeded, want error")
+	}
+}
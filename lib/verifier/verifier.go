@@ -23,6 +23,7 @@ import (
 	"google.golang.org/grpc/codes" /* copybara-comment */
 	"google.golang.org/grpc/status" /* copybara-comment */
 	"gopkg.in/square/go-jose.v2/jwt" /* copybara-comment */
+	"github.com/coreos/go-oidc" /* copybara-comment */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/errutil" /* copybara-comment: errutil */
 	"github.com/GoogleCloudPlatform/healthcare-federated-access-services/lib/ga4gh" /* copybara-comment: ga4gh */
 )
@@ -82,17 +83,17 @@ func (s *UserinfoAccesssTokenVerifier) Verify(ctx context.Context, token string,
 }
 
 // NewVisaVerifier creates a visa token verifier.
-func NewVisaVerifier(ctx context.Context, issuer, jku, prefix string) (*VisaVerifier, error) {
+func NewVisaVerifier(ctx context.Context, issuer, jku, prefix string, opts ...Option) (*VisaVerifier, error) {
 	v := &VisaVerifier{
 		aud: &visaAudienceVerifier{prefix: prefix},
 	}
 	if len(jku) > 0 {
-		v.tok = newJkuVisaSigVerifier(ctx, issuer, jku)
+		v.tok = newJkuVisaSigVerifier(ctx, issuer, jku, opts)
 		return v, nil
 	}
 
 	var err error
-	v.tok, err = newOIDCSigVerifier(ctx, issuer)
+	v.tok, err = newOIDCSigVerifier(ctx, issuer, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +101,8 @@ func NewVisaVerifier(ctx context.Context, issuer, jku, prefix string) (*VisaVeri
 }
 
 // NewPassportVerifier creates a passport token verifier.
-func NewPassportVerifier(ctx context.Context, issuer, clientID string) (*PassportVerifier, error) {
-	tok, err := newOIDCSigVerifier(ctx, issuer)
+func NewPassportVerifier(ctx context.Context, issuer, clientID string, opts ...Option) (*PassportVerifier, error) {
+	tok, err := newOIDCSigVerifier(ctx, issuer, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +121,7 @@ type AccessTokenVerifier interface {
 }
 
 // NewAccessTokenVerifier creates a access tok verifier.
-func NewAccessTokenVerifier(ctx context.Context, issuer string, useUserinfoVerifier bool) (AccessTokenVerifier, error) {
+func NewAccessTokenVerifier(ctx context.Context, issuer string, useUserinfoVerifier bool, opts ...Option) (AccessTokenVerifier, error) {
 	if useUserinfoVerifier {
 		tok, err := newOIDCUserinfoVerifier(ctx, issuer)
 		if err != nil {
@@ -132,7 +133,7 @@ func NewAccessTokenVerifier(ctx context.Context, issuer string, useUserinfoVerif
 		}, nil
 	}
 
-	tok, err := newOIDCSigVerifier(ctx, issuer)
+	tok, err := newOIDCSigVerifier(ctx, issuer, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -204,6 +205,51 @@ type Option interface {
 	isOption()
 }
 
+type algorithmsOption struct {
+	algs []string
+}
+
+func (s *algorithmsOption) isOption() {}
+
+// AlgorithmsOption for verifier constructors limits the accepted signing
+// algorithms of tokens. Defaults to ga4gh.DefaultSigningAlgorithms.
+func AlgorithmsOption(algs []string) Option {
+	return &algorithmsOption{algs: algs}
+}
+
+// signingAlgorithms returns the signing algorithms accepted with the options.
+func signingAlgorithms(opts []Option) []string {
+	for _, o := range opts {
+		if a, ok := o.(*algorithmsOption); ok && len(a.algs) > 0 {
+			return a.algs
+		}
+	}
+	return ga4gh.DefaultSigningAlgorithms
+}
+
+type jwksCacheOption struct {
+	cache *JWKSCache
+}
+
+func (s *jwksCacheOption) isOption() {}
+
+// JWKSCacheOption for verifier constructors shares the key sets of issuers
+// in the cache instead of a key set per verifier.
+func JWKSCacheOption(c *JWKSCache) Option {
+	return &jwksCacheOption{cache: c}
+}
+
+// keySet returns the key set at the given URL, from the JWKSCache of the
+// options if any.
+func keySet(ctx context.Context, jwksURL string, opts []Option) oidc.KeySet {
+	for _, o := range opts {
+		if c, ok := o.(*jwksCacheOption); ok && c.cache != nil {
+			return c.cache.KeySet(ctx, jwksURL)
+		}
+	}
+	return oidc.NewRemoteKeySet(ctx, jwksURL)
+}
+
 // unsafeClaimsFromJWTToken extracts custom claims from jwt body.
 func unsafeClaimsFromJWTToken(token string, obj interface{}) error {
 	tok, err := jwt.ParseSigned(token)
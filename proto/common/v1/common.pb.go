@@ -171,6 +171,7 @@ type Condition struct {
 	Source               string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
 	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
 	By                   string   `protobuf:"bytes,4,opt,name=by,proto3" json:"by,omitempty"`
+	DuoTerms             []string `protobuf:"bytes,5,rep,name=duo_terms,json=duoTerms,proto3" json:"duo_terms,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -229,6 +230,13 @@ func (m *Condition) GetBy() string {
 	return ""
 }
 
+func (m *Condition) GetDuoTerms() []string {
+	if m != nil {
+		return m.DuoTerms
+	}
+	return nil
+}
+
 type ConditionSet struct {
 	AllOf                []*Condition `protobuf:"bytes,1,rep,name=all_of,json=allOf,proto3" json:"all_of,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
@@ -1552,123 +1560,124 @@ func init() {
 }
 
 var fileDescriptor_988ca6f500b2cf3b = []byte{
-	// 1876 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x5d, 0x72, 0x24, 0x47,
-	0x11, 0x66, 0xfe, 0x34, 0x9a, 0x9c, 0x1f, 0x8d, 0xca, 0xcb, 0xba, 0x19, 0xc3, 0x5a, 0x3b, 0xd8,
-	0x46, 0x60, 0x6b, 0x64, 0x64, 0x13, 0xb1, 0xd8, 0x04, 0x81, 0xac, 0x5d, 0xec, 0x8d, 0x58, 0xef,
-	0x2a, 0x5a, 0x5a, 0x3f, 0x38, 0x20, 0x3a, 0x4a, 0xdd, 0x35, 0x33, 0xe5, 0xed, 0xe9, 0x6e, 0x57,
-	0x55, 0x0b, 0x0d, 0x47, 0xe0, 0x85, 0x27, 0x6e, 0x00, 0x67, 0xf0, 0x11, 0x08, 0x8e, 0xc0, 0x13,
-	0x27, 0xe0, 0x89, 0x0b, 0x10, 0x99, 0x55, 0xd5, 0xd3, 0xd2, 0xca, 0xb1, 0xe1, 0x08, 0xbf, 0x55,
-	0x7e, 0x95, 0x95, 0x95, 0xf5, 0x65, 0x76, 0x66, 0xce, 0xc0, 0x8f, 0x0b, 0x95, 0x9b, 0xfc, 0x30,
-	0xce, 0x57, 0xab, 0x3c, 0x3b, 0xbc, 0xfc, 0xa5, 0x5b, 0xcd, 0x08, 0x66, 0x5b, 0x56, 0x9a, 0xbc,
-	0xb9, 0xc8, 0xf3, 0x45, 0x2a, 0x0e, 0x09, 0xbd, 0x28, 0xe7, 0x87, 0x46, 0xae, 0x84, 0x36, 0x7c,
-	0x55, 0x58, 0xc5, 0xe9, 0xff, 0x1a, 0x00, 0x0f, 0x85, 0x8e, 0x95, 0x2c, 0x4c, 0xae, 0xd8, 0x1d,
-	0xe8, 0xa4, 0xfc, 0x42, 0xa4, 0x41, 0x63, 0xaf, 0xb1, 0xdf, 0x0b, 0xad, 0xc0, 0xf6, 0xa0, 0x9f,
-	0x38, 0x1d, 0x99, 0x67, 0x41, 0x93, 0xf6, 0xea, 0x10, 0xbb, 0x0b, 0x5b, 0x4a, 0x2c, 0xc4, 0x55,
-	0x11, 0xb4, 0x68, 0xd3, 0x49, 0x8c, 0x41, 0xdb, 0xac, 0x0b, 0x11, 0xb4, 0x09, 0xa5, 0x35, 0x7b,
-	0x1d, 0xba, 0x52, 0x47, 0xa9, 0xd4, 0x26, 0xe8, 0xec, 0x35, 0xf6, 0xb7, 0xc3, 0x2d, 0xa9, 0x9f,
-	0x48, 0x6d, 0xd8, 0x9b, 0xd0, 0x17, 0x59, 0xb9, 0x8a, 0x2e, 0x79, 0x5a, 0x0a, 0x1d, 0x6c, 0xed,
-	0xb5, 0xf6, 0x7b, 0x21, 0x20, 0xf4, 0x05, 0x21, 0x6c, 0x0c, 0xad, 0x95, 0xcc, 0x82, 0x2e, 0x19,
-	0xc3, 0x25, 0x21, 0xfc, 0x2a, 0xd8, 0x76, 0x08, 0xbf, 0x62, 0x3f, 0x85, 0x61, 0x22, 0xe6, 0xbc,
-	0x4c, 0x8d, 0xb5, 0x13, 0xf4, 0x68, 0x6f, 0xe0, 0x40, 0xb2, 0x34, 0xfd, 0x23, 0xf4, 0x4e, 0xf2,
-	0x2c, 0x91, 0xe4, 0xbb, 0xf7, 0xb1, 0x51, 0xf3, 0xf1, 0x2e, 0x6c, 0xe9, 0xbc, 0x54, 0xb1, 0x70,
-	0x8f, 0x75, 0x12, 0xf2, 0x63, 0xad, 0xda, 0x67, 0x5a, 0x81, 0x8d, 0xa0, 0x79, 0xb1, 0x76, 0x6f,
-	0x6c, 0x5e, 0xac, 0xa7, 0x0f, 0x60, 0x50, 0x99, 0x3f, 0x13, 0x86, 0xed, 0xc3, 0x16, 0x4f, 0xd3,
-	0x28, 0x9f, 0x07, 0x8d, 0xbd, 0xd6, 0x7e, 0xff, 0x68, 0x77, 0xe6, 0x82, 0x55, 0x69, 0x85, 0x1d,
-	0x9e, 0xa6, 0xcf, 0xe6, 0xd3, 0x7f, 0x34, 0xa1, 0x77, 0xac, 0xb5, 0x50, 0xdf, 0x93, 0x67, 0xef,
-	0xc2, 0x2e, 0x27, 0x73, 0x22, 0x89, 0x92, 0x52, 0x71, 0x8a, 0x9f, 0x75, 0x74, 0xec, 0x37, 0x1e,
-	0x3a, 0x9c, 0xfd, 0x1c, 0xc6, 0xe2, 0xaa, 0x90, 0x4a, 0xe8, 0x8d, 0x6e, 0x87, 0x74, 0x77, 0x1c,
-	0x5e, 0xa9, 0xfe, 0x0e, 0x76, 0x79, 0xb6, 0x8e, 0xf2, 0x79, 0x14, 0xfb, 0x27, 0xd8, 0x80, 0xf5,
-	0x8f, 0xee, 0xbc, 0xf4, 0xb8, 0x33, 0x61, 0xc2, 0x1d, 0x9e, 0xad, 0x9f, 0xcd, 0x2b, 0x48, 0x3b,
-	0xce, 0xba, 0x9e, 0x33, 0x36, 0x81, 0x6d, 0xef, 0x10, 0x85, 0xb3, 0x15, 0x56, 0x32, 0x46, 0x19,
-	0x53, 0xab, 0x47, 0x30, 0x2e, 0xa7, 0x11, 0x0c, 0xbf, 0x90, 0x9a, 0x87, 0xe2, 0x2b, 0x11, 0x6f,
-	0x12, 0x90, 0xeb, 0x3c, 0x73, 0x64, 0x39, 0x09, 0x69, 0x99, 0x4b, 0x91, 0x26, 0x8e, 0x2d, 0x2b,
-	0xdc, 0x4c, 0xe8, 0xd6, 0x4b, 0x09, 0x3d, 0xfd, 0x57, 0x03, 0x06, 0xd6, 0xba, 0x48, 0xf0, 0x26,
-	0x76, 0x1f, 0x06, 0x26, 0x7f, 0x21, 0xb2, 0x68, 0x9e, 0xab, 0x15, 0x37, 0xee, 0x9a, 0x3e, 0x61,
-	0xbf, 0x27, 0x08, 0x7d, 0x90, 0x5a, 0x97, 0x42, 0xf9, 0xd0, 0x58, 0x89, 0x05, 0xd0, 0xd5, 0xe5,
-	0x05, 0xda, 0x72, 0x37, 0x79, 0x91, 0x1d, 0x42, 0x8f, 0xfb, 0x68, 0x53, 0x58, 0x6a, 0xb9, 0x51,
-	0xa5, 0x41, 0xb8, 0xd1, 0x61, 0x1f, 0x40, 0x4f, 0xf9, 0x37, 0x53, 0x6c, 0xfa, 0x47, 0x3f, 0xf4,
-	0x07, 0xae, 0x11, 0x12, 0x6e, 0xf4, 0xa6, 0xff, 0x6e, 0xc0, 0xc8, 0xbf, 0xe5, 0x34, 0x4f, 0x65,
-	0xbc, 0x66, 0xf7, 0x00, 0xaa, 0x7d, 0x4d, 0x6f, 0xe9, 0x84, 0x35, 0x84, 0x7d, 0x0c, 0x23, 0xe5,
-	0x4e, 0x44, 0x97, 0x52, 0x73, 0x1d, 0x34, 0xaf, 0x07, 0xb7, 0xce, 0x4d, 0x38, 0x54, 0x35, 0x49,
-	0x23, 0x55, 0x05, 0x5d, 0x13, 0x5d, 0x70, 0x2d, 0x75, 0xd0, 0xa2, 0x0f, 0xb9, 0x6f, 0xb1, 0x4f,
-	0x10, 0x62, 0x07, 0xc0, 0x94, 0xf8, 0xba, 0x14, 0x1a, 0x2f, 0x50, 0xc2, 0x65, 0xb4, 0x4d, 0xcc,
-	0xdd, 0x6a, 0x27, 0x74, 0x1b, 0xc8, 0xe0, 0x4a, 0x68, 0xcd, 0x17, 0xc2, 0x25, 0xa4, 0x17, 0xa7,
-	0xdf, 0x34, 0x61, 0xfb, 0x94, 0x6b, 0x5d, 0xe4, 0xca, 0xb0, 0xcf, 0x61, 0x47, 0x1b, 0x9e, 0x25,
-	0x5c, 0x25, 0x51, 0x9c, 0x72, 0xb9, 0xd2, 0xee, 0x83, 0x7b, 0xcb, 0xbb, 0xed, 0x55, 0x67, 0x67,
-	0x4e, 0xef, 0x84, 0xd4, 0x1e, 0x65, 0x46, 0xad, 0xc3, 0x91, 0xbe, 0x06, 0xb2, 0xdf, 0xc0, 0x78,
-	0xc1, 0x3f, 0x5c, 0x2c, 0xa3, 0x8a, 0x7f, 0x4f, 0xc3, 0x2d, 0x41, 0xda, 0x21, 0xd5, 0x4a, 0xd6,
-	0xec, 0x01, 0x04, 0x32, 0x33, 0x42, 0x65, 0x3c, 0x8d, 0x44, 0x16, 0xab, 0x75, 0xb1, 0x21, 0xb3,
-	0xbf, 0xd7, 0xda, 0x1f, 0x84, 0x77, 0xfd, 0xfe, 0x23, 0xbf, 0x5d, 0xf1, 0x27, 0xae, 0x8c, 0xe2,
-	0x91, 0x8e, 0xf3, 0x42, 0x68, 0x9f, 0x9e, 0x84, 0x9d, 0x11, 0x34, 0x39, 0x86, 0xd7, 0x6e, 0x79,
-	0x01, 0x7e, 0x28, 0x2f, 0xc4, 0xda, 0xe5, 0x26, 0x2e, 0x37, 0x65, 0xa1, 0x59, 0x2b, 0x0b, 0x1f,
-	0x35, 0x1f, 0x34, 0xa6, 0xdf, 0x34, 0xa0, 0x7f, 0x2e, 0xb4, 0x39, 0x15, 0x4a, 0xe7, 0x19, 0x67,
-	0xef, 0xc1, 0x76, 0xe1, 0xd8, 0x21, 0x03, 0xfd, 0xa3, 0xf1, 0x4d, 0xd6, 0xc2, 0x4a, 0x03, 0x73,
-	0x9d, 0xc7, 0xb1, 0xd0, 0x96, 0x91, 0x5e, 0xe8, 0x24, 0xf6, 0x2e, 0x34, 0x4b, 0x49, 0x11, 0xef,
-	0x1f, 0xbd, 0xe1, 0xcf, 0xd7, 0xae, 0x99, 0x3d, 0x97, 0x96, 0xec, 0x66, 0x29, 0x27, 0xbf, 0x82,
-	0xae, 0x13, 0xbf, 0x93, 0xe7, 0x7f, 0x69, 0x41, 0xff, 0x54, 0xa8, 0x95, 0xd4, 0x9a, 0x98, 0x0e,
-	0xa0, 0x7b, 0x29, 0x14, 0xae, 0xdd, 0x79, 0x2f, 0x62, 0x51, 0x51, 0xe2, 0x52, 0x6a, 0xdf, 0xb5,
-	0x5a, 0x61, 0x25, 0x63, 0xb7, 0x41, 0xf7, 0xa4, 0x89, 0xb0, 0x27, 0x12, 0xc9, 0x8d, 0x10, 0x2c,
-	0x74, 0x2e, 0x57, 0x82, 0x7d, 0x08, 0x9d, 0x52, 0x0b, 0xa5, 0x83, 0x36, 0xbd, 0xe6, 0x5e, 0xc5,
-	0xc6, 0xe6, 0xea, 0xd9, 0x73, 0x54, 0xb0, 0x0f, 0xb2, 0xca, 0x93, 0xbf, 0x36, 0x60, 0xb4, 0xd1,
-	0xc0, 0x7d, 0x76, 0x02, 0x1d, 0x95, 0xa7, 0xc2, 0x27, 0xe3, 0xc1, 0x6d, 0x86, 0xae, 0x1f, 0x99,
-	0x85, 0xa8, 0xef, 0xec, 0xd2, 0xd9, 0xc9, 0x03, 0x80, 0x0d, 0xf8, 0x2a, 0xba, 0x5a, 0x35, 0xba,
-	0x26, 0x7f, 0x00, 0xd8, 0xb8, 0x79, 0xcb, 0xc9, 0x07, 0xf5, 0x93, 0xfd, 0xa3, 0xe9, 0xab, 0xdd,
-	0xab, 0x07, 0xe3, 0xbf, 0x4d, 0x80, 0x27, 0xf9, 0x42, 0x66, 0x67, 0x86, 0x1b, 0x6c, 0x38, 0x6d,
-	0x6d, 0x44, 0x41, 0xf6, 0x47, 0x47, 0xaf, 0x7b, 0x5b, 0x1b, 0x8d, 0xd9, 0x99, 0x11, 0x45, 0x48,
-	0x4a, 0x18, 0x9e, 0x42, 0xe5, 0x97, 0x32, 0xa9, 0x4a, 0x66, 0x25, 0xe3, 0x7b, 0x94, 0xe0, 0xe9,
-	0xca, 0xf7, 0x33, 0x12, 0xd8, 0xcf, 0x60, 0x27, 0x45, 0x53, 0x51, 0xbc, 0xe4, 0x69, 0x2a, 0xb2,
-	0x85, 0x2f, 0x1a, 0x23, 0x82, 0x4f, 0x3c, 0x5a, 0xaf, 0xb9, 0x9d, 0xeb, 0x35, 0xf7, 0x0e, 0x74,
-	0xe8, 0xbb, 0x0a, 0xb6, 0xac, 0x61, 0x12, 0xd8, 0x4f, 0x00, 0xac, 0xe1, 0xa5, 0xcc, 0x8c, 0x6b,
-	0x4b, 0x3d, 0x42, 0x3e, 0x93, 0x99, 0xc1, 0x3e, 0x1a, 0xe7, 0x99, 0x16, 0x99, 0xa9, 0xdd, 0x6c,
-	0xa7, 0x8e, 0xb1, 0xdb, 0xd8, 0xdc, 0x8d, 0xad, 0xac, 0x4c, 0xa4, 0xc8, 0x62, 0x9c, 0x3e, 0xf0,
-	0xeb, 0xa8, 0x64, 0xca, 0xba, 0x54, 0xa2, 0x9d, 0x8c, 0xaf, 0x44, 0x00, 0x64, 0x02, 0x2c, 0xf4,
-	0x94, 0xaf, 0xc4, 0xf4, 0x1e, 0xb4, 0x91, 0x21, 0xd6, 0x83, 0xce, 0x93, 0x67, 0x9f, 0x3e, 0x7e,
-	0x3a, 0xfe, 0x01, 0xeb, 0x43, 0xf7, 0xe4, 0xd9, 0xd3, 0xb3, 0x47, 0x4f, 0xcf, 0xc7, 0x8d, 0xe9,
-	0x97, 0x30, 0x3c, 0xc7, 0x9e, 0xf3, 0xb9, 0x30, 0x3c, 0xe1, 0x86, 0xe3, 0x90, 0x40, 0xa6, 0xdc,
-	0x90, 0x80, 0x6b, 0xf6, 0x06, 0xf4, 0xa8, 0xf7, 0x24, 0x11, 0x37, 0x9e, 0x59, 0x0b, 0x1c, 0x1b,
-	0xa4, 0xc6, 0xb5, 0x73, 0xdf, 0x8e, 0x9c, 0x38, 0xfd, 0x5b, 0x07, 0xb6, 0x4f, 0x52, 0x69, 0x23,
-	0x39, 0x82, 0xa6, 0x4c, 0x9c, 0xd5, 0xa6, 0x4c, 0x90, 0x37, 0xb1, 0xe2, 0x32, 0xf5, 0xdf, 0x23,
-	0x09, 0x78, 0x93, 0x7b, 0x8f, 0x4c, 0x9c, 0xb9, 0x6d, 0x0b, 0x3c, 0x4e, 0x36, 0x54, 0xb7, 0xeb,
-	0x54, 0xff, 0x08, 0xe9, 0x31, 0xcb, 0xa8, 0x54, 0xa9, 0x8f, 0x0d, 0xca, 0xcf, 0x55, 0xca, 0x7e,
-	0x0d, 0x10, 0x2b, 0xc1, 0x8d, 0x75, 0x7c, 0x8b, 0xf2, 0x71, 0x32, 0xb3, 0x33, 0xec, 0xcc, 0xcf,
-	0xb0, 0xb3, 0x73, 0x3f, 0xc3, 0x86, 0x3d, 0xa7, 0x7d, 0x6c, 0xf0, 0xa8, 0x1f, 0x5e, 0xb8, 0x0d,
-	0xe0, 0x2b, 0x8e, 0x3a, 0xed, 0x63, 0xca, 0x88, 0x2c, 0xc7, 0x60, 0xd9, 0x80, 0x5a, 0x81, 0xa6,
-	0xa1, 0xaa, 0x6c, 0x6b, 0x11, 0x2b, 0x61, 0x68, 0x02, 0x19, 0x84, 0x3b, 0x15, 0x7e, 0x46, 0x30,
-	0x7b, 0x1b, 0x46, 0x1b, 0xd5, 0x38, 0x4f, 0x6c, 0x5c, 0x07, 0xe1, 0xb0, 0x42, 0x4f, 0xf2, 0xc4,
-	0x8e, 0x6e, 0xd6, 0x4e, 0xdf, 0x8d, 0x6e, 0xf6, 0xf8, 0x7d, 0x18, 0xd8, 0xea, 0x19, 0xd1, 0x34,
-	0x11, 0x0c, 0x6c, 0xbd, 0xb7, 0x18, 0x05, 0x1b, 0xa7, 0x5a, 0x25, 0xe6, 0x4a, 0xe8, 0xa5, 0xd3,
-	0x19, 0xda, 0xa9, 0xd6, 0x81, 0x56, 0x09, 0xe9, 0xc6, 0xd0, 0x05, 0x23, 0x47, 0x37, 0xc5, 0xf1,
-	0x63, 0x20, 0x4b, 0x85, 0x23, 0x75, 0xe7, 0x95, 0xcc, 0x80, 0x57, 0x3f, 0x36, 0xec, 0x21, 0x0c,
-	0xb0, 0xac, 0x45, 0x85, 0xca, 0xe7, 0x32, 0x15, 0xc1, 0x98, 0x2a, 0xd8, 0xfd, 0x6a, 0xc4, 0x73,
-	0xc9, 0x42, 0x75, 0xf0, 0xd4, 0xea, 0xd8, 0xaa, 0xd5, 0x2f, 0x37, 0xc8, 0xe4, 0xb7, 0x30, 0xbe,
-	0xa9, 0xf0, 0x9d, 0x0a, 0xfe, 0xdf, 0x9b, 0x30, 0xf8, 0x4c, 0x6a, 0x93, 0xab, 0xb5, 0x3d, 0x5c,
-	0xaf, 0xeb, 0x8d, 0x1b, 0x75, 0x9d, 0x41, 0x1b, 0xef, 0x76, 0x56, 0x68, 0xfd, 0xea, 0x5a, 0xcf,
-	0xa0, 0x5d, 0x70, 0xb3, 0xf4, 0xbf, 0x53, 0x70, 0x8d, 0xfe, 0x7c, 0x5d, 0x0a, 0xb5, 0x76, 0x49,
-	0x6a, 0x05, 0xd4, 0xc4, 0x39, 0xd1, 0x55, 0x0f, 0x5a, 0x63, 0x60, 0x57, 0xc2, 0x2c, 0xf3, 0xc4,
-	0x15, 0x0e, 0x27, 0xd1, 0xb5, 0x4b, 0x9e, 0x2d, 0x44, 0x44, 0x63, 0xfc, 0xb6, 0xfb, 0xd8, 0x09,
-	0x3a, 0xc7, 0x61, 0xfe, 0x6d, 0x18, 0xe5, 0x4a, 0x2e, 0x24, 0xce, 0x08, 0xf5, 0x5f, 0x2b, 0x43,
-	0x8f, 0xd2, 0xcf, 0x15, 0x54, 0x73, 0x76, 0xdc, 0x68, 0xe4, 0xea, 0xc6, 0xd0, 0xa2, 0xa1, 0x05,
-	0xa7, 0x1c, 0xba, 0x8e, 0x25, 0x36, 0x83, 0xee, 0xd2, 0x2e, 0x5d, 0xd3, 0xa9, 0x06, 0xb7, 0x3a,
-	0x8f, 0xa1, 0x57, 0x62, 0xef, 0xc0, 0x4e, 0x26, 0xae, 0x4c, 0x54, 0x70, 0x74, 0x96, 0x32, 0xcc,
-	0xf2, 0x37, 0x44, 0xf8, 0x94, 0x2f, 0x04, 0xa5, 0xd8, 0xf4, 0x3f, 0x4d, 0x80, 0x67, 0x32, 0x89,
-	0x4f, 0xf2, 0x6c, 0x2e, 0x17, 0xb5, 0x89, 0xb7, 0x71, 0x6d, 0xe2, 0x9d, 0xc0, 0xf6, 0x57, 0x7f,
-	0x7a, 0xa1, 0xa3, 0x52, 0x49, 0x5f, 0x7e, 0xbc, 0xcc, 0x0e, 0x60, 0x48, 0x9f, 0xbf, 0xc8, 0x92,
-	0x22, 0xc7, 0x62, 0x6b, 0xab, 0xc6, 0x5d, 0x04, 0x73, 0x25, 0xff, 0x4c, 0x3f, 0x30, 0xaa, 0x5d,
-	0xf6, 0x11, 0x04, 0x4a, 0xe8, 0x02, 0x6b, 0x2c, 0xb1, 0xa8, 0x23, 0x5d, 0x16, 0x38, 0x82, 0x88,
-	0x84, 0x1a, 0x73, 0x2f, 0xfc, 0xd6, 0x7d, 0xf6, 0x0e, 0x8c, 0xec, 0xcc, 0x5e, 0xdd, 0x65, 0x43,
-	0x79, 0x03, 0x65, 0xef, 0xc3, 0x6b, 0x4a, 0x5c, 0xe6, 0xf1, 0xf5, 0xab, 0x5d, 0xbc, 0x6e, 0xdb,
-	0x62, 0xef, 0xc1, 0x2e, 0x26, 0x96, 0xcc, 0xe6, 0xf9, 0x46, 0xdf, 0xa6, 0xc4, 0xcb, 0x1b, 0xec,
-	0x17, 0x30, 0xb6, 0xa3, 0x5c, 0xcd, 0xf7, 0x2e, 0xf9, 0xfe, 0x12, 0x3e, 0xfd, 0x67, 0x13, 0x76,
-	0x91, 0x61, 0xe2, 0x3b, 0x74, 0x2f, 0x63, 0xd3, 0x1b, 0x25, 0xc2, 0xd2, 0x7d, 0x0d, 0xc3, 0x99,
-	0xde, 0xbe, 0x8b, 0x92, 0xcd, 0xd2, 0x5e, 0x43, 0x70, 0xdf, 0x57, 0x48, 0x69, 0x7f, 0xf3, 0x74,
-	0xc2, 0x1a, 0xc2, 0xde, 0xba, 0x59, 0x63, 0xec, 0xc7, 0x70, 0x1d, 0xc4, 0xd0, 0xca, 0xc4, 0x29,
-	0x74, 0x5c, 0x67, 0x71, 0xf2, 0xb7, 0xb4, 0xd6, 0x31, 0xb4, 0x4a, 0xe9, 0x3f, 0x0d, 0x5c, 0xb2,
-	0x23, 0x68, 0x23, 0x41, 0x44, 0x70, 0x6d, 0xb0, 0x7a, 0xe9, 0xd9, 0xb3, 0xc7, 0xd9, 0x3c, 0x0f,
-	0x49, 0x77, 0xf2, 0x3e, 0xb4, 0x51, 0xba, 0xb5, 0xdd, 0xdd, 0xda, 0x9a, 0x3e, 0x79, 0xfe, 0xe5,
-	0xd9, 0x42, 0x9a, 0x65, 0x79, 0x81, 0xf6, 0x0f, 0x3f, 0xa5, 0x7a, 0x77, 0x92, 0xe6, 0x65, 0x72,
-	0x9a, 0x72, 0x83, 0xbf, 0xe2, 0x0e, 0x97, 0x82, 0xa7, 0x66, 0x19, 0x73, 0x25, 0x0e, 0xe6, 0x22,
-	0x11, 0x0a, 0x5b, 0xc9, 0x81, 0xe5, 0xf4, 0x40, 0x0b, 0x75, 0x29, 0x63, 0xa1, 0x0f, 0x6f, 0xfc,
-	0xcf, 0x72, 0xb1, 0x45, 0xc0, 0x07, 0xff, 0x0f, 0x00, 0x00, 0xff, 0xff, 0xe0, 0x42, 0x91, 0x67,
-	0x81, 0x11, 0x00, 0x00,
+	// 1889 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xef, 0x6e, 0x23, 0xb7,
+	0x11, 0xaf, 0xfe, 0x59, 0xd2, 0xe8, 0x8f, 0x65, 0xe6, 0x7a, 0xd9, 0x2a, 0xed, 0xc5, 0xa7, 0x26,
+	0xa9, 0xdb, 0xc4, 0x72, 0xea, 0xa4, 0xc0, 0x35, 0x29, 0x8a, 0x3a, 0xbe, 0x6b, 0x72, 0xc0, 0xe5,
+	0x6c, 0xac, 0xed, 0x7c, 0x08, 0x0a, 0x2c, 0xe8, 0x5d, 0x4a, 0x62, 0x6e, 0xb5, 0xdc, 0x90, 0x5c,
+	0xd7, 0xca, 0x23, 0xf4, 0x4b, 0x3f, 0xf5, 0x0d, 0xda, 0x67, 0xc8, 0x23, 0x14, 0x7d, 0x84, 0x7e,
+	0xea, 0x13, 0xf4, 0x53, 0x5f, 0xa0, 0xe0, 0x90, 0x5c, 0xad, 0x6c, 0x07, 0x46, 0x80, 0x7c, 0xe3,
+	0xfc, 0x38, 0x1c, 0x72, 0x7e, 0x33, 0x3b, 0x33, 0x12, 0xfc, 0x34, 0x97, 0x42, 0x8b, 0x83, 0x58,
+	0x2c, 0x97, 0x22, 0x3b, 0xb8, 0xfa, 0xb5, 0x5b, 0x4d, 0x11, 0x26, 0x5b, 0x56, 0x1a, 0xbf, 0x39,
+	0x17, 0x62, 0x9e, 0xb2, 0x03, 0x44, 0x2f, 0x8b, 0xd9, 0x81, 0xe6, 0x4b, 0xa6, 0x34, 0x5d, 0xe6,
+	0x56, 0x71, 0xf2, 0xbf, 0x1a, 0xc0, 0x53, 0xa6, 0x62, 0xc9, 0x73, 0x2d, 0x24, 0x79, 0x00, 0xad,
+	0x94, 0x5e, 0xb2, 0x34, 0xa8, 0xed, 0xd6, 0xf6, 0xba, 0xa1, 0x15, 0xc8, 0x2e, 0xf4, 0x12, 0xa7,
+	0xc3, 0x45, 0x16, 0xd4, 0x71, 0xaf, 0x0a, 0x91, 0x87, 0xb0, 0x25, 0xd9, 0x9c, 0x5d, 0xe7, 0x41,
+	0x03, 0x37, 0x9d, 0x44, 0x08, 0x34, 0xf5, 0x2a, 0x67, 0x41, 0x13, 0x51, 0x5c, 0x93, 0xd7, 0xa1,
+	0xcd, 0x55, 0x94, 0x72, 0xa5, 0x83, 0xd6, 0x6e, 0x6d, 0xaf, 0x13, 0x6e, 0x71, 0xf5, 0x82, 0x2b,
+	0x4d, 0xde, 0x84, 0x1e, 0xcb, 0x8a, 0x65, 0x74, 0x45, 0xd3, 0x82, 0xa9, 0x60, 0x6b, 0xb7, 0xb1,
+	0xd7, 0x0d, 0xc1, 0x40, 0x5f, 0x20, 0x42, 0x46, 0xd0, 0x58, 0xf2, 0x2c, 0x68, 0xa3, 0x31, 0xb3,
+	0x44, 0x84, 0x5e, 0x07, 0x1d, 0x87, 0xd0, 0x6b, 0xf2, 0x73, 0x18, 0x24, 0x6c, 0x46, 0x8b, 0x54,
+	0x5b, 0x3b, 0x41, 0x17, 0xf7, 0xfa, 0x0e, 0x44, 0x4b, 0x93, 0x6f, 0xa0, 0x7b, 0x2c, 0xb2, 0x84,
+	0xe3, 0xdb, 0xfd, 0x1b, 0x6b, 0x95, 0x37, 0x3e, 0x84, 0x2d, 0x25, 0x0a, 0x19, 0x33, 0xe7, 0xac,
+	0x93, 0x0c, 0x3f, 0xd6, 0xaa, 0x75, 0xd3, 0x0a, 0x64, 0x08, 0xf5, 0xcb, 0x95, 0xf3, 0xb1, 0x7e,
+	0xb9, 0x22, 0x6f, 0x40, 0x37, 0x29, 0x44, 0xa4, 0x99, 0x5c, 0xaa, 0xa0, 0x85, 0x6e, 0x74, 0x92,
+	0x42, 0x9c, 0x1b, 0x79, 0xf2, 0x04, 0xfa, 0xe5, 0xdd, 0x67, 0x4c, 0x93, 0x3d, 0xd8, 0xa2, 0x69,
+	0x1a, 0x89, 0x59, 0x50, 0xdb, 0x6d, 0xec, 0xf5, 0x0e, 0x77, 0xa6, 0x2e, 0x92, 0xa5, 0x56, 0xd8,
+	0xa2, 0x69, 0x7a, 0x32, 0x9b, 0xfc, 0xa3, 0x0e, 0xdd, 0x23, 0xa5, 0x98, 0xfc, 0x81, 0x9e, 0xfd,
+	0x2e, 0xec, 0x50, 0x34, 0xc7, 0x92, 0x28, 0x29, 0x24, 0xc5, 0xe0, 0x5a, 0x2f, 0x46, 0x7e, 0xe3,
+	0xa9, 0xc3, 0xc9, 0x2f, 0x61, 0xc4, 0xae, 0x73, 0x2e, 0x99, 0x5a, 0xeb, 0xb6, 0x50, 0x77, 0xdb,
+	0xe1, 0xa5, 0xea, 0x1f, 0x60, 0x87, 0x66, 0xab, 0x48, 0xcc, 0xa2, 0xd8, 0xbb, 0x60, 0xa3, 0xd9,
+	0x3b, 0x7c, 0x70, 0xcb, 0xb9, 0x33, 0xa6, 0xc3, 0x6d, 0x9a, 0xad, 0x4e, 0x66, 0x25, 0xa4, 0x1c,
+	0xa1, 0xed, 0x92, 0xd0, 0x31, 0x74, 0xfc, 0x83, 0x30, 0xd6, 0x8d, 0xb0, 0x94, 0x4d, 0x0a, 0x98,
+	0xbc, 0xeb, 0x22, 0x6c, 0x96, 0x93, 0x08, 0x06, 0x5f, 0x70, 0x45, 0x43, 0xf6, 0x15, 0x8b, 0xd7,
+	0xd9, 0x49, 0x95, 0xc8, 0x1c, 0x59, 0x4e, 0x32, 0xb4, 0xcc, 0x38, 0x4b, 0x13, 0xc7, 0x96, 0x15,
+	0x6e, 0x66, 0x7b, 0xe3, 0x56, 0xb6, 0x4f, 0xfe, 0x55, 0x83, 0xbe, 0xb5, 0xce, 0x12, 0x73, 0x13,
+	0x79, 0x0c, 0x7d, 0x2d, 0x5e, 0xb1, 0x2c, 0x9a, 0x09, 0xb9, 0xa4, 0xda, 0x5d, 0xd3, 0x43, 0xec,
+	0x8f, 0x08, 0x99, 0x37, 0x70, 0xa5, 0x0a, 0x26, 0x7d, 0x68, 0xac, 0x44, 0x02, 0x68, 0xab, 0xe2,
+	0xd2, 0xd8, 0x72, 0x37, 0x79, 0x91, 0x1c, 0x40, 0x97, 0xfa, 0x68, 0x63, 0x58, 0x2a, 0xb9, 0x51,
+	0xa6, 0x41, 0xb8, 0xd6, 0x21, 0x1f, 0x40, 0x57, 0x7a, 0x9f, 0x31, 0x36, 0xbd, 0xc3, 0x1f, 0xfb,
+	0x03, 0x1b, 0x84, 0x84, 0x6b, 0xbd, 0xc9, 0xbf, 0x6b, 0x30, 0xf4, 0xbe, 0x9c, 0x8a, 0x94, 0xc7,
+	0x2b, 0xf2, 0x08, 0xa0, 0xdc, 0x57, 0xe8, 0x4b, 0x2b, 0xac, 0x20, 0xe4, 0x63, 0x18, 0x4a, 0x77,
+	0x22, 0xba, 0xe2, 0x8a, 0xaa, 0xa0, 0xbe, 0x19, 0xdc, 0x2a, 0x37, 0xe1, 0x40, 0x56, 0x24, 0x65,
+	0xa8, 0xca, 0xf1, 0x9a, 0xe8, 0x92, 0x2a, 0xae, 0x82, 0x06, 0x7e, 0x1e, 0x3d, 0x8b, 0x7d, 0x62,
+	0x20, 0xb2, 0x0f, 0x44, 0xb2, 0xaf, 0x0b, 0xa6, 0xcc, 0x05, 0x92, 0xb9, 0x8c, 0xb6, 0x89, 0xb9,
+	0x53, 0xee, 0x84, 0x6e, 0xc3, 0x30, 0xb8, 0x64, 0x4a, 0xd1, 0x39, 0x73, 0x09, 0xe9, 0xc5, 0xc9,
+	0xb7, 0x75, 0xe8, 0x9c, 0x52, 0xa5, 0x72, 0x21, 0x35, 0xf9, 0x1c, 0xb6, 0x95, 0xa6, 0x59, 0x42,
+	0x65, 0x12, 0xc5, 0x29, 0xe5, 0x4b, 0xe5, 0x3e, 0xb8, 0xb7, 0xfc, 0xb3, 0xbd, 0xea, 0xf4, 0xcc,
+	0xe9, 0x1d, 0xa3, 0xda, 0xb3, 0x4c, 0xcb, 0x55, 0x38, 0x54, 0x1b, 0x20, 0xf9, 0x1d, 0x8c, 0xe6,
+	0xf4, 0xc3, 0xf9, 0x22, 0x2a, 0xf9, 0xf7, 0x34, 0xdc, 0x11, 0xa4, 0x6d, 0x54, 0x2d, 0x65, 0x45,
+	0x9e, 0x40, 0xc0, 0x33, 0xcd, 0x64, 0x46, 0xd3, 0x88, 0x65, 0xb1, 0x5c, 0xe5, 0x6b, 0x32, 0x7b,
+	0xbb, 0x8d, 0xbd, 0x7e, 0xf8, 0xd0, 0xef, 0x3f, 0xf3, 0xdb, 0x25, 0x7f, 0xec, 0x5a, 0x4b, 0x1a,
+	0xa9, 0x58, 0xe4, 0x4c, 0xf9, 0xf4, 0x44, 0xec, 0x0c, 0xa1, 0xf1, 0x11, 0xbc, 0x76, 0x87, 0x07,
+	0xe6, 0x43, 0x79, 0xc5, 0x56, 0x2e, 0x37, 0xcd, 0x72, 0x5d, 0x16, 0xea, 0x95, 0xb2, 0xf0, 0x51,
+	0xfd, 0x49, 0x6d, 0xf2, 0x6d, 0x0d, 0x7a, 0xe7, 0x4c, 0xe9, 0x53, 0x26, 0x95, 0xc8, 0x28, 0x79,
+	0x0f, 0x3a, 0xb9, 0x63, 0x07, 0x0d, 0xf4, 0x0e, 0x47, 0x37, 0x59, 0x0b, 0x4b, 0x0d, 0x93, 0xeb,
+	0x34, 0x8e, 0x99, 0xb2, 0x8c, 0x74, 0x43, 0x27, 0x91, 0x77, 0xa1, 0x5e, 0x70, 0x8c, 0x78, 0xef,
+	0xf0, 0x0d, 0x7f, 0xbe, 0x72, 0xcd, 0xf4, 0x82, 0x5b, 0xb2, 0xeb, 0x05, 0x1f, 0xff, 0x06, 0xda,
+	0x17, 0xfc, 0xfb, 0xbf, 0xfc, 0x2f, 0x0d, 0xe8, 0x9d, 0x32, 0xb9, 0xe4, 0x4a, 0x21, 0xd3, 0x01,
+	0xb4, 0xaf, 0x98, 0x34, 0x6b, 0x77, 0xde, 0x8b, 0xa6, 0xa8, 0x48, 0x76, 0xc5, 0x95, 0x6f, 0x69,
+	0x8d, 0xb0, 0x94, 0x4d, 0x2b, 0x32, 0xcf, 0xe3, 0x3a, 0x32, 0x0d, 0x13, 0x49, 0xae, 0x85, 0x60,
+	0xa1, 0x73, 0xbe, 0x64, 0xe4, 0x43, 0x68, 0x15, 0x8a, 0x49, 0x15, 0x34, 0xd1, 0x9b, 0x47, 0x25,
+	0x1b, 0xeb, 0xab, 0xa7, 0x17, 0x46, 0xc1, 0x3a, 0x64, 0x95, 0xc7, 0x7f, 0xad, 0xc1, 0x70, 0xad,
+	0x61, 0xf6, 0xc9, 0x31, 0xb4, 0xa4, 0x48, 0x99, 0x4f, 0xc6, 0xfd, 0xbb, 0x0c, 0x6d, 0x1e, 0x99,
+	0x86, 0x46, 0xdf, 0xd9, 0xc5, 0xb3, 0xe3, 0x27, 0x00, 0x6b, 0xf0, 0x3e, 0xba, 0x1a, 0x15, 0xba,
+	0xc6, 0x7f, 0x02, 0x58, 0x3f, 0xf3, 0x8e, 0x93, 0x4f, 0xaa, 0x27, 0x7b, 0x87, 0x93, 0xfb, 0x9f,
+	0x57, 0x0d, 0xc6, 0x7f, 0xeb, 0x00, 0x2f, 0xc4, 0x9c, 0x67, 0x67, 0x9a, 0x6a, 0xd3, 0x70, 0x9a,
+	0x4a, 0xb3, 0x1c, 0xed, 0x0f, 0x0f, 0x5f, 0xf7, 0xb6, 0xd6, 0x1a, 0xd3, 0x33, 0xcd, 0xf2, 0x10,
+	0x95, 0x4c, 0x78, 0x72, 0x29, 0xae, 0x78, 0x52, 0x96, 0xcc, 0x52, 0x36, 0xfe, 0x48, 0x46, 0xd3,
+	0xa5, 0xef, 0x67, 0x28, 0x90, 0x5f, 0xc0, 0x76, 0x6a, 0x4c, 0x45, 0xf1, 0x82, 0xa6, 0x29, 0xcb,
+	0xe6, 0xbe, 0x68, 0x0c, 0x11, 0x3e, 0xf6, 0x68, 0xb5, 0xe6, 0xb6, 0x36, 0x6b, 0xee, 0x03, 0x68,
+	0xe1, 0x77, 0x15, 0x6c, 0x59, 0xc3, 0x28, 0x90, 0x9f, 0x01, 0x58, 0xc3, 0x0b, 0x9e, 0x69, 0xd7,
+	0x96, 0xba, 0x88, 0x7c, 0xc6, 0x33, 0x6d, 0xfa, 0x68, 0x2c, 0x32, 0xc5, 0x32, 0x5d, 0xb9, 0xd9,
+	0x8e, 0x24, 0x23, 0xb7, 0xb1, 0xbe, 0xdb, 0xb4, 0xb2, 0x22, 0xe1, 0x2c, 0x8b, 0xcd, 0x68, 0x82,
+	0xa3, 0x81, 0x97, 0x31, 0xeb, 0x52, 0x6e, 0xec, 0x64, 0x74, 0xc9, 0x02, 0x40, 0x13, 0x60, 0xa1,
+	0x97, 0x74, 0xc9, 0x26, 0x8f, 0xa0, 0x69, 0x18, 0x22, 0x5d, 0x68, 0xbd, 0x38, 0xf9, 0xf4, 0xf9,
+	0xcb, 0xd1, 0x8f, 0x48, 0x0f, 0xda, 0xc7, 0x27, 0x2f, 0xcf, 0x9e, 0xbd, 0x3c, 0x1f, 0xd5, 0x26,
+	0x5f, 0xc2, 0xe0, 0xdc, 0xf4, 0x9c, 0xcf, 0x99, 0xa6, 0x09, 0xd5, 0xd4, 0x0c, 0x09, 0x68, 0xca,
+	0x0d, 0x09, 0x66, 0x6d, 0xa6, 0x13, 0xec, 0x3d, 0x49, 0x44, 0xb5, 0x67, 0xd6, 0x02, 0x47, 0xda,
+	0x50, 0xe3, 0xda, 0xb9, 0x6f, 0x47, 0x4e, 0x9c, 0xfc, 0xad, 0x05, 0x9d, 0xe3, 0x94, 0xdb, 0x48,
+	0x0e, 0xa1, 0xce, 0x13, 0x67, 0xb5, 0xce, 0x13, 0xc3, 0x1b, 0x5b, 0x52, 0x9e, 0xfa, 0xef, 0x11,
+	0x05, 0x73, 0x93, 0xf3, 0x87, 0x27, 0xce, 0x5c, 0xc7, 0x02, 0xcf, 0x93, 0x35, 0xd5, 0xcd, 0x2a,
+	0xd5, 0x3f, 0x31, 0xf4, 0xe8, 0x45, 0x54, 0xc8, 0xd4, 0xc7, 0xc6, 0xc8, 0x17, 0x32, 0x25, 0xbf,
+	0x05, 0x88, 0x25, 0xa3, 0xda, 0x3e, 0x7c, 0x0b, 0xf3, 0x71, 0x3c, 0xb5, 0x03, 0xee, 0xd4, 0x0f,
+	0xb8, 0xd3, 0x73, 0x3f, 0xe0, 0x86, 0x5d, 0xa7, 0x7d, 0xa4, 0xcd, 0x51, 0x3f, 0xbc, 0x50, 0x1b,
+	0xc0, 0x7b, 0x8e, 0x3a, 0xed, 0x23, 0xcc, 0x88, 0x4c, 0x64, 0xb1, 0x0f, 0xa8, 0x15, 0x70, 0x1a,
+	0x2a, 0xcb, 0xb6, 0x62, 0xb1, 0x64, 0x1a, 0x27, 0x90, 0x7e, 0xb8, 0x5d, 0xe2, 0x67, 0x08, 0x93,
+	0xb7, 0x61, 0xb8, 0x56, 0x8d, 0x45, 0x62, 0xe3, 0xda, 0x0f, 0x07, 0x25, 0x7a, 0x2c, 0x12, 0x3b,
+	0xba, 0x59, 0x3b, 0x3d, 0x37, 0xba, 0xd9, 0xe3, 0x8f, 0xa1, 0x6f, 0xab, 0x67, 0x84, 0xd3, 0x44,
+	0xd0, 0xb7, 0xf5, 0xde, 0x62, 0x18, 0x6c, 0x33, 0xf2, 0x4a, 0x36, 0x93, 0x4c, 0x2d, 0x9c, 0xce,
+	0xc0, 0x8e, 0xbc, 0x0e, 0xb4, 0x4a, 0x86, 0x6e, 0x13, 0xba, 0x60, 0xe8, 0xe8, 0x36, 0x02, 0xf9,
+	0x18, 0xd0, 0x52, 0xee, 0x48, 0xdd, 0xbe, 0x97, 0x19, 0xf0, 0xea, 0x47, 0x9a, 0x3c, 0x85, 0xbe,
+	0x29, 0x6b, 0x51, 0x2e, 0xc5, 0x8c, 0xa7, 0x2c, 0x18, 0x61, 0x05, 0x7b, 0x5c, 0x8e, 0x78, 0x2e,
+	0x59, 0xb0, 0x0e, 0x9e, 0x5a, 0x1d, 0x5b, 0xb5, 0x7a, 0xc5, 0x1a, 0x19, 0xff, 0x1e, 0x46, 0x37,
+	0x15, 0xbe, 0x57, 0xc1, 0xff, 0x7b, 0x1d, 0xfa, 0x9f, 0x71, 0xa5, 0x85, 0x5c, 0xd9, 0xc3, 0xd5,
+	0xba, 0x5e, 0xbb, 0x51, 0xd7, 0x09, 0x34, 0xcd, 0xdd, 0xce, 0x0a, 0xae, 0xef, 0xaf, 0xf5, 0x04,
+	0x9a, 0x39, 0xd5, 0x0b, 0xff, 0x23, 0xc6, 0xac, 0xcd, 0x7b, 0xbe, 0x2e, 0x98, 0x5c, 0xb9, 0x24,
+	0xb5, 0x82, 0xd1, 0x34, 0x73, 0xa2, 0xab, 0x1e, 0xb8, 0x36, 0x81, 0x5d, 0x32, 0xbd, 0x10, 0x89,
+	0x2b, 0x1c, 0x4e, 0xc2, 0x6b, 0x17, 0x34, 0x9b, 0xb3, 0x08, 0xc7, 0xf8, 0x8e, 0xfb, 0xd8, 0x11,
+	0x3a, 0x37, 0xc3, 0xfc, 0xdb, 0x30, 0x14, 0x92, 0xcf, 0xb9, 0x99, 0x11, 0xaa, 0x3f, 0x65, 0x06,
+	0x1e, 0xc5, 0xdf, 0x32, 0x46, 0xcd, 0xd9, 0x71, 0xa3, 0x91, 0xab, 0x1b, 0x03, 0x8b, 0x86, 0x16,
+	0x9c, 0x50, 0x68, 0x3b, 0x96, 0xc8, 0x14, 0xda, 0x0b, 0xbb, 0x0c, 0x6a, 0x9b, 0x83, 0x5b, 0x95,
+	0xc7, 0xd0, 0x2b, 0x91, 0x77, 0x60, 0x3b, 0x63, 0xd7, 0x3a, 0xca, 0xa9, 0x79, 0x2c, 0x66, 0x98,
+	0xe5, 0x6f, 0x60, 0xe0, 0x53, 0x3a, 0x67, 0x98, 0x62, 0x93, 0xff, 0xd4, 0x01, 0x4e, 0x78, 0x12,
+	0x1f, 0x8b, 0x6c, 0xc6, 0xe7, 0x95, 0x89, 0xb7, 0xb6, 0x31, 0xf1, 0x8e, 0xa1, 0xf3, 0xd5, 0x9f,
+	0x5f, 0xa9, 0xa8, 0x90, 0xdc, 0x97, 0x1f, 0x2f, 0x93, 0x7d, 0x18, 0xe0, 0xe7, 0xcf, 0xb2, 0x24,
+	0x17, 0xa6, 0xd8, 0xda, 0xaa, 0xf1, 0xd0, 0x80, 0x42, 0xf2, 0x6f, 0xf0, 0x07, 0x46, 0xb9, 0x4b,
+	0x3e, 0x82, 0x40, 0x32, 0x95, 0x9b, 0x1a, 0x8b, 0x2c, 0xaa, 0x48, 0x15, 0xb9, 0x19, 0x41, 0x58,
+	0x82, 0x8d, 0xb9, 0x1b, 0x7e, 0xe7, 0x3e, 0x79, 0x07, 0x86, 0x76, 0x66, 0x2f, 0xef, 0xb2, 0xa1,
+	0xbc, 0x81, 0x92, 0xf7, 0xe1, 0x35, 0xc9, 0xae, 0x44, 0xbc, 0x79, 0xb5, 0x8b, 0xd7, 0x5d, 0x5b,
+	0xe4, 0x3d, 0xd8, 0x31, 0x89, 0xc5, 0xb3, 0x99, 0x58, 0xeb, 0xdb, 0x94, 0xb8, 0xbd, 0x41, 0x7e,
+	0x05, 0x23, 0x3b, 0xca, 0x55, 0xde, 0xde, 0xc6, 0xb7, 0xdf, 0xc2, 0x27, 0xff, 0xac, 0xc3, 0x8e,
+	0x61, 0x18, 0xf9, 0x0e, 0x9d, 0x67, 0x64, 0x72, 0xa3, 0x44, 0x58, 0xba, 0x37, 0x30, 0x33, 0xd3,
+	0x5b, 0xbf, 0x30, 0xd9, 0x2c, 0xed, 0x15, 0xc4, 0xec, 0xfb, 0x0a, 0xc9, 0xed, 0x6f, 0x9e, 0x56,
+	0x58, 0x41, 0xc8, 0x5b, 0x37, 0x6b, 0x8c, 0xfd, 0x18, 0x36, 0x41, 0x13, 0x5a, 0x9e, 0x38, 0x85,
+	0x96, 0xeb, 0x2c, 0x4e, 0xfe, 0x8e, 0xd6, 0x3a, 0x82, 0x46, 0xc1, 0xfd, 0xa7, 0x61, 0x96, 0xe4,
+	0x10, 0x9a, 0x86, 0x20, 0x24, 0xb8, 0x32, 0x58, 0xdd, 0x72, 0x7b, 0xfa, 0x3c, 0x9b, 0x89, 0x10,
+	0x75, 0xc7, 0xef, 0x43, 0xd3, 0x48, 0x77, 0xb6, 0xbb, 0x3b, 0x5b, 0xd3, 0x27, 0x17, 0x5f, 0x9e,
+	0xcd, 0xb9, 0x5e, 0x14, 0x97, 0xc6, 0xfe, 0xc1, 0xa7, 0x58, 0xef, 0x8e, 0x53, 0x51, 0x24, 0xa7,
+	0x29, 0xd5, 0xe6, 0x57, 0xdc, 0xc1, 0x82, 0xd1, 0x54, 0x2f, 0x62, 0x2a, 0xd9, 0xfe, 0x8c, 0x25,
+	0x4c, 0x9a, 0x56, 0xb2, 0x6f, 0x39, 0xdd, 0x57, 0x4c, 0x5e, 0xf1, 0x98, 0xa9, 0x83, 0x1b, 0x7f,
+	0xc2, 0x5c, 0x6e, 0x21, 0xf0, 0xc1, 0xff, 0x07, 0x00, 0x85, 0x8e, 0x00, 0x6c, 0x9e, 0x11, 0x00,
+	0x00,
 }
@@ -999,10 +999,25 @@ func (m *ConfigOptions) GetAwsManagedKeysPerIamUser() int32 {
 }
 
 type VisaType struct {
-	Ui                   map[string]string `protobuf:"bytes,2,rep,name=ui,proto3" json:"ui,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Ui map[string]string `protobuf:"bytes,2,rep,name=ui,proto3" json:"ui,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Constraints on the visas of the type, visas which do not conform are
+	// rejected. Empty fields do not constrain visas.
+	//
+	// A regular expression the visa value must fully match.
+	ValueRegex string `protobuf:"bytes,3,opt,name=value_regex,json=valueRegex,proto3" json:"value_regex,omitempty"`
+	// A JSON schema the visa value must conform to. Values which are JSON objects
+	// or arrays are validated as such, other values as JSON strings. See
+	// lib/jsonschema for the supported keywords.
+	ValueSchema string `protobuf:"bytes,4,opt,name=value_schema,json=valueSchema,proto3" json:"value_schema,omitempty"`
+	// The accepted visa sources. Entries starting with "^" and ending with "$"
+	// are regular expressions.
+	Sources []string `protobuf:"bytes,5,rep,name=sources,proto3" json:"sources,omitempty"`
+	// The accepted visa "by" values. Entries starting with "^" and ending with
+	// "$" are regular expressions.
+	By                   []string `protobuf:"bytes,6,rep,name=by,proto3" json:"by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *VisaType) Reset()         { *m = VisaType{} }
@@ -1037,6 +1052,34 @@ func (m *VisaType) GetUi() map[string]string {
 	return nil
 }
 
+func (m *VisaType) GetValueRegex() string {
+	if m != nil {
+		return m.ValueRegex
+	}
+	return ""
+}
+
+func (m *VisaType) GetValueSchema() string {
+	if m != nil {
+		return m.ValueSchema
+	}
+	return ""
+}
+
+func (m *VisaType) GetSources() []string {
+	if m != nil {
+		return m.Sources
+	}
+	return nil
+}
+
+func (m *VisaType) GetBy() []string {
+	if m != nil {
+		return m.By
+	}
+	return nil
+}
+
 type ServiceDescriptor struct {
 	Platform             string                        `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
 	ServiceVariables     map[string]*VariableFormat    `protobuf:"bytes,2,rep,name=service_variables,json=serviceVariables,proto3" json:"service_variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -2478,15 +2521,17 @@ func (m *GetTestResultsResponse_RejectedVisa) GetBy() string {
 }
 
 type GetTestResultsResponse_TestResult struct {
-	Name                 string                                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Result               string                                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
-	Access               []string                               `protobuf:"bytes,3,rep,name=access,proto3" json:"access,omitempty"`
-	NoAccess             map[string]string                      `protobuf:"bytes,4,rep,name=no_access,json=noAccess,proto3" json:"no_access,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	RejectedVisas        []*GetTestResultsResponse_RejectedVisa `protobuf:"bytes,5,rep,name=rejected_visas,json=rejectedVisas,proto3" json:"rejected_visas,omitempty"`
-	Error                string                                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                               `json:"-"`
-	XXX_unrecognized     []byte                                 `json:"-"`
-	XXX_sizecache        int32                                  `json:"-"`
+	Name          string                                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Result        string                                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	Access        []string                               `protobuf:"bytes,3,rep,name=access,proto3" json:"access,omitempty"`
+	NoAccess      map[string]string                      `protobuf:"bytes,4,rep,name=no_access,json=noAccess,proto3" json:"no_access,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RejectedVisas []*GetTestResultsResponse_RejectedVisa `protobuf:"bytes,5,rep,name=rejected_visas,json=rejectedVisas,proto3" json:"rejected_visas,omitempty"`
+	Error         string                                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// Trace of the policy evaluation, only set when requested by "explain".
+	Explanation          *GetTestResultsResponse_Trace `protobuf:"bytes,7,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
 }
 
 func (m *GetTestResultsResponse_TestResult) Reset()         { *m = GetTestResultsResponse_TestResult{} }
@@ -2556,6 +2601,190 @@ func (m *GetTestResultsResponse_TestResult) GetError() string {
 	return ""
 }
 
+func (m *GetTestResultsResponse_TestResult) GetExplanation() *GetTestResultsResponse_Trace {
+	if m != nil {
+		return m.Explanation
+	}
+	return nil
+}
+
+// Trace is the evaluation of a policy, or of a part of it, and of the visas
+// it considered.
+type GetTestResultsResponse_Trace struct {
+	Kind                 string                               `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name                 string                               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Result               bool                                 `protobuf:"varint,3,opt,name=result,proto3" json:"result,omitempty"`
+	Visas                []*GetTestResultsResponse_Trace_Visa `protobuf:"bytes,4,rep,name=visas,proto3" json:"visas,omitempty"`
+	Children             []*GetTestResultsResponse_Trace      `protobuf:"bytes,5,rep,name=children,proto3" json:"children,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                             `json:"-"`
+	XXX_unrecognized     []byte                               `json:"-"`
+	XXX_sizecache        int32                                `json:"-"`
+}
+
+func (m *GetTestResultsResponse_Trace) Reset()         { *m = GetTestResultsResponse_Trace{} }
+func (m *GetTestResultsResponse_Trace) String() string { return proto.CompactTextString(m) }
+func (*GetTestResultsResponse_Trace) ProtoMessage()    {}
+func (*GetTestResultsResponse_Trace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1b3693f36078fb7, []int{36, 2}
+}
+
+func (m *GetTestResultsResponse_Trace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTestResultsResponse_Trace.Unmarshal(m, b)
+}
+func (m *GetTestResultsResponse_Trace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTestResultsResponse_Trace.Marshal(b, m, deterministic)
+}
+func (m *GetTestResultsResponse_Trace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTestResultsResponse_Trace.Merge(m, src)
+}
+func (m *GetTestResultsResponse_Trace) XXX_Size() int {
+	return xxx_messageInfo_GetTestResultsResponse_Trace.Size(m)
+}
+func (m *GetTestResultsResponse_Trace) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTestResultsResponse_Trace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTestResultsResponse_Trace proto.InternalMessageInfo
+
+func (m *GetTestResultsResponse_Trace) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *GetTestResultsResponse_Trace) GetVisas() []*GetTestResultsResponse_Trace_Visa {
+	if m != nil {
+		return m.Visas
+	}
+	return nil
+}
+
+func (m *GetTestResultsResponse_Trace) GetChildren() []*GetTestResultsResponse_Trace {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+type GetTestResultsResponse_Trace_Visa struct {
+	Issuer   string `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	VisaType string `protobuf:"bytes,2,opt,name=visa_type,json=visaType,proto3" json:"visa_type,omitempty"`
+	Source   string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Value    string `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	By       string `protobuf:"bytes,5,opt,name=by,proto3" json:"by,omitempty"`
+	Matched  bool   `protobuf:"varint,6,opt,name=matched,proto3" json:"matched,omitempty"`
+	// Reason, field and description of visas not matched.
+	Reason               string   `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	Field                string   `protobuf:"bytes,8,opt,name=field,proto3" json:"field,omitempty"`
+	Description          string   `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) Reset()         { *m = GetTestResultsResponse_Trace_Visa{} }
+func (m *GetTestResultsResponse_Trace_Visa) String() string { return proto.CompactTextString(m) }
+func (*GetTestResultsResponse_Trace_Visa) ProtoMessage()    {}
+func (*GetTestResultsResponse_Trace_Visa) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1b3693f36078fb7, []int{36, 2, 0}
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTestResultsResponse_Trace_Visa.Unmarshal(m, b)
+}
+func (m *GetTestResultsResponse_Trace_Visa) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTestResultsResponse_Trace_Visa.Marshal(b, m, deterministic)
+}
+func (m *GetTestResultsResponse_Trace_Visa) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTestResultsResponse_Trace_Visa.Merge(m, src)
+}
+func (m *GetTestResultsResponse_Trace_Visa) XXX_Size() int {
+	return xxx_messageInfo_GetTestResultsResponse_Trace_Visa.Size(m)
+}
+func (m *GetTestResultsResponse_Trace_Visa) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTestResultsResponse_Trace_Visa.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTestResultsResponse_Trace_Visa proto.InternalMessageInfo
+
+func (m *GetTestResultsResponse_Trace_Visa) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetVisaType() string {
+	if m != nil {
+		return m.VisaType
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetBy() string {
+	if m != nil {
+		return m.By
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetMatched() bool {
+	if m != nil {
+		return m.Matched
+	}
+	return false
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *GetTestResultsResponse_Trace_Visa) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
 type ServicesRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -4482,6 +4711,8 @@ func init() {
 	proto.RegisterType((*GetTestResultsResponse_RejectedVisa)(nil), "dam.v1.GetTestResultsResponse.RejectedVisa")
 	proto.RegisterType((*GetTestResultsResponse_TestResult)(nil), "dam.v1.GetTestResultsResponse.TestResult")
 	proto.RegisterMapType((map[string]string)(nil), "dam.v1.GetTestResultsResponse.TestResult.NoAccessEntry")
+	proto.RegisterType((*GetTestResultsResponse_Trace)(nil), "dam.v1.GetTestResultsResponse.Trace")
+	proto.RegisterType((*GetTestResultsResponse_Trace_Visa)(nil), "dam.v1.GetTestResultsResponse.Trace.Visa")
 	proto.RegisterType((*ServicesRequest)(nil), "dam.v1.ServicesRequest")
 	proto.RegisterType((*ServicesResponse)(nil), "dam.v1.ServicesResponse")
 	proto.RegisterMapType((map[string]*ServiceDescriptor)(nil), "dam.v1.ServicesResponse.ServicesEntry")
@@ -4541,278 +4772,288 @@ func init() {
 }
 
 var fileDescriptor_b1b3693f36078fb7 = []byte{
-	// 4363 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x3b, 0x4d, 0x6f, 0x1c, 0x47,
-	0x76, 0xe9, 0x21, 0x39, 0x9c, 0x79, 0xc3, 0x19, 0x92, 0x45, 0x4a, 0x1a, 0xb5, 0x3e, 0x4c, 0x8f,
-	0x64, 0xeb, 0xcb, 0x22, 0x2d, 0x39, 0x82, 0xec, 0xf5, 0x87, 0x22, 0x91, 0x92, 0x4c, 0x7b, 0x25,
-	0x73, 0x9b, 0x94, 0xe0, 0xd5, 0x1a, 0xdb, 0x28, 0x4e, 0x17, 0x87, 0xbd, 0xea, 0xe9, 0x9e, 0xed,
-	0xee, 0xa1, 0x34, 0x40, 0x02, 0x24, 0xc8, 0x21, 0x08, 0xf6, 0x10, 0x04, 0xd8, 0x7f, 0x91, 0xc4,
-	0x41, 0x80, 0x5c, 0xf6, 0x10, 0x24, 0x40, 0x0e, 0x39, 0x25, 0x08, 0x72, 0xc9, 0x39, 0x9b, 0x6b,
-	0x0e, 0x41, 0x90, 0x9c, 0x72, 0x48, 0x50, 0x9f, 0x5d, 0xd5, 0xdd, 0xc3, 0x0f, 0x79, 0xe0, 0xbd,
-	0x90, 0x53, 0xaf, 0xde, 0x7b, 0xf5, 0xaa, 0xea, 0xd5, 0xfb, 0xaa, 0x6a, 0xb8, 0x38, 0x88, 0xa3,
-	0x34, 0x5a, 0xf3, 0x70, 0x7f, 0xed, 0xe0, 0x16, 0xfd, 0xe7, 0x26, 0x24, 0x3e, 0xf0, 0xbb, 0x64,
-	0x95, 0x75, 0xa0, 0xaa, 0x87, 0xfb, 0xab, 0x07, 0xb7, 0xec, 0xf3, 0x1c, 0xaf, 0x1b, 0xf5, 0xfb,
-	0x51, 0x48, 0x51, 0xf9, 0x2f, 0x8e, 0x65, 0xbf, 0x9d, 0xef, 0x8d, 0xf0, 0x30, 0xdd, 0xef, 0x06,
-	0x3e, 0x09, 0x53, 0x81, 0x22, 0x06, 0x1a, 0xc4, 0x51, 0x97, 0x24, 0x09, 0xc5, 0x11, 0x3f, 0x79,
-	0x7f, 0xe7, 0x9f, 0x1a, 0x50, 0xdf, 0xc0, 0xfd, 0xf5, 0x28, 0xdc, 0xf3, 0x7b, 0xa8, 0x0d, 0xb3,
-	0x07, 0x24, 0x4e, 0xfc, 0x28, 0x6c, 0x5b, 0x2b, 0xd6, 0xd5, 0xba, 0x23, 0x9b, 0xc8, 0x86, 0x5a,
-	0x4c, 0x0e, 0x7c, 0xd6, 0x55, 0x59, 0xb1, 0xae, 0x4e, 0x39, 0xaa, 0x8d, 0xde, 0x82, 0x06, 0x15,
-	0xc1, 0x4f, 0xdd, 0xd4, 0xef, 0x93, 0xf6, 0xd4, 0x8a, 0x75, 0xd5, 0x72, 0x80, 0x83, 0x76, 0xfc,
-	0x3e, 0x41, 0x4f, 0x61, 0x3e, 0x8d, 0x87, 0x49, 0x4a, 0x3c, 0xd7, 0x4f, 0x92, 0x21, 0x89, 0x93,
-	0xf6, 0xf4, 0xca, 0xd4, 0xd5, 0xc6, 0xed, 0x77, 0x56, 0xf9, 0x3c, 0x57, 0x95, 0x08, 0xab, 0x3b,
-	0x1c, 0x71, 0x93, 0xe3, 0x3d, 0x0c, 0xd3, 0x78, 0xe4, 0xb4, 0x52, 0x03, 0xa8, 0xf3, 0x4b, 0xa2,
-	0x61, 0xdc, 0x25, 0x49, 0x7b, 0xe6, 0x08, 0x7e, 0xdb, 0x1c, 0xcf, 0xe4, 0x27, 0x80, 0xe8, 0x63,
-	0xa8, 0x0d, 0xa2, 0xc0, 0xef, 0xfa, 0x24, 0x69, 0x57, 0x19, 0xa3, 0xb7, 0x8a, 0x8c, 0xb6, 0x04,
-	0x06, 0x67, 0xa1, 0x08, 0xd0, 0x67, 0x50, 0x8f, 0x89, 0x14, 0x63, 0x96, 0x51, 0xaf, 0x14, 0xa9,
-	0x1d, 0x89, 0xc2, 0xc9, 0x33, 0x12, 0xf4, 0x21, 0xcc, 0xf2, 0x1d, 0x4b, 0xda, 0x35, 0x46, 0x7d,
-	0xb1, 0x48, 0xbd, 0xce, 0x11, 0x38, 0xad, 0x44, 0x47, 0x3b, 0xb0, 0x28, 0xb4, 0xc6, 0x4d, 0x49,
-	0x7f, 0x10, 0xe0, 0x94, 0x24, 0xed, 0x3a, 0xe3, 0x71, 0xa5, 0xc8, 0x63, 0x9b, 0xa3, 0xee, 0x48,
-	0x4c, 0xce, 0x6c, 0x21, 0xc9, 0x81, 0xd1, 0x3d, 0x80, 0x03, 0x3f, 0xc1, 0x6e, 0x3a, 0x1a, 0x90,
-	0xa4, 0x0d, 0xe3, 0x26, 0xf4, 0xdc, 0x4f, 0xf0, 0x0e, 0x45, 0x11, 0x13, 0x3a, 0x90, 0x6d, 0xf4,
-	0x39, 0x34, 0x53, 0x92, 0xa4, 0xee, 0x80, 0xc4, 0x49, 0x14, 0xe2, 0xa4, 0xdd, 0x60, 0x3c, 0x2e,
-	0x95, 0xec, 0x0d, 0x49, 0xd2, 0x2d, 0x81, 0xc5, 0xd9, 0xcc, 0xa5, 0x1a, 0x08, 0xad, 0xc1, 0x6c,
-	0x34, 0x48, 0xfd, 0x28, 0x4c, 0xda, 0x73, 0x2b, 0xd6, 0xd5, 0xc6, 0xed, 0x53, 0x92, 0x07, 0x67,
-	0xf0, 0x15, 0xef, 0x74, 0x24, 0x16, 0xba, 0x06, 0x95, 0xa1, 0xdf, 0x6e, 0xb2, 0xf1, 0xce, 0x16,
-	0xc7, 0x7b, 0xe6, 0xf3, 0x51, 0x2a, 0x43, 0xdf, 0xfe, 0x1a, 0x96, 0x4a, 0x54, 0x0d, 0x2d, 0xc0,
-	0xd4, 0x4b, 0x32, 0x12, 0xda, 0x4f, 0x7f, 0xa2, 0x1b, 0x30, 0x73, 0x80, 0x83, 0x21, 0x61, 0x6a,
-	0xaf, 0x89, 0x60, 0x50, 0x3b, 0x1c, 0xe7, 0x07, 0x95, 0x0f, 0x2d, 0x8d, 0xb3, 0xae, 0x74, 0x27,
-	0xe7, 0xcc, 0xa9, 0x75, 0xce, 0x5f, 0x42, 0xd3, 0xd0, 0xc2, 0x12, 0x9e, 0x97, 0x4d, 0x9e, 0x2d,
-	0xc9, 0x93, 0xd1, 0x8d, 0x74, 0x66, 0x4f, 0xa1, 0x65, 0x2a, 0x65, 0x09, 0xb7, 0x77, 0x4d, 0x6e,
-	0x0b, 0x92, 0x9b, 0x24, 0xd4, 0xf9, 0x7d, 0x01, 0x73, 0xba, 0x9a, 0x1e, 0x47, 0x36, 0x61, 0xcc,
-	0x38, 0x99, 0xce, 0xeb, 0x1b, 0x38, 0x55, 0xaa, 0xae, 0x25, 0x4c, 0x6f, 0x9a, 0x4c, 0xcf, 0x48,
-	0x11, 0x73, 0xf4, 0xb9, 0x99, 0x9b, 0xda, 0x7b, 0x82, 0x99, 0x4b, 0x42, 0x9d, 0xdf, 0x0e, 0x2c,
-	0x16, 0x34, 0xb9, 0x84, 0xe5, 0x35, 0x93, 0xe5, 0x92, 0x9c, 0xbe, 0x46, 0xab, 0x73, 0xbd, 0x03,
-	0xb3, 0x42, 0x5f, 0x4b, 0x78, 0x2d, 0xeb, 0xbc, 0xea, 0x1a, 0x59, 0xe7, 0x17, 0x15, 0x68, 0x1a,
-	0xaa, 0x89, 0x4e, 0x43, 0x95, 0x5b, 0x5d, 0xc1, 0x40, 0xb4, 0xd0, 0x15, 0x6a, 0x45, 0x71, 0x98,
-	0xd0, 0xe5, 0x71, 0x87, 0x89, 0x1f, 0xf6, 0x04, 0xb7, 0x96, 0x02, 0x3f, 0xa3, 0x50, 0x74, 0x0e,
-	0xea, 0xdc, 0xe4, 0xb8, 0xbe, 0xc7, 0xac, 0x7b, 0xdd, 0xa9, 0x71, 0xc0, 0xa6, 0x87, 0xce, 0x42,
-	0x8d, 0x3a, 0x1d, 0x77, 0x18, 0x07, 0xed, 0x69, 0xee, 0x33, 0x68, 0xfb, 0x59, 0x1c, 0x50, 0xba,
-	0x34, 0x7a, 0x49, 0x42, 0xd6, 0x37, 0xc3, 0xe9, 0x18, 0x80, 0x76, 0xde, 0x64, 0x47, 0x95, 0x5b,
-	0xdb, 0x0b, 0xa5, 0x67, 0xca, 0x38, 0xae, 0x6f, 0xb8, 0x1a, 0xdf, 0x5a, 0x6a, 0x35, 0xf8, 0x71,
-	0xa2, 0x2e, 0x4e, 0x1a, 0x6b, 0x6b, 0x65, 0x8a, 0x8a, 0x2b, 0x0d, 0xf1, 0x05, 0xc3, 0xf0, 0x55,
-	0x58, 0xa7, 0x66, 0xd6, 0xb8, 0xc0, 0x53, 0xa5, 0x02, 0x73, 0xde, 0x93, 0x10, 0xf8, 0x9f, 0x2b,
-	0x50, 0xe5, 0x67, 0x15, 0xdd, 0x80, 0x2a, 0x0e, 0x47, 0x6e, 0xb4, 0xc7, 0x04, 0x6d, 0xdc, 0x5e,
-	0x56, 0xe7, 0x25, 0x0a, 0x3d, 0x9f, 0x1a, 0xbc, 0x6d, 0x92, 0x3a, 0x33, 0x38, 0x1c, 0x7d, 0xb5,
-	0x87, 0x5e, 0xc0, 0xf2, 0x01, 0x8e, 0x7d, 0xbc, 0x1b, 0x10, 0xd7, 0x23, 0x7b, 0x7e, 0xe8, 0x73,
-	0xbb, 0x59, 0x31, 0xdd, 0x01, 0x67, 0xbd, 0xfa, 0x5c, 0xa0, 0x6e, 0x64, 0x98, 0x5c, 0xf2, 0xa5,
-	0x83, 0x62, 0x0f, 0x7a, 0x57, 0x9b, 0xf9, 0xe9, 0x1c, 0x27, 0x7d, 0xca, 0x3f, 0x85, 0xf6, 0x38,
-	0xc6, 0x25, 0x6b, 0xf0, 0x9e, 0x79, 0x1c, 0x14, 0x63, 0xc9, 0xe2, 0x51, 0x14, 0xf7, 0x71, 0x3a,
-	0x81, 0x13, 0xf1, 0x3f, 0x55, 0x98, 0x7e, 0xee, 0x93, 0x57, 0xe8, 0x1a, 0x2c, 0xe4, 0xfd, 0xa5,
-	0xe0, 0x30, 0x9f, 0xf3, 0x82, 0xe8, 0x7d, 0xa8, 0x06, 0x78, 0x97, 0x04, 0x72, 0x01, 0xdb, 0xd9,
-	0xf9, 0x27, 0xaf, 0x56, 0x7f, 0xc8, 0xba, 0xf8, 0xc4, 0x05, 0x1e, 0xba, 0x04, 0xcd, 0x6e, 0x14,
-	0xa6, 0xf4, 0x94, 0x70, 0x05, 0x9a, 0x62, 0x0a, 0x34, 0x27, 0x80, 0x52, 0x87, 0x66, 0xe2, 0x28,
-	0x20, 0x32, 0xfc, 0x39, 0x63, 0x70, 0x75, 0x68, 0x0f, 0x67, 0xca, 0xb1, 0xd0, 0xdb, 0x30, 0xe7,
-	0x91, 0x3d, 0x3c, 0x0c, 0x52, 0x97, 0x02, 0xc4, 0x19, 0x6a, 0x08, 0x18, 0xc5, 0x47, 0x57, 0x60,
-	0xc6, 0x4f, 0x49, 0x5f, 0xc6, 0x2d, 0x8b, 0x06, 0xc7, 0xcd, 0x94, 0xf4, 0x1d, 0xde, 0x8f, 0x2e,
-	0xb3, 0x4d, 0x9c, 0x15, 0x9a, 0xa4, 0x63, 0x69, 0x5b, 0x88, 0x9e, 0xc0, 0x52, 0x37, 0xea, 0x0f,
-	0x86, 0x2c, 0x54, 0x0b, 0x53, 0x12, 0xef, 0x61, 0x7a, 0x52, 0x78, 0x60, 0x72, 0xd9, 0x20, 0x5b,
-	0x17, 0x78, 0x9b, 0x0a, 0x8d, 0xb3, 0x81, 0x8c, 0xce, 0xfe, 0xb5, 0x05, 0xd3, 0x54, 0x08, 0xb4,
-	0x06, 0xd3, 0x38, 0xee, 0x25, 0x42, 0x93, 0xcf, 0x15, 0xa4, 0x5c, 0xbd, 0x1f, 0xf7, 0x04, 0x3d,
-	0x43, 0x44, 0x77, 0x72, 0x1b, 0x70, 0xa1, 0x48, 0x52, 0xb2, 0x0b, 0xf6, 0x5d, 0xa8, 0x2b, 0x4e,
-	0x27, 0x51, 0x12, 0xfb, 0x23, 0x68, 0x68, 0xfc, 0xbe, 0x2f, 0xd2, 0x2f, 0x00, 0xb2, 0x5d, 0x3f,
-	0x91, 0x17, 0x22, 0xaf, 0x28, 0xe1, 0x77, 0x3f, 0x1d, 0xf6, 0xd7, 0x70, 0x66, 0xcc, 0x4e, 0x96,
-	0xb0, 0xb9, 0x62, 0xca, 0xa3, 0xb4, 0x4d, 0x51, 0xea, 0xe7, 0xee, 0x4f, 0x2c, 0xa8, 0xab, 0x0e,
-	0xca, 0x6c, 0x18, 0xfb, 0xc2, 0xe6, 0xd2, 0x9f, 0xe3, 0xb7, 0x58, 0x11, 0x95, 0x6e, 0xf1, 0x9b,
-	0x2f, 0x77, 0xe7, 0xef, 0x2a, 0x50, 0x93, 0xa1, 0x0b, 0xcd, 0x68, 0x86, 0xfd, 0xdd, 0x98, 0x04,
-	0x01, 0x16, 0xd4, 0xaa, 0x8d, 0x6e, 0xc1, 0xcc, 0x81, 0x4f, 0x5e, 0x49, 0xc9, 0xce, 0xe5, 0xe3,
-	0x1e, 0xb6, 0x01, 0xf2, 0xac, 0x32, 0x4c, 0xea, 0x57, 0x64, 0x18, 0xcf, 0x4f, 0xbe, 0x0a, 0xd3,
-	0x3b, 0xd0, 0xec, 0xe3, 0xd7, 0x2e, 0x77, 0x85, 0x69, 0x2a, 0xdd, 0x64, 0xa3, 0x8f, 0x5f, 0xef,
-	0x50, 0xd8, 0x4e, 0x1a, 0xa0, 0xab, 0xec, 0x74, 0xce, 0x98, 0xb6, 0x46, 0x8d, 0xa6, 0x1b, 0xd9,
-	0x47, 0x00, 0xd9, 0xe0, 0x25, 0xb3, 0xef, 0x98, 0x5b, 0x34, 0x67, 0xa8, 0xcc, 0x77, 0x37, 0xa6,
-	0x7f, 0x3f, 0x05, 0xf3, 0xb9, 0xd0, 0x8a, 0x9a, 0x29, 0x69, 0x57, 0x43, 0xdc, 0x97, 0x36, 0xb5,
-	0x21, 0x60, 0x4f, 0x71, 0x9f, 0xa0, 0xc7, 0xa0, 0x99, 0x85, 0xbc, 0x53, 0xca, 0xf1, 0x5b, 0x3d,
-	0xc4, 0xa2, 0xa0, 0xc7, 0xd0, 0x94, 0x63, 0x71, 0x4b, 0xca, 0xdd, 0xd2, 0xb5, 0x71, 0xbc, 0x44,
-	0xbb, 0x68, 0x5b, 0xd7, 0xd8, 0x8a, 0x4f, 0x9b, 0xd9, 0x5e, 0x9e, 0x5a, 0x5f, 0xf8, 0x4f, 0x61,
-	0xfe, 0xe8, 0x03, 0x32, 0xfe, 0x9c, 0xed, 0xc0, 0x62, 0x41, 0x96, 0xe3, 0x04, 0x89, 0xa6, 0x64,
-	0x93, 0x39, 0xf4, 0x9d, 0xff, 0xac, 0x40, 0x43, 0xe3, 0x88, 0x1e, 0x67, 0x3b, 0xa8, 0x99, 0xe9,
-	0xcb, 0x25, 0x83, 0xcb, 0xdf, 0x99, 0xbd, 0x96, 0xfb, 0x4c, 0x21, 0x68, 0x15, 0x96, 0x3c, 0xdc,
-	0x67, 0x5b, 0xe3, 0x76, 0x71, 0x4a, 0x7a, 0x51, 0xec, 0xab, 0x60, 0x6a, 0xd1, 0xc3, 0x7d, 0xca,
-	0x63, 0x5d, 0x75, 0xa0, 0x1b, 0x5a, 0x68, 0x71, 0xae, 0x6c, 0x38, 0x7d, 0x07, 0x2e, 0x03, 0x64,
-	0xa3, 0xd3, 0xb0, 0x96, 0x4d, 0x48, 0x72, 0x17, 0x2d, 0xfb, 0xa7, 0xb0, 0x90, 0x97, 0xb1, 0x64,
-	0x6d, 0x7e, 0xdb, 0x5c, 0xe7, 0x8b, 0x87, 0x4f, 0x75, 0x02, 0x4b, 0xfe, 0x67, 0x53, 0x50, 0x93,
-	0x66, 0x1b, 0xdd, 0xd5, 0x0a, 0x0e, 0x25, 0x2e, 0x91, 0x8d, 0x4e, 0x7f, 0x88, 0xac, 0x2d, 0x2b,
-	0x36, 0xbc, 0x0f, 0x6d, 0xe5, 0x9f, 0xcb, 0x17, 0xb9, 0x15, 0x9b, 0x2b, 0xfc, 0x0c, 0x4e, 0x29,
-	0x0a, 0xc6, 0x66, 0xe4, 0xee, 0xe2, 0xc4, 0x2f, 0x1c, 0x1c, 0x35, 0xae, 0xf4, 0x06, 0x7c, 0xec,
-	0x07, 0x14, 0x57, 0x6c, 0xf4, 0x20, 0x83, 0xd8, 0xbf, 0xb4, 0xb8, 0x1d, 0x12, 0xb1, 0x2a, 0x82,
-	0x69, 0xed, 0xe8, 0xb3, 0xdf, 0xe8, 0x23, 0xe1, 0xf3, 0x2b, 0x66, 0x69, 0xa6, 0x64, 0x82, 0x79,
-	0xef, 0xff, 0xe6, 0x6e, 0xfc, 0x11, 0xb4, 0xc7, 0xc9, 0x7f, 0x14, 0x9f, 0x9a, 0xbe, 0x5b, 0x7f,
-	0x33, 0x0d, 0x4d, 0xa3, 0xc6, 0x80, 0x3e, 0x80, 0xd3, 0x31, 0xc1, 0x9e, 0x1b, 0x85, 0xc1, 0xc8,
-	0xed, 0xe3, 0x24, 0x25, 0xb1, 0x1b, 0x13, 0x1c, 0xf4, 0x19, 0xc3, 0x9a, 0xb3, 0x44, 0x7b, 0xbf,
-	0x0a, 0x83, 0xd1, 0x13, 0xd6, 0xe7, 0xd0, 0x2e, 0xb4, 0x09, 0x9d, 0x5e, 0x77, 0xe0, 0xf6, 0x71,
-	0x88, 0x7b, 0xc4, 0x73, 0x5f, 0x92, 0x51, 0xe2, 0x52, 0x5f, 0x10, 0x93, 0x9f, 0x0f, 0x09, 0xab,
-	0x5f, 0x51, 0x7f, 0xc0, 0x53, 0xaa, 0x0b, 0xbd, 0xee, 0xe0, 0x09, 0x47, 0xfc, 0x92, 0x8c, 0x92,
-	0x27, 0xf8, 0xb5, 0x23, 0xb1, 0xa8, 0x87, 0xf8, 0x0c, 0xce, 0x17, 0x58, 0x0d, 0x48, 0xec, 0xe2,
-	0x6e, 0x37, 0x1a, 0x86, 0x29, 0x73, 0x2a, 0x33, 0x4e, 0xdb, 0x64, 0xb2, 0x45, 0xe2, 0xfb, 0xbc,
-	0x1f, 0x7d, 0x0a, 0xe7, 0x28, 0xbd, 0x3a, 0xe6, 0x1c, 0xec, 0x0e, 0xe2, 0xe8, 0x67, 0xa4, 0x9b,
-	0x8a, 0xd0, 0x92, 0x92, 0x4b, 0xad, 0xe7, 0x08, 0x5b, 0xbc, 0x1f, 0xfd, 0x18, 0x96, 0x95, 0x1a,
-	0x79, 0x24, 0xe9, 0xc6, 0xfe, 0x20, 0x8d, 0x62, 0x19, 0x76, 0xae, 0x96, 0xd6, 0x65, 0x94, 0x2a,
-	0x6d, 0x64, 0x04, 0x42, 0x95, 0x34, 0x16, 0xe8, 0x0e, 0x9c, 0xa1, 0x92, 0xf9, 0xb8, 0xef, 0xee,
-	0xfa, 0x41, 0xe0, 0x87, 0x3d, 0x25, 0xd5, 0x2c, 0x93, 0x6a, 0xb9, 0xd7, 0x1d, 0x6c, 0xe2, 0xfe,
-	0x03, 0xde, 0x29, 0x25, 0xba, 0x07, 0x17, 0xf0, 0xab, 0xa4, 0xb8, 0x20, 0x94, 0xcf, 0x30, 0x21,
-	0x71, 0xbb, 0xc6, 0x57, 0x04, 0xbf, 0x4a, 0xcc, 0x15, 0xd9, 0xc4, 0xfd, 0x67, 0x09, 0x89, 0xed,
-	0x17, 0x99, 0xae, 0xe4, 0x05, 0x2c, 0xd1, 0x95, 0xab, 0xa6, 0xc1, 0x40, 0x32, 0x19, 0xcb, 0x48,
-	0x75, 0xfd, 0x79, 0x49, 0x0f, 0x3b, 0xcf, 0x1c, 0x85, 0x6f, 0x2f, 0xe4, 0x11, 0xbc, 0x77, 0x12,
-	0x39, 0xe3, 0x3f, 0xcc, 0x28, 0xdf, 0x92, 0x49, 0x43, 0xe3, 0x1b, 0xea, 0xc4, 0xf6, 0xa2, 0xb8,
-	0x2f, 0xe3, 0x1b, 0xd9, 0x46, 0xdf, 0x64, 0x95, 0x43, 0x99, 0xf0, 0xc9, 0x73, 0xba, 0x96, 0xb3,
-	0x84, 0x19, 0x47, 0x09, 0x91, 0x09, 0x5a, 0xae, 0x82, 0xa8, 0xc0, 0x68, 0x1b, 0x5a, 0x34, 0xe7,
-	0xd0, 0x58, 0x73, 0x5b, 0xf3, 0xde, 0x78, 0xd6, 0x34, 0xa0, 0xcf, 0xf1, 0x6d, 0xfa, 0x3a, 0x0c,
-	0x6d, 0x00, 0x0c, 0xe2, 0x68, 0x40, 0xe2, 0xd4, 0x67, 0xf9, 0x93, 0x55, 0xe2, 0xa0, 0x34, 0x86,
-	0x5b, 0x0a, 0xd7, 0xd1, 0xe8, 0xd0, 0x2d, 0xad, 0xea, 0xf0, 0xf6, 0x78, 0x6a, 0x7d, 0x53, 0x7e,
-	0x17, 0x20, 0x63, 0x46, 0x63, 0x1d, 0x3f, 0x71, 0x71, 0xaf, 0x17, 0x93, 0x9e, 0xcc, 0x1f, 0x6b,
-	0x4e, 0xc3, 0x4f, 0xee, 0x4b, 0x10, 0xba, 0x0e, 0x8b, 0x5d, 0x1c, 0xba, 0xbb, 0x24, 0x43, 0xf3,
-	0x84, 0x85, 0x99, 0xef, 0xe2, 0xf0, 0x01, 0x51, 0xa8, 0x1e, 0x7a, 0x0b, 0x1a, 0x89, 0x1f, 0xf6,
-	0x02, 0xe2, 0xd2, 0xd9, 0x32, 0x4b, 0x50, 0x73, 0x80, 0x83, 0xe8, 0x9a, 0xd8, 0x3f, 0x51, 0x95,
-	0x30, 0x73, 0x79, 0x26, 0x92, 0x50, 0x7f, 0x0d, 0xa8, 0xb8, 0xf0, 0xbf, 0xc9, 0x54, 0xfd, 0xdf,
-	0x2d, 0x68, 0x99, 0x4c, 0xa9, 0x9b, 0x8f, 0x49, 0x8f, 0xbc, 0x1e, 0xc8, 0xea, 0x15, 0x6f, 0x51,
-	0xf5, 0xe6, 0x55, 0x5f, 0x1c, 0x88, 0xc5, 0x55, 0x6d, 0xb4, 0xaa, 0x45, 0x15, 0x17, 0xcb, 0x85,
-	0x35, 0xb2, 0x5e, 0x04, 0xd3, 0x34, 0x67, 0x17, 0x81, 0x39, 0xfb, 0x8d, 0x3a, 0x30, 0x47, 0x5e,
-	0x0f, 0x48, 0xec, 0xf7, 0x49, 0x98, 0x62, 0x5e, 0xbf, 0xaa, 0x39, 0x06, 0xec, 0x4d, 0x67, 0x39,
-	0x0b, 0x33, 0xcc, 0x3d, 0x74, 0xbe, 0xb5, 0x00, 0x6d, 0xe1, 0x24, 0x19, 0x44, 0x71, 0xba, 0x23,
-	0x6a, 0x6e, 0x51, 0x8c, 0x6e, 0x02, 0xa2, 0xb6, 0x16, 0xa7, 0xfe, 0x2e, 0x55, 0x0c, 0x71, 0x63,
-	0xc2, 0x33, 0xa7, 0xc5, 0xac, 0x47, 0xde, 0x86, 0xdc, 0xd6, 0xec, 0x4b, 0x47, 0x95, 0x67, 0x0a,
-	0x6c, 0x27, 0x61, 0x69, 0x16, 0xa0, 0xf5, 0x98, 0xa4, 0x9b, 0xe1, 0x5e, 0x24, 0x7c, 0x53, 0xe7,
-	0xd7, 0x16, 0xcc, 0x2b, 0x50, 0x32, 0x88, 0xc2, 0x84, 0x94, 0x06, 0x03, 0x36, 0xd4, 0xc4, 0x55,
-	0x92, 0x0c, 0x54, 0x54, 0x1b, 0x5d, 0x00, 0x48, 0x52, 0x1c, 0x6b, 0xd7, 0x47, 0x53, 0x4e, 0x9d,
-	0x41, 0xd8, 0xed, 0x51, 0x1b, 0x66, 0xfb, 0x91, 0x37, 0x94, 0x65, 0x93, 0xba, 0x23, 0x9b, 0x22,
-	0x86, 0x9f, 0x31, 0x63, 0xf8, 0x9c, 0x34, 0x93, 0x98, 0xf6, 0x2d, 0x98, 0x63, 0x1b, 0x26, 0x26,
-	0x8d, 0xde, 0x86, 0x69, 0x76, 0x5c, 0x2d, 0x76, 0x1c, 0x9a, 0x59, 0xbe, 0x46, 0x71, 0x58, 0x57,
-	0x67, 0x1e, 0x9a, 0x82, 0x84, 0x8b, 0xd1, 0x79, 0x0c, 0x4b, 0x8f, 0x49, 0xaa, 0x2a, 0xee, 0x92,
-	0xd5, 0x69, 0xa8, 0xee, 0xf9, 0x41, 0x9a, 0x15, 0x67, 0x79, 0x8b, 0x4e, 0xda, 0x0f, 0xbb, 0xc1,
-	0xd0, 0x93, 0xe2, 0xc8, 0x66, 0xe7, 0xaf, 0x2c, 0x58, 0x36, 0x39, 0x89, 0x65, 0xdf, 0xd4, 0x2f,
-	0xa2, 0x78, 0x54, 0x79, 0x43, 0x5b, 0x94, 0x02, 0xc1, 0xf8, 0x3b, 0xa9, 0x49, 0xdf, 0x0d, 0x74,
-	0x4e, 0xb1, 0xc9, 0x3f, 0x0a, 0x70, 0xca, 0x72, 0x57, 0xa9, 0x3c, 0x7f, 0x5d, 0x67, 0x53, 0xd1,
-	0xe0, 0x62, 0x2a, 0x9f, 0xca, 0xfc, 0xdb, 0x32, 0x33, 0xc5, 0x32, 0xe4, 0x62, 0x2e, 0x6e, 0xff,
-	0xef, 0x2c, 0xd4, 0x24, 0x1e, 0xba, 0x04, 0x4d, 0x39, 0x31, 0x77, 0x80, 0xd3, 0x7d, 0x31, 0x87,
-	0x39, 0x09, 0xdc, 0xc2, 0xe9, 0xbe, 0x51, 0x0c, 0xa8, 0xe4, 0x8a, 0x01, 0x3a, 0x03, 0xa6, 0xd7,
-	0x53, 0x26, 0x03, 0x96, 0xe0, 0x9e, 0x83, 0x3a, 0x1d, 0x9b, 0x23, 0x70, 0x3b, 0x52, 0xa3, 0x00,
-	0xd9, 0xc9, 0x82, 0x75, 0xd6, 0x29, 0x0a, 0xe1, 0x14, 0xc0, 0x3a, 0xdf, 0x81, 0x96, 0xca, 0x6f,
-	0x39, 0x46, 0x95, 0x61, 0x34, 0x15, 0x94, 0xa1, 0x5d, 0x82, 0x0c, 0xe0, 0x0e, 0x63, 0x5f, 0xc4,
-	0x46, 0x73, 0x0a, 0xf8, 0x2c, 0xf6, 0xa9, 0x77, 0xd2, 0x8b, 0x90, 0x2c, 0x04, 0xaa, 0x3b, 0x0d,
-	0xad, 0x06, 0x89, 0x36, 0x55, 0xd5, 0x85, 0xdf, 0x14, 0xde, 0x3a, 0x74, 0x6d, 0x25, 0xa4, 0xb4,
-	0xe4, 0x99, 0xcf, 0xfb, 0xa1, 0x98, 0xf7, 0xeb, 0x41, 0x48, 0x23, 0x17, 0x84, 0x5c, 0x83, 0x05,
-	0xf9, 0x5b, 0x86, 0xa5, 0xec, 0x9a, 0xaf, 0xee, 0xcc, 0x4b, 0xb8, 0x70, 0x7d, 0xc5, 0x12, 0x4a,
-	0xb3, 0x58, 0x42, 0x79, 0x0e, 0x0d, 0xb5, 0x4d, 0x43, 0xbf, 0xdd, 0x62, 0xb3, 0xbb, 0x73, 0xbc,
-	0xd9, 0x49, 0x9d, 0x95, 0xb6, 0x02, 0x62, 0x05, 0x40, 0x5f, 0xc0, 0x2c, 0xdb, 0xd9, 0xa1, 0xdf,
-	0x9e, 0x3f, 0xc9, 0x8a, 0xd1, 0x3f, 0x92, 0x5f, 0xf5, 0x80, 0x35, 0x28, 0x2f, 0xa6, 0x08, 0x43,
-	0xbf, 0xbd, 0x70, 0x12, 0x5e, 0x34, 0x5f, 0x52, 0xbc, 0x62, 0xd6, 0x40, 0x57, 0x60, 0x3e, 0x9f,
-	0x01, 0x2e, 0x96, 0x65, 0x80, 0xdf, 0xa5, 0x3e, 0xf9, 0x29, 0xcc, 0xe7, 0x96, 0xe6, 0xa4, 0x95,
-	0x51, 0x6d, 0x15, 0x4e, 0x4a, 0xaa, 0x4d, 0xfa, 0x44, 0xa4, 0xee, 0x11, 0x15, 0xb2, 0x8f, 0x4d,
-	0xc3, 0xf5, 0xce, 0xb1, 0xb6, 0x40, 0xb7, 0x66, 0xcb, 0x80, 0x34, 0x7b, 0x2a, 0x8d, 0xd9, 0x4f,
-	0x0c, 0x03, 0xaf, 0x4c, 0xd9, 0x7b, 0x50, 0x93, 0xca, 0x24, 0xfc, 0x45, 0xd1, 0x52, 0x2a, 0x0c,
-	0xea, 0x0e, 0x70, 0xb7, 0x4b, 0x12, 0x55, 0xd4, 0xe0, 0xad, 0xce, 0x22, 0xf3, 0xb2, 0x86, 0xf1,
-	0xfc, 0x0b, 0x0b, 0x16, 0x32, 0x98, 0x18, 0xed, 0x23, 0xd3, 0x70, 0x5e, 0xd2, 0xe6, 0x76, 0x84,
-	0xd1, 0x1c, 0x37, 0xf4, 0xa4, 0x0a, 0x8e, 0x22, 0x76, 0x60, 0x50, 0x31, 0x83, 0x2f, 0xd5, 0xa4,
-	0x94, 0xfc, 0x2b, 0x30, 0x4d, 0xa5, 0x11, 0x2b, 0x65, 0xf2, 0x62, 0x3d, 0x63, 0x57, 0x88, 0xbb,
-	0x18, 0x59, 0x5c, 0x50, 0xab, 0xf4, 0x2b, 0xee, 0x2d, 0x35, 0x78, 0xe6, 0x62, 0x78, 0x01, 0xb1,
-	0xe8, 0x62, 0x0a, 0xc8, 0x25, 0x57, 0x33, 0xe3, 0x56, 0x6b, 0x82, 0x15, 0x7d, 0xa1, 0x67, 0xaa,
-	0x47, 0xcc, 0x68, 0xdb, 0x98, 0xa8, 0x9a, 0xcf, 0x65, 0x98, 0x66, 0x77, 0x44, 0xd6, 0x18, 0xbe,
-	0xac, 0x77, 0xec, 0xea, 0x3d, 0x67, 0x5b, 0xc1, 0x6c, 0xa9, 0x16, 0x99, 0xc4, 0x24, 0x1d, 0xc6,
-	0x61, 0x16, 0x78, 0xd3, 0x16, 0x3a, 0x0b, 0x35, 0x0f, 0xa7, 0x98, 0xe6, 0xd8, 0x32, 0x34, 0xa1,
-	0xed, 0x67, 0x09, 0xab, 0xf1, 0x67, 0xf5, 0x0c, 0xfa, 0xb3, 0x73, 0x06, 0x4e, 0x51, 0xbe, 0x24,
-	0xa1, 0x07, 0x63, 0x18, 0xa4, 0x6a, 0x5f, 0xfe, 0x63, 0x16, 0x4e, 0xe7, 0x7b, 0xc4, 0x4c, 0xde,
-	0xec, 0x11, 0xd2, 0x79, 0xa8, 0xd3, 0xf0, 0x31, 0x49, 0x71, 0x7f, 0x20, 0x9e, 0x20, 0x65, 0x00,
-	0xf4, 0x39, 0xd4, 0xd4, 0x73, 0x94, 0x69, 0x33, 0x19, 0x2d, 0x97, 0x62, 0xd5, 0x7c, 0x97, 0xa2,
-	0xa8, 0xd1, 0x0f, 0x81, 0xbd, 0x51, 0x71, 0x63, 0x8e, 0x2f, 0xa2, 0xcf, 0x6b, 0x47, 0x70, 0xcb,
-	0x60, 0x4e, 0x23, 0xcd, 0xfa, 0xd1, 0x67, 0x30, 0xd7, 0x8f, 0x3c, 0x7f, 0xcf, 0xef, 0x62, 0x9a,
-	0xbb, 0x30, 0xd7, 0xdf, 0xb8, 0x6d, 0x9b, 0xe5, 0x94, 0x27, 0x1a, 0x86, 0x63, 0xe0, 0xd3, 0x15,
-	0x21, 0xaf, 0x49, 0x77, 0x48, 0x53, 0xcc, 0x59, 0x56, 0xef, 0x50, 0x6d, 0xba, 0x81, 0x03, 0x9c,
-	0x24, 0xc4, 0x13, 0x95, 0x10, 0xd1, 0xa2, 0x96, 0x93, 0xc4, 0x71, 0x14, 0xb7, 0xeb, 0xdc, 0x72,
-	0xb2, 0x86, 0xfd, 0x2b, 0x8b, 0x06, 0xb9, 0x3f, 0x23, 0xdd, 0x94, 0x78, 0xcf, 0xfd, 0x04, 0xf3,
-	0xfd, 0xc7, 0x49, 0xa4, 0xed, 0x3f, 0x6d, 0x51, 0xf2, 0x3d, 0x9f, 0x04, 0x9e, 0x34, 0xbc, 0xac,
-	0x81, 0x56, 0x40, 0xd5, 0x74, 0xe8, 0x3c, 0xa6, 0xe4, 0x4d, 0xa5, 0x02, 0xf1, 0x08, 0x49, 0x5c,
-	0xaf, 0x67, 0x11, 0x92, 0xa8, 0x91, 0x9c, 0x86, 0xaa, 0xb0, 0x91, 0x3c, 0x3c, 0x12, 0xad, 0xcc,
-	0xca, 0x57, 0x35, 0x2b, 0x8f, 0x5a, 0x50, 0xd9, 0x1d, 0x89, 0x00, 0xa8, 0xb2, 0x3b, 0xb2, 0xff,
-	0xb1, 0x02, 0x90, 0xad, 0x70, 0x69, 0xfe, 0xc1, 0x66, 0x43, 0x7b, 0x85, 0xd8, 0xa2, 0xa5, 0x1d,
-	0x88, 0x29, 0xfd, 0x40, 0xa0, 0x1d, 0xa8, 0x87, 0x91, 0x2b, 0xba, 0xb8, 0xc6, 0xdc, 0x3d, 0xf6,
-	0x1e, 0xaf, 0x3e, 0x8d, 0xee, 0x33, 0x4a, 0xa1, 0x3c, 0xa1, 0x68, 0x22, 0x07, 0x5a, 0xb1, 0x58,
-	0x63, 0x97, 0xce, 0x5d, 0xaa, 0xcf, 0x8d, 0x23, 0x58, 0xeb, 0x1b, 0xe3, 0x34, 0x63, 0xad, 0x95,
-	0x64, 0xdb, 0x59, 0xd5, 0xb7, 0xf3, 0x63, 0x68, 0x1a, 0x42, 0x9c, 0xc8, 0x83, 0x6e, 0x41, 0x73,
-	0xb2, 0x8f, 0x59, 0xa8, 0xff, 0x12, 0xb1, 0x9c, 0xb2, 0x00, 0xdf, 0x5a, 0xaa, 0x50, 0x9f, 0x9d,
-	0xfd, 0x07, 0x50, 0x13, 0xa1, 0xa0, 0x34, 0xcc, 0xef, 0xe6, 0xaa, 0x34, 0xd9, 0xa2, 0x48, 0x80,
-	0x58, 0x64, 0x49, 0x67, 0x3f, 0x87, 0xa6, 0xd1, 0x55, 0x22, 0xfd, 0x9a, 0x29, 0xfd, 0xd9, 0xb1,
-	0x95, 0x20, 0x7d, 0x0e, 0xe7, 0xc1, 0x2e, 0x66, 0xd5, 0x6a, 0x3a, 0xff, 0x65, 0xc1, 0xb9, 0xd2,
-	0x6e, 0x31, 0xb3, 0x08, 0x96, 0x07, 0xa2, 0xdb, 0x4d, 0xb3, 0x7e, 0x31, 0xcb, 0x4f, 0xc6, 0xe7,
-	0xed, 0x9a, 0x49, 0x2a, 0xf6, 0x89, 0x57, 0x1b, 0x83, 0x62, 0x8f, 0xbd, 0x0b, 0xed, 0x71, 0x04,
-	0x25, 0x2b, 0xf2, 0xbe, 0xb9, 0x22, 0xf6, 0x78, 0x79, 0xf4, 0x25, 0xb1, 0xa1, 0xbd, 0x91, 0xbf,
-	0xd3, 0x91, 0x0b, 0xf2, 0xc7, 0xd4, 0xa0, 0x64, 0x3d, 0x4c, 0xdf, 0xa2, 0xd8, 0x13, 0x99, 0xee,
-	0x8c, 0xc3, 0x1b, 0xe8, 0x3d, 0xad, 0x7a, 0x71, 0x5e, 0x45, 0x46, 0x1a, 0xdd, 0x24, 0x12, 0xf8,
-	0x7f, 0xb3, 0xe0, 0x6c, 0x89, 0xa0, 0x62, 0x6b, 0xf6, 0xcb, 0x2f, 0xad, 0xf8, 0xce, 0x7c, 0xa8,
-	0x3d, 0x23, 0x2c, 0xa7, 0x2f, 0xf6, 0x70, 0x79, 0x8b, 0xd7, 0x5d, 0xf6, 0x0b, 0x38, 0x5d, 0x8e,
-	0x5c, 0x32, 0x9b, 0xeb, 0xe6, 0x8e, 0x2c, 0x97, 0xad, 0x8d, 0x3e, 0xc7, 0xb6, 0x72, 0xa8, 0xf2,
-	0xec, 0xca, 0x9d, 0xf8, 0xd7, 0x0a, 0x9c, 0x29, 0x74, 0xa9, 0xa2, 0x41, 0xe6, 0x18, 0xf9, 0x84,
-	0x6f, 0xe6, 0x6c, 0x51, 0x9e, 0x64, 0xac, 0x67, 0xfc, 0x06, 0xe6, 0x93, 0x14, 0x87, 0x1e, 0x8e,
-	0x3d, 0xb7, 0x1b, 0x60, 0xbf, 0x2f, 0x4b, 0xca, 0x1f, 0x1c, 0xc5, 0x71, 0x5b, 0x90, 0xad, 0x33,
-	0x2a, 0xf1, 0x46, 0x37, 0x31, 0x80, 0x93, 0xb7, 0x49, 0xf6, 0x7d, 0x58, 0x2a, 0x19, 0xf8, 0x44,
-	0x7a, 0x75, 0x1e, 0xec, 0x07, 0xb8, 0xfb, 0xb2, 0x17, 0x47, 0xc3, 0xd0, 0xdb, 0xe2, 0x0f, 0xab,
-	0xb3, 0x13, 0xf0, 0xb7, 0x16, 0x9c, 0x2b, 0xed, 0x16, 0x6b, 0xbf, 0x05, 0xf5, 0x81, 0x04, 0x8a,
-	0xc5, 0xbf, 0x2d, 0x97, 0xea, 0x10, 0xba, 0x55, 0x05, 0x11, 0x75, 0x1b, 0xc5, 0xc4, 0x7e, 0x0a,
-	0x2d, 0xb3, 0xf3, 0x38, 0x11, 0xa8, 0x7c, 0x10, 0x2e, 0x28, 0x73, 0xe7, 0xbb, 0x20, 0x88, 0x9c,
-	0xdd, 0x63, 0x38, 0x5b, 0xd2, 0x27, 0xa6, 0x76, 0x1d, 0x66, 0x05, 0x5b, 0x15, 0x90, 0xe6, 0x87,
-	0x91, 0x08, 0x9d, 0x79, 0x68, 0xb2, 0xc0, 0x53, 0x71, 0xbe, 0x07, 0x2d, 0x09, 0x10, 0xec, 0x6e,
-	0x42, 0x95, 0xe5, 0xfe, 0x72, 0x99, 0x4e, 0xa9, 0xad, 0xa5, 0xd0, 0x27, 0x24, 0xc5, 0x34, 0x04,
-	0x75, 0x04, 0x52, 0xa7, 0x05, 0x73, 0x7a, 0x28, 0xdb, 0xf9, 0x44, 0x8c, 0xa0, 0xf8, 0xdd, 0x80,
-	0x19, 0x86, 0x2a, 0x84, 0x1b, 0xc3, 0x8e, 0xe3, 0x74, 0x7e, 0x7f, 0x0a, 0x50, 0x31, 0x10, 0x33,
-	0x82, 0x51, 0x2b, 0x17, 0x8c, 0xfe, 0x28, 0xff, 0x04, 0xba, 0x62, 0xc6, 0x9c, 0x45, 0x76, 0x47,
-	0xbe, 0x85, 0x3e, 0x03, 0xb3, 0x5e, 0x3c, 0x72, 0xe3, 0x61, 0x28, 0x6e, 0x09, 0xaa, 0x5e, 0x3c,
-	0x72, 0x86, 0xa1, 0xfd, 0x73, 0x58, 0x12, 0x48, 0x86, 0x78, 0x59, 0x60, 0x63, 0x19, 0x81, 0xcd,
-	0x05, 0x00, 0xec, 0x79, 0xae, 0x91, 0x05, 0xd4, 0xb1, 0xe7, 0x89, 0x08, 0x85, 0x15, 0xbb, 0xfa,
-	0xd1, 0x01, 0x71, 0x8d, 0xb0, 0x68, 0x8e, 0x03, 0x39, 0x92, 0x1d, 0x1d, 0xef, 0xc1, 0xeb, 0x86,
-	0xa9, 0x69, 0xab, 0x87, 0xcc, 0xbe, 0x64, 0x06, 0xb9, 0xdc, 0x91, 0x13, 0xa9, 0x72, 0xea, 0x81,
-	0xbc, 0x9f, 0x95, 0xe9, 0xca, 0x3b, 0x46, 0x4d, 0x76, 0xb1, 0xf0, 0xf8, 0x9b, 0xd7, 0x65, 0x0b,
-	0x01, 0x77, 0xe5, 0x64, 0x01, 0x77, 0xe7, 0xf7, 0xe0, 0x94, 0x92, 0x44, 0x4f, 0xff, 0x69, 0xfe,
-	0xa5, 0x8d, 0x5f, 0xcc, 0xf1, 0x27, 0x33, 0xfc, 0x10, 0x16, 0x39, 0x8e, 0x96, 0x47, 0xd3, 0xa4,
-	0x59, 0x1b, 0x3a, 0x97, 0x34, 0x4f, 0x64, 0xd8, 0x3f, 0xb7, 0xc0, 0xe6, 0x48, 0xe6, 0xab, 0x77,
-	0x21, 0xc0, 0x35, 0x43, 0x80, 0x31, 0x2f, 0xe4, 0xb9, 0x24, 0x97, 0xa0, 0x29, 0xde, 0x12, 0x27,
-	0xa4, 0x1b, 0x93, 0x54, 0x16, 0x53, 0x39, 0x70, 0x9b, 0xc1, 0xbe, 0xb3, 0xb8, 0x7f, 0x94, 0x17,
-	0x77, 0xdb, 0xd8, 0xaa, 0xc3, 0xc5, 0xdd, 0x9e, 0xe4, 0x7e, 0x8d, 0x60, 0x89, 0xe3, 0x88, 0x97,
-	0x1c, 0x42, 0x82, 0x8e, 0x21, 0x41, 0xfe, 0x91, 0xfe, 0x64, 0x86, 0xfe, 0x03, 0x0b, 0x96, 0xcd,
-	0xcf, 0x24, 0x0e, 0x9f, 0xbe, 0x89, 0x3b, 0xe1, 0xd3, 0xa2, 0x9e, 0xcd, 0x1f, 0x7e, 0x5a, 0x14,
-	0xda, 0x64, 0x86, 0xff, 0x85, 0x05, 0xe7, 0x39, 0x52, 0xfe, 0x6b, 0x00, 0x21, 0xc6, 0x0d, 0x43,
-	0x8c, 0xb1, 0xdf, 0x0e, 0x4c, 0x46, 0x9a, 0x3f, 0xb4, 0xa0, 0x2d, 0xb4, 0x52, 0x0b, 0x48, 0x84,
-	0x24, 0x57, 0x0c, 0x49, 0x4a, 0x43, 0x97, 0xc9, 0x48, 0xf1, 0x7f, 0x55, 0x38, 0x2b, 0x8d, 0x92,
-	0xee, 0x24, 0xb7, 0x53, 0x9c, 0x12, 0x74, 0x4f, 0xdc, 0x84, 0xce, 0xad, 0x58, 0x57, 0x5b, 0x59,
-	0x5a, 0x3a, 0x96, 0x80, 0x7b, 0x4c, 0xbe, 0x65, 0xec, 0xda, 0xf4, 0xf3, 0xe2, 0x25, 0xd4, 0xf5,
-	0xa3, 0xb9, 0x28, 0x2b, 0xa9, 0x7d, 0x17, 0x65, 0x7c, 0x75, 0x50, 0xc9, 0x7d, 0x75, 0xd0, 0x86,
-	0x99, 0x84, 0x52, 0x72, 0xf3, 0xf1, 0xa0, 0xd2, 0xb6, 0x1c, 0x0e, 0xa0, 0x7e, 0x6f, 0x37, 0x8e,
-	0x5e, 0x92, 0x58, 0xd4, 0x18, 0x44, 0x0b, 0x5d, 0xa4, 0xee, 0xda, 0xf3, 0x63, 0xf5, 0xd8, 0x85,
-	0x11, 0x29, 0x98, 0xac, 0x5d, 0x55, 0x99, 0x27, 0xa7, 0x3f, 0xd1, 0x75, 0x58, 0x8c, 0x85, 0xbb,
-	0x71, 0x5f, 0x92, 0x91, 0xbb, 0xe7, 0x07, 0x84, 0x15, 0x1d, 0x6a, 0xce, 0xbc, 0xec, 0xf8, 0x92,
-	0x8c, 0x1e, 0xf9, 0xec, 0x19, 0xf6, 0x7c, 0x10, 0xf5, 0xfc, 0xd0, 0xed, 0xee, 0xe3, 0x20, 0x20,
-	0x61, 0x4f, 0xde, 0xbd, 0xb4, 0x18, 0x78, 0x5d, 0x42, 0xb5, 0x8f, 0x31, 0xea, 0xc6, 0xc7, 0x18,
-	0x6d, 0x98, 0x4d, 0x86, 0xbb, 0xec, 0xd1, 0x0b, 0xbf, 0x46, 0x91, 0x4d, 0x6a, 0x31, 0xc9, 0x20,
-	0xea, 0xee, 0x53, 0x83, 0x19, 0x85, 0x5e, 0xc2, 0xee, 0x51, 0xa6, 0x9c, 0x39, 0x06, 0xdc, 0xe6,
-	0x30, 0x1a, 0xa2, 0xf2, 0xc7, 0x48, 0xfc, 0x62, 0x84, 0x37, 0xd0, 0x45, 0x00, 0xdf, 0x23, 0x61,
-	0xea, 0xb3, 0x37, 0x13, 0x2d, 0xe6, 0xc9, 0x35, 0x08, 0xba, 0x09, 0x28, 0x7b, 0x89, 0x84, 0x87,
-	0x9e, 0x4f, 0xc2, 0x2e, 0x61, 0xb7, 0x1c, 0x75, 0x67, 0x51, 0xf5, 0xdc, 0x17, 0x1d, 0xec, 0xc6,
-	0x41, 0xa1, 0x27, 0xdd, 0x68, 0x40, 0xd8, 0x2d, 0x46, 0xdd, 0x69, 0x29, 0xf0, 0x36, 0x85, 0xa2,
-	0x1b, 0xb0, 0xd8, 0xa5, 0xab, 0x13, 0xa6, 0xda, 0x7a, 0x2c, 0x32, 0xc9, 0x16, 0x44, 0x47, 0xb6,
-	0x22, 0x6f, 0x41, 0x43, 0xec, 0x33, 0x2b, 0xda, 0x20, 0x86, 0x06, 0x1c, 0xf4, 0x14, 0xf7, 0x89,
-	0xfd, 0x4b, 0x4b, 0x7b, 0xb5, 0xab, 0x26, 0x6a, 0xe9, 0x13, 0xb5, 0xb5, 0x22, 0xbb, 0x50, 0x15,
-	0x55, 0x52, 0x47, 0xa2, 0xa4, 0xcc, 0x1d, 0x0d, 0x2f, 0x22, 0x23, 0x51, 0x2c, 0x15, 0x17, 0xfe,
-	0xac, 0x34, 0x7a, 0x1e, 0xea, 0xea, 0x2e, 0x4d, 0x54, 0xa1, 0x32, 0x00, 0x7f, 0xbe, 0x1c, 0x88,
-	0x1a, 0x0b, 0xfd, 0xd9, 0xb9, 0x0b, 0x75, 0xa5, 0xfc, 0x68, 0x1e, 0x1a, 0xcf, 0x9e, 0x6e, 0x6f,
-	0x3d, 0x5c, 0xdf, 0x7c, 0xb4, 0xf9, 0x70, 0x63, 0xe1, 0xb7, 0x50, 0x03, 0x66, 0x37, 0xee, 0xef,
-	0xdc, 0xdf, 0x7e, 0xb8, 0xb3, 0x60, 0xa1, 0x39, 0xa8, 0x3d, 0x7c, 0xba, 0xb1, 0xf5, 0xd5, 0xe6,
-	0xd3, 0x9d, 0x85, 0x4a, 0x67, 0x0f, 0x6a, 0xf7, 0x87, 0xe9, 0xfe, 0x7a, 0xe4, 0xd1, 0xc9, 0x6b,
-	0x4a, 0x6e, 0x65, 0x6a, 0xa9, 0x14, 0x7d, 0x59, 0x2a, 0xba, 0xc8, 0x3d, 0xb8, 0x92, 0x17, 0x74,
-	0x62, 0xaa, 0xa8, 0x13, 0x9d, 0xff, 0xae, 0x67, 0xb7, 0x37, 0xb2, 0xde, 0xb8, 0x51, 0x3c, 0x9e,
-	0xef, 0x16, 0x42, 0x15, 0x8e, 0x7b, 0xc8, 0x27, 0x8b, 0x1f, 0x1b, 0x55, 0x64, 0xed, 0x9a, 0x21,
-	0xcf, 0x42, 0xaf, 0x82, 0xc9, 0x00, 0xb4, 0x54, 0xf6, 0xa6, 0x29, 0xbb, 0xfd, 0xa7, 0x15, 0x40,
-	0x92, 0x99, 0xf6, 0xa6, 0xe9, 0x85, 0xf1, 0x8c, 0x98, 0xcb, 0xff, 0x83, 0xa3, 0xe4, 0xd7, 0x9f,
-	0x19, 0x1d, 0xf2, 0xb2, 0x58, 0x2f, 0x8d, 0x5b, 0x5a, 0xc0, 0xbc, 0x02, 0x8d, 0x01, 0x89, 0xfb,
-	0x7e, 0xc2, 0x1f, 0x2f, 0xf0, 0x78, 0x58, 0x07, 0xd9, 0xe4, 0x38, 0x2f, 0x83, 0x3f, 0x31, 0x83,
-	0xe1, 0xb1, 0xab, 0xae, 0x38, 0xc9, 0xcb, 0x19, 0x95, 0xaf, 0xfe, 0x08, 0x5a, 0x66, 0x27, 0xba,
-	0x27, 0x3f, 0xfe, 0xb0, 0xcc, 0x22, 0xf4, 0xb8, 0x95, 0xd0, 0x9e, 0xe9, 0x33, 0x3a, 0xfb, 0x2f,
-	0x2d, 0x58, 0x2c, 0x74, 0x66, 0x4f, 0xf5, 0x2d, 0xf9, 0x54, 0xff, 0x49, 0xee, 0xa9, 0xfe, 0x9d,
-	0x63, 0x8f, 0x34, 0xe1, 0x27, 0xfc, 0xf6, 0xbf, 0x54, 0xb2, 0xa7, 0x09, 0x22, 0x65, 0xf9, 0x31,
-	0x34, 0xba, 0x31, 0x61, 0x56, 0x0d, 0x07, 0x72, 0x2d, 0xee, 0x1e, 0x25, 0x21, 0x27, 0x5e, 0x5d,
-	0xcf, 0x28, 0xc5, 0xd3, 0x44, 0x8d, 0x17, 0xfa, 0x22, 0x37, 0xef, 0xdb, 0xc7, 0xe4, 0x5a, 0x76,
-	0x5b, 0x7e, 0x11, 0x80, 0xbc, 0x1e, 0xf8, 0x31, 0x49, 0x5c, 0x3f, 0x14, 0x4a, 0xaf, 0x41, 0xec,
-	0xcf, 0x60, 0x21, 0x2f, 0xcc, 0xf7, 0xf5, 0x19, 0xca, 0xfe, 0x31, 0x9e, 0x7b, 0xfc, 0x8e, 0xa9,
-	0xbf, 0xd7, 0x8f, 0x7f, 0xea, 0xf4, 0x91, 0x30, 0x34, 0x0e, 0x2f, 0x4a, 0x1f, 0xf7, 0x98, 0x98,
-	0x0b, 0xae, 0x0d, 0xf1, 0xc0, 0x79, 0xb1, 0xd5, 0xf3, 0xd3, 0xfd, 0xe1, 0x2e, 0x8d, 0xa1, 0xd6,
-	0x1e, 0x47, 0x51, 0x2f, 0x20, 0xeb, 0x41, 0x34, 0xf4, 0xb6, 0xc4, 0xa3, 0x82, 0xb5, 0x7d, 0x82,
-	0x83, 0x74, 0xbf, 0x8b, 0x63, 0x72, 0x73, 0x8f, 0x78, 0x24, 0xc6, 0x29, 0xf1, 0x6e, 0xf2, 0xd3,
-	0x7e, 0x53, 0x56, 0x91, 0xd7, 0xf4, 0x0f, 0xf4, 0x77, 0xab, 0xac, 0xf5, 0xc1, 0xff, 0x07, 0x00,
-	0x00, 0xff, 0xff, 0xcc, 0xb5, 0x31, 0x1c, 0xb7, 0x3f, 0x00, 0x00,
+	// 4525 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x7c, 0x4b, 0x6f, 0x1c, 0x49,
+	0x72, 0xf0, 0x57, 0x4d, 0x36, 0xd9, 0x1d, 0xcd, 0xe6, 0x23, 0x49, 0x49, 0xad, 0xd2, 0x63, 0x38,
+	0x3d, 0x9a, 0xd1, 0x6b, 0x44, 0x8e, 0x34, 0x9f, 0xa0, 0x99, 0x9d, 0x87, 0x56, 0x22, 0x25, 0x0d,
+	0x67, 0x56, 0x1a, 0x6e, 0x91, 0x14, 0x66, 0xb5, 0x83, 0x2d, 0x24, 0xab, 0x92, 0xcd, 0xb2, 0xaa,
+	0xab, 0x7a, 0xab, 0xaa, 0x29, 0x11, 0xb0, 0x01, 0x1b, 0x3e, 0x18, 0xc6, 0x1e, 0x0c, 0x03, 0xf6,
+	0xaf, 0xb0, 0xbd, 0xb6, 0x01, 0x5f, 0xf6, 0x60, 0xd8, 0x80, 0x0f, 0xbe, 0x19, 0x86, 0x2f, 0x86,
+	0x8f, 0xde, 0x3d, 0xf8, 0x6a, 0x18, 0xf6, 0xc9, 0x07, 0x1b, 0xf9, 0xac, 0xcc, 0xaa, 0x6a, 0x3e,
+	0x34, 0x8d, 0xf5, 0x45, 0xea, 0x8c, 0x8c, 0x88, 0x8c, 0xc8, 0x8c, 0x8c, 0x8c, 0x88, 0xcc, 0x22,
+	0x5c, 0x1e, 0x24, 0x71, 0x16, 0xaf, 0xfa, 0xb8, 0xbf, 0x7a, 0x70, 0x9b, 0xfe, 0xe7, 0xa6, 0x24,
+	0x39, 0x08, 0x3c, 0xb2, 0xc2, 0x3a, 0xd0, 0x94, 0x8f, 0xfb, 0x2b, 0x07, 0xb7, 0xed, 0x8b, 0x1c,
+	0xcf, 0x8b, 0xfb, 0xfd, 0x38, 0xa2, 0xa8, 0xfc, 0x17, 0xc7, 0xb2, 0xdf, 0x2e, 0xf6, 0xc6, 0x78,
+	0x98, 0xed, 0x7b, 0x61, 0x40, 0xa2, 0x4c, 0xa0, 0x88, 0x81, 0x06, 0x49, 0xec, 0x91, 0x34, 0xa5,
+	0x38, 0xe2, 0x27, 0xef, 0xef, 0xfe, 0x43, 0x0b, 0x9a, 0xeb, 0xb8, 0xbf, 0x16, 0x47, 0x7b, 0x41,
+	0x0f, 0x75, 0x60, 0xfa, 0x80, 0x24, 0x69, 0x10, 0x47, 0x1d, 0x6b, 0xd9, 0xba, 0xd6, 0x74, 0x64,
+	0x13, 0xd9, 0xd0, 0x48, 0xc8, 0x41, 0xc0, 0xba, 0x6a, 0xcb, 0xd6, 0xb5, 0x09, 0x47, 0xb5, 0xd1,
+	0x5b, 0xd0, 0xa2, 0x22, 0x04, 0x99, 0x9b, 0x05, 0x7d, 0xd2, 0x99, 0x58, 0xb6, 0xae, 0x59, 0x0e,
+	0x70, 0xd0, 0x76, 0xd0, 0x27, 0xe8, 0x19, 0xcc, 0x65, 0xc9, 0x30, 0xcd, 0x88, 0xef, 0x06, 0x69,
+	0x3a, 0x24, 0x49, 0xda, 0x99, 0x5c, 0x9e, 0xb8, 0xd6, 0xba, 0xf3, 0xee, 0x0a, 0xd7, 0x73, 0x45,
+	0x89, 0xb0, 0xb2, 0xcd, 0x11, 0x37, 0x38, 0xde, 0xa3, 0x28, 0x4b, 0x0e, 0x9d, 0xd9, 0xcc, 0x00,
+	0xea, 0xfc, 0xd2, 0x78, 0x98, 0x78, 0x24, 0xed, 0xd4, 0x8f, 0xe1, 0xb7, 0xc5, 0xf1, 0x4c, 0x7e,
+	0x02, 0x88, 0x3e, 0x81, 0xc6, 0x20, 0x0e, 0x03, 0x2f, 0x20, 0x69, 0x67, 0x8a, 0x31, 0x7a, 0xab,
+	0xcc, 0x68, 0x53, 0x60, 0x70, 0x16, 0x8a, 0x00, 0x7d, 0x0e, 0xcd, 0x84, 0x48, 0x31, 0xa6, 0x19,
+	0xf5, 0x72, 0x99, 0xda, 0x91, 0x28, 0x9c, 0x3c, 0x27, 0x41, 0x1f, 0xc1, 0x34, 0x5f, 0xb1, 0xb4,
+	0xd3, 0x60, 0xd4, 0x97, 0xcb, 0xd4, 0x6b, 0x1c, 0x81, 0xd3, 0x4a, 0x74, 0xb4, 0x0d, 0x0b, 0xc2,
+	0x6a, 0xdc, 0x8c, 0xf4, 0x07, 0x21, 0xce, 0x48, 0xda, 0x69, 0x32, 0x1e, 0x57, 0xcb, 0x3c, 0xb6,
+	0x38, 0xea, 0xb6, 0xc4, 0xe4, 0xcc, 0xe6, 0xd3, 0x02, 0x18, 0xdd, 0x07, 0x38, 0x08, 0x52, 0xec,
+	0x66, 0x87, 0x03, 0x92, 0x76, 0x60, 0x94, 0x42, 0xcf, 0x83, 0x14, 0x6f, 0x1f, 0x0e, 0x24, 0x9f,
+	0xe6, 0x81, 0x6c, 0xa3, 0x2f, 0xa0, 0x9d, 0x91, 0x34, 0x73, 0x07, 0x24, 0x49, 0xe3, 0x08, 0xa7,
+	0x9d, 0x16, 0xe3, 0xf1, 0x4e, 0xc5, 0xda, 0x90, 0x34, 0xdb, 0x14, 0x58, 0x9c, 0xcd, 0x4c, 0xa6,
+	0x81, 0xd0, 0x2a, 0x4c, 0xc7, 0x83, 0x2c, 0x88, 0xa3, 0xb4, 0x33, 0xb3, 0x6c, 0x5d, 0x6b, 0xdd,
+	0x39, 0x23, 0x79, 0x70, 0x06, 0x5f, 0xf3, 0x4e, 0x47, 0x62, 0xa1, 0xeb, 0x50, 0x1b, 0x06, 0x9d,
+	0x36, 0x1b, 0xef, 0x7c, 0x79, 0xbc, 0x9d, 0x80, 0x8f, 0x52, 0x1b, 0x06, 0xf6, 0x37, 0xb0, 0x58,
+	0x61, 0x6a, 0x68, 0x1e, 0x26, 0x5e, 0x92, 0x43, 0x61, 0xfd, 0xf4, 0x27, 0xba, 0x09, 0xf5, 0x03,
+	0x1c, 0x0e, 0x49, 0xa7, 0x66, 0x8a, 0x60, 0x50, 0x3b, 0x1c, 0xe7, 0x7b, 0xb5, 0x8f, 0x2c, 0x8d,
+	0xb3, 0x6e, 0x74, 0xa7, 0xe7, 0xcc, 0xa9, 0x75, 0xce, 0x5f, 0x41, 0xdb, 0xb0, 0xc2, 0x0a, 0x9e,
+	0x57, 0x4c, 0x9e, 0xb3, 0x92, 0x27, 0xa3, 0x3b, 0xd4, 0x99, 0x3d, 0x83, 0x59, 0xd3, 0x28, 0x2b,
+	0xb8, 0xbd, 0x67, 0x72, 0x9b, 0x97, 0xdc, 0x24, 0xa1, 0xce, 0xef, 0x4b, 0x98, 0xd1, 0xcd, 0xf4,
+	0x24, 0xb2, 0x09, 0x67, 0xc6, 0xc9, 0x74, 0x5e, 0xdf, 0xc2, 0x99, 0x4a, 0x73, 0xad, 0x60, 0x7a,
+	0xcb, 0x64, 0x7a, 0x4e, 0x8a, 0x58, 0xa0, 0x2f, 0x68, 0x6e, 0x5a, 0xef, 0x29, 0x34, 0x97, 0x84,
+	0x3a, 0xbf, 0x6d, 0x58, 0x28, 0x59, 0x72, 0x05, 0xcb, 0xeb, 0x26, 0xcb, 0x45, 0xa9, 0xbe, 0x46,
+	0xab, 0x73, 0xbd, 0x0b, 0xd3, 0x3b, 0xc1, 0x28, 0x5e, 0x4b, 0x3a, 0xaf, 0xa6, 0x46, 0xd6, 0xfd,
+	0x59, 0x0d, 0xda, 0x86, 0x69, 0xa2, 0xb3, 0x30, 0xc5, 0xbd, 0xae, 0x60, 0x20, 0x5a, 0xe8, 0x2a,
+	0xf5, 0xa2, 0x38, 0x4a, 0xe9, 0xf4, 0xb8, 0xc3, 0x34, 0x88, 0x7a, 0x82, 0xdb, 0xac, 0x02, 0xef,
+	0x50, 0x28, 0xba, 0x00, 0x4d, 0xee, 0x72, 0xdc, 0xc0, 0x67, 0xde, 0xbd, 0xe9, 0x34, 0x38, 0x60,
+	0xc3, 0x47, 0xe7, 0xa1, 0x41, 0x0f, 0x1d, 0x77, 0x98, 0x84, 0x9d, 0x49, 0x7e, 0x66, 0xd0, 0xf6,
+	0x4e, 0x12, 0x52, 0xba, 0x2c, 0x7e, 0x49, 0x22, 0xd6, 0x57, 0xe7, 0x74, 0x0c, 0x40, 0x3b, 0x6f,
+	0xb1, 0xad, 0xca, 0xbd, 0xed, 0xa5, 0xca, 0x3d, 0x65, 0x6c, 0xd7, 0x37, 0x9c, 0x8d, 0x9f, 0x5b,
+	0xd0, 0x36, 0xb6, 0x13, 0x3d, 0xe2, 0xa4, 0xb3, 0xb6, 0x96, 0x27, 0xa8, 0xb8, 0xa2, 0x89, 0x2e,
+	0x19, 0x8e, 0xaf, 0xc6, 0x3a, 0x35, 0xb7, 0xc6, 0x05, 0x9e, 0xa8, 0x14, 0x98, 0xf3, 0x1e, 0x87,
+	0xc0, 0xff, 0x58, 0x83, 0x29, 0xbe, 0x57, 0xd1, 0x4d, 0x98, 0xc2, 0xd1, 0xa1, 0x1b, 0xef, 0x31,
+	0x41, 0x5b, 0x77, 0x96, 0xd4, 0x7e, 0x89, 0x23, 0x3f, 0xa0, 0x0e, 0x6f, 0x8b, 0x64, 0x4e, 0x1d,
+	0x47, 0x87, 0x5f, 0xef, 0xa1, 0x17, 0xb0, 0x74, 0x80, 0x93, 0x00, 0xef, 0x86, 0xc4, 0xf5, 0xc9,
+	0x5e, 0x10, 0x05, 0xdc, 0x6f, 0xd6, 0xcc, 0xe3, 0x80, 0xb3, 0x5e, 0x79, 0x2e, 0x50, 0xd7, 0x73,
+	0x4c, 0x2e, 0xf9, 0xe2, 0x41, 0xb9, 0x07, 0xbd, 0xa7, 0x69, 0x7e, 0xb6, 0xc0, 0x49, 0x57, 0xf9,
+	0x27, 0xd0, 0x19, 0xc5, 0xb8, 0x62, 0x0e, 0xde, 0x37, 0xb7, 0x83, 0x62, 0x2c, 0x59, 0x3c, 0x8e,
+	0x93, 0x3e, 0xce, 0xc6, 0xb0, 0x23, 0xfe, 0x6b, 0x0a, 0x26, 0x9f, 0x07, 0xe4, 0x15, 0xba, 0x0e,
+	0xf3, 0xc5, 0xf3, 0x52, 0x70, 0x98, 0x2b, 0x9c, 0x82, 0xe8, 0x03, 0x98, 0x0a, 0xf1, 0x2e, 0x09,
+	0xe5, 0x04, 0x76, 0xf2, 0xfd, 0x4f, 0x5e, 0xad, 0xfc, 0x80, 0x75, 0x71, 0xc5, 0x05, 0x1e, 0x7a,
+	0x07, 0xda, 0x5e, 0x1c, 0x65, 0x74, 0x97, 0x70, 0x03, 0x9a, 0x60, 0x06, 0x34, 0x23, 0x80, 0xd2,
+	0x86, 0xea, 0x49, 0x1c, 0x12, 0x19, 0xfe, 0x9c, 0x33, 0xb8, 0x3a, 0xb4, 0x87, 0x33, 0xe5, 0x58,
+	0xe8, 0x6d, 0x98, 0xf1, 0xc9, 0x1e, 0x1e, 0x86, 0x99, 0x4b, 0x01, 0x62, 0x0f, 0xb5, 0x04, 0x8c,
+	0xe2, 0xa3, 0xab, 0x50, 0x0f, 0x32, 0xd2, 0x97, 0x71, 0xcb, 0x82, 0xc1, 0x71, 0x23, 0x23, 0x7d,
+	0x87, 0xf7, 0xa3, 0x2b, 0x6c, 0x11, 0xa7, 0x85, 0x25, 0xe9, 0x58, 0xda, 0x12, 0xa2, 0xa7, 0xb0,
+	0xe8, 0xc5, 0xfd, 0xc1, 0x90, 0x85, 0x6a, 0x51, 0x46, 0x92, 0x3d, 0xec, 0x11, 0x19, 0x98, 0x5c,
+	0x31, 0xc8, 0xd6, 0x04, 0xde, 0x86, 0x42, 0xe3, 0x6c, 0x20, 0xa7, 0xb3, 0x7f, 0x69, 0xc1, 0x24,
+	0x15, 0x02, 0xad, 0xc2, 0x24, 0x4e, 0x7a, 0xa9, 0xb0, 0xe4, 0x0b, 0x25, 0x29, 0x57, 0x1e, 0x24,
+	0x3d, 0x41, 0xcf, 0x10, 0xd1, 0xdd, 0xc2, 0x02, 0x5c, 0x2a, 0x93, 0x54, 0xac, 0x82, 0x7d, 0x0f,
+	0x9a, 0x8a, 0xd3, 0x69, 0x8c, 0xc4, 0xfe, 0x18, 0x5a, 0x1a, 0xbf, 0x5f, 0x17, 0xe9, 0x97, 0x00,
+	0xf9, 0xaa, 0x9f, 0xea, 0x14, 0x22, 0xaf, 0x28, 0xe1, 0x77, 0xdf, 0x1d, 0xf6, 0x37, 0x70, 0x6e,
+	0xc4, 0x4a, 0x56, 0xb0, 0xb9, 0x6a, 0xca, 0xa3, 0xac, 0x4d, 0x51, 0xea, 0xfb, 0xee, 0x0f, 0x2c,
+	0x68, 0xaa, 0x0e, 0xca, 0x6c, 0x98, 0x04, 0xc2, 0xe7, 0xd2, 0x9f, 0xa3, 0x97, 0x58, 0x11, 0x55,
+	0x2e, 0xf1, 0x9b, 0x4f, 0x77, 0xf7, 0x6f, 0x6b, 0xd0, 0x90, 0xa1, 0x0b, 0xcd, 0x68, 0x86, 0xfd,
+	0xdd, 0x84, 0x84, 0x21, 0x16, 0xd4, 0xaa, 0x8d, 0x6e, 0x43, 0xfd, 0x20, 0x20, 0xaf, 0xa4, 0x64,
+	0x17, 0x8a, 0x71, 0x0f, 0x5b, 0x00, 0xb9, 0x57, 0x19, 0x26, 0x3d, 0x57, 0x64, 0x18, 0xcf, 0x77,
+	0xbe, 0x6c, 0xa2, 0x2e, 0xb4, 0xfb, 0xf8, 0xb5, 0xcb, 0x8f, 0xc2, 0x2c, 0x93, 0xc7, 0x64, 0xab,
+	0x8f, 0x5f, 0x6f, 0x53, 0xd8, 0x76, 0x16, 0xa2, 0x6b, 0x6c, 0x77, 0xd6, 0x4d, 0x5f, 0xa3, 0x46,
+	0xd3, 0x9d, 0xec, 0x63, 0x80, 0x7c, 0xf0, 0x0a, 0xed, 0xbb, 0xe6, 0x12, 0xcd, 0x18, 0x26, 0xf3,
+	0xdd, 0x9d, 0xe9, 0xdf, 0x4d, 0xc0, 0x5c, 0x21, 0xb4, 0xa2, 0x6e, 0x4a, 0xfa, 0xd5, 0x08, 0xf7,
+	0xa5, 0x4f, 0x6d, 0x09, 0xd8, 0x33, 0xdc, 0x27, 0xe8, 0x09, 0x68, 0x6e, 0xa1, 0x78, 0x28, 0x15,
+	0xf8, 0xad, 0x1c, 0xe1, 0x51, 0xd0, 0x13, 0x68, 0xcb, 0xb1, 0xb8, 0x27, 0xe5, 0xc7, 0xd2, 0xf5,
+	0x51, 0xbc, 0x44, 0xbb, 0xec, 0x5b, 0x57, 0xd9, 0x8c, 0x4f, 0x9a, 0xd9, 0x5e, 0x91, 0x5a, 0x9f,
+	0xf8, 0xcf, 0x60, 0xee, 0xf8, 0x0d, 0x32, 0x7a, 0x9f, 0x6d, 0xc3, 0x42, 0x49, 0x96, 0x93, 0x04,
+	0x89, 0xa6, 0x64, 0xe3, 0xd9, 0xf4, 0xdd, 0x7f, 0xaf, 0x41, 0x4b, 0xe3, 0x88, 0x9e, 0xe4, 0x2b,
+	0xa8, 0xb9, 0xe9, 0x2b, 0x15, 0x83, 0xcb, 0xdf, 0xb9, 0xbf, 0x6e, 0xa5, 0x39, 0x04, 0xad, 0xc0,
+	0x22, 0x2d, 0x66, 0xd0, 0x29, 0x76, 0x3d, 0x9c, 0x91, 0x5e, 0x9c, 0x04, 0x2a, 0x98, 0x5a, 0xf0,
+	0x71, 0x9f, 0xf2, 0x58, 0x53, 0x1d, 0xe8, 0xa6, 0x16, 0x5a, 0x5c, 0xa8, 0x1a, 0x4e, 0x5f, 0x81,
+	0x2b, 0x00, 0xf9, 0xe8, 0x34, 0xac, 0x65, 0x0a, 0x49, 0xee, 0xa2, 0x65, 0xff, 0x04, 0xe6, 0x8b,
+	0x32, 0x56, 0xcc, 0xcd, 0xff, 0x37, 0xe7, 0xf9, 0xf2, 0xd1, 0xaa, 0x8e, 0x61, 0xca, 0xff, 0x64,
+	0x02, 0x1a, 0xd2, 0x6d, 0xa3, 0x7b, 0x5a, 0xc1, 0xa1, 0xe2, 0x48, 0x64, 0xa3, 0xd3, 0x1f, 0x22,
+	0x6b, 0x53, 0xc8, 0xe8, 0x03, 0xe8, 0xa8, 0xf3, 0xb9, 0x7a, 0x92, 0x67, 0x13, 0x73, 0x86, 0x77,
+	0xe0, 0x8c, 0xa2, 0x60, 0x6c, 0x0e, 0xdd, 0x5d, 0x9c, 0x06, 0xa5, 0x8d, 0xa3, 0xc6, 0x95, 0xa7,
+	0x01, 0x1f, 0xfb, 0x21, 0xc5, 0x15, 0x0b, 0x3d, 0xc8, 0x21, 0xf6, 0x1f, 0x59, 0xdc, 0x0f, 0x71,
+	0x2c, 0x84, 0x60, 0x52, 0xdb, 0xfa, 0xec, 0x37, 0xfa, 0x58, 0x9c, 0xf9, 0x35, 0xb3, 0x34, 0x53,
+	0xa1, 0x60, 0xf1, 0xf4, 0x7f, 0xf3, 0x63, 0xfc, 0x31, 0x74, 0x46, 0xc9, 0x7f, 0x1c, 0x9f, 0x86,
+	0xbe, 0x5a, 0x7f, 0x3d, 0x09, 0x6d, 0xa3, 0xc6, 0x80, 0x3e, 0x84, 0xb3, 0x09, 0xc1, 0xbe, 0x1b,
+	0x47, 0xe1, 0xa1, 0xdb, 0xc7, 0x69, 0x46, 0x12, 0x37, 0x21, 0x38, 0xec, 0x33, 0x86, 0x0d, 0x67,
+	0x91, 0xf6, 0x7e, 0x1d, 0x85, 0x87, 0x4f, 0x59, 0x9f, 0x43, 0xbb, 0xd0, 0x06, 0x74, 0x7b, 0xde,
+	0xc0, 0xed, 0xe3, 0x08, 0xf7, 0x88, 0xef, 0xbe, 0x24, 0x87, 0xa9, 0x4b, 0xcf, 0x82, 0x84, 0xfc,
+	0x74, 0x48, 0x58, 0xfd, 0x8a, 0x9e, 0x07, 0x3c, 0xa5, 0xba, 0xd4, 0xf3, 0x06, 0x4f, 0x39, 0xe2,
+	0x57, 0xe4, 0x30, 0x7d, 0x8a, 0x5f, 0x3b, 0x12, 0x8b, 0x9e, 0x10, 0x9f, 0xc3, 0xc5, 0x12, 0xab,
+	0x01, 0x49, 0x5c, 0xec, 0x79, 0xf1, 0x30, 0xca, 0xd8, 0xa1, 0x52, 0x77, 0x3a, 0x26, 0x93, 0x4d,
+	0x92, 0x3c, 0xe0, 0xfd, 0xe8, 0x33, 0xb8, 0x40, 0xe9, 0xd5, 0x36, 0xe7, 0x60, 0x77, 0x90, 0xc4,
+	0xbf, 0x41, 0xbc, 0x4c, 0x84, 0x96, 0x94, 0x5c, 0x5a, 0x3d, 0x47, 0xd8, 0xe4, 0xfd, 0xe8, 0x47,
+	0xb0, 0xa4, 0xcc, 0xc8, 0x27, 0xa9, 0x97, 0x04, 0x83, 0x2c, 0x4e, 0x64, 0xd8, 0xb9, 0x52, 0x59,
+	0x97, 0x51, 0xa6, 0xb4, 0x9e, 0x13, 0x08, 0x53, 0xd2, 0x58, 0xa0, 0xbb, 0x70, 0x8e, 0x4a, 0x16,
+	0xe0, 0xbe, 0xbb, 0x1b, 0x84, 0x61, 0x10, 0xf5, 0x94, 0x54, 0xd3, 0x4c, 0xaa, 0xa5, 0x9e, 0x37,
+	0xd8, 0xc0, 0xfd, 0x87, 0xbc, 0x53, 0x4a, 0x74, 0x1f, 0x2e, 0xe1, 0x57, 0x69, 0x79, 0x42, 0x28,
+	0x9f, 0x61, 0x4a, 0x92, 0x4e, 0x83, 0xcf, 0x08, 0x7e, 0x95, 0x9a, 0x33, 0xb2, 0x81, 0xfb, 0x3b,
+	0x29, 0x49, 0xec, 0x17, 0xd0, 0x19, 0x25, 0x60, 0x85, 0xad, 0x5c, 0x33, 0x1d, 0x06, 0x92, 0xc9,
+	0x58, 0x4e, 0xaa, 0xdb, 0xcf, 0xbf, 0x58, 0x74, 0xb7, 0xf3, 0xd4, 0x51, 0x1c, 0xee, 0xa5, 0x44,
+	0x82, 0xf7, 0x1a, 0xe1, 0xf7, 0x5b, 0xd0, 0x62, 0x3c, 0xdc, 0x84, 0xf4, 0xc8, 0x6b, 0x61, 0x18,
+	0xc0, 0x40, 0x0e, 0x85, 0xd0, 0xa3, 0x96, 0x23, 0xa4, 0xde, 0x3e, 0xe9, 0x63, 0x19, 0x4a, 0x30,
+	0xd8, 0x16, 0x03, 0xe9, 0x09, 0x6e, 0xdd, 0x4c, 0x70, 0x67, 0xa1, 0xb6, 0x7b, 0xc8, 0x56, 0xac,
+	0xe9, 0xd4, 0x76, 0x0f, 0xdf, 0xd4, 0x93, 0xfd, 0x7d, 0x5d, 0x1d, 0x65, 0xb9, 0xf2, 0x34, 0x9c,
+	0xa2, 0x67, 0xe6, 0x5e, 0x9c, 0xf4, 0x65, 0x38, 0x25, 0xdb, 0xe8, 0xdb, 0xbc, 0x50, 0x29, 0xf3,
+	0x4b, 0xe9, 0x16, 0x56, 0x0b, 0x8e, 0x37, 0xe7, 0x28, 0x21, 0x32, 0x1f, 0x2c, 0x14, 0x2c, 0x15,
+	0x18, 0x6d, 0xc1, 0x2c, 0x4d, 0x71, 0x34, 0xd6, 0xdc, 0xb5, 0xbd, 0x3f, 0x9a, 0x35, 0xcd, 0x1f,
+	0x0a, 0x7c, 0xdb, 0x81, 0x0e, 0x43, 0xeb, 0x00, 0x83, 0x24, 0x1e, 0x90, 0x24, 0x0b, 0x58, 0xba,
+	0x66, 0x55, 0x9c, 0x87, 0x1a, 0xc3, 0x4d, 0x85, 0xeb, 0x68, 0x74, 0xe8, 0xb6, 0x56, 0xe4, 0x78,
+	0x7b, 0x34, 0xb5, 0x7e, 0xc8, 0xfd, 0x26, 0x40, 0xce, 0x8c, 0xae, 0x77, 0x90, 0xba, 0xb8, 0xd7,
+	0x4b, 0x48, 0x4f, 0xa6, 0xab, 0x0d, 0xa7, 0x15, 0xa4, 0x0f, 0x24, 0x08, 0xdd, 0x80, 0x05, 0x0f,
+	0x47, 0xee, 0x2e, 0xc9, 0xd1, 0x7c, 0xe1, 0xd0, 0xe6, 0x3c, 0x1c, 0x3d, 0x24, 0x0a, 0xd5, 0xa7,
+	0xf6, 0x45, 0x2b, 0x3a, 0x21, 0x71, 0xa9, 0xb6, 0xcc, 0xbe, 0x1a, 0x0e, 0x70, 0x10, 0x9d, 0x13,
+	0xfb, 0xc7, 0xaa, 0xf0, 0x66, 0x4e, 0xcf, 0x58, 0xf2, 0xf7, 0x6f, 0x00, 0x95, 0x27, 0xfe, 0xff,
+	0xb2, 0x32, 0xf0, 0x2b, 0x0b, 0x66, 0x4d, 0xa6, 0x34, 0xaa, 0x60, 0x7b, 0x6f, 0x20, 0x8b, 0x65,
+	0xbc, 0x45, 0xcd, 0x9b, 0x17, 0x99, 0x71, 0x28, 0x26, 0x57, 0xb5, 0xd1, 0x8a, 0x16, 0xc4, 0x5c,
+	0xae, 0x16, 0xd6, 0xd8, 0xe5, 0x08, 0x26, 0x69, 0x89, 0x40, 0x6c, 0x5e, 0xf6, 0x1b, 0x75, 0x61,
+	0x86, 0xbc, 0x1e, 0x90, 0x24, 0xe8, 0x93, 0x28, 0xc3, 0xbc, 0x5c, 0xd6, 0x70, 0x0c, 0xd8, 0x9b,
+	0x6a, 0x39, 0x0d, 0x75, 0x76, 0x1a, 0xd1, 0x62, 0x18, 0xda, 0xc4, 0x69, 0x3a, 0x88, 0x93, 0x6c,
+	0x5b, 0x94, 0xf8, 0xe2, 0x04, 0xdd, 0x02, 0x44, 0x5d, 0x3b, 0xce, 0x02, 0x5a, 0x3c, 0x92, 0x17,
+	0x34, 0x3c, 0x51, 0x5b, 0xc8, 0x7b, 0xe4, 0xe5, 0xcb, 0x1d, 0xcd, 0x9b, 0x75, 0x55, 0x35, 0xa8,
+	0xc4, 0x76, 0x1c, 0xc5, 0xb0, 0x79, 0x98, 0x7d, 0x42, 0xb2, 0x8d, 0x68, 0x2f, 0x16, 0x47, 0x61,
+	0xf7, 0x97, 0x16, 0xcc, 0x29, 0x50, 0x3a, 0x88, 0xa3, 0x94, 0x54, 0xc6, 0x1e, 0x36, 0x34, 0xc4,
+	0xcd, 0x95, 0x8c, 0x8b, 0x54, 0x9b, 0xd6, 0xf9, 0xd2, 0x0c, 0x27, 0xda, 0x6d, 0xd5, 0x84, 0xd3,
+	0x64, 0x10, 0x76, 0x59, 0xd5, 0x81, 0xe9, 0x7e, 0xec, 0x0f, 0x65, 0x95, 0xa6, 0xe9, 0xc8, 0xa6,
+	0x48, 0x19, 0xea, 0x66, 0xca, 0x50, 0x90, 0x66, 0x1c, 0x6a, 0xdf, 0x86, 0x19, 0xb6, 0x60, 0x42,
+	0x69, 0xf4, 0x36, 0x4c, 0xb2, 0xed, 0x6a, 0xb1, 0xed, 0xd0, 0xce, 0xd3, 0x43, 0x8a, 0xc3, 0xba,
+	0xba, 0x73, 0xd0, 0x16, 0x24, 0x5c, 0x8c, 0xee, 0x13, 0x58, 0x7c, 0x42, 0x32, 0x55, 0xe0, 0x97,
+	0xac, 0xce, 0xc2, 0xd4, 0x5e, 0x10, 0x66, 0x79, 0x2d, 0x98, 0xb7, 0xa8, 0xd2, 0x41, 0xe4, 0x85,
+	0x43, 0x5f, 0x8a, 0x23, 0x9b, 0xdd, 0xbf, 0xb4, 0x60, 0xc9, 0xe4, 0x24, 0xa6, 0x7d, 0x43, 0xbf,
+	0xf7, 0xe2, 0x41, 0xec, 0x4d, 0x6d, 0x52, 0x4a, 0x04, 0xa3, 0xaf, 0xc0, 0xc6, 0x7d, 0x15, 0xd1,
+	0x3d, 0xc3, 0x94, 0x7f, 0x1c, 0xe2, 0x8c, 0xa5, 0xca, 0xd2, 0x78, 0xfe, 0xaa, 0x09, 0x4b, 0x26,
+	0x5c, 0xa8, 0xf2, 0x99, 0x4c, 0xf7, 0x2d, 0x33, 0x31, 0xad, 0x42, 0x2e, 0xa7, 0xfe, 0xf6, 0x7f,
+	0x4f, 0x43, 0x43, 0xe2, 0xd1, 0x3a, 0xa0, 0x54, 0xcc, 0x1d, 0xe0, 0x6c, 0x5f, 0xe8, 0x30, 0x23,
+	0x81, 0x9b, 0x38, 0xdb, 0x37, 0x6a, 0x0f, 0xb5, 0x42, 0xed, 0x41, 0x67, 0x10, 0x61, 0x61, 0xa1,
+	0x1a, 0x03, 0x96, 0x4f, 0x5f, 0x80, 0x26, 0x1d, 0x9b, 0x23, 0x70, 0x3f, 0xd2, 0xa0, 0x00, 0xd9,
+	0xc9, 0x72, 0x03, 0xd6, 0x29, 0xea, 0xee, 0x14, 0xc0, 0x3a, 0xdf, 0x85, 0x59, 0x95, 0x4e, 0x73,
+	0x8c, 0x29, 0x86, 0xd1, 0x56, 0x50, 0x86, 0xf6, 0x0e, 0xe4, 0x00, 0x97, 0x16, 0x6e, 0x78, 0x28,
+	0x36, 0xa3, 0x80, 0x3b, 0x49, 0x40, 0x4f, 0x27, 0xbd, 0xe6, 0xc9, 0x22, 0xae, 0xa6, 0xd3, 0xd2,
+	0x4a, 0x9e, 0x68, 0x43, 0x15, 0x79, 0xf8, 0xc5, 0xe4, 0xed, 0x23, 0xe7, 0x56, 0x42, 0x2a, 0x2b,
+	0xac, 0xc5, 0x32, 0x03, 0x94, 0xcb, 0x0c, 0x7a, 0x10, 0xd2, 0x2a, 0x04, 0x21, 0xd7, 0x61, 0x5e,
+	0xfe, 0x96, 0x51, 0x30, 0xbb, 0x55, 0x6c, 0x3a, 0x73, 0x12, 0x2e, 0x8e, 0xbe, 0x72, 0xc5, 0xa6,
+	0x5d, 0xae, 0xd8, 0x3c, 0x87, 0x96, 0x5a, 0xa6, 0x61, 0xd0, 0x99, 0x65, 0xda, 0xdd, 0x3d, 0x99,
+	0x76, 0xd2, 0x66, 0xa5, 0xaf, 0x80, 0x44, 0x01, 0xd0, 0x97, 0x30, 0xcd, 0x56, 0x76, 0x18, 0x74,
+	0xe6, 0x4e, 0x33, 0x63, 0xf4, 0x1f, 0xc9, 0x6f, 0xea, 0x80, 0x35, 0x28, 0x2f, 0x66, 0x08, 0xc3,
+	0xa0, 0x33, 0x7f, 0x1a, 0x5e, 0x34, 0x3d, 0x53, 0xbc, 0x12, 0xd6, 0xa0, 0xb7, 0x45, 0xc5, 0x84,
+	0x73, 0xa1, 0x2a, 0xe1, 0xfc, 0x2e, 0xe5, 0xd0, 0xcf, 0x60, 0xae, 0x30, 0x35, 0xa7, 0x2d, 0xc4,
+	0x6a, 0xb3, 0x70, 0x5a, 0x52, 0x4d, 0xe9, 0x53, 0x91, 0xba, 0xc7, 0x14, 0xe4, 0x3e, 0x31, 0x1d,
+	0xd7, 0xbb, 0x27, 0x5a, 0x02, 0xdd, 0x9b, 0x2d, 0x01, 0xd2, 0xfc, 0xa9, 0x74, 0x66, 0x3f, 0x36,
+	0x1c, 0xbc, 0x72, 0x65, 0xef, 0xd3, 0x77, 0x1a, 0x1c, 0xd6, 0xb1, 0x46, 0x78, 0x4a, 0x85, 0x41,
+	0x8f, 0x03, 0xec, 0x79, 0x24, 0x55, 0x35, 0x14, 0xde, 0xea, 0x2e, 0xb0, 0x53, 0xd6, 0x70, 0x9e,
+	0x7f, 0x66, 0xc1, 0x7c, 0x0e, 0x13, 0xa3, 0x7d, 0x6c, 0x3a, 0xce, 0x77, 0x34, 0xdd, 0x8e, 0x71,
+	0x9a, 0xa3, 0x86, 0x1e, 0x57, 0x7d, 0x53, 0xc4, 0x0e, 0x0c, 0x2a, 0x34, 0xf8, 0x4a, 0x29, 0xa5,
+	0xe4, 0x5f, 0x86, 0x49, 0x2a, 0x4d, 0xc7, 0xaa, 0xe0, 0xc5, 0x7a, 0x46, 0xce, 0x10, 0x3f, 0x62,
+	0x64, 0x2d, 0x43, 0xcd, 0xd2, 0x2f, 0xf8, 0x69, 0xa9, 0xc1, 0xf3, 0x23, 0x86, 0xd7, 0x2b, 0xcb,
+	0x47, 0x4c, 0x09, 0xb9, 0xe2, 0x26, 0x68, 0xd4, 0x6c, 0x8d, 0xf1, 0x02, 0x41, 0xd8, 0x99, 0xea,
+	0x11, 0x1a, 0x6d, 0x19, 0x8a, 0x2a, 0x7d, 0xae, 0xc0, 0x24, 0x95, 0xac, 0x68, 0x63, 0x0a, 0x8f,
+	0xf5, 0x8e, 0x9c, 0xbd, 0xe7, 0x6c, 0x29, 0x98, 0x2f, 0xd5, 0x22, 0x93, 0x84, 0x64, 0xc3, 0x24,
+	0xca, 0x03, 0x6f, 0xda, 0xa2, 0xf7, 0xcb, 0x3e, 0xce, 0x30, 0x4d, 0xe9, 0x65, 0x68, 0x42, 0xdb,
+	0x3b, 0x29, 0xbb, 0x52, 0xc8, 0xcb, 0x27, 0xf4, 0x67, 0xf7, 0x1c, 0x9c, 0xa1, 0x7c, 0x49, 0x4a,
+	0x37, 0xc6, 0x30, 0xcc, 0xd4, 0xba, 0xfc, 0x5b, 0x0b, 0xce, 0x16, 0x7b, 0x84, 0x26, 0x6f, 0xf6,
+	0xe6, 0xe9, 0x22, 0x34, 0x69, 0xf8, 0x98, 0x66, 0xb8, 0x3f, 0x10, 0x2f, 0x9e, 0x72, 0x00, 0xfa,
+	0x02, 0x1a, 0xea, 0xf5, 0xcb, 0xa4, 0x99, 0x8c, 0x56, 0x4b, 0xb1, 0x62, 0x3e, 0x83, 0x51, 0xd4,
+	0xe8, 0x07, 0xc0, 0x9e, 0xc4, 0xb8, 0x09, 0xc7, 0xef, 0xd4, 0xcd, 0xaa, 0xdd, 0x08, 0x6e, 0x39,
+	0xcc, 0x69, 0x65, 0x79, 0x3f, 0xfa, 0x1c, 0x66, 0xfa, 0xb1, 0x1f, 0xec, 0x05, 0x1e, 0xa6, 0xb9,
+	0x0b, 0x3b, 0xfa, 0x5b, 0x77, 0x6c, 0xb3, 0x7a, 0xf3, 0x54, 0xc3, 0x70, 0x0c, 0x7c, 0x3a, 0x23,
+	0xe4, 0x35, 0xf1, 0x68, 0xc9, 0x84, 0x05, 0x04, 0x75, 0x47, 0xb5, 0xe9, 0x02, 0x0e, 0x70, 0x9a,
+	0x12, 0x5f, 0x14, 0x5e, 0x44, 0x8b, 0x7a, 0x4e, 0x92, 0x24, 0x71, 0xd2, 0x69, 0x72, 0xcf, 0xc9,
+	0x1a, 0xf6, 0x2f, 0x2c, 0x1a, 0xe4, 0xd2, 0x42, 0x0e, 0xf1, 0x69, 0x29, 0x84, 0xaf, 0x3f, 0x4e,
+	0x63, 0x6d, 0xfd, 0x69, 0x8b, 0x92, 0xef, 0x05, 0x24, 0xf4, 0xa5, 0xe3, 0x65, 0x0d, 0xb4, 0x0c,
+	0xaa, 0x84, 0x44, 0xf5, 0x98, 0x90, 0x17, 0xa3, 0x0a, 0xc4, 0x23, 0x24, 0x71, 0x9b, 0x9f, 0x47,
+	0x48, 0xa2, 0x22, 0x73, 0x16, 0xa6, 0x84, 0x8f, 0xe4, 0xe1, 0x91, 0x68, 0xe5, 0x5e, 0x7e, 0x4a,
+	0xf3, 0xf2, 0xa2, 0x6e, 0xc2, 0x03, 0x20, 0x5a, 0x37, 0xf9, 0xe3, 0x09, 0x80, 0x7c, 0x86, 0x2b,
+	0xf3, 0x0f, 0xa6, 0x0d, 0xed, 0x15, 0x62, 0x8b, 0x96, 0xb6, 0x21, 0x26, 0xf4, 0x0d, 0x81, 0xb6,
+	0xa1, 0x19, 0xc5, 0xae, 0xe8, 0xe2, 0x16, 0x73, 0xef, 0xc4, 0x6b, 0xbc, 0xf2, 0x2c, 0x7e, 0xc0,
+	0x28, 0x85, 0xf1, 0x44, 0xa2, 0x89, 0x1c, 0x98, 0x4d, 0xc4, 0x1c, 0xbb, 0x54, 0x77, 0x69, 0x3e,
+	0x37, 0x8f, 0x61, 0xad, 0x2f, 0x8c, 0xd3, 0x4e, 0xb4, 0x56, 0x9a, 0x2f, 0xe7, 0x94, 0xb6, 0x9c,
+	0xe8, 0x31, 0xb4, 0xc8, 0xeb, 0x41, 0x88, 0x23, 0x6e, 0x57, 0xd3, 0x66, 0xbd, 0x64, 0x94, 0x06,
+	0x09, 0xf6, 0x88, 0xa3, 0x13, 0xda, 0x9f, 0x40, 0xdb, 0x50, 0xe6, 0x54, 0x27, 0xf1, 0x5f, 0x4c,
+	0x40, 0x9d, 0xf1, 0xa4, 0x4b, 0xf2, 0x32, 0x88, 0x7c, 0xb9, 0x24, 0xf4, 0xb7, 0x5a, 0xa6, 0x5a,
+	0xe5, 0x32, 0xf1, 0x52, 0x88, 0x5c, 0xa6, 0xfb, 0x50, 0xe7, 0xf3, 0x35, 0x79, 0xb2, 0xed, 0x46,
+	0x07, 0x65, 0x15, 0x3d, 0x87, 0xd3, 0xa1, 0xef, 0x43, 0xc3, 0xdb, 0x0f, 0x42, 0x3f, 0x21, 0x91,
+	0x98, 0xf3, 0x93, 0x4d, 0x86, 0xa2, 0xb2, 0x7f, 0x65, 0xc1, 0xa4, 0xdc, 0x18, 0x95, 0xcf, 0x77,
+	0x0c, 0x03, 0xaf, 0x8d, 0x34, 0xf0, 0x89, 0x6a, 0x03, 0x9f, 0x2c, 0x1b, 0x78, 0x5d, 0x1a, 0x38,
+	0x4b, 0x81, 0x71, 0xe6, 0xed, 0x13, 0x9f, 0xad, 0x72, 0xc3, 0x91, 0x4d, 0x6d, 0x97, 0x4e, 0x57,
+	0xef, 0xd2, 0xc6, 0x11, 0xbb, 0xb4, 0x59, 0xda, 0xa5, 0xf6, 0x26, 0xb4, 0xc7, 0xfb, 0x6c, 0x8a,
+	0x86, 0x2e, 0x22, 0x8c, 0x57, 0xce, 0xff, 0xe7, 0x96, 0xba, 0x12, 0xca, 0xdd, 0xfe, 0x43, 0x68,
+	0x88, 0x2c, 0x40, 0x9e, 0xc9, 0xef, 0x15, 0x0a, 0x74, 0xf9, 0xda, 0x48, 0x80, 0xd8, 0x5f, 0x92,
+	0xce, 0x7e, 0x0e, 0x6d, 0xa3, 0xab, 0x42, 0xfa, 0x55, 0x53, 0xfa, 0xf3, 0x23, 0x8b, 0x80, 0xba,
+	0x0e, 0x17, 0xc1, 0x2e, 0x17, 0x54, 0x94, 0x3a, 0xff, 0x61, 0xc1, 0x85, 0xca, 0x6e, 0xa1, 0x59,
+	0x0c, 0x4b, 0x03, 0xd1, 0xed, 0x66, 0x79, 0xbf, 0xd0, 0xf2, 0xd3, 0xd1, 0x25, 0x1b, 0xed, 0x34,
+	0x2a, 0xf7, 0x89, 0xf7, 0x41, 0x83, 0x72, 0x8f, 0xbd, 0x0b, 0x9d, 0x51, 0x04, 0x15, 0x33, 0xf2,
+	0x81, 0x39, 0x23, 0xf6, 0x68, 0x79, 0xf4, 0x29, 0xb1, 0xa1, 0xb3, 0x5e, 0xbc, 0x3d, 0x94, 0x13,
+	0xf2, 0xfb, 0xf4, 0x2c, 0xc9, 0x7b, 0x98, 0x8b, 0x88, 0x13, 0x5f, 0xec, 0x98, 0xba, 0xc3, 0x1b,
+	0xe8, 0x7d, 0xad, 0x70, 0x75, 0x51, 0x8e, 0xaa, 0xd3, 0x8d, 0xa3, 0x76, 0xf3, 0xaf, 0x16, 0x9c,
+	0xaf, 0x10, 0x54, 0x2c, 0xcd, 0x7e, 0xf5, 0xf5, 0x28, 0x5f, 0x99, 0x8f, 0xb4, 0x07, 0xab, 0xd5,
+	0xf4, 0xe5, 0x1e, 0x2e, 0x6f, 0xf9, 0x62, 0xd5, 0x7e, 0x01, 0x67, 0xab, 0x91, 0x2b, 0xb4, 0xb9,
+	0x61, 0xae, 0xc8, 0x52, 0xd5, 0xdc, 0xe8, 0x3a, 0x76, 0x54, 0x2c, 0x25, 0xf7, 0xae, 0x5c, 0x89,
+	0x7f, 0xae, 0xc1, 0xb9, 0x52, 0x97, 0xaa, 0x17, 0xe5, 0x31, 0x11, 0x57, 0xf8, 0x56, 0xc1, 0x25,
+	0x16, 0x49, 0x46, 0x06, 0x45, 0xdf, 0xc2, 0x5c, 0x9a, 0xe1, 0xc8, 0xc7, 0x89, 0xef, 0x7a, 0x21,
+	0x0e, 0xfa, 0xf2, 0x36, 0xe1, 0xc3, 0xe3, 0x38, 0x6e, 0x09, 0xb2, 0x35, 0x46, 0x25, 0x5e, 0x83,
+	0xa7, 0x06, 0x70, 0xfc, 0x3e, 0xc9, 0x7e, 0x00, 0x8b, 0x15, 0x03, 0x9f, 0xca, 0xae, 0x2e, 0x82,
+	0xfd, 0x10, 0x7b, 0x2f, 0x7b, 0x49, 0x3c, 0x8c, 0xfc, 0x4d, 0xfe, 0x84, 0x3f, 0xdf, 0x01, 0x7f,
+	0x63, 0xc1, 0x85, 0xca, 0x6e, 0x31, 0xf7, 0x9b, 0xd0, 0x1c, 0x48, 0xa0, 0x98, 0xfc, 0x3b, 0x72,
+	0xaa, 0x8e, 0xa0, 0x5b, 0x51, 0x10, 0x51, 0xb2, 0x53, 0x4c, 0x68, 0xc9, 0xce, 0xec, 0x3c, 0x49,
+	0xf2, 0x21, 0xc8, 0x25, 0xdb, 0xc2, 0xfe, 0x2e, 0x09, 0x22, 0xb5, 0x7b, 0x02, 0xe7, 0x2b, 0xfa,
+	0x84, 0x6a, 0x37, 0x60, 0x5a, 0xb0, 0x55, 0xb9, 0x48, 0x71, 0x18, 0x89, 0x40, 0xab, 0xa4, 0x2c,
+	0xe7, 0x50, 0x9c, 0xef, 0xc3, 0xac, 0x04, 0x08, 0x76, 0xb7, 0x60, 0x8a, 0x95, 0x7d, 0xe4, 0x34,
+	0x9d, 0x51, 0x4b, 0x4b, 0xa1, 0x4f, 0x49, 0x86, 0x69, 0xf6, 0xe1, 0x08, 0xa4, 0xee, 0x2c, 0xcc,
+	0xe8, 0x59, 0x4c, 0xf7, 0x53, 0x31, 0x82, 0xe2, 0x77, 0x13, 0xea, 0x0c, 0x55, 0x08, 0x37, 0x82,
+	0x1d, 0xc7, 0xe9, 0xfe, 0xf6, 0x04, 0xa0, 0x72, 0x0c, 0x6e, 0xe4, 0x21, 0x56, 0x21, 0x0f, 0xf9,
+	0x61, 0xf1, 0xb1, 0x7d, 0xcd, 0x4c, 0x37, 0xca, 0xec, 0x8e, 0x7d, 0x75, 0x7f, 0x0e, 0xa6, 0xfd,
+	0xe4, 0xd0, 0x4d, 0x86, 0x91, 0x8c, 0x8a, 0xfc, 0xe4, 0xd0, 0x19, 0x46, 0xf6, 0x4f, 0x61, 0x51,
+	0x20, 0x19, 0xe2, 0xe5, 0x31, 0xad, 0x65, 0xc4, 0xb4, 0x97, 0x00, 0xb0, 0xef, 0xbb, 0x46, 0x02,
+	0xd8, 0xc4, 0xbe, 0x2f, 0x82, 0x53, 0x56, 0xe7, 0xec, 0xc7, 0x07, 0xc4, 0x35, 0x22, 0xe2, 0x19,
+	0x0e, 0xe4, 0x48, 0x76, 0x7c, 0xb2, 0xa7, 0xd5, 0xeb, 0xa6, 0xa5, 0xad, 0x1c, 0xa1, 0x7d, 0x85,
+	0x06, 0x85, 0xb2, 0x01, 0x27, 0x52, 0x95, 0xf4, 0x03, 0xf9, 0x12, 0x40, 0x66, 0xaa, 0xef, 0x1a,
+	0xe5, 0xf8, 0x85, 0xd2, 0x67, 0x06, 0xbc, 0x24, 0x5f, 0xca, 0xb5, 0x6a, 0xa7, 0xcb, 0xb5, 0xba,
+	0xbf, 0x05, 0x67, 0x94, 0x24, 0x7a, 0xe5, 0x87, 0xa6, 0xde, 0xda, 0xf8, 0xe5, 0xf2, 0xce, 0x78,
+	0x86, 0x1f, 0xc2, 0x02, 0xc7, 0xd1, 0x4a, 0x28, 0xb4, 0x5e, 0xa2, 0x0d, 0x5d, 0xa8, 0x97, 0x8c,
+	0x65, 0xd8, 0x3f, 0xb5, 0xc0, 0xe6, 0x48, 0xe6, 0xf7, 0x15, 0x42, 0x80, 0xeb, 0x86, 0x00, 0x23,
+	0xbe, 0xc5, 0xe0, 0x92, 0xd0, 0x07, 0xb9, 0xfc, 0xd5, 0x7a, 0x4a, 0xbc, 0x84, 0x64, 0xb2, 0x8e,
+	0xce, 0x81, 0x5b, 0x0c, 0xf6, 0x9d, 0xc5, 0xfd, 0xbd, 0xa2, 0xb8, 0x5b, 0xc6, 0x52, 0x1d, 0x2d,
+	0xee, 0xd6, 0x38, 0xd7, 0xeb, 0x10, 0x16, 0x39, 0x8e, 0x78, 0x33, 0x24, 0x24, 0xe8, 0x1a, 0x12,
+	0x14, 0x3f, 0x07, 0x19, 0xcf, 0xd0, 0xbf, 0x63, 0xc1, 0x92, 0xf9, 0x41, 0xce, 0xd1, 0xea, 0x9b,
+	0xb8, 0x63, 0xde, 0x2d, 0xea, 0x03, 0x8d, 0xa3, 0x77, 0x8b, 0x42, 0x1b, 0xcf, 0xf0, 0x3f, 0xb3,
+	0xe0, 0x22, 0x47, 0x2a, 0x7e, 0x77, 0x22, 0xc4, 0xb8, 0x69, 0x88, 0x31, 0xf2, 0x2b, 0x95, 0xf1,
+	0x48, 0xf3, 0xbb, 0x16, 0x74, 0x38, 0x92, 0x1e, 0x90, 0x08, 0x49, 0xae, 0x1a, 0x92, 0x54, 0x86,
+	0x2e, 0xe3, 0x91, 0xe2, 0x7f, 0xa6, 0xe0, 0xbc, 0x74, 0x4a, 0xfa, 0x21, 0xb9, 0x95, 0xe1, 0x8c,
+	0xa0, 0xfb, 0xe2, 0x12, 0x9c, 0x5e, 0xc1, 0xcc, 0xe6, 0x15, 0x89, 0x91, 0x04, 0xfc, 0xc4, 0xe4,
+	0x4b, 0x46, 0x09, 0xd1, 0x17, 0xe5, 0xfb, 0xc7, 0x1b, 0xc7, 0x73, 0x91, 0x3d, 0xfa, 0x17, 0x78,
+	0xc6, 0xf7, 0x2d, 0xb5, 0xc2, 0xf7, 0x2d, 0x1d, 0xa8, 0xa7, 0x94, 0x92, 0xbb, 0x8f, 0x87, 0xb5,
+	0x8e, 0xe5, 0x70, 0x00, 0x3d, 0xf7, 0x76, 0x93, 0xf8, 0x25, 0x49, 0x44, 0x32, 0x2d, 0x5a, 0xe8,
+	0x32, 0x3d, 0xae, 0xfd, 0x20, 0x51, 0xcf, 0xaa, 0x18, 0x91, 0x82, 0xc9, 0xb2, 0xe5, 0x14, 0x3b,
+	0xc9, 0xe9, 0x4f, 0xfa, 0x84, 0x23, 0x11, 0xc7, 0x0d, 0x7d, 0xc7, 0xe4, 0xee, 0x05, 0x21, 0x61,
+	0x09, 0x76, 0xc3, 0x99, 0x93, 0x1d, 0x5f, 0x91, 0xc3, 0xc7, 0x01, 0x7b, 0xf0, 0x3f, 0x17, 0xc6,
+	0xbd, 0x20, 0x72, 0xbd, 0x7d, 0x1c, 0x86, 0x24, 0xea, 0xc9, 0x6b, 0xb7, 0x59, 0x06, 0x5e, 0x93,
+	0x50, 0xad, 0x6e, 0xd0, 0x34, 0xea, 0x06, 0xf4, 0x7d, 0xd0, 0x70, 0x97, 0x3d, 0xaf, 0xe2, 0x37,
+	0x68, 0xb2, 0x49, 0x3d, 0x26, 0x19, 0xc4, 0xde, 0x3e, 0x75, 0x98, 0x71, 0xe4, 0xa7, 0xec, 0x0a,
+	0x6d, 0xc2, 0x99, 0x61, 0xc0, 0x2d, 0x0e, 0xa3, 0x21, 0x2a, 0x7f, 0xf6, 0xc6, 0xef, 0xc4, 0x78,
+	0x03, 0x5d, 0x06, 0x08, 0x7c, 0x12, 0x65, 0x01, 0x7b, 0x2e, 0x33, 0xcb, 0x4e, 0x72, 0x0d, 0x42,
+	0xdf, 0x18, 0xe4, 0x6f, 0xde, 0xf0, 0xd0, 0x0f, 0x48, 0xe4, 0x11, 0x76, 0xc1, 0xd5, 0x74, 0x16,
+	0x54, 0xcf, 0x03, 0xd1, 0x41, 0x95, 0xcc, 0xd1, 0x53, 0x2f, 0x1e, 0x90, 0xce, 0xbc, 0xb8, 0x6c,
+	0x92, 0xe0, 0x2d, 0x0a, 0x45, 0x37, 0x61, 0xc1, 0xa3, 0xb3, 0x13, 0x65, 0xda, 0x7c, 0x2c, 0x30,
+	0xc9, 0xe6, 0x45, 0x47, 0x3e, 0x23, 0xf4, 0x3b, 0x55, 0xbe, 0xce, 0xac, 0x10, 0x84, 0x18, 0x1a,
+	0x70, 0x10, 0xbd, 0x3e, 0xa4, 0x8f, 0x1a, 0xf3, 0xf7, 0xe1, 0x4a, 0x51, 0x4b, 0x57, 0xd4, 0xd6,
+	0xee, 0x57, 0x84, 0xa9, 0xc8, 0x36, 0xad, 0x30, 0xb1, 0xdb, 0x04, 0x7e, 0xd0, 0xb0, 0xdf, 0x14,
+	0xc6, 0xea, 0xe4, 0xe2, 0xad, 0x07, 0xfd, 0x4d, 0x6b, 0xc7, 0xea, 0x1a, 0x55, 0x54, 0x5d, 0x72,
+	0x00, 0x7f, 0x28, 0x1f, 0x8a, 0xf2, 0x1a, 0xfd, 0xd9, 0xbd, 0x07, 0x4d, 0x65, 0xfc, 0x68, 0x0e,
+	0x5a, 0x3b, 0xcf, 0xb6, 0x36, 0x1f, 0xad, 0x6d, 0x3c, 0xde, 0x78, 0xb4, 0x3e, 0xff, 0xff, 0x50,
+	0x0b, 0xa6, 0xd7, 0x1f, 0x6c, 0x3f, 0xd8, 0x7a, 0xb4, 0x3d, 0x6f, 0xa1, 0x19, 0x68, 0x3c, 0x7a,
+	0xb6, 0xbe, 0xf9, 0xf5, 0xc6, 0xb3, 0xed, 0xf9, 0x5a, 0x77, 0x0f, 0x1a, 0x0f, 0x86, 0xd9, 0xfe,
+	0x5a, 0xec, 0x53, 0xe5, 0x35, 0x23, 0xb7, 0x72, 0xb3, 0x54, 0x86, 0xbe, 0x24, 0x0d, 0x5d, 0xe4,
+	0x1e, 0xac, 0x51, 0xb6, 0x89, 0x89, 0xb2, 0x4d, 0x74, 0xff, 0xb3, 0x99, 0x5f, 0xdc, 0xc9, 0x52,
+	0xf3, 0x7a, 0x79, 0x7b, 0xbe, 0x57, 0x0a, 0x55, 0x38, 0xee, 0x11, 0x1f, 0xc7, 0x7e, 0x62, 0x5c,
+	0x20, 0x68, 0x37, 0x4c, 0x45, 0x16, 0x7a, 0x01, 0x54, 0x90, 0x54, 0xcb, 0xde, 0x36, 0x65, 0xb7,
+	0xff, 0xb0, 0x06, 0x48, 0x32, 0xd3, 0x9e, 0xb3, 0xbd, 0x30, 0x1e, 0xac, 0x73, 0xf9, 0xbf, 0x77,
+	0x9c, 0xfc, 0xfa, 0x0b, 0xb3, 0x23, 0xde, 0xb0, 0xeb, 0xb7, 0x22, 0x96, 0x16, 0x30, 0x2f, 0x43,
+	0x6b, 0x40, 0x92, 0x7e, 0x90, 0xf2, 0x77, 0x2b, 0x3c, 0x1e, 0xd6, 0x41, 0x36, 0x39, 0xc9, 0x1b,
+	0xf4, 0x4f, 0xcd, 0x60, 0x78, 0xe4, 0xac, 0x2b, 0x4e, 0xf2, 0x5e, 0x4e, 0xe5, 0xab, 0x3f, 0x84,
+	0x59, 0xb3, 0x93, 0x16, 0x44, 0xf9, 0x67, 0x46, 0x96, 0x59, 0x10, 0x1d, 0x35, 0x13, 0xda, 0x07,
+	0x21, 0x8c, 0xce, 0xfe, 0x73, 0x0b, 0x16, 0x4a, 0x9d, 0xf9, 0x47, 0x21, 0x96, 0xfc, 0x28, 0xe4,
+	0x69, 0xe1, 0xa3, 0x90, 0xbb, 0x27, 0x1e, 0x69, 0xcc, 0x1f, 0x8b, 0xd8, 0xff, 0x54, 0xcb, 0x5f,
+	0xa5, 0x88, 0x94, 0xe5, 0x47, 0xd0, 0xf2, 0x12, 0xc2, 0xbc, 0x1a, 0x0e, 0xe5, 0x5c, 0xdc, 0x3b,
+	0x4e, 0x42, 0x4e, 0xbc, 0xb2, 0x96, 0x53, 0x8a, 0x47, 0xb0, 0x1a, 0x2f, 0xf4, 0x65, 0x41, 0xef,
+	0x3b, 0x27, 0xe4, 0x5a, 0xa1, 0x34, 0x75, 0xc6, 0xe4, 0xf5, 0x20, 0x48, 0x48, 0xea, 0x06, 0x91,
+	0x30, 0x7a, 0x0d, 0x62, 0x7f, 0x0e, 0xf3, 0x45, 0x61, 0x7e, 0x5d, 0x1f, 0x3c, 0xed, 0x9f, 0xe0,
+	0xa5, 0xcf, 0xf7, 0x4d, 0xfb, 0xbd, 0x71, 0xf2, 0x5d, 0xa7, 0x8f, 0x84, 0xa1, 0x75, 0xf4, 0x3d,
+	0xc2, 0x49, 0xb7, 0x89, 0x39, 0xe1, 0xda, 0x10, 0x0f, 0x9d, 0x17, 0x9b, 0xbd, 0x20, 0xdb, 0x1f,
+	0xee, 0xd2, 0x18, 0x6a, 0xf5, 0x49, 0x1c, 0xf7, 0x42, 0xb2, 0x16, 0xc6, 0x43, 0x7f, 0x53, 0xbc,
+	0x27, 0x59, 0xdd, 0x27, 0x38, 0xcc, 0xf6, 0x3d, 0x9c, 0x90, 0x5b, 0x7b, 0xc4, 0x27, 0x09, 0xce,
+	0x88, 0x7f, 0x8b, 0xef, 0xf6, 0x5b, 0xb2, 0x8a, 0xbc, 0xaa, 0xff, 0x29, 0x88, 0xdd, 0x29, 0xd6,
+	0xfa, 0xf0, 0x7f, 0x07, 0x00, 0xb3, 0xe6, 0x17, 0xc5, 0x21, 0x42, 0x00, 0x00,
 }
@@ -1,4 +1,23 @@
 #!/bin/bash
 
+# Usage: ./runtest.bash [--postgres]
+#
+# The tests of the PostgreSQL store need a database given by POSTGRES_DSN and
+# are skipped without it. With --postgres, if POSTGRES_DSN is not set, a
+# disposable PostgreSQL container is started with docker for the tests.
+
 export PROJECT_ROOT=`pwd`
+
+if [[ "$1" == "--postgres" && -z "${POSTGRES_DSN}" ]]; then
+  container=$(docker run -d --rm -e POSTGRES_HOST_AUTH_METHOD=trust -p 127.0.0.1::5432 postgres:12) || exit 1
+  trap "docker stop ${container} > /dev/null" EXIT
+  for i in $(seq 30); do
+    if docker exec ${container} pg_isready -U postgres -h localhost > /dev/null 2>&1; then
+      break
+    fi
+    sleep 1
+  done
+  export POSTGRES_DSN="postgres://postgres@$(docker port ${container} 5432)/postgres?sslmode=disable"
+fi
+
 go test ./...